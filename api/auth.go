@@ -0,0 +1,348 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"codeplugs/models"
+	"codeplugs/services"
+
+	"gorm.io/gorm"
+)
+
+// sessionCookie carries the browser's session token
+const sessionCookie = "codeplugs_session"
+
+// authState is what the auth middleware learned about a request
+type authState struct {
+	enabled bool
+	user    *models.User
+}
+
+type authKey struct{}
+
+// currentUser is the request's account, or nil when auth is off
+func currentUser(r *http.Request) *models.User {
+	if s, ok := r.Context().Value(authKey{}).(*authState); ok {
+		return s.user
+	}
+	return nil
+}
+
+// requireRole responds 403 and returns false unless the request's account
+// has role. Handlers use it for checks that depend on the request body;
+// with auth off, or outside the middleware, everything is allowed.
+func requireRole(w http.ResponseWriter, r *http.Request, role models.Role) bool {
+	s, ok := r.Context().Value(authKey{}).(*authState)
+	if !ok || !s.enabled || (s.user != nil && s.user.Role.Allows(role)) {
+		return true
+	}
+	RespondError(w, http.StatusForbidden, "This requires the "+string(role)+" role")
+	return false
+}
+
+// authorize wraps a route's handler with authentication and the role its
+// operation requires, then opens the request's project. Nothing is checked
+// while no account exists.
+func (s *Server) authorize(route apiRoute) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(context.WithValue(r.Context(), serverKey{}, s))
+		enabled, err := services.AuthEnabled(mainDB(r))
+		if err != nil {
+			RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		state := &authState{enabled: enabled}
+		if state.enabled {
+			user, viaCookie, err := authenticate(r)
+			if err != nil {
+				RespondError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			state.user = user
+			if !route.Public {
+				if user == nil {
+					w.Header().Set("WWW-Authenticate", `Bearer realm="codeplugs"`)
+					RespondError(w, http.StatusUnauthorized, "Login required")
+					return
+				}
+				// SameSite covers most cross-site requests; this covers the rest
+				if viaCookie && r.Method != "GET" && !s.checkOrigin(r) {
+					RespondError(w, http.StatusForbidden, "Cross-origin request denied")
+					return
+				}
+				if role, ok := route.role(r.Method, r.URL.Query()); ok && !user.Role.Allows(role) {
+					RespondError(w, http.StatusForbidden, "This requires the "+string(role)+" role")
+					return
+				}
+			}
+		}
+		if r = s.withProject(w, r); r == nil {
+			return
+		}
+		route.Handler(w, r.WithContext(context.WithValue(r.Context(), authKey{}, state)))
+	})
+}
+
+// mainDB holds the accounts, which every project shares
+func mainDB(r *http.Request) *gorm.DB {
+	return serverFrom(r).Workspaces.Main().DB()
+}
+
+// authenticate finds the account behind a bearer API token or a session
+// cookie. A missing or unknown credential yields a nil user.
+func authenticate(r *http.Request) (user *models.User, viaCookie bool, err error) {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		user, err = services.UserForAPIToken(mainDB(r), strings.TrimSpace(token))
+	} else if cookie, cerr := r.Cookie(sessionCookie); cerr == nil {
+		viaCookie = true
+		user, err = services.UserForSession(mainDB(r), cookie.Value)
+	} else {
+		return nil, false, nil
+	}
+	if err == gorm.ErrRecordNotFound {
+		return nil, viaCookie, nil
+	}
+	return user, viaCookie, err
+}
+
+// checkOrigin allows requests without an Origin header (non-browser
+// clients), from pages on this host, and from Config.AllowedOrigins
+func (s *Server) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	for _, allowed := range s.Config.AllowedOrigins {
+		if strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// loginRequest is the body of a login
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// sessionInfo describes the caller's login
+type sessionInfo struct {
+	AuthEnabled bool         `json:"auth_enabled"`
+	User        *models.User `json:"user"`
+	ExpiresAt   *time.Time   `json:"expires_at,omitempty"`
+}
+
+// HandleLogin checks a password and sets the session cookie
+func HandleLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if enabled, err := services.AuthEnabled(mainDB(r)); err != nil {
+		RespondError(w, http.StatusInternalServerError, err.Error())
+		return
+	} else if !enabled {
+		RespondError(w, http.StatusBadRequest, "No accounts exist, so login is not required")
+		return
+	}
+	var req loginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondError(w, http.StatusBadRequest, "Invalid JSON")
+		return
+	}
+	token, session, err := services.Login(mainDB(r), req.Username, req.Password, serverFrom(r).Config.SessionTTL)
+	if err == services.ErrInvalidLogin {
+		RespondError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if err != nil {
+		RespondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    token,
+		Path:     "/",
+		Expires:  session.ExpiresAt,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+	RespondJSON(w, sessionInfo{AuthEnabled: true, User: &session.User, ExpiresAt: &session.ExpiresAt})
+}
+
+// HandleLogout ends the session and clears its cookie
+func HandleLogout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if cookie, err := r.Cookie(sessionCookie); err == nil {
+		services.Logout(mainDB(r), cookie.Value)
+	}
+	http.SetCookie(w, &http.Cookie{Name: sessionCookie, Path: "/", MaxAge: -1, HttpOnly: true, SameSite: http.SameSiteStrictMode})
+	RespondJSON(w, map[string]string{"message": "Logged out"})
+}
+
+// HandleSession reports whether auth is on and who the caller is, so the UI
+// knows whether to show the login page
+func HandleSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	enabled, err := services.AuthEnabled(mainDB(r))
+	if err != nil {
+		RespondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	RespondJSON(w, sessionInfo{AuthEnabled: enabled, User: currentUser(r)})
+}
+
+// tokenRequest names a new API token
+type tokenRequest struct {
+	Name string `json:"name"`
+}
+
+// createdToken is a new API token, the only time its secret is shown
+type createdToken struct {
+	models.APIToken
+	Token string `json:"token"`
+}
+
+// HandleAPITokens lists, creates and revokes the caller's API tokens
+func HandleAPITokens(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" && r.Method != "POST" && r.Method != "DELETE" {
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	user := currentUser(r)
+	if user == nil {
+		RespondError(w, http.StatusBadRequest, "API tokens need an account; none exist yet")
+		return
+	}
+	switch r.Method {
+	case "GET":
+		var tokens []models.APIToken
+		mainDB(r).Where("user_id = ?", user.ID).Order("id").Find(&tokens)
+		RespondJSON(w, tokens)
+	case "POST":
+		var req tokenRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			RespondError(w, http.StatusBadRequest, "Invalid JSON")
+			return
+		}
+		if strings.TrimSpace(req.Name) == "" {
+			RespondValidationError(w, models.ValidationErrors{{Field: "name", Message: "name is required"}})
+			return
+		}
+		token, t, err := services.CreateAPIToken(mainDB(r), user.ID, strings.TrimSpace(req.Name))
+		if err != nil {
+			RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		RespondJSON(w, createdToken{APIToken: *t, Token: token})
+	case "DELETE":
+		id, _ := strconv.Atoi(r.URL.Query().Get("id"))
+		res := mainDB(r).Where("id = ? AND user_id = ?", id, user.ID).Delete(&models.APIToken{})
+		if res.Error != nil {
+			RespondError(w, http.StatusInternalServerError, res.Error.Error())
+			return
+		}
+		if res.RowsAffected == 0 {
+			RespondError(w, http.StatusNotFound, "Token not found")
+			return
+		}
+		RespondJSON(w, map[string]string{"message": "Token revoked"})
+	}
+}
+
+// userRequest creates or changes an account; omitted fields are unchanged
+type userRequest struct {
+	Username string       `json:"username"`
+	Password *string      `json:"password"`
+	Role     *models.Role `json:"role"`
+}
+
+// HandleUsers manages accounts. Creating the first one, which must be an
+// admin, turns authentication on.
+func HandleUsers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		var users []models.User
+		mainDB(r).Order("username").Find(&users)
+		RespondJSON(w, users)
+	case "POST":
+		var req userRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			RespondError(w, http.StatusBadRequest, "Invalid JSON")
+			return
+		}
+		role, password := models.RoleReadOnly, ""
+		if req.Role != nil {
+			role = *req.Role
+		}
+		if req.Password != nil {
+			password = *req.Password
+		}
+		user, err := services.CreateUser(mainDB(r), req.Username, password, role)
+		if err != nil {
+			respondUserError(w, err)
+			return
+		}
+		RespondJSON(w, user)
+	case "PATCH":
+		id, _ := strconv.Atoi(r.URL.Query().Get("id"))
+		var req userRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			RespondError(w, http.StatusBadRequest, "Invalid JSON")
+			return
+		}
+		user, err := services.UpdateUser(mainDB(r), uint(id), req.Role, req.Password)
+		if err != nil {
+			respondUserError(w, err)
+			return
+		}
+		RespondJSON(w, user)
+	case "DELETE":
+		id, _ := strconv.Atoi(r.URL.Query().Get("id"))
+		if err := services.DeleteUser(mainDB(r), uint(id)); err != nil {
+			respondUserError(w, err)
+			return
+		}
+		RespondJSON(w, map[string]string{"message": "User deleted"})
+	default:
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func respondUserError(w http.ResponseWriter, err error) {
+	var fields models.ValidationErrors
+	switch {
+	case errors.As(err, &fields):
+		RespondValidationError(w, err)
+	case err == gorm.ErrRecordNotFound:
+		RespondError(w, http.StatusNotFound, "User not found")
+	case err == services.ErrLastAdmin, err == services.ErrFirstUserAdmin:
+		RespondError(w, http.StatusConflict, err.Error())
+	case strings.Contains(err.Error(), "UNIQUE constraint failed"):
+		RespondError(w, http.StatusConflict, "Username already exists")
+	default:
+		RespondError(w, http.StatusInternalServerError, err.Error())
+	}
+}
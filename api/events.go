@@ -0,0 +1,170 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Events sent over /api/ws. Record events are named "<kind>.<action>":
+//
+//   - kind is channel, contact, zone, scanlist, filter_list, roaming_channel,
+//     roaming_zone, nxdn_talkgroup, nxdn_contact, dstar_repeater,
+//     p25_talkgroup, contact_pin or contact_override
+//   - action is created, updated or deleted; reordered when a zone's or
+//     list's members change; bulk_updated or merged when many records change
+//     at once
+//
+// Record events carry a ChangeEvent and are only sent to clients of the
+// project they happened in. import.completed means any table may have
+// changed and clients should reload. project.created and project.updated
+// carry the Project and go to every client.
+const (
+	EventImportProgress  = "import_progress"
+	EventImportCompleted = "import.completed"
+
+	EventChannelReordered   = "channel.reordered"
+	EventChannelBulkUpdated = "channel.bulk_updated"
+	EventContactMerged      = "contact.merged"
+	EventContactBulkUpdated = "contact.bulk_updated"
+
+	// Control messages, sent only to the client they concern
+	EventSubscribed = "subscribed"
+	EventResync     = "resync"
+	EventError      = "error"
+)
+
+// Event is one message on the socket. Seq increases by one per event, so a
+// reconnecting client passes the last Seq it saw as ?since= to catch up.
+// Sequence numbers are shared by every project, so a client may see gaps.
+type Event struct {
+	Seq     uint64          `json:"seq,omitempty"`
+	Type    string          `json:"type"`
+	Project string          `json:"project,omitempty"` // Project whose records changed; empty for server-wide events
+	Time    time.Time       `json:"time"`
+	Data    json.RawMessage `json:"data,omitempty"`
+
+	transient bool // not sequenced or replayed
+}
+
+func newEvent(eventType string, data json.RawMessage) Event {
+	return Event{Type: eventType, Time: time.Now().UTC(), Data: data}
+}
+
+// controlMessage encodes a control event for a single client
+func controlMessage(eventType string, data interface{}) []byte {
+	raw, _ := json.Marshal(data)
+	msg, _ := json.Marshal(newEvent(eventType, raw))
+	return msg
+}
+
+// ChangeEvent identifies the records an event is about: ID and, after a
+// write, the new Version for one record; IDs and Count for several.
+type ChangeEvent struct {
+	ID      uint   `json:"id,omitempty"`
+	Version int    `json:"version,omitempty"`
+	IDs     []uint `json:"ids,omitempty"`
+	Count   int64  `json:"count,omitempty"`
+}
+
+// Subscription is sent by the client, as
+// {"action": "subscribe", "events": ["channel.*"], "since": 42}, to choose
+// the events it receives. No events means all of them; since, if set,
+// replays what it missed.
+type Subscription struct {
+	Action string   `json:"action"`
+	Events []string `json:"events"`
+	Since  *uint64  `json:"since"`
+}
+
+// BroadcastEvent sends an event with data to the clients subscribed to it
+// on the project r was served for
+func BroadcastEvent(r *http.Request, eventType string, data interface{}) {
+	raw, _ := json.Marshal(data)
+	e := newEvent(eventType, raw)
+	e.Project = projectID(r)
+	serverFrom(r).Hub.send(e)
+}
+
+// broadcastServerEvent sends an event that isn't about one project's records
+// to every subscribed client
+func broadcastServerEvent(r *http.Request, eventType string, data interface{}) {
+	raw, _ := json.Marshal(data)
+	serverFrom(r).Hub.send(newEvent(eventType, raw))
+}
+
+// BroadcastChange notifies clients of a change, e.g. "channel.updated"
+func BroadcastChange(r *http.Request, event string, id uint, version int) {
+	BroadcastEvent(r, event, ChangeEvent{ID: id, Version: version})
+}
+
+// broadcastDeleted notifies clients that the record with the ?id= value id
+// was deleted
+func broadcastDeleted(r *http.Request, kind, id string) {
+	n, _ := strconv.ParseUint(id, 10, 64)
+	BroadcastChange(r, kind+".deleted", uint(n), 0)
+}
+
+// broadcastDMRIDDeleted is broadcastDeleted for records keyed by DMR ID
+func broadcastDMRIDDeleted(r *http.Request, kind, dmrID string) {
+	n, _ := strconv.Atoi(dmrID)
+	BroadcastEvent(r, kind+".deleted", map[string]int{"dmr_id": n})
+}
+
+// changeEvent names the event for a written record: a record at version 1
+// was just created
+func changeEvent(kind string, version int) string {
+	if version == 1 {
+		return kind + ".created"
+	}
+	return kind + ".updated"
+}
+
+// eventFilter matches event types against subscription patterns: an exact
+// type, "kind.*" for every event of a kind, or "*". No patterns match all.
+type eventFilter []string
+
+func newEventFilter(patterns []string) eventFilter {
+	var f eventFilter
+	for _, p := range patterns {
+		if p = strings.TrimSpace(p); p != "" {
+			f = append(f, p)
+		}
+	}
+	return f
+}
+
+func (f eventFilter) match(eventType string) bool {
+	if len(f) == 0 {
+		return true
+	}
+	for _, p := range f {
+		if p == "*" || p == eventType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(p, "*"); ok && strings.HasPrefix(eventType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f eventFilter) patterns() []string {
+	if len(f) == 0 {
+		return []string{"*"}
+	}
+	return f
+}
+
+// statusRecorder remembers the status code a handler responded with
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
@@ -4,16 +4,19 @@ import (
 	"archive/zip"
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 
-	"codeplugs/database"
+	"codeplugs/config"
 	"codeplugs/exporter"
 	"codeplugs/importer"
 	"codeplugs/models"
@@ -24,41 +27,187 @@ import (
 )
 
 func HandleChannels(w http.ResponseWriter, r *http.Request) {
+	db := dbFrom(r)
 	switch r.Method {
 	case "GET":
-		var channels []models.Channel
-		database.DB.Order("sort_order asc").Find(&channels)
-		RespondJSON(w, channels)
-	case "POST":
-		var ch models.Channel
-		if err := json.NewDecoder(r.Body).Decode(&ch); err != nil {
+		if id := r.URL.Query().Get("id"); id != "" {
+			getResource[models.Channel](w, r, "Channel", id)
+			return
+		}
+		q, err := parseChannelQuery(r)
+		if err != nil {
 			RespondError(w, http.StatusBadRequest, err.Error())
 			return
 		}
-		if ch.ID == 0 {
-			database.DB.Create(&ch)
-		} else {
-			database.DB.Save(&ch)
+		result, err := services.QueryChannels(db, q)
+		if errors.Is(err, services.ErrInvalidSort) || errors.Is(err, services.ErrInvalidCursor) || errors.Is(err, services.ErrInvalidQuery) {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err != nil {
+			RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if result.Data == nil {
+			result.Data = []models.Channel{}
+		}
+		RespondJSONWithMeta(w, result.Data, map[string]interface{}{
+			"total":       result.Total,
+			"page":        q.Page,
+			"limit":       q.Limit,
+			"next_cursor": result.NextCursor,
+		})
+	case "POST", "PUT", "PATCH":
+		ch, err := writeResource[models.Channel](w, r, "Channel")
+		if err != nil {
+			return
 		}
 		RespondJSON(w, ch)
+		BroadcastChange(r, changeEvent("channel", ch.Version), ch.ID, ch.Version)
 	case "DELETE":
-		id := r.URL.Query().Get("id")
-		if id != "" {
-			database.DB.Delete(&models.Channel{}, id)
-			RespondJSON(w, nil)
+		ch, err := deleteResource[models.Channel](w, r, "Channel", nil)
+		if err != nil {
+			return
+		}
+		RespondJSON(w, nil)
+		BroadcastChange(r, "channel.deleted", ch.ID, 0)
+	default:
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// channelBulkRequest is the body of PATCH /api/channels/bulk. The filter
+// takes the same selectors as the channel listing and export.
+type channelBulkRequest struct {
+	Filter struct {
+		IDs      []uint   `json:"ids"`
+		Zone     []string `json:"zone"`
+		Mode     []string `json:"mode"`
+		Protocol []string `json:"protocol"`
+		Band     []string `json:"band"`
+		Contact  []string `json:"contact"`
+		Search   string   `json:"search"`
+		MinFreq  float64  `json:"min_freq"`
+		MaxFreq  float64  `json:"max_freq"`
+		Skip     *bool    `json:"skip"`
+	} `json:"filter"`
+	Set map[string]interface{} `json:"set"`
+}
+
+// HandleChannelBulk sets the same fields on every channel matching a filter
+// in one transaction, e.g. {"filter": {"zone": ["Detroit Area"]}, "set":
+// {"power": "Low"}}, and reports how many channels matched and changed.
+func HandleChannelBulk(w http.ResponseWriter, r *http.Request) {
+	db := dbFrom(r)
+	if r.Method != "PATCH" {
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req channelBulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	f := req.Filter
+	result, err := services.BulkEditChannels(db, services.ChannelBulkEdit{
+		Filter: services.ChannelQuery{
+			IDs:       f.IDs,
+			Zones:     f.Zone,
+			Modes:     f.Mode,
+			Protocols: f.Protocol,
+			Bands:     f.Band,
+			Contacts:  f.Contact,
+			Search:    f.Search,
+			MinFreq:   f.MinFreq,
+			MaxFreq:   f.MaxFreq,
+			Skip:      f.Skip,
+		},
+		Set: req.Set,
+	})
+	var invalid *services.ChannelValidationError
+	if errors.As(err, &invalid) {
+		RespondValidationError(w, err)
+		return
+	}
+	if errors.Is(err, services.ErrInvalidQuery) || errors.Is(err, services.ErrEmptySelection) {
+		RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		RespondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	RespondJSON(w, result)
+	if result.Changed > 0 {
+		BroadcastEvent(r, EventChannelBulkUpdated, ChangeEvent{IDs: result.IDs, Count: result.Changed})
+	}
+}
+
+// parseChannelQuery reads channel listing filters from the query string:
+// mode, protocol, band, zone and contact (comma-separated or repeated),
+// search, min_freq/max_freq in MHz, skip, sort, order, page, limit and cursor.
+// Without a limit every matching channel is returned.
+func parseChannelQuery(r *http.Request) (services.ChannelQuery, error) {
+	params := r.URL.Query()
+	q := services.ChannelQuery{
+		Modes:     splitQueryList(params["mode"]),
+		Protocols: splitQueryList(params["protocol"]),
+		Bands:     splitQueryList(params["band"]),
+		Zones:     splitQueryList(params["zone"]),
+		Contacts:  splitQueryList(params["contact"]),
+		Search:    params.Get("search"),
+		Sort:      params.Get("sort"),
+		Order:     params.Get("order"),
+		Cursor:    params.Get("cursor"),
+	}
+	for name, dst := range map[string]*float64{"min_freq": &q.MinFreq, "max_freq": &q.MaxFreq} {
+		if v := params.Get(name); v != "" {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return q, fmt.Errorf("invalid %s %q", name, v)
+			}
+			*dst = f
+		}
+	}
+	if v := params.Get("skip"); v != "" {
+		skip, err := strconv.ParseBool(v)
+		if err != nil {
+			return q, fmt.Errorf("invalid skip %q", v)
+		}
+		q.Skip = &skip
+	}
+	for name, dst := range map[string]*int{"page": &q.Page, "limit": &q.Limit} {
+		if v := params.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return q, fmt.Errorf("invalid %s %q", name, v)
+			}
+			*dst = n
 		}
 	}
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.Limit > services.MaxChannelQueryLimit {
+		q.Limit = services.MaxChannelQueryLimit
+	}
+	return q, nil
+}
+
+// channelReorderRequest lists every channel ID in its new order
+type channelReorderRequest struct {
+	IDs []uint `json:"ids"`
 }
 
 func HandleChannelReorder(w http.ResponseWriter, r *http.Request) {
+	db := dbFrom(r)
 	if r.Method != "POST" {
 		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
-	var req struct {
-		IDs []uint `json:"ids"`
-	}
+	var req channelReorderRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		RespondError(w, http.StatusBadRequest, err.Error())
 		return
@@ -66,7 +215,7 @@ func HandleChannelReorder(w http.ResponseWriter, r *http.Request) {
 
 	log.Printf("Reordering %d channels via SortOrder...", len(req.IDs))
 
-	tx := database.DB.Begin()
+	tx := db.Begin()
 	if tx.Error != nil {
 		RespondError(w, http.StatusInternalServerError, "Failed to begin transaction")
 		return
@@ -81,7 +230,9 @@ func HandleChannelReorder(w http.ResponseWriter, r *http.Request) {
 	for i, id := range req.IDs {
 		// i is 0-based index, we can use it directly as order (or i+1)
 		// Batch update is efficient, but simple iteration is fine for SQLite.
-		if err := tx.Model(&models.Channel{}).Where("id = ?", id).Update("sort_order", i+1).Error; err != nil {
+		// Only channels that actually move get a new version
+		if err := tx.Model(&models.Channel{}).Where("id = ? AND sort_order <> ?", id, i+1).
+			Updates(map[string]interface{}{"sort_order": i + 1, "version": models.BumpVersion()}).Error; err != nil {
 			tx.Rollback()
 			log.Printf("Error updating SortOrder for channel %d: %v", id, err)
 			RespondError(w, http.StatusInternalServerError, "Failed to update channel order")
@@ -95,9 +246,12 @@ func HandleChannelReorder(w http.ResponseWriter, r *http.Request) {
 	}
 
 	RespondJSON(w, nil)
+	BroadcastEvent(r, EventChannelReordered, ChangeEvent{IDs: req.IDs, Count: int64(len(req.IDs))})
 }
 
 func HandleImport(w http.ResponseWriter, r *http.Request) {
+	db := dbFrom(r)
+	progress := serverFrom(r).Jobs.Import
 	if r.Method != "POST" {
 		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
@@ -108,6 +262,20 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 	format := r.FormValue("format")
 	sourceMode := r.FormValue("source_mode")
 
+	// Restoring a database or overwriting existing records can't be undone
+	if (format == "db" || r.FormValue("overwrite") == "true") && !requireRole(w, r, models.RoleAdmin) {
+		return
+	}
+
+	// Imports can touch any table, so one event tells clients to reload
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	w = rec
+	defer func() {
+		if rec.status < 300 {
+			BroadcastEvent(r, EventImportCompleted, map[string]string{"format": format})
+		}
+	}()
+
 	var path string
 	var tempFile *os.File
 	var err error
@@ -149,24 +317,24 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 				}
 			}
 
-			CurrentProgress.mu.Lock()
-			CurrentProgress.Total = totalToProcess
-			CurrentProgress.Processed = 0
-			CurrentProgress.Status = "running"
-			CurrentProgress.Message = "Starting ZIP import..."
-			CurrentProgress.mu.Unlock()
-			BroadcastProgress()
+			progress.mu.Lock()
+			progress.Total = totalToProcess
+			progress.Processed = 0
+			progress.Status = "running"
+			progress.Message = "Starting ZIP import..."
+			progress.mu.Unlock()
+			progress.Broadcast()
 
 			processedCount := 0
 
 			if f, ok := filesMap["digital_contacts.csv"]; ok {
-				CurrentProgress.mu.Lock()
-				CurrentProgress.Message = "Importing digital contacts..."
-				CurrentProgress.mu.Unlock()
-				BroadcastProgress()
+				progress.mu.Lock()
+				progress.Message = "Importing digital contacts..."
+				progress.mu.Unlock()
+				progress.Broadcast()
 
 				rc, _ := f.Open()
-				err := importer.ImportDM32UVDigitalContacts(database.DB, rc)
+				err := importer.ImportDM32UVDigitalContacts(db, rc)
 				rc.Close()
 				if err != nil {
 					log.Printf("Error importing digital contacts: %v", err)
@@ -174,20 +342,20 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 					return
 				}
 				processedCount++
-				CurrentProgress.mu.Lock()
-				CurrentProgress.Processed = processedCount
-				CurrentProgress.mu.Unlock()
-				BroadcastProgress()
+				progress.mu.Lock()
+				progress.Processed = processedCount
+				progress.mu.Unlock()
+				progress.Broadcast()
 			}
 
 			if f, ok := filesMap["talkgroups.csv"]; ok {
-				CurrentProgress.mu.Lock()
-				CurrentProgress.Message = "Importing talkgroups..."
-				CurrentProgress.mu.Unlock()
-				BroadcastProgress()
+				progress.mu.Lock()
+				progress.Message = "Importing talkgroups..."
+				progress.mu.Unlock()
+				progress.Broadcast()
 
 				rc, _ := f.Open()
-				err := importer.ImportDM32UVTalkgroups(database.DB, rc)
+				err := importer.ImportDM32UVTalkgroups(db, rc)
 				rc.Close()
 				if err != nil {
 					log.Printf("Error importing talkgroups: %v", err)
@@ -195,20 +363,20 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 					return
 				}
 				processedCount++
-				CurrentProgress.mu.Lock()
-				CurrentProgress.Processed = processedCount
-				CurrentProgress.mu.Unlock()
-				BroadcastProgress()
+				progress.mu.Lock()
+				progress.Processed = processedCount
+				progress.mu.Unlock()
+				progress.Broadcast()
 			}
 
 			if f, ok := filesMap["channels.csv"]; ok {
-				CurrentProgress.mu.Lock()
-				CurrentProgress.Message = "Importing channels..."
-				CurrentProgress.mu.Unlock()
-				BroadcastProgress()
+				progress.mu.Lock()
+				progress.Message = "Importing channels..."
+				progress.mu.Unlock()
+				progress.Broadcast()
 
 				rc, _ := f.Open()
-				err := importer.ImportDM32UVChannels(database.DB, rc)
+				err := importer.ImportDM32UVChannels(db, rc)
 				rc.Close()
 				if err != nil {
 					log.Printf("Error importing channels: %v", err)
@@ -216,20 +384,20 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 					return
 				}
 				processedCount++
-				CurrentProgress.mu.Lock()
-				CurrentProgress.Processed = processedCount
-				CurrentProgress.mu.Unlock()
-				BroadcastProgress()
+				progress.mu.Lock()
+				progress.Processed = processedCount
+				progress.mu.Unlock()
+				progress.Broadcast()
 			}
 
 			if f, ok := filesMap["zones.csv"]; ok {
-				CurrentProgress.mu.Lock()
-				CurrentProgress.Message = "Importing zones..."
-				CurrentProgress.mu.Unlock()
-				BroadcastProgress()
+				progress.mu.Lock()
+				progress.Message = "Importing zones..."
+				progress.mu.Unlock()
+				progress.Broadcast()
 
 				rc, _ := f.Open()
-				err := importer.ImportDM32UVZones(database.DB, rc)
+				err := importer.ImportDM32UVZones(db, rc)
 				rc.Close()
 				if err != nil {
 					log.Printf("Error importing zones: %v", err)
@@ -237,21 +405,21 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 					return
 				}
 				processedCount++
-				CurrentProgress.mu.Lock()
-				CurrentProgress.Processed = processedCount
-				CurrentProgress.mu.Unlock()
-				BroadcastProgress()
+				progress.mu.Lock()
+				progress.Processed = processedCount
+				progress.mu.Unlock()
+				progress.Broadcast()
 			}
 
 			// AnyTone 890 / DM32UV Scan Lists
 			if f, ok := filesMap["ScanList.CSV"]; ok {
-				CurrentProgress.mu.Lock()
-				CurrentProgress.Message = "Importing scan lists (AnyTone)..."
-				CurrentProgress.mu.Unlock()
-				BroadcastProgress()
+				progress.mu.Lock()
+				progress.Message = "Importing scan lists (AnyTone)..."
+				progress.mu.Unlock()
+				progress.Broadcast()
 
 				rc, _ := f.Open()
-				err := importer.ImportAnyTone890ScanLists(database.DB, rc)
+				err := importer.ImportAnyTone890ScanLists(db, rc)
 				rc.Close()
 				if err != nil {
 					log.Printf("Error importing scan lists (AnyTone): %v", err)
@@ -260,13 +428,13 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 				}
 				processedCount++
 			} else if f, ok := filesMap["scan_lists.csv"]; ok {
-				CurrentProgress.mu.Lock()
-				CurrentProgress.Message = "Importing scan lists (DM32UV)..."
-				CurrentProgress.mu.Unlock()
-				BroadcastProgress()
+				progress.mu.Lock()
+				progress.Message = "Importing scan lists (DM32UV)..."
+				progress.mu.Unlock()
+				progress.Broadcast()
 
 				rc, _ := f.Open()
-				err := importer.ImportDM32UVScanLists(database.DB, rc)
+				err := importer.ImportDM32UVScanLists(db, rc)
 				rc.Close()
 				if err != nil {
 					log.Printf("Error importing scan lists (DM32UV): %v", err)
@@ -275,20 +443,20 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 				}
 				processedCount++
 			}
-			CurrentProgress.mu.Lock()
-			CurrentProgress.Processed = processedCount
-			CurrentProgress.mu.Unlock()
-			BroadcastProgress()
+			progress.mu.Lock()
+			progress.Processed = processedCount
+			progress.mu.Unlock()
+			progress.Broadcast()
 
 			// AnyTone 890 / DM32UV Roaming Channels
 			if f, ok := filesMap["RoamChannel.CSV"]; ok {
-				CurrentProgress.mu.Lock()
-				CurrentProgress.Message = "Importing roaming channels (AnyTone)..."
-				CurrentProgress.mu.Unlock()
-				BroadcastProgress()
+				progress.mu.Lock()
+				progress.Message = "Importing roaming channels (AnyTone)..."
+				progress.mu.Unlock()
+				progress.Broadcast()
 
 				rc, _ := f.Open()
-				err := importer.ImportAnyTone890RoamingChannels(database.DB, rc)
+				err := importer.ImportAnyTone890RoamingChannels(db, rc)
 				rc.Close()
 				if err != nil {
 					log.Printf("Error importing roaming channels (AnyTone): %v", err)
@@ -297,13 +465,13 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 				}
 				processedCount++
 			} else if f, ok := filesMap["roaming_channels.csv"]; ok {
-				CurrentProgress.mu.Lock()
-				CurrentProgress.Message = "Importing roaming channels (DM32UV)..."
-				CurrentProgress.mu.Unlock()
-				BroadcastProgress()
+				progress.mu.Lock()
+				progress.Message = "Importing roaming channels (DM32UV)..."
+				progress.mu.Unlock()
+				progress.Broadcast()
 
 				rc, _ := f.Open()
-				err := importer.ImportDM32UVRoamingChannels(database.DB, rc)
+				err := importer.ImportDM32UVRoamingChannels(db, rc)
 				rc.Close()
 				if err != nil {
 					log.Printf("Error importing roaming channels (DM32UV): %v", err)
@@ -312,20 +480,20 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 				}
 				processedCount++
 			}
-			CurrentProgress.mu.Lock()
-			CurrentProgress.Processed = processedCount
-			CurrentProgress.mu.Unlock()
-			BroadcastProgress()
+			progress.mu.Lock()
+			progress.Processed = processedCount
+			progress.mu.Unlock()
+			progress.Broadcast()
 
 			// AnyTone 890 / DM32UV Roaming Zones
 			if f, ok := filesMap["RoamZone.CSV"]; ok {
-				CurrentProgress.mu.Lock()
-				CurrentProgress.Message = "Importing roaming zones (AnyTone)..."
-				CurrentProgress.mu.Unlock()
-				BroadcastProgress()
+				progress.mu.Lock()
+				progress.Message = "Importing roaming zones (AnyTone)..."
+				progress.mu.Unlock()
+				progress.Broadcast()
 
 				rc, _ := f.Open()
-				err := importer.ImportAnyTone890RoamingZones(database.DB, rc)
+				err := importer.ImportAnyTone890RoamingZones(db, rc)
 				rc.Close()
 				if err != nil {
 					log.Printf("Error importing roaming zones (AnyTone): %v", err)
@@ -334,13 +502,13 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 				}
 				processedCount++
 			} else if f, ok := filesMap["roaming_zones.csv"]; ok {
-				CurrentProgress.mu.Lock()
-				CurrentProgress.Message = "Importing roaming zones (DM32UV)..."
-				CurrentProgress.mu.Unlock()
-				BroadcastProgress()
+				progress.mu.Lock()
+				progress.Message = "Importing roaming zones (DM32UV)..."
+				progress.mu.Unlock()
+				progress.Broadcast()
 
 				rc, _ := f.Open()
-				err := importer.ImportDM32UVRoamingZones(database.DB, rc)
+				err := importer.ImportDM32UVRoamingZones(db, rc)
 				rc.Close()
 				if err != nil {
 					log.Printf("Error importing roaming zones (DM32UV): %v", err)
@@ -349,16 +517,16 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 				}
 				processedCount++
 			}
-			CurrentProgress.mu.Lock()
-			CurrentProgress.Processed = processedCount
-			CurrentProgress.mu.Unlock()
-			BroadcastProgress()
+			progress.mu.Lock()
+			progress.Processed = processedCount
+			progress.mu.Unlock()
+			progress.Broadcast()
 
-			CurrentProgress.mu.Lock()
-			CurrentProgress.Status = "completed"
-			CurrentProgress.Message = "Zip Import Complete"
-			CurrentProgress.mu.Unlock()
-			BroadcastProgress()
+			progress.mu.Lock()
+			progress.Status = "completed"
+			progress.Message = "Zip Import Complete"
+			progress.mu.Unlock()
+			progress.Broadcast()
 
 			RespondJSON(w, map[string]string{"message": "Zip Import Complete"})
 			return
@@ -387,19 +555,10 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 			}
 			tempFile.Close()
 
-			database.Close()
-			targetDB := "codeplugs.db" // TODO: Use actual configured path
-			os.Rename(targetDB, targetDB+".bak")
-
-			src, _ := os.Open(tempName)
-			dst, _ := os.Create(targetDB)
-			io.Copy(dst, src)
-			src.Close()
-			dst.Close()
-
-			database.Connect(targetDB)
-
-			database.Connect(targetDB)
+			if err := handleFrom(r).Restore(tempName); err != nil {
+				RespondError(w, http.StatusInternalServerError, "Error restoring database: "+err.Error())
+				return
+			}
 
 			RespondJSON(w, map[string]interface{}{
 				"message": "Database restored successfully. Please refresh.",
@@ -462,13 +621,13 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 		}
 		defer f.Close()
 
-		CurrentProgress.mu.Lock()
-		CurrentProgress.Total = 0
-		CurrentProgress.Processed = 0
-		CurrentProgress.Status = "running"
-		CurrentProgress.Message = fmt.Sprintf("Importing %s...", importType)
-		CurrentProgress.mu.Unlock()
-		BroadcastProgress()
+		progress.mu.Lock()
+		progress.Total = 0
+		progress.Processed = 0
+		progress.Status = "running"
+		progress.Message = fmt.Sprintf("Importing %s...", importType)
+		progress.mu.Unlock()
+		progress.Broadcast()
 
 		var count int
 		var skipped int
@@ -476,8 +635,8 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 		switch importType {
 		case "channels":
 			if overwrite {
-				database.DB.Session(&gorm.Session{AllowGlobalUpdate: true}).Unscoped().Delete(&models.Channel{})
-				database.DB.Exec("DELETE FROM sqlite_sequence WHERE name = 'channels'")
+				db.Session(&gorm.Session{AllowGlobalUpdate: true}).Unscoped().Delete(&models.Channel{})
+				db.Exec("DELETE FROM sqlite_sequence WHERE name = 'channels'")
 			}
 
 			var channels []models.Channel
@@ -485,9 +644,9 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 
 			switch radioPlatform {
 			case "dm32uv":
-				err = importer.ImportDM32UVChannels(database.DB, f)
+				err = importer.ImportDM32UVChannels(db, f)
 			case "at890":
-				err = importer.ImportAnyTone890Channels(database.DB, f)
+				err = importer.ImportAnyTone890Channels(db, f)
 			default:
 				channels, err = importer.ImportChannelsCSV(f)
 				if err != nil || len(channels) == 0 {
@@ -499,16 +658,16 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 					}
 				}
 				if err == nil {
-					services.ResolveContacts(database.DB, channels)
+					services.ResolveContacts(db, channels)
 					for _, ch := range channels {
 						if !overwrite {
 							var existing models.Channel
-							if database.DB.Where("name = ? AND rx_frequency = ?", ch.Name, ch.RxFrequency).First(&existing).Error == nil {
+							if db.Where("name = ? AND rx_frequency = ?", ch.Name, ch.RxFrequency).First(&existing).Error == nil {
 								skipped++
 								continue
 							}
 						}
-						if res := database.DB.Create(&ch); res.Error == nil {
+						if res := db.Create(&ch); res.Error == nil {
 							count++
 						}
 					}
@@ -522,7 +681,7 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 
 		case "talkgroups":
 			if overwrite {
-				database.DB.Where("type = ?", models.ContactTypeGroup).Delete(&models.Contact{})
+				db.Where("type = ?", models.ContactTypeGroup).Delete(&models.Contact{})
 			}
 
 			var contacts []models.Contact
@@ -530,14 +689,14 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 
 			switch radioPlatform {
 			case "dm32uv":
-				err = importer.ImportDM32UVTalkgroups(database.DB, f)
+				err = importer.ImportDM32UVTalkgroups(db, f)
 			case "at890":
-				err = importer.ImportAnyTone890Talkgroups(database.DB, f)
+				err = importer.ImportAnyTone890Talkgroups(db, f)
 			default:
 				contacts, err = importer.ImportGenericTalkgroups(f)
 				if err == nil {
 					for _, c := range contacts {
-						if err := database.DB.Where("dmr_id = ? AND type = ?", c.DMRID, c.Type).FirstOrCreate(&c).Error; err == nil {
+						if err := db.Where("dmr_id = ? AND type = ?", c.DMRID, c.Type).FirstOrCreate(&c).Error; err == nil {
 							count++
 						}
 					}
@@ -551,35 +710,20 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 
 		case "contacts":
 			if overwrite {
-				database.DB.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&models.DigitalContact{})
+				db.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&models.DigitalContact{})
 			}
 
 			var err error
 			switch radioPlatform {
 			case "dm32uv":
-				err = importer.ImportDM32UVDigitalContacts(database.DB, f)
+				err = importer.ImportDM32UVDigitalContacts(db, f)
 			case "at890":
-				err = importer.ImportAnyTone890DigitalContacts(database.DB, f)
+				err = importer.ImportAnyTone890DigitalContacts(db, f)
 			default:
 				f.Seek(0, 0)
-				contacts, rErr := importer.ImportRadioIDCSV(f, nil)
-				if rErr != nil {
-					err = rErr
-				} else {
-					batchSize := 1000
-					for i := 0; i < len(contacts); i += batchSize {
-						end := i + batchSize
-						if end > len(contacts) {
-							end = len(contacts)
-						}
-						batch := contacts[i:end]
-						database.DB.Clauses(clause.OnConflict{
-							Columns:   []clause.Column{{Name: "dmr_id"}},
-							DoUpdates: clause.AssignmentColumns([]string{"name", "callsign", "city", "state", "country", "remarks"}),
-						}).Create(&batch)
-						count += len(batch)
-					}
-				}
+				var imported int
+				imported, err = importer.ImportRadioIDToDB(db, f, nil, nil)
+				count += imported
 			}
 
 			if err != nil {
@@ -587,13 +731,58 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 
+		case "nxdn_talkgroups":
+			if overwrite {
+				db.Session(&gorm.Session{AllowGlobalUpdate: true}).Unscoped().Delete(&models.NXDNTalkgroup{})
+			}
+
+			talkgroups, err := importer.ImportNXDNTalkgroups(f)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error importing NXDN talkgroups: %v", err), http.StatusBadRequest)
+				return
+			}
+			if len(talkgroups) > 0 {
+				if err := db.Clauses(clause.OnConflict{
+					Columns:   []clause.Column{{Name: "tg_id"}},
+					DoUpdates: clause.AssignmentColumns([]string{"name", "deleted_at", "updated_at"}),
+				}).CreateInBatches(&talkgroups, 1000).Error; err != nil {
+					http.Error(w, fmt.Sprintf("Error saving NXDN talkgroups: %v", err), http.StatusInternalServerError)
+					return
+				}
+			}
+			count = len(talkgroups)
+
+		case "nxdn_contacts":
+			if overwrite {
+				db.Session(&gorm.Session{AllowGlobalUpdate: true}).Unscoped().Delete(&models.NXDNContact{})
+			}
+
+			contacts, err := importer.ImportNXDNCSV(f)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error importing NXDN contacts: %v", err), http.StatusBadRequest)
+				return
+			}
+			if len(contacts) > 0 {
+				if err := db.Clauses(clause.OnConflict{
+					Columns: []clause.Column{{Name: "unit_id"}},
+					DoUpdates: clause.AssignmentColumns([]string{
+						"name", "callsign", "city", "state", "country", "remarks",
+						"deleted_at", "updated_at",
+					}),
+				}).CreateInBatches(&contacts, 1000).Error; err != nil {
+					http.Error(w, fmt.Sprintf("Error saving NXDN contacts: %v", err), http.StatusInternalServerError)
+					return
+				}
+			}
+			count = len(contacts)
+
 		case "zones":
 			var err error
 			switch radioPlatform {
 			case "dm32uv":
-				err = importer.ImportDM32UVZones(database.DB, f)
+				err = importer.ImportDM32UVZones(db, f)
 			case "at890":
-				err = importer.ImportAnyTone890Zones(database.DB, f)
+				err = importer.ImportAnyTone890Zones(db, f)
 			default:
 				http.Error(w, "Generic Zone import not supported yet", http.StatusBadRequest)
 				return
@@ -604,12 +793,12 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
-		CurrentProgress.mu.Lock()
-		CurrentProgress.Status = "completed"
-		CurrentProgress.Message = fmt.Sprintf("Imported %s successfully.", importType)
-		CurrentProgress.Processed = count
-		CurrentProgress.mu.Unlock()
-		BroadcastProgress()
+		progress.mu.Lock()
+		progress.Status = "completed"
+		progress.Message = fmt.Sprintf("Imported %s successfully.", importType)
+		progress.Processed = count
+		progress.mu.Unlock()
+		progress.Broadcast()
 
 		RespondJSON(w, map[string]interface{}{
 			"message": fmt.Sprintf("Successfully imported %s", importType),
@@ -621,13 +810,16 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 	if format == "radioid" {
 		sourceMode := r.FormValue("source_mode")
 		overwrite := r.FormValue("overwrite") == "true"
-		if overwrite {
-			database.DB.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&models.DigitalContact{})
+		// Sync mode diffs against the stored contacts instead of deleting them,
+		// and retires anything missing from the dump, so a filter makes no sense
+		syncMode := r.FormValue("sync") == "true"
+		if overwrite && !syncMode {
+			db.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&models.DigitalContact{})
 		}
 
 		var activeIDs map[int]bool
 		filterFile, _, err := r.FormFile("filter_file")
-		if err == nil {
+		if err == nil && !syncMode {
 			defer filterFile.Close()
 			ids, err := importer.ParseBrandmeisterLastHeard(filterFile)
 			if err == nil {
@@ -636,20 +828,21 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 		}
 
 		var reader io.Reader
+		var totalBytes int64
 
-		CurrentProgress.mu.Lock()
-		CurrentProgress.Total = 0
-		CurrentProgress.Processed = 0
-		CurrentProgress.Status = "running"
-		CurrentProgress.Message = "Initializing..."
-		CurrentProgress.mu.Unlock()
-		BroadcastProgress()
+		progress.mu.Lock()
+		progress.Total = 0
+		progress.Processed = 0
+		progress.Status = "running"
+		progress.Message = "Initializing..."
+		progress.mu.Unlock()
+		progress.Broadcast()
 
 		if sourceMode == "download" {
-			CurrentProgress.mu.Lock()
-			CurrentProgress.Message = "Downloading contacts from RadioID.net..."
-			CurrentProgress.mu.Unlock()
-			BroadcastProgress()
+			progress.mu.Lock()
+			progress.Message = "Downloading contacts from RadioID.net..."
+			progress.mu.Unlock()
+			progress.Broadcast()
 
 			resp, err := http.Get("https://database.radioid.net/static/user.csv")
 			if err != nil {
@@ -658,72 +851,80 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 			}
 			defer resp.Body.Close()
 			reader = resp.Body
+			if resp.ContentLength > 0 {
+				totalBytes = resp.ContentLength
+			}
 		} else {
 			fileRef, _ := os.Open(path)
 			defer fileRef.Close()
 			reader = fileRef
+			if info, err := fileRef.Stat(); err == nil {
+				totalBytes = info.Size()
+			}
 		}
 
-		CurrentProgress.mu.Lock()
-		CurrentProgress.Message = "Parsing CSV data..."
-		CurrentProgress.mu.Unlock()
-		BroadcastProgress()
+		// Progress is reported in bytes read, since the row count isn't known until the end
+		counter := &importer.CountingReader{R: reader}
 
-		contacts, err := importer.ImportRadioIDCSV(reader, activeIDs)
-		if err != nil {
-			http.Error(w, fmt.Sprintf("Error parsing RadioID CSV: %v", err), http.StatusBadRequest)
-			return
-		}
+		progress.mu.Lock()
+		progress.Total = int(totalBytes)
+		progress.Message = "Importing contacts..."
+		progress.mu.Unlock()
+		progress.Broadcast()
 
-		CurrentProgress.mu.Lock()
-		CurrentProgress.Total = len(contacts)
-		CurrentProgress.Processed = 0
-		CurrentProgress.Status = "running"
-		CurrentProgress.Message = "Starting import..."
-		CurrentProgress.mu.Unlock()
-		BroadcastProgress()
+		if syncMode {
+			source := "upload"
+			if sourceMode == "download" {
+				source = "download"
+			}
+			sync, err := services.SyncRadioIDContacts(db, counter, source, func(n int) {
+				progress.mu.Lock()
+				progress.Processed = int(counter.N)
+				progress.Message = fmt.Sprintf("Synced %d contacts...", n)
+				progress.mu.Unlock()
+				progress.Broadcast()
+			})
 
-		err = database.DB.Transaction(func(tx *gorm.DB) error {
-			if len(contacts) > 0 {
-				batchSize := 1000
-				for i := 0; i < len(contacts); i += batchSize {
-					end := i + batchSize
-					if end > len(contacts) {
-						end = len(contacts)
-					}
+			progress.mu.Lock()
+			if err != nil {
+				progress.Status = "error"
+				progress.Message = fmt.Sprintf("Error: %v", err)
+			} else {
+				progress.Processed = progress.Total
+				progress.Status = "completed"
+				progress.Message = fmt.Sprintf("Sync complete: %d added, %d changed, %d removed.", sync.Added, sync.Changed, sync.Removed)
+			}
+			progress.mu.Unlock()
+			progress.Broadcast()
 
-					CurrentProgress.mu.Lock()
-					CurrentProgress.Processed = i
-					CurrentProgress.Message = fmt.Sprintf("Importing contacts %d to %d...", i, end)
-					CurrentProgress.mu.Unlock()
-					BroadcastProgress()
-
-					batch := contacts[i:end]
-					if err := tx.Clauses(clause.OnConflict{
-						Columns: []clause.Column{{Name: "dmr_id"}},
-						DoUpdates: clause.AssignmentColumns([]string{
-							"name", "callsign", "city", "state", "country", "remarks",
-							"deleted_at", "updated_at",
-						}),
-					}).Create(&batch).Error; err != nil {
-						return err
-					}
-				}
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error syncing contacts: %v", err), http.StatusInternalServerError)
+				return
 			}
-			return nil
+
+			RespondJSON(w, sync)
+			return
+		}
+
+		imported, err := importer.ImportRadioIDToDB(db, counter, activeIDs, func(n int) {
+			progress.mu.Lock()
+			progress.Processed = int(counter.N)
+			progress.Message = fmt.Sprintf("Imported %d contacts...", n)
+			progress.mu.Unlock()
+			progress.Broadcast()
 		})
 
-		CurrentProgress.mu.Lock()
+		progress.mu.Lock()
 		if err != nil {
-			CurrentProgress.Status = "error"
-			CurrentProgress.Message = fmt.Sprintf("Error: %v", err)
+			progress.Status = "error"
+			progress.Message = fmt.Sprintf("Error: %v", err)
 		} else {
-			CurrentProgress.Processed = len(contacts)
-			CurrentProgress.Status = "completed"
-			CurrentProgress.Message = fmt.Sprintf("Imported %d contacts successfully.", len(contacts))
+			progress.Processed = progress.Total
+			progress.Status = "completed"
+			progress.Message = fmt.Sprintf("Imported %d contacts successfully.", imported)
 		}
-		CurrentProgress.mu.Unlock()
-		BroadcastProgress()
+		progress.mu.Unlock()
+		progress.Broadcast()
 
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Error saving contacts: %v", err), http.StatusInternalServerError)
@@ -731,9 +932,56 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 		}
 
 		RespondJSON(w, map[string]interface{}{
-			"imported": len(contacts),
+			"imported": imported,
 			"skipped":  0,
-			"message":  fmt.Sprintf("Processed %d contacts successfully.", len(contacts)),
+			"message":  fmt.Sprintf("Processed %d contacts successfully.", imported),
+		})
+		return
+	}
+
+	if format == "talkgroup_catalog" {
+		network := r.FormValue("network")
+		if network == "" {
+			RespondError(w, http.StatusBadRequest, "Network is required")
+			return
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, "Error opening uploaded file")
+			return
+		}
+		defer f.Close()
+
+		n, err := importer.ImportTalkgroupCatalog(db, f, network)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Error importing talkgroups: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		RespondJSON(w, map[string]interface{}{
+			"message": fmt.Sprintf("Imported %d %s talkgroups", n, network),
+			"count":   n,
+		})
+		return
+	}
+
+	if format == "last_heard" {
+		f, err := os.Open(path)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, "Error opening uploaded file")
+			return
+		}
+		defer f.Close()
+
+		n, err := importer.ImportContactActivity(db, f)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Error importing last heard activity: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		RespondJSON(w, map[string]interface{}{
+			"message": fmt.Sprintf("Recorded activity for %d DMR IDs", n),
+			"count":   n,
 		})
 		return
 	}
@@ -745,7 +993,7 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		if err := importer.ImportFilterListToDB(database.DB, path, listName); err != nil {
+		if err := importer.ImportFilterListToDB(db, path, listName); err != nil {
 			http.Error(w, fmt.Sprintf("Error importing filter list: %v", err), http.StatusInternalServerError)
 			return
 		}
@@ -763,8 +1011,8 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 
 	overwrite := r.FormValue("overwrite") == "true"
 	if overwrite {
-		database.DB.Session(&gorm.Session{AllowGlobalUpdate: true}).Unscoped().Delete(&models.Channel{})
-		database.DB.Exec("DELETE FROM sqlite_sequence WHERE name = 'channels'")
+		db.Session(&gorm.Session{AllowGlobalUpdate: true}).Unscoped().Delete(&models.Channel{})
+		db.Exec("DELETE FROM sqlite_sequence WHERE name = 'channels'")
 	}
 
 	var channels []models.Channel
@@ -795,7 +1043,7 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	services.ResolveContacts(database.DB, channels)
+	services.ResolveContacts(db, channels)
 
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Error parsing CSV: %v", err), http.StatusBadRequest)
@@ -807,12 +1055,12 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 	for _, ch := range channels {
 		if !overwrite {
 			var existing models.Channel
-			if database.DB.Where("name = ? AND rx_frequency = ?", ch.Name, ch.RxFrequency).First(&existing).Error == nil {
+			if db.Where("name = ? AND rx_frequency = ?", ch.Name, ch.RxFrequency).First(&existing).Error == nil {
 				skipped++
 				continue
 			}
 		}
-		if result := database.DB.Create(&ch); result.Error == nil {
+		if result := db.Create(&ch); result.Error == nil {
 			count++
 		}
 	}
@@ -824,15 +1072,27 @@ func HandleImport(w http.ResponseWriter, r *http.Request) {
 }
 
 func HandleExport(w http.ResponseWriter, r *http.Request) {
+	db := dbFrom(r)
 	if r.Method != "GET" {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	format := r.URL.Query().Get("format")
-	radio := r.URL.Query().Get("radio")
+	q := r.URL.Query()
+	var allowedIDs map[int]bool
+	if name := q.Get("profile"); name != "" {
+		var status int
+		var err error
+		if allowedIDs, status, err = applyExportProfile(r, q, name); err != nil {
+			RespondError(w, status, err.Error())
+			return
+		}
+	}
+
+	format := q.Get("format")
+	radio := q.Get("radio")
 
-	zoneIDsStr := r.URL.Query()["zone_id"]
+	zoneIDsStr := q["zone_id"]
 	var zoneIDs []int
 
 	for _, idStr := range zoneIDsStr {
@@ -844,15 +1104,15 @@ func HandleExport(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	var filterListID uint
-	useList := r.URL.Query().Get("use_list")
-	if useList != "" {
-		var list models.ContactList
-		if err := database.DB.Where("name = ?", useList).First(&list).Error; err == nil {
-			filterListID = list.ID
-		} else {
-			fmt.Printf("Warning: Filter list '%s' not found.\n", useList)
+	// use_list accepts a list name or an expression over lists
+	var contactFilter *gorm.DB
+	if useList := q.Get("use_list"); useList != "" {
+		members, err := services.ContactListExprFilter(db, useList)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, fmt.Sprintf("Error evaluating filter list: %v", err))
+			return
 		}
+		contactFilter = members
 	}
 
 	if (format == "" || format == "zip") && radio != "" {
@@ -861,10 +1121,25 @@ func HandleExport(w http.ResponseWriter, r *http.Request) {
 
 	switch format {
 	case "db":
+		// A snapshot, since the live file may have changes still in its WAL
+		dir, err := os.MkdirTemp("", "codeplugs-export-")
+		if err != nil {
+			RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		defer os.RemoveAll(dir)
 		filename := "codeplugs.db"
+		if id := projectID(r); id != models.DefaultProject {
+			filename = id + ".db"
+		}
+		snapshot := filepath.Join(dir, filename)
+		if err := handleFrom(r).Backup(snapshot); err != nil {
+			RespondError(w, http.StatusInternalServerError, "Error copying database: "+err.Error())
+			return
+		}
 		w.Header().Set("Content-Type", "application/x-sqlite3")
 		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
-		http.ServeFile(w, r, filename)
+		http.ServeFile(w, r, snapshot)
 		return
 
 	case "dm32uv", "at890":
@@ -877,20 +1152,67 @@ func HandleExport(w http.ResponseWriter, r *http.Request) {
 		switch format {
 		case "dm32uv":
 			var channels []models.Channel
-			db := database.DB.Model(&models.Channel{}).Preload("Contact").Where("skip = ?", false)
+			cdb := db.Model(&models.Channel{}).Preload("Contact").Where("skip = ?", false)
 
 			if len(zoneIDs) > 0 {
-				db = db.Joins("JOIN zone_channels ON zone_channels.channel_id = channels.id").
+				cdb = cdb.Joins("JOIN zone_channels ON zone_channels.channel_id = channels.id").
 					Where("zone_channels.zone_id IN ?", zoneIDs)
 			}
 
-			db.Find(&channels)
+			cdb.Find(&channels)
+
+			// Buffered so the skipped channels header goes out before the zip
+			var channelsCSV bytes.Buffer
+			skipped, err := exporter.ExportDM32UVChannels(channels, &channelsCSV)
+			if err != nil {
+				RespondError(w, http.StatusInternalServerError, "Failed to export channels: "+err.Error())
+				return
+			}
+
+			var digitalContacts []models.DigitalContact
+			query := db.Model(&models.DigitalContact{}).Where("retired_at IS NULL")
+
+			if contactFilter != nil {
+				query = query.Where("dmr_id IN (?)", contactFilter)
+			}
+
+			query.Find(&digitalContacts)
+			if allowedIDs != nil {
+				filtered := digitalContacts[:0]
+				for _, c := range digitalContacts {
+					if allowedIDs[c.DMRID] {
+						filtered = append(filtered, c)
+					}
+				}
+				digitalContacts = filtered
+			}
+
+			// Over capacity: keep the most active/prioritized contacts and report
+			// the rest. Ranked before the zip starts, so a failure can still 500.
+			maxContacts := 50000 // DM32UV capacity
+			if l, err := strconv.Atoi(q.Get("limit")); err == nil && l > 0 {
+				maxContacts = l
+			}
+			digitalContacts, dropped, err := services.TruncateContacts(db, digitalContacts, services.ContactRankOptions{
+				Limit:             maxContacts,
+				PriorityStates:    splitQueryList(q["priority_state"]),
+				PriorityCountries: splitQueryList(q["priority_country"]),
+			})
+			if err != nil {
+				RespondError(w, http.StatusInternalServerError, fmt.Sprintf("Error ranking contacts: %v", err))
+				return
+			}
+			if err := models.ApplyContactOverrides(db, digitalContacts); err != nil {
+				RespondError(w, http.StatusInternalServerError, fmt.Sprintf("Error applying contact overrides: %v", err))
+				return
+			}
 
+			setSkippedHeader(w, skipped)
 			f, _ := zipWriter.Create("channels.csv")
-			exporter.ExportDM32UVChannels(channels, f)
+			f.Write(channelsCSV.Bytes())
 
 			var zones []models.Zone
-			zdb := database.DB.Preload("Channels")
+			zdb := db.Preload("Channels")
 			if len(zoneIDs) > 0 {
 				zdb = zdb.Where("id IN ?", zoneIDs)
 			}
@@ -900,23 +1222,18 @@ func HandleExport(w http.ResponseWriter, r *http.Request) {
 			exporter.ExportDM32UVZones(zones, f)
 
 			var talkgroups []models.Contact
-			database.DB.Where("type = ?", models.ContactTypeGroup).Find(&talkgroups)
+			db.Where("type = ?", models.ContactTypeGroup).Find(&talkgroups)
 			f, _ = zipWriter.Create("talkgroups.csv")
 			exporter.ExportDM32UVTalkgroups(talkgroups, f)
 
-			var digitalContacts []models.DigitalContact
-			query := database.DB.Model(&models.DigitalContact{})
-
-			if filterListID > 0 {
-				query = query.Where("dmr_id IN (?)", database.DB.Model(&models.ContactListEntry{}).Select("dmr_id").Where("contact_list_id = ?", filterListID))
-			} else {
-				query = query.Limit(50000)
-			}
-
-			query.Find(&digitalContacts)
 			f, _ = zipWriter.Create("digital_contacts.csv")
 			exporter.ExportDM32UVDigitalContacts(digitalContacts, f)
 
+			if len(dropped) > 0 {
+				f, _ = zipWriter.Create("dropped_contacts.csv")
+				services.WriteTruncationReport(dropped, f)
+			}
+
 		case "at890":
 			tempDir, err := os.MkdirTemp("", "at890_export_*")
 			if err != nil {
@@ -925,10 +1242,12 @@ func HandleExport(w http.ResponseWriter, r *http.Request) {
 			}
 			defer os.RemoveAll(tempDir)
 
-			if err := exporter.ExportAnyTone890(database.DB, tempDir, filterListID); err != nil {
+			skipped, err := exporter.ExportAnyTone890Filtered(db, tempDir, contactFilter)
+			if err != nil {
 				RespondError(w, http.StatusInternalServerError, "Failed to export 890")
 				return
 			}
+			setSkippedHeader(w, skipped)
 
 			err = filepath.Walk(tempDir, func(path string, info os.FileInfo, err error) error {
 				if err != nil {
@@ -952,16 +1271,31 @@ func HandleExport(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if format == "icom" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", "attachment; filename=\"icom_repeater_list.csv\"")
+
+		var repeaters []models.DStarRepeater
+		db.Where("type <> ?", models.DStarTypeReflector).Order("group_no asc, id asc").Find(&repeaters)
+		exporter.ExportIcomRepeaterList(repeaters, w)
+		return
+	}
+
 	w.Header().Set("Content-Type", "text/csv")
 
 	filename := "codeplug.csv"
-	if format == "chirp" {
+	switch format {
+	case "chirp":
 		filename = "chirp_export.csv"
+	case "p25":
+		filename = "p25_channels.csv"
+	case "icom_mycall":
+		filename = "icom_mycall.csv"
 	}
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
 
 	var channels []models.Channel
-	query := database.DB.Model(&models.Channel{}).Preload("Contact").Where("skip = ?", false)
+	query := db.Model(&models.Channel{}).Preload("Contact").Where("skip = ?", false)
 
 	if len(zoneIDs) > 0 {
 		query = query.Joins("JOIN zone_channels ON zone_channels.channel_id = channels.id").
@@ -970,18 +1304,113 @@ func HandleExport(w http.ResponseWriter, r *http.Request) {
 
 	query.Find(&channels)
 
-	if format == "chirp" {
-		exporter.ExportChirpCSV(channels, w)
-	} else {
+	switch format {
+	case "chirp":
+		var out bytes.Buffer
+		skipped, err := exporter.ExportChirpCSV(channels, &out)
+		if err != nil {
+			RespondError(w, http.StatusInternalServerError, "Failed to export CSV: "+err.Error())
+			return
+		}
+		setSkippedHeader(w, skipped)
+		w.Write(out.Bytes())
+	case "p25":
+		var talkgroups []models.P25Talkgroup
+		db.Find(&talkgroups)
+		exporter.ExportP25Channels(channels, talkgroups, w)
+	case "icom_mycall":
+		exporter.ExportIcomMyCallSigns(channels, w)
+	default:
 		exporter.ExportDB25D(channels, w, false)
 	}
 }
 
+// setSkippedHeader lists the P25 channels an export left out, since the
+// radio can't carry them
+func setSkippedHeader(w http.ResponseWriter, skipped []string) {
+	if len(skipped) > 0 {
+		w.Header().Set("X-Skipped-Channels", strings.Join(skipped, ", "))
+	}
+}
+
+// applyExportProfile fills the export parameters q doesn't set from the
+// server's named profile. It returns the DMR IDs the profile's filter_file
+// allows, nil without one, or the status to fail with. The file is only
+// ever named by a profile, never by a request.
+func applyExportProfile(r *http.Request, q url.Values, name string) (map[int]bool, int, error) {
+	var profile *config.Profile
+	for _, p := range serverFrom(r).Config.Profiles {
+		if p.Name == name {
+			profile = &p
+			break
+		}
+	}
+	if profile == nil {
+		return nil, http.StatusNotFound, fmt.Errorf("no export profile named %q", name)
+	}
+
+	if q.Get("format") == "" && q.Get("radio") == "" {
+		if profile.Radio == "db25d" {
+			q.Set("format", profile.Format)
+		} else {
+			q.Set("radio", profile.Radio)
+		}
+	}
+	if len(q["zone_id"]) == 0 {
+		for _, zoneName := range profile.Zones {
+			var zone models.Zone
+			if err := dbFrom(r).Where("name = ?", zoneName).First(&zone).Error; err != nil {
+				return nil, http.StatusBadRequest, fmt.Errorf("profile %s: zone not found: %s", name, zoneName)
+			}
+			q.Add("zone_id", strconv.Itoa(int(zone.ID)))
+		}
+	}
+	if q.Get("use_list") == "" && profile.UseList != "" {
+		q.Set("use_list", profile.UseList)
+	}
+	if q.Get("limit") == "" && profile.Limit > 0 {
+		q.Set("limit", strconv.Itoa(profile.Limit))
+	}
+	if len(q["priority_state"]) == 0 {
+		q["priority_state"] = profile.PriorityStates
+	}
+	if len(q["priority_country"]) == 0 {
+		q["priority_country"] = profile.PriorityCountries
+	}
+	if profile.FilterFile == "" {
+		return nil, 0, nil
+	}
+	allowedIDs, err := importer.LoadFilterList(profile.FilterFile)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("profile %s: loading filter list: %w", name, err)
+	}
+	return allowedIDs, 0, nil
+}
+
+// HandleExportProfiles lists the export profiles from codeplugs.yaml
+func HandleExportProfiles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	profiles := serverFrom(r).Config.Profiles
+	if profiles == nil {
+		profiles = []config.Profile{}
+	}
+	RespondJSON(w, profiles)
+}
+
 func HandleContacts(w http.ResponseWriter, r *http.Request) {
+	db := dbFrom(r)
 	switch r.Method {
 	case "GET":
 		source := r.URL.Query().Get("source")
 
+		if id := r.URL.Query().Get("id"); id != "" && source == "" {
+			getResource[models.Contact](w, r, "Contact", id)
+			return
+		}
+
 		if source == "RadioID" {
 			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
 			if page < 1 {
@@ -991,88 +1420,85 @@ func HandleContacts(w http.ResponseWriter, r *http.Request) {
 			if limit < 1 {
 				limit = 50
 			}
-			search := r.URL.Query().Get("search")
-			sort := r.URL.Query().Get("sort")
-			order := r.URL.Query().Get("order")
-
-			offset := (page - 1) * limit
 
-			var contacts []models.DigitalContact
-			var total int64
-
-			db := database.DB.Model(&models.DigitalContact{})
-
-			if search != "" {
-				term := "%" + search + "%"
-				db = db.Where("name LIKE ? OR callsign LIKE ? OR CAST(dmr_id AS TEXT) LIKE ?", term, term, term)
+			// search supports field-qualified and prefix terms, e.g. "state:MI call:KF8*"
+			result, err := services.SearchDigitalContacts(db, services.ContactSearch{
+				Query:  r.URL.Query().Get("search"),
+				Sort:   r.URL.Query().Get("sort"),
+				Order:  r.URL.Query().Get("order"),
+				Limit:  limit,
+				Page:   page,
+				Cursor: r.URL.Query().Get("cursor"),
+			})
+			if errors.Is(err, services.ErrInvalidSort) || errors.Is(err, services.ErrInvalidCursor) || errors.Is(err, services.ErrInvalidQuery) {
+				RespondError(w, http.StatusBadRequest, err.Error())
+				return
 			}
-
-			db.Count(&total)
-
-			if sort != "" {
-				if order != "desc" {
-					order = "asc"
-				}
-				db = db.Order(fmt.Sprintf("%s %s", sort, order))
-			} else {
-				db = db.Order("id asc")
+			if err != nil {
+				RespondError(w, http.StatusInternalServerError, err.Error())
+				return
 			}
 
-			db.Limit(limit).Offset(offset).Find(&contacts)
+			// Show the effective remarks and call alert the radios will get
+			if err := models.ApplyContactOverrides(db, result.Data); err != nil {
+				RespondError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
 
 			RespondJSON(w, map[string]interface{}{
-				"data": contacts,
+				"data": result.Data,
 				"meta": map[string]interface{}{
-					"total": total,
-					"page":  page,
-					"limit": limit,
+					"total":       result.Total,
+					"page":        page,
+					"limit":       limit,
+					"next_cursor": result.NextCursor,
 				},
 			})
 			return
 		}
 
 		var contacts []models.Contact
-		database.DB.Find(&contacts)
+		db.Find(&contacts)
 
 		RespondJSON(w, map[string]interface{}{
 			"data": contacts,
 		})
 
-	case "POST":
-		var c models.Contact
-		if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
-			RespondError(w, http.StatusBadRequest, err.Error())
+	case "POST", "PUT", "PATCH":
+		c, err := writeResource[models.Contact](w, r, "Contact")
+		if err != nil {
 			return
 		}
-		if c.ID == 0 {
-			database.DB.Create(&c)
-		} else {
-			database.DB.Save(&c)
-		}
 		RespondJSON(w, c)
+		BroadcastChange(r, changeEvent("contact", c.Version), c.ID, c.Version)
 	case "DELETE":
-		id := r.URL.Query().Get("id")
-		if id != "" {
+		c, err := deleteResource[models.Contact](w, r, "Contact", func(id string) string {
 			var count int64
-			database.DB.Model(&models.Channel{}).Where("contact_id = ?", id).Count(&count)
+			db.Model(&models.Channel{}).Where("contact_id = ?", id).Count(&count)
 			if count > 0 {
-				RespondError(w, http.StatusConflict, "Contact is in use by channels")
-				return
+				return "Contact is in use by channels"
 			}
-			database.DB.Delete(&models.Contact{}, id)
-			RespondJSON(w, nil)
+			return ""
+		})
+		if err != nil {
+			return
 		}
+		RespondJSON(w, nil)
+		BroadcastChange(r, "contact.deleted", c.ID, 0)
+	default:
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 	}
 }
 
 func HandleZones(w http.ResponseWriter, r *http.Request) {
+	db := dbFrom(r)
 	switch r.Method {
 	case "GET":
 		id := r.URL.Query().Get("id")
 		if id != "" {
 			var zone models.Zone
 			// Preload via ZoneChannels to guarantee order
-			if err := database.DB.Preload("ZoneChannels", func(db *gorm.DB) *gorm.DB {
+			if err := db.Preload("ZoneChannels", func(db *gorm.DB) *gorm.DB {
 				return db.Order("sort_order ASC")
 			}).Preload("ZoneChannels.Channel").First(&zone, id).Error; err != nil {
 				RespondError(w, http.StatusNotFound, "Zone not found")
@@ -1084,12 +1510,13 @@ func HandleZones(w http.ResponseWriter, r *http.Request) {
 			for i, zc := range zone.ZoneChannels {
 				zone.Channels[i] = zc.Channel
 			}
+			setETag(w, &zone)
 			RespondJSON(w, zone)
 			return
 		}
 
 		var zones []models.Zone
-		database.DB.Preload("ZoneChannels", func(db *gorm.DB) *gorm.DB {
+		db.Preload("ZoneChannels", func(db *gorm.DB) *gorm.DB {
 			return db.Order("sort_order ASC")
 		}).Preload("ZoneChannels.Channel").Find(&zones)
 
@@ -1108,25 +1535,41 @@ func HandleZones(w http.ResponseWriter, r *http.Request) {
 		}
 
 		if z.ID == 0 {
-			database.DB.Create(&z)
+			db.Create(&z)
 		} else {
-			if err := database.DB.Model(&z).Where("id = ?", z.ID).Update("name", z.Name).Error; err != nil {
+			zone, err := loadVersioned[models.Zone](w, r, "Zone", z.ID, z.Version)
+			if err != nil {
+				return
+			}
+			ok, err := updateVersioned(db, zone, map[string]interface{}{"name": z.Name})
+			if err != nil {
 				RespondError(w, http.StatusInternalServerError, err.Error())
 				return
 			}
+			db.First(&z, z.ID)
+			if !ok {
+				respondPreconditionFailed(w, &z)
+				return
+			}
 		}
+		setETag(w, &z)
 		RespondJSON(w, z)
+		BroadcastChange(r, changeEvent("zone", z.Version), z.ID, z.Version)
 	case "DELETE":
-		id := r.URL.Query().Get("id")
-		if id != "" {
-			database.DB.Exec("DELETE FROM zone_channels WHERE zone_id = ?", id)
-			database.DB.Delete(&models.Zone{}, id)
-			RespondJSON(w, nil)
+		z, err := deleteResource[models.Zone](w, r, "Zone", nil)
+		if err != nil {
+			return
 		}
+		db.Exec("DELETE FROM zone_channels WHERE zone_id = ?", z.ID)
+		RespondJSON(w, nil)
+		BroadcastChange(r, "zone.deleted", z.ID, 0)
+	default:
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 	}
 }
 
 func HandleZoneAssignment(w http.ResponseWriter, r *http.Request) {
+	db := dbFrom(r)
 	if r.Method != "POST" {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
@@ -1145,8 +1588,13 @@ func HandleZoneAssignment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	zone, err := loadVersioned[models.Zone](w, r, "Zone", zoneID, 0)
+	if err != nil {
+		return
+	}
+
 	// Manual transaction to update zone_channels with order
-	tx := database.DB.Begin()
+	tx := db.Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
@@ -1178,19 +1626,45 @@ func HandleZoneAssignment(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Membership and order are part of the zone's version
+	if ok, err := updateVersioned(tx, zone, nil); err != nil || !ok {
+		tx.Rollback()
+		if err != nil {
+			RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		db.First(zone, zoneID)
+		respondPreconditionFailed(w, zone)
+		return
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		RespondError(w, http.StatusInternalServerError, "Commit failed")
 		return
 	}
 
+	db.First(zone, zoneID)
+	setETag(w, zone)
 	RespondJSON(w, nil)
+	BroadcastChange(r, "zone.reordered", zone.ID, zone.Version)
 }
 
 func HandleScanLists(w http.ResponseWriter, r *http.Request) {
+	db := dbFrom(r)
 	switch r.Method {
 	case "GET":
+		if id := r.URL.Query().Get("id"); id != "" {
+			var list models.ScanList
+			if err := db.Preload("Channels").First(&list, id).Error; err != nil {
+				RespondError(w, http.StatusNotFound, "Scan List not found")
+				return
+			}
+			setETag(w, &list)
+			RespondJSON(w, list)
+			return
+		}
 		var lists []models.ScanList
-		database.DB.Preload("Channels").Find(&lists)
+		db.Preload("Channels").Find(&lists)
 		RespondJSON(w, lists)
 	case "POST":
 		var list models.ScanList
@@ -1199,72 +1673,137 @@ func HandleScanLists(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		if list.ID == 0 {
-			database.DB.Create(&list)
+			db.Create(&list)
 		} else {
-			database.DB.Model(&list).Update("name", list.Name)
+			current, err := loadVersioned[models.ScanList](w, r, "Scan List", list.ID, list.Version)
+			if err != nil {
+				return
+			}
+			ok, err := updateVersioned(db, current, map[string]interface{}{"name": list.Name})
+			if err != nil {
+				RespondError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			db.First(&list, list.ID)
+			if !ok {
+				respondPreconditionFailed(w, &list)
+				return
+			}
 		}
+		setETag(w, &list)
 		RespondJSON(w, list)
+		BroadcastChange(r, changeEvent("scanlist", list.Version), list.ID, list.Version)
 	case "DELETE":
-		id := r.URL.Query().Get("id")
-		if id != "" {
-			database.DB.Exec("DELETE FROM scan_list_channels WHERE scan_list_id = ?", id)
-			database.DB.Delete(&models.ScanList{}, id)
-			RespondJSON(w, nil)
+		list, err := deleteResource[models.ScanList](w, r, "Scan List", nil)
+		if err != nil {
+			return
 		}
+		db.Exec("DELETE FROM scan_list_channels WHERE scan_list_id = ?", list.ID)
+		RespondJSON(w, nil)
+		BroadcastChange(r, "scanlist.deleted", list.ID, 0)
+	default:
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 	}
 }
 
+// scanListAssignRequest replaces a scan list's channels
+type scanListAssignRequest struct {
+	ScanListID int   `json:"scan_list_id"`
+	ChannelIDs []int `json:"channel_ids"`
+}
+
 func HandleScanListAssignment(w http.ResponseWriter, r *http.Request) {
+	db := dbFrom(r)
 	if r.Method != "POST" {
 		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
-	var req struct {
-		ScanListID int   `json:"scan_list_id"`
-		ChannelIDs []int `json:"channel_ids"`
-	}
+	var req scanListAssignRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		RespondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	var list models.ScanList
-	if err := database.DB.First(&list, req.ScanListID).Error; err != nil {
-		RespondError(w, http.StatusNotFound, "Scan List not found")
+	list, err := loadVersioned[models.ScanList](w, r, "Scan List", req.ScanListID, 0)
+	if err != nil {
 		return
 	}
 
 	var channels []models.Channel
-	database.DB.Find(&channels, req.ChannelIDs)
+	db.Find(&channels, req.ChannelIDs)
 
-	if err := database.DB.Model(&list).Association("Channels").Replace(&channels); err != nil {
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(list).Association("Channels").Replace(&channels); err != nil {
+			return err
+		}
+		ok, err := updateVersioned(tx, list, nil)
+		if err == nil && !ok {
+			err = errVersionConflict
+		}
+		return err
+	})
+	db.First(list, req.ScanListID)
+	if err == errVersionConflict {
+		respondPreconditionFailed(w, list)
+		return
+	}
+	if err != nil {
 		RespondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	setETag(w, list)
 	RespondJSON(w, nil)
+	BroadcastChange(r, "scanlist.reordered", list.ID, list.Version)
+}
+
+// filterListRequest creates a filter list, or updates one when ID is set,
+// replacing its rules
+type filterListRequest struct {
+	ID          uint                     `json:"ID"`
+	Version     int                      `json:"version"`
+	Name        string                   `json:"Name"`
+	Description string                   `json:"Description"`
+	Rules       []models.ContactListRule `json:"Rules"`
 }
 
 func HandleFilterLists(w http.ResponseWriter, r *http.Request) {
+	db := dbFrom(r)
 	if r.Method == "GET" {
 		id := r.URL.Query().Get("id")
 
 		if id != "" {
 			var list models.ContactList
-			if err := database.DB.First(&list, id).Error; err != nil {
+			if err := db.First(&list, id).Error; err != nil {
 				RespondError(w, http.StatusNotFound, "List not found")
 				return
 			}
+			setETag(w, &list)
 
 			if r.URL.Query().Get("mode") == "ids" {
-				var ids []int
-				database.DB.Model(&models.ContactListEntry{}).Where("contact_list_id = ?", list.ID).Pluck("dmr_id", &ids)
+				resolved, err := models.ResolveContactList(db, list.ID)
+				if err != nil {
+					RespondError(w, http.StatusBadRequest, err.Error())
+					return
+				}
+				ids := make([]int, 0, len(resolved))
+				for id := range resolved {
+					ids = append(ids, id)
+				}
+				sort.Ints(ids)
 				RespondJSON(w, ids)
 				return
 			}
 
+			if r.URL.Query().Get("mode") == "rules" {
+				var rules []models.ContactListRule
+				db.Where("contact_list_id = ?", list.ID).Order("rule_group, id").Find(&rules)
+				RespondJSON(w, rules)
+				return
+			}
+
 			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
 			if page < 1 {
 				page = 1
@@ -1276,7 +1815,7 @@ func HandleFilterLists(w http.ResponseWriter, r *http.Request) {
 			offset := (page - 1) * limit
 			search := r.URL.Query().Get("search")
 
-			query := database.DB.Model(&models.ContactListEntry{}).Where("contact_list_id = ?", list.ID)
+			query := db.Model(&models.ContactListEntry{}).Where("contact_list_id = ?", list.ID)
 
 			if search != "" {
 				query = query.Where("CAST(dmr_id AS TEXT) LIKE ?", "%"+search+"%")
@@ -1300,15 +1839,133 @@ func HandleFilterLists(w http.ResponseWriter, r *http.Request) {
 		}
 
 		var lists []models.ContactList
-		database.DB.Find(&lists)
+		db.Preload("Rules").Find(&lists)
+		RespondJSON(w, lists)
+		return
+	}
+
+	switch r.Method {
+	case "POST":
+		var req filterListRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if req.Name == "" {
+			RespondError(w, http.StatusBadRequest, "Name is required")
+			return
+		}
+		for i := range req.Rules {
+			if err := req.Rules[i].Validate(); err != nil {
+				RespondError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		list := &models.ContactList{Name: req.Name, Description: req.Description}
+		var current *models.ContactList
+		if req.ID != 0 {
+			var err error
+			if current, err = loadVersioned[models.ContactList](w, r, "List", req.ID, req.Version); err != nil {
+				return
+			}
+			list = current
+		}
+
+		// The list is only saved if its rules resolve
+		status := http.StatusConflict
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if current != nil {
+				ok, err := updateVersioned(tx, current, map[string]interface{}{"name": req.Name, "description": req.Description})
+				if err == nil && !ok {
+					err = errVersionConflict
+				}
+				if err != nil {
+					return err
+				}
+			} else if err := tx.Create(list).Error; err != nil {
+				return err
+			}
+			status = http.StatusBadRequest
+			if err := models.SetContactListRules(tx, list.ID, req.Rules); err != nil {
+				return err
+			}
+			// Catch rules that only fail once evaluated, e.g. list references
+			_, err := models.ResolveContactList(tx, list.ID)
+			return err
+		})
+		if err == errVersionConflict {
+			db.First(current, req.ID)
+			respondPreconditionFailed(w, current)
+			return
+		}
+		if err != nil {
+			RespondError(w, status, err.Error())
+			return
+		}
+		db.Preload("Rules").First(list, list.ID)
+		setETag(w, list)
+		RespondJSON(w, list)
+		BroadcastChange(r, changeEvent("filter_list", list.Version), list.ID, list.Version)
+	case "DELETE":
+		id := r.URL.Query().Get("id")
+		if id == "" {
+			RespondError(w, http.StatusBadRequest, "id is required")
+			return
+		}
+		list, err := loadVersioned[models.ContactList](w, r, "List", id, 0)
+		if err != nil {
+			return
+		}
+		err = db.Transaction(func(tx *gorm.DB) error {
+			for _, m := range []interface{}{&models.ContactListEntry{}, &models.ContactListRule{}} {
+				if err := tx.Unscoped().Where("contact_list_id = ?", id).Delete(m).Error; err != nil {
+					return err
+				}
+			}
+			return tx.Unscoped().Delete(&models.ContactList{}, id).Error
+		})
+		if err != nil {
+			RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		RespondJSON(w, nil)
+		BroadcastChange(r, "filter_list.deleted", list.ID, 0)
+	default:
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleFilterListOps computes a list from set operations over existing
+// lists. Without "into" it only reports the counts.
+func HandleFilterListOps(w http.ResponseWriter, r *http.Request) {
+	db := dbFrom(r)
+	if r.Method != "POST" {
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	var op services.ListOperation
+	if err := json.NewDecoder(r.Body).Decode(&op); err != nil {
+		RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	result, err := services.RunListOperation(db, op)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	RespondJSON(w, result)
+	if result.List != nil {
+		BroadcastChange(r, changeEvent("filter_list", result.List.Version), result.List.ID, result.List.Version)
 	}
 }
 
 func HandleRoamingChannels(w http.ResponseWriter, r *http.Request) {
+	db := dbFrom(r)
 	switch r.Method {
 	case "GET":
 		var channels []models.RoamingChannel
-		database.DB.Find(&channels)
+		db.Find(&channels)
 		RespondJSON(w, channels)
 	case "POST":
 		var rc models.RoamingChannel
@@ -1316,28 +1973,35 @@ func HandleRoamingChannels(w http.ResponseWriter, r *http.Request) {
 			RespondError(w, http.StatusBadRequest, err.Error())
 			return
 		}
+		event := "roaming_channel.updated"
 		if rc.ID == 0 {
-			database.DB.Create(&rc)
+			event = "roaming_channel.created"
+			db.Create(&rc)
 		} else {
-			database.DB.Save(&rc)
+			db.Save(&rc)
 		}
 		RespondJSON(w, rc)
+		BroadcastChange(r, event, rc.ID, 0)
 	case "DELETE":
 		id := r.URL.Query().Get("id")
 		if id != "" {
-			database.DB.Delete(&models.RoamingChannel{}, id)
+			db.Delete(&models.RoamingChannel{}, id)
 			RespondJSON(w, nil)
+			broadcastDeleted(r, "roaming_channel", id)
 		}
+	default:
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 	}
 }
 
 func HandleRoamingZones(w http.ResponseWriter, r *http.Request) {
+	db := dbFrom(r)
 	switch r.Method {
 	case "GET":
 		id := r.URL.Query().Get("id")
 		if id != "" {
 			var zone models.RoamingZone
-			if err := database.DB.Preload("Channels").First(&zone, id).Error; err != nil {
+			if err := db.Preload("Channels").First(&zone, id).Error; err != nil {
 				RespondError(w, http.StatusNotFound, "Roaming Zone not found")
 				return
 			}
@@ -1346,7 +2010,7 @@ func HandleRoamingZones(w http.ResponseWriter, r *http.Request) {
 		}
 
 		var zones []models.RoamingZone
-		database.DB.Preload("Channels").Find(&zones)
+		db.Preload("Channels").Find(&zones)
 		RespondJSON(w, zones)
 
 	case "POST":
@@ -1356,30 +2020,37 @@ func HandleRoamingZones(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		event := "roaming_zone.updated"
 		if z.ID == 0 {
-			database.DB.Create(&z)
+			event = "roaming_zone.created"
+			db.Create(&z)
 		} else {
-			if err := database.DB.Model(&z).Where("id = ?", z.ID).Update("name", z.Name).Error; err != nil {
+			if err := db.Model(&z).Where("id = ?", z.ID).Update("name", z.Name).Error; err != nil {
 				RespondError(w, http.StatusInternalServerError, err.Error())
 				return
 			}
 		}
 		RespondJSON(w, z)
+		BroadcastChange(r, event, z.ID, 0)
 
 	case "DELETE":
 		id := r.URL.Query().Get("id")
 		if id != "" {
 			var zone models.RoamingZone
-			if err := database.DB.First(&zone, id).Error; err == nil {
-				database.DB.Model(&zone).Association("Channels").Clear()
+			if err := db.First(&zone, id).Error; err == nil {
+				db.Model(&zone).Association("Channels").Clear()
 			}
-			database.DB.Delete(&models.RoamingZone{}, id)
+			db.Delete(&models.RoamingZone{}, id)
 			RespondJSON(w, nil)
+			broadcastDeleted(r, "roaming_zone", id)
 		}
+	default:
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 	}
 }
 
 func HandleRoamingZoneAssignment(w http.ResponseWriter, r *http.Request) {
+	db := dbFrom(r)
 	if r.Method != "POST" {
 		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
@@ -1398,20 +2069,515 @@ func HandleRoamingZoneAssignment(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var zone models.RoamingZone
-	if err := database.DB.First(&zone, id).Error; err != nil {
+	if err := db.First(&zone, id).Error; err != nil {
 		RespondError(w, http.StatusNotFound, "Roaming Zone not found")
 		return
 	}
 
 	var channels []models.RoamingChannel
 	if len(channelIDs) > 0 {
-		database.DB.Find(&channels, channelIDs)
+		db.Find(&channels, channelIDs)
 	}
 
-	if err := database.DB.Model(&zone).Association("Channels").Replace(&channels); err != nil {
+	if err := db.Model(&zone).Association("Channels").Replace(&channels); err != nil {
 		RespondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	RespondJSON(w, nil)
+	BroadcastChange(r, "roaming_zone.reordered", zone.ID, 0)
+}
+
+func HandleNXDNTalkgroups(w http.ResponseWriter, r *http.Request) {
+	db := dbFrom(r)
+	switch r.Method {
+	case "GET":
+		var talkgroups []models.NXDNTalkgroup
+		db.Order("tg_id asc").Find(&talkgroups)
+		RespondJSON(w, talkgroups)
+	case "POST":
+		var tg models.NXDNTalkgroup
+		if err := json.NewDecoder(r.Body).Decode(&tg); err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := tg.Validate(); err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		var err error
+		event := "nxdn_talkgroup.updated"
+		if tg.ID == 0 {
+			event = "nxdn_talkgroup.created"
+			err = db.Create(&tg).Error
+		} else {
+			err = db.Save(&tg).Error
+		}
+		if err != nil {
+			RespondError(w, http.StatusConflict, err.Error())
+			return
+		}
+		RespondJSON(w, tg)
+		BroadcastChange(r, event, tg.ID, 0)
+	case "DELETE":
+		id := r.URL.Query().Get("id")
+		if id != "" {
+			db.Delete(&models.NXDNTalkgroup{}, id)
+			RespondJSON(w, nil)
+			broadcastDeleted(r, "nxdn_talkgroup", id)
+		}
+	default:
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func HandleNXDNContacts(w http.ResponseWriter, r *http.Request) {
+	db := dbFrom(r)
+	switch r.Method {
+	case "GET":
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page < 1 {
+			page = 1
+		}
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		if limit < 1 {
+			limit = 50
+		}
+		search := r.URL.Query().Get("search")
+
+		var contacts []models.NXDNContact
+		var total int64
+
+		query := db.Model(&models.NXDNContact{})
+		if search != "" {
+			term := "%" + search + "%"
+			query = query.Where("name LIKE ? OR callsign LIKE ? OR CAST(unit_id AS TEXT) LIKE ?", term, term, term)
+		}
+		query.Count(&total)
+		query.Order("unit_id asc").Limit(limit).Offset((page - 1) * limit).Find(&contacts)
+
+		RespondJSON(w, map[string]interface{}{
+			"data": contacts,
+			"meta": map[string]interface{}{
+				"total": total,
+				"page":  page,
+				"limit": limit,
+			},
+		})
+	case "POST":
+		var c models.NXDNContact
+		if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := c.Validate(); err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		var err error
+		event := "nxdn_contact.updated"
+		if c.ID == 0 {
+			event = "nxdn_contact.created"
+			err = db.Create(&c).Error
+		} else {
+			err = db.Save(&c).Error
+		}
+		if err != nil {
+			RespondError(w, http.StatusConflict, err.Error())
+			return
+		}
+		RespondJSON(w, c)
+		BroadcastChange(r, event, c.ID, 0)
+	case "DELETE":
+		id := r.URL.Query().Get("id")
+		if id != "" {
+			db.Delete(&models.NXDNContact{}, id)
+			RespondJSON(w, nil)
+			broadcastDeleted(r, "nxdn_contact", id)
+		}
+	default:
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func HandleDStarRepeaters(w http.ResponseWriter, r *http.Request) {
+	db := dbFrom(r)
+	switch r.Method {
+	case "GET":
+		var repeaters []models.DStarRepeater
+		query := db.Order("group_no asc, name asc")
+		if t := r.URL.Query().Get("type"); t != "" {
+			query = query.Where("type = ?", t)
+		}
+		query.Find(&repeaters)
+		RespondJSON(w, repeaters)
+	case "POST":
+		var rpt models.DStarRepeater
+		if err := json.NewDecoder(r.Body).Decode(&rpt); err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if rpt.Type == "" {
+			rpt.Type = models.DStarTypeRepeater
+		}
+		if err := rpt.Validate(); err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		event := "dstar_repeater.updated"
+		if rpt.ID == 0 {
+			event = "dstar_repeater.created"
+			db.Create(&rpt)
+		} else {
+			db.Save(&rpt)
+		}
+		RespondJSON(w, rpt)
+		BroadcastChange(r, event, rpt.ID, 0)
+	case "DELETE":
+		id := r.URL.Query().Get("id")
+		if id != "" {
+			db.Delete(&models.DStarRepeater{}, id)
+			RespondJSON(w, nil)
+			broadcastDeleted(r, "dstar_repeater", id)
+		}
+	default:
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func HandleP25Talkgroups(w http.ResponseWriter, r *http.Request) {
+	db := dbFrom(r)
+	switch r.Method {
+	case "GET":
+		var talkgroups []models.P25Talkgroup
+		db.Order("tg_id asc").Find(&talkgroups)
+		RespondJSON(w, talkgroups)
+	case "POST":
+		var tg models.P25Talkgroup
+		if err := json.NewDecoder(r.Body).Decode(&tg); err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := tg.Validate(); err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		var err error
+		event := "p25_talkgroup.updated"
+		if tg.ID == 0 {
+			event = "p25_talkgroup.created"
+			err = db.Create(&tg).Error
+		} else {
+			err = db.Save(&tg).Error
+		}
+		if err != nil {
+			RespondError(w, http.StatusConflict, err.Error())
+			return
+		}
+		RespondJSON(w, tg)
+		BroadcastChange(r, event, tg.ID, 0)
+	case "DELETE":
+		id := r.URL.Query().Get("id")
+		if id != "" {
+			db.Delete(&models.P25Talkgroup{}, id)
+			RespondJSON(w, nil)
+			broadcastDeleted(r, "p25_talkgroup", id)
+		}
+	default:
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func HandleContactSyncs(w http.ResponseWriter, r *http.Request) {
+	db := dbFrom(r)
+	if r.Method != "GET" {
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		var syncs []models.ContactSync
+		db.Order("id desc").Find(&syncs)
+		RespondJSON(w, syncs)
+		return
+	}
+
+	var sync models.ContactSync
+	if err := db.First(&sync, id).Error; err != nil {
+		RespondError(w, http.StatusNotFound, "Sync not found")
+		return
+	}
+
+	// Change log can be narrowed by callsign and action
+	query := db.Where("contact_sync_id = ?", sync.ID)
+	if callsign := r.URL.Query().Get("callsign"); callsign != "" {
+		query = query.Where("callsign = ?", strings.ToUpper(callsign))
+	}
+	if action := r.URL.Query().Get("action"); action != "" {
+		query = query.Where("action = ?", action)
+	}
+	query.Order("callsign asc").Find(&sync.Changes)
+
+	RespondJSON(w, sync)
+}
+
+// splitQueryList flattens repeated and comma-separated query values
+func splitQueryList(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
+
+func HandleContactPins(w http.ResponseWriter, r *http.Request) {
+	db := dbFrom(r)
+	switch r.Method {
+	case "GET":
+		var pins []models.ContactPin
+		db.Order("dmr_id asc").Find(&pins)
+		RespondJSON(w, pins)
+	case "POST":
+		var pin models.ContactPin
+		if err := json.NewDecoder(r.Body).Decode(&pin); err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if pin.DMRID <= 0 {
+			RespondError(w, http.StatusBadRequest, "dmr_id is required")
+			return
+		}
+		db.Where(models.ContactPin{DMRID: pin.DMRID}).Assign(models.ContactPin{Note: pin.Note}).FirstOrCreate(&pin)
+		RespondJSON(w, pin)
+		BroadcastEvent(r, "contact_pin.updated", map[string]int{"dmr_id": pin.DMRID})
+	case "DELETE":
+		dmrID := r.URL.Query().Get("dmr_id")
+		if dmrID != "" {
+			db.Unscoped().Where("dmr_id = ?", dmrID).Delete(&models.ContactPin{})
+			RespondJSON(w, nil)
+			broadcastDMRIDDeleted(r, "contact_pin", dmrID)
+		}
+	default:
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleContactOverrides manages per-contact call alert and remarks
+// overrides, keyed by DMR ID. They are applied to RadioID contacts on export
+// and kept across re-syncs. POST replaces both fields; a null remarks falls
+// back to the RadioID remarks.
+func HandleContactOverrides(w http.ResponseWriter, r *http.Request) {
+	db := dbFrom(r)
+	switch r.Method {
+	case "GET":
+		var overrides []models.ContactOverride
+		q := db.Order("dmr_id asc")
+		if dmrID := r.URL.Query().Get("dmr_id"); dmrID != "" {
+			q = q.Where("dmr_id = ?", dmrID)
+		}
+		q.Find(&overrides)
+		RespondJSON(w, overrides)
+	case "POST":
+		var o models.ContactOverride
+		if err := json.NewDecoder(r.Body).Decode(&o); err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := o.Validate(); err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := db.Where(models.ContactOverride{DMRID: o.DMRID}).
+			Assign(map[string]interface{}{"call_alert": o.CallAlert, "remarks": o.Remarks}).
+			FirstOrCreate(&o).Error; err != nil {
+			RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		RespondJSON(w, o)
+		BroadcastEvent(r, "contact_override.updated", map[string]int{"dmr_id": o.DMRID})
+	case "DELETE":
+		dmrID := r.URL.Query().Get("dmr_id")
+		if dmrID == "" {
+			RespondError(w, http.StatusBadRequest, "dmr_id is required")
+			return
+		}
+		db.Unscoped().Where("dmr_id = ?", dmrID).Delete(&models.ContactOverride{})
+		RespondJSON(w, nil)
+		broadcastDMRIDDeleted(r, "contact_override", dmrID)
+	default:
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// HandleTalkgroupCatalog lists catalog talkgroups, filtered by network and
+// name or ID search.
+func HandleTalkgroupCatalog(w http.ResponseWriter, r *http.Request) {
+	db := dbFrom(r)
+	if r.Method != "GET" {
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 {
+		limit = 100
+	}
+
+	query := db.Model(&models.TalkgroupCatalog{})
+	if network := r.URL.Query().Get("network"); network != "" {
+		query = query.Where("network = ?", network)
+	}
+	if search := r.URL.Query().Get("search"); search != "" {
+		query = query.Where("name LIKE ? OR CAST(tg_id AS TEXT) LIKE ?", "%"+search+"%", search+"%")
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var talkgroups []models.TalkgroupCatalog
+	query.Order("tg_id asc, network asc").Limit(limit).Offset((page - 1) * limit).Find(&talkgroups)
+
+	RespondJSON(w, map[string]interface{}{
+		"data": talkgroups,
+		"meta": map[string]interface{}{
+			"total": total,
+			"page":  page,
+			"limit": limit,
+		},
+	})
+}
+
+// HandleResolvePlaceholders matches negative-ID placeholder contacts against
+// the talkgroup catalog, optionally limited to ?network=a,b.
+func HandleResolvePlaceholders(w http.ResponseWriter, r *http.Request) {
+	db := dbFrom(r)
+	if r.Method != "POST" {
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	results, err := services.ResolvePlaceholderContacts(db, splitQueryList(r.URL.Query()["network"]))
+	if err != nil {
+		RespondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	RespondJSON(w, results)
+	var changed []uint
+	for _, res := range results {
+		if res.Status == services.PlaceholderResolved || res.Status == services.PlaceholderMerged {
+			changed = append(changed, res.ContactID)
+		}
+	}
+	if len(changed) > 0 {
+		BroadcastEvent(r, EventContactBulkUpdated, ChangeEvent{IDs: changed, Count: int64(len(changed))})
+	}
+}
+
+// HandleUnresolvedContacts reports placeholder contacts with the channels
+// using them and suggested real talkgroups (?suggestions=N, default 5).
+func HandleUnresolvedContacts(w http.ResponseWriter, r *http.Request) {
+	db := dbFrom(r)
+	if r.Method != "GET" {
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	maxSuggestions := 5
+	if n, err := strconv.Atoi(r.URL.Query().Get("suggestions")); err == nil && n > 0 {
+		maxSuggestions = n
+	}
+	report, err := services.UnresolvedContacts(db, maxSuggestions)
+	if err != nil {
+		RespondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	RespondJSON(w, report)
+}
+
+// HandleMergeContact merges a placeholder contact into an existing contact or
+// talkgroup ID, repointing its channels and deleting the placeholder.
+func HandleMergeContact(w http.ResponseWriter, r *http.Request) {
+	db := dbFrom(r)
+	if r.Method != "POST" {
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	var req services.MergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	result, err := services.MergePlaceholder(db, req)
+	if err == gorm.ErrRecordNotFound {
+		RespondError(w, http.StatusNotFound, "Contact not found")
+		return
+	}
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	RespondJSON(w, result)
+	BroadcastEvent(r, EventContactMerged, ChangeEvent{IDs: []uint{req.PlaceholderID, result.Contact.ID}})
+}
+
+// HandleContactDuplicates lists duplicate contact groups with their proposed
+// canonical record (GET), or merges duplicates (POST). POST takes
+// {canonical_id, duplicate_ids}, or ?all=true to merge every group.
+func HandleContactDuplicates(w http.ResponseWriter, r *http.Request) {
+	db := dbFrom(r)
+	switch r.Method {
+	case "GET":
+		groups, err := services.FindDuplicateContacts(db)
+		if err != nil {
+			RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if groups == nil {
+			groups = []services.DuplicateGroup{}
+		}
+		RespondJSON(w, groups)
+	case "POST":
+		if r.URL.Query().Get("all") == "true" {
+			groups, channels, err := services.DedupeAllContacts(db)
+			if err != nil {
+				RespondError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			RespondJSON(w, map[string]interface{}{
+				"merged":           groups,
+				"channels_updated": channels,
+			})
+			if len(groups) > 0 {
+				BroadcastEvent(r, EventContactMerged, ChangeEvent{Count: int64(len(groups))})
+			}
+			return
+		}
+
+		var req services.DedupeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		channels, err := services.MergeDuplicateContacts(db, req)
+		if err == gorm.ErrRecordNotFound {
+			RespondError(w, http.StatusNotFound, "Contact not found")
+			return
+		}
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		RespondJSON(w, map[string]interface{}{
+			"channels_updated": channels,
+		})
+		BroadcastEvent(r, EventContactMerged, ChangeEvent{IDs: append([]uint{req.CanonicalID}, req.DuplicateIDs...)})
+	default:
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
 }
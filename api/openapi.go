@@ -0,0 +1,430 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// object is a JSON object in the OpenAPI document
+type object = map[string]interface{}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+	rawType       = reflect.TypeOf(json.RawMessage{})
+)
+
+var (
+	specOnce sync.Once
+	specJSON []byte
+)
+
+// OpenAPISpec returns the OpenAPI 3 document for the routes table
+func OpenAPISpec() []byte {
+	specOnce.Do(func() {
+		specJSON, _ = json.MarshalIndent(newSpecBuilder().build(), "", "  ")
+	})
+	return specJSON
+}
+
+// HandleOpenAPI serves the OpenAPI document as is, without the JSONResponse
+// envelope
+func HandleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(OpenAPISpec())
+}
+
+// specBuilder derives schemas from Go types by reflection, collecting named
+// structs as components
+type specBuilder struct {
+	schemas object
+	names   map[string]reflect.Type
+}
+
+func newSpecBuilder() *specBuilder {
+	return &specBuilder{schemas: object{}, names: map[string]reflect.Type{}}
+}
+
+func (b *specBuilder) build() object {
+	paths := object{}
+	var tags []interface{}
+	seenTags := map[string]bool{}
+	for _, route := range routes {
+		tag := routeTag(route.Path)
+		if !seenTags[tag] {
+			seenTags[tag] = true
+			tags = append(tags, object{"name": tag})
+		}
+		item := object{}
+		for _, method := range routeMethods(route) {
+			var ops []apiOp
+			for _, op := range route.Ops {
+				if op.Method == method {
+					ops = append(ops, op)
+				}
+			}
+			op := b.operation(tag, ops)
+			if route.Public {
+				op["security"] = []interface{}{}
+			} else {
+				role, _ := route.role(method, nil)
+				op["x-role"] = role
+				responses := op["responses"].(object)
+				responses["401"] = b.errorResponse("Accounts exist and no valid session or API token was sent")
+				responses["403"] = b.errorResponse("The account's role is below " + string(role))
+			}
+			if !route.Global {
+				op["x-project-scoped"] = true
+				op["responses"].(object)["410"] = b.errorResponse("The project is archived")
+			}
+			item[strings.ToLower(method)] = op
+		}
+		paths[route.Path] = item
+	}
+	b.schema(reflect.TypeOf(JSONResponse{}))
+	return object{
+		"openapi": "3.0.3",
+		"info": object{
+			"title":   "codeplugs API",
+			"version": "1",
+			"description": "REST API of the codeplugs web UI. JSON responses are wrapped in a JSONResponse envelope with the payload in data. " +
+				"Once any account exists, operations need a session cookie or API token, from an account whose role is at least the operation's x-role. " +
+				"Operations marked x-project-scoped act on the default project at the path shown, and on another project under /api/projects/{project}/, e.g. /api/projects/club/channels.",
+		},
+		"security": []interface{}{object{"bearerAuth": []string{}}, object{"cookieAuth": []string{}}},
+		"tags":     tags,
+		"paths":    paths,
+		"components": object{
+			"schemas": b.schemas,
+			"securitySchemes": object{
+				"bearerAuth": object{"type": "http", "scheme": "bearer", "description": "API token from POST /api/auth/tokens"},
+				"cookieAuth": object{"type": "apiKey", "in": "cookie", "name": sessionCookie},
+			},
+		},
+	}
+}
+
+// routeTag groups a path by its first segment, e.g. contacts
+func routeTag(path string) string {
+	tag, _, _ := strings.Cut(strings.TrimPrefix(path, "/api/"), "/")
+	return strings.TrimSuffix(tag, ".json")
+}
+
+// routeMethods lists the route's methods in the order they first appear
+func routeMethods(route apiRoute) []string {
+	var methods []string
+	seen := map[string]bool{}
+	for _, op := range route.Ops {
+		if !seen[op.Method] {
+			seen[op.Method] = true
+			methods = append(methods, op.Method)
+		}
+	}
+	return methods
+}
+
+// operation documents the ops sharing one method. Variants add their
+// parameters as optional ones, their responses as oneOf alternatives and
+// themselves to x-variants so clients can call each one directly.
+func (b *specBuilder) operation(tag string, ops []apiOp) object {
+	primary := ops[0]
+	op := object{
+		"operationId": primary.ID,
+		"summary":     primary.Summary,
+		"tags":        []string{tag},
+	}
+
+	var params []interface{}
+	seen := map[string]bool{}
+	var fixedNames []string
+	fixed := map[string][]string{}
+	for i, o := range ops {
+		for _, p := range o.Params {
+			if !seen[p.Name] {
+				seen[p.Name] = true
+				params = append(params, b.parameter(p, i == 0 && p.Required))
+			}
+		}
+		for name, value := range o.Fixed {
+			if fixed[name] == nil {
+				fixedNames = append(fixedNames, name)
+			}
+			fixed[name] = append(fixed[name], value)
+		}
+	}
+	for _, name := range fixedNames {
+		if seen[name] {
+			continue // Documented by a parameter; the variants still list it
+		}
+		params = append(params, object{"name": name, "in": "query", "schema": object{"type": "string", "enum": fixed[name]}})
+	}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+
+	if primary.Body != nil {
+		op["requestBody"] = object{
+			"required": true,
+			"content":  object{"application/json": object{"schema": b.schemaOf(primary.Body)}},
+		}
+	}
+	if len(primary.Form) > 0 {
+		props := object{}
+		for _, p := range primary.Form {
+			props[p.Name] = b.paramSchema(p)
+		}
+		op["requestBody"] = object{
+			"required": true,
+			"content":  object{"multipart/form-data": object{"schema": object{"type": "object", "properties": props}}},
+		}
+	}
+
+	responses := object{
+		"default": object{
+			"description": "Error",
+			"content":     object{"application/json": object{"schema": b.schema(reflect.TypeOf(JSONResponse{}))}},
+		},
+	}
+	switch {
+	case primary.Upgrade:
+		responses["101"] = object{"description": "Switched to a WebSocket streaming Event messages"}
+	case primary.Content != "":
+		responses["200"] = object{
+			"description": "OK",
+			"content":     object{primary.Content: object{"schema": object{"type": "string", "format": "binary"}}},
+		}
+		if len(ops) > 1 {
+			op["x-variants"] = b.variants(ops, false)
+		}
+	case len(ops) == 1:
+		responses["200"] = b.jsonResponse(b.envelope(primary))
+	default:
+		var alternatives []interface{}
+		for _, o := range ops {
+			alternatives = append(alternatives, b.envelope(o))
+		}
+		responses["200"] = b.jsonResponse(object{"oneOf": alternatives})
+		op["x-variants"] = b.variants(ops, true)
+	}
+	for _, p := range primary.Params {
+		if p.Name == ifMatchParam.Name {
+			responses["412"] = b.errorResponse("Stale If-Match or version; data holds the current record")
+		}
+	}
+	op["responses"] = responses
+	return op
+}
+
+// variants describes each of ops for the x-variants extension, with its JSON
+// response envelope unless the ops return raw content
+func (b *specBuilder) variants(ops []apiOp, withResponse bool) []interface{} {
+	var variants []interface{}
+	for _, o := range ops {
+		v := object{"operationId": o.ID, "summary": o.Summary}
+		if withResponse {
+			v["response"] = b.envelope(o)
+		}
+		var names []string
+		for _, p := range o.Params {
+			names = append(names, p.Name)
+		}
+		if len(names) > 0 {
+			v["parameters"] = names
+		}
+		if len(o.Fixed) > 0 {
+			v["fixed"] = o.Fixed
+		}
+		if o.Role != "" {
+			v["x-role"] = o.Role
+		}
+		if o.Body != nil {
+			v["requestBody"] = b.schemaOf(o.Body)
+		}
+		variants = append(variants, v)
+	}
+	return variants
+}
+
+func (b *specBuilder) jsonResponse(schema object) object {
+	return object{
+		"description": "OK",
+		"content":     object{"application/json": object{"schema": schema}},
+	}
+}
+
+// errorResponse is a failure with its own description
+func (b *specBuilder) errorResponse(description string) object {
+	resp := b.jsonResponse(b.schema(reflect.TypeOf(JSONResponse{})))
+	resp["description"] = description
+	return resp
+}
+
+// envelope is the JSONResponse schema with op's data and meta types
+func (b *specBuilder) envelope(op apiOp) object {
+	props := object{"success": object{"type": "boolean"}}
+	if op.Data != nil {
+		props["data"] = b.schemaOf(op.Data)
+	}
+	if op.Meta {
+		props["meta"] = b.schema(reflect.TypeOf(ListMeta{}))
+	}
+	return object{"type": "object", "properties": props}
+}
+
+func (b *specBuilder) parameter(p apiParam, required bool) object {
+	param := object{"name": p.Name, "in": p.In, "schema": b.paramSchema(p)}
+	if required {
+		param["required"] = true
+	}
+	if p.Description != "" {
+		param["description"] = p.Description
+	}
+	if p.Array {
+		param["explode"] = true
+	}
+	return param
+}
+
+func (b *specBuilder) paramSchema(p apiParam) object {
+	s := object{"type": p.Type}
+	if p.Type == "file" {
+		s = object{"type": "string", "format": "binary"}
+	}
+	if p.Array {
+		s = object{"type": "array", "items": s}
+	}
+	if p.In == "form" && p.Description != "" {
+		s["description"] = p.Description
+	}
+	return s
+}
+
+// schemaOf is the schema of v's type, or a oneOf of several
+func (b *specBuilder) schemaOf(v interface{}) object {
+	if alts, ok := v.(oneOf); ok {
+		var schemas []interface{}
+		for _, alt := range alts {
+			schemas = append(schemas, b.schemaOf(alt))
+		}
+		return object{"oneOf": schemas}
+	}
+	return b.schema(reflect.TypeOf(v))
+}
+
+func (b *specBuilder) schema(t reflect.Type) object {
+	switch t {
+	case timeType:
+		return object{"type": "string", "format": "date-time"}
+	case deletedAtType:
+		return object{"type": "string", "format": "date-time", "nullable": true}
+	case rawType:
+		return object{}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		s := b.schema(t.Elem())
+		if _, ok := s["$ref"]; ok {
+			return object{"allOf": []interface{}{s}, "nullable": true}
+		}
+		s["nullable"] = true
+		return s
+	case reflect.Bool:
+		return object{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return object{"type": "integer"}
+	case reflect.Int64:
+		return object{"type": "integer", "format": "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return object{"type": "integer", "minimum": 0}
+	case reflect.Uint64:
+		return object{"type": "integer", "format": "int64", "minimum": 0}
+	case reflect.Float32:
+		return object{"type": "number", "format": "float"}
+	case reflect.Float64:
+		return object{"type": "number", "format": "double"}
+	case reflect.String:
+		return object{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return object{"type": "string", "format": "byte"}
+		}
+		return object{"type": "array", "items": b.schema(t.Elem())}
+	case reflect.Map:
+		return object{"type": "object", "additionalProperties": b.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.object(t)
+		}
+		name := b.componentName(t)
+		if _, ok := b.schemas[name]; !ok {
+			b.schemas[name] = object{} // placeholder for recursive types
+			b.schemas[name] = b.object(t)
+		}
+		return object{"$ref": "#/components/schemas/" + name}
+	}
+	return object{}
+}
+
+// object describes a struct's JSON fields, flattening embedded structs the
+// way encoding/json does
+func (b *specBuilder) object(t reflect.Type) object {
+	props := object{}
+	b.fields(t, props)
+	return object{"type": "object", "properties": props}
+}
+
+func (b *specBuilder) fields(t reflect.Type, props object) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				b.fields(ft, props)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = b.schema(f.Type)
+	}
+}
+
+// componentName names a struct's schema after its Go type, capitalized.
+// Generic types are named after their argument, e.g. Page[models.Zone] is
+// ZonePage. Clashing names are prefixed with the package name.
+func (b *specBuilder) componentName(t reflect.Type) string {
+	name := t.Name()
+	if base, arg, ok := strings.Cut(name, "["); ok {
+		arg = strings.TrimSuffix(arg, "]")
+		name = arg[strings.LastIndex(arg, ".")+1:] + base
+	}
+	name = strings.ToUpper(name[:1]) + name[1:]
+	if other, ok := b.names[name]; ok && other != t {
+		pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+	b.names[name] = t
+	return name
+}
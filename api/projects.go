@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"codeplugs/database"
+	"codeplugs/models"
+
+	"gorm.io/gorm"
+)
+
+// projectPrefix is where every project-scoped route is served again for a
+// project other than the default, e.g. /api/projects/club/channels
+const projectPrefix = "/api/projects/"
+
+type projectKey struct{}
+type handleKey struct{}
+
+// projectID is the project the request was routed to
+func projectID(r *http.Request) string {
+	if id, ok := r.Context().Value(projectKey{}).(string); ok {
+		return id
+	}
+	return models.DefaultProject
+}
+
+// handleFrom is the database of the request's project
+func handleFrom(r *http.Request) *database.Handle {
+	if h, ok := r.Context().Value(handleKey{}).(*database.Handle); ok {
+		return h
+	}
+	return serverFrom(r).Workspaces.Main()
+}
+
+// dbFrom is the connection handlers query for the request's project
+func dbFrom(r *http.Request) *gorm.DB {
+	return handleFrom(r).DB()
+}
+
+// withProject opens the database of the project the request was routed to
+// and adds it to the context. It responds and returns nil if the project
+// can't be served.
+func (s *Server) withProject(w http.ResponseWriter, r *http.Request) *http.Request {
+	id, ok := r.Context().Value(projectKey{}).(string)
+	if !ok {
+		return r
+	}
+	h, _, err := s.Workspaces.Project(id)
+	if err != nil {
+		respondProjectError(w, err)
+		return nil
+	}
+	return r.WithContext(context.WithValue(r.Context(), handleKey{}, h))
+}
+
+// projectRouter serves the routes that aren't Global under projectPrefix,
+// stripping the project from the path. The project's database is opened by
+// authorize, once the caller is known.
+func (s *Server) projectRouter() http.Handler {
+	inner := http.NewServeMux()
+	for _, route := range routes {
+		if !route.Global {
+			inner.Handle(route.Path, s.authorize(route))
+		}
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("project")
+		scoped := r.Clone(context.WithValue(r.Context(), projectKey{}, id))
+		scoped.URL.Path = "/api" + strings.TrimPrefix(r.URL.Path, projectPrefix+id)
+		scoped.URL.RawPath = ""
+		inner.ServeHTTP(w, scoped)
+	})
+}
+
+// projectRequest creates or changes a project; omitted fields are unchanged
+type projectRequest struct {
+	ID          string  `json:"id"`
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	CloneFrom   string  `json:"clone_from"` // Project to copy, e.g. default
+	Archived    *bool   `json:"archived"`
+}
+
+// HandleProjects lists, creates, clones, renames and archives projects
+func HandleProjects(w http.ResponseWriter, r *http.Request) {
+	ws := serverFrom(r).Workspaces
+	switch r.Method {
+	case "GET":
+		projects, err := ws.Projects(r.URL.Query().Get("archived") == "true")
+		if err != nil {
+			RespondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		RespondJSON(w, projects)
+	case "POST":
+		var req projectRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			RespondError(w, http.StatusBadRequest, "Invalid JSON")
+			return
+		}
+		p := models.Project{ID: strings.TrimSpace(req.ID), Name: req.ID}
+		if req.Name != nil {
+			p.Name = *req.Name
+		}
+		if req.Description != nil {
+			p.Description = *req.Description
+		}
+		if err := ws.CreateProject(&p, req.CloneFrom); err != nil {
+			respondProjectError(w, err)
+			return
+		}
+		broadcastServerEvent(r, "project.created", p)
+		RespondJSON(w, p)
+	case "PATCH":
+		var req projectRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			RespondError(w, http.StatusBadRequest, "Invalid JSON")
+			return
+		}
+		p, err := ws.UpdateProject(r.URL.Query().Get("id"), req.Name, req.Description, req.Archived)
+		if err != nil {
+			respondProjectError(w, err)
+			return
+		}
+		broadcastServerEvent(r, "project.updated", p)
+		RespondJSON(w, p)
+	default:
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func respondProjectError(w http.ResponseWriter, err error) {
+	var fields models.ValidationErrors
+	switch {
+	case errors.As(err, &fields):
+		RespondValidationError(w, err)
+	case errors.Is(err, database.ErrProjectNotFound):
+		RespondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, database.ErrProjectArchived):
+		RespondError(w, http.StatusGone, err.Error())
+	case errors.Is(err, database.ErrProjectExists), errors.Is(err, database.ErrProjectsDisabled), errors.Is(err, database.ErrDefaultProject):
+		RespondError(w, http.StatusConflict, err.Error())
+	default:
+		RespondError(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,298 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"codeplugs/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// validator is a model that checks its own fields before it is written
+type validator interface {
+	Validate() error
+}
+
+// versioned is a model embedding models.Versioned
+type versioned interface {
+	CurrentVersion() int
+	SetVersion(int)
+}
+
+// errResponded means writeResource has already written an error response
+var errResponded = errors.New("response written")
+
+// errVersionConflict rolls back a transaction whose versioned update lost
+// to another write
+var errVersionConflict = errors.New("version conflict")
+
+// readOnlyFields are gorm.Model fields a request body may not set
+var readOnlyFields = []string{"ID", "id", "CreatedAt", "created_at", "UpdatedAt", "updated_at", "DeletedAt", "deleted_at", "version"}
+
+// writeResource implements the write methods of a resource endpoint:
+//
+//   - POST without an ID creates a row.
+//   - PUT replaces every field of an existing row; omitted fields are zeroed.
+//     POST with an ID is treated as PUT for older clients.
+//   - PATCH changes only the fields present in the body.
+//
+// The ID comes from ?id= or the body. Unknown IDs get 404 rather than
+// creating a row, and the result is validated before it is saved, with
+// field errors in the response's errors[]. PUT and PATCH must match the
+// stored version, given as If-Match or the body's version, or get 412 with
+// the current record. On success the saved row is returned with its ETag
+// set; otherwise the response has been written and the error is
+// errResponded.
+func writeResource[T any, PT interface {
+	*T
+	validator
+	versioned
+}](w http.ResponseWriter, r *http.Request, name string) (PT, error) {
+	db := dbFrom(r)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, err.Error())
+		return nil, errResponded
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		RespondError(w, http.StatusBadRequest, err.Error())
+		return nil, errResponded
+	}
+
+	id, err := resourceID(r, fields)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, err.Error())
+		return nil, errResponded
+	}
+	var sentVersion int
+	if raw, ok := fields["version"]; ok {
+		json.Unmarshal(raw, &sentVersion)
+	}
+	for _, f := range readOnlyFields {
+		delete(fields, f)
+	}
+	body, _ = json.Marshal(fields)
+
+	method := r.Method
+	if method == "POST" && id != 0 {
+		method = "PUT"
+	}
+	if method != "POST" && id == 0 {
+		RespondError(w, http.StatusBadRequest, fmt.Sprintf("%s ID is required", name))
+		return nil, errResponded
+	}
+
+	row := PT(new(T))
+	if method != "POST" {
+		if row, err = loadVersioned[T, PT](w, r, name, id, sentVersion); err != nil {
+			return nil, err
+		}
+	}
+	version := row.CurrentVersion()
+
+	// PATCH decodes onto the stored row; POST and PUT start from zero values
+	target := row
+	if method != "PATCH" {
+		target = PT(new(T))
+	}
+	if err := json.Unmarshal(body, target); err != nil {
+		RespondError(w, http.StatusBadRequest, err.Error())
+		return nil, errResponded
+	}
+	if err := target.Validate(); err != nil {
+		RespondValidationError(w, err)
+		return nil, errResponded
+	}
+
+	if method == "POST" {
+		err = db.Omit(clause.Associations).Create(target).Error
+	} else {
+		// Only write if nobody else has since the row was read
+		target.SetVersion(version + 1)
+		res := db.Model(row).Where("version = ?", version).
+			Select("*").Omit("id", "created_at", "deleted_at", clause.Associations).Updates(target)
+		if err = res.Error; err == nil && res.RowsAffected == 0 {
+			current := PT(new(T))
+			db.First(current, id)
+			respondPreconditionFailed(w, current)
+			return nil, errResponded
+		}
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			RespondError(w, http.StatusConflict, fmt.Sprintf("%s already exists", name))
+		} else {
+			RespondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return nil, errResponded
+	}
+	if method != "POST" {
+		target = PT(new(T))
+		if err := db.First(target, id).Error; err != nil {
+			RespondError(w, http.StatusInternalServerError, err.Error())
+			return nil, errResponded
+		}
+	}
+	setETag(w, target)
+	return target, nil
+}
+
+// etag is the entity tag for a record version
+func etag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+func setETag(w http.ResponseWriter, v versioned) {
+	w.Header().Set("ETag", etag(v.CurrentVersion()))
+}
+
+// checkVersion reports whether a write may replace current: its If-Match
+// header, if any, must name current's ETag (or be "*"), and so must the
+// version sent in the body, if non-zero. Otherwise it responds 412 with
+// current so the client can merge and retry.
+func checkVersion(w http.ResponseWriter, r *http.Request, current versioned, sent int) bool {
+	if match := r.Header.Get("If-Match"); match != "" && match != "*" {
+		ok := false
+		for _, tag := range strings.Split(match, ",") {
+			if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == etag(current.CurrentVersion()) {
+				ok = true
+			}
+		}
+		if !ok {
+			respondPreconditionFailed(w, current)
+			return false
+		}
+	}
+	if sent != 0 && sent != current.CurrentVersion() {
+		respondPreconditionFailed(w, current)
+		return false
+	}
+	return true
+}
+
+func respondPreconditionFailed(w http.ResponseWriter, current versioned) {
+	setETag(w, current)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusPreconditionFailed)
+	json.NewEncoder(w).Encode(JSONResponse{
+		Success: false,
+		Error:   "Record was changed by someone else; reload and retry",
+		Data:    current,
+	})
+}
+
+// loadVersioned loads the row with the given ID for a write, responding
+// 404 if it doesn't exist or 412 if the request's If-Match or sent version
+// (when non-zero) doesn't match it.
+func loadVersioned[T any, PT interface {
+	*T
+	versioned
+}](w http.ResponseWriter, r *http.Request, name string, id interface{}, sent int) (PT, error) {
+	db := dbFrom(r)
+	row := PT(new(T))
+	if err := db.First(row, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			RespondError(w, http.StatusNotFound, fmt.Sprintf("%s %v not found", name, id))
+		} else {
+			RespondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return nil, errResponded
+	}
+	if !checkVersion(w, r, row, sent) {
+		return nil, errResponded
+	}
+	return row, nil
+}
+
+// updateVersioned applies updates to row, bumping its version, only if the
+// stored version still matches row's. It reports false when another write
+// got there first.
+func updateVersioned(tx *gorm.DB, row versioned, updates map[string]interface{}) (bool, error) {
+	if updates == nil {
+		updates = map[string]interface{}{}
+	}
+	updates["version"] = models.BumpVersion()
+	res := tx.Model(row).Where("version = ?", row.CurrentVersion()).Updates(updates)
+	return res.RowsAffected == 1, res.Error
+}
+
+// deleteResource deletes the row with ?id= after checking If-Match, and
+// returns the deleted row. inUse, if set, may veto the delete with a 409
+// message. Cleanup of join rows is left to the caller.
+func deleteResource[T any, PT interface {
+	*T
+	versioned
+}](w http.ResponseWriter, r *http.Request, name string, inUse func(id string) string) (PT, error) {
+	db := dbFrom(r)
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		RespondError(w, http.StatusBadRequest, name+" ID is required")
+		return nil, errResponded
+	}
+	row, err := loadVersioned[T, PT](w, r, name, id, 0)
+	if err != nil {
+		return nil, err
+	}
+	if inUse != nil {
+		if msg := inUse(id); msg != "" {
+			RespondError(w, http.StatusConflict, msg)
+			return nil, errResponded
+		}
+	}
+	res := db.Where("version = ?", row.CurrentVersion()).Delete(row)
+	if res.Error != nil {
+		RespondError(w, http.StatusInternalServerError, res.Error.Error())
+		return nil, errResponded
+	}
+	if res.RowsAffected == 0 {
+		current := PT(new(T))
+		db.First(current, id)
+		respondPreconditionFailed(w, current)
+		return nil, errResponded
+	}
+	return row, nil
+}
+
+// getResource responds with the row with the given ID and its ETag, or 404
+func getResource[T any, PT interface {
+	*T
+	versioned
+}](w http.ResponseWriter, r *http.Request, name, id string) {
+	db := dbFrom(r)
+	row := PT(new(T))
+	if err := db.First(row, id).Error; err != nil {
+		RespondError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found", name, id))
+		return
+	}
+	setETag(w, row)
+	RespondJSON(w, row)
+}
+
+// resourceID reads the row ID from ?id= or the body's ID field
+func resourceID(r *http.Request, fields map[string]json.RawMessage) (uint, error) {
+	if v := r.URL.Query().Get("id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid id %q", v)
+		}
+		return uint(id), nil
+	}
+	for _, key := range []string{"ID", "id"} {
+		if raw, ok := fields[key]; ok && string(raw) != "null" {
+			var id uint
+			if err := json.Unmarshal(raw, &id); err != nil {
+				return 0, fmt.Errorf("invalid %s %s", key, raw)
+			}
+			return id, nil
+		}
+	}
+	return 0, nil
+}
@@ -2,13 +2,19 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+
+	"codeplugs/models"
 )
 
 type JSONResponse struct {
 	Success bool        `json:"success"`
 	Data    interface{} `json:"data,omitempty"`
+	Meta    interface{} `json:"meta,omitempty"`
 	Error   string      `json:"error,omitempty"`
+
+	Errors []models.FieldError `json:"errors,omitempty"` // Field-level validation failures
 }
 
 func RespondJSON(w http.ResponseWriter, data interface{}) {
@@ -20,6 +26,18 @@ func RespondJSON(w http.ResponseWriter, data interface{}) {
 	})
 }
 
+// RespondJSONWithMeta responds with data plus listing metadata such as
+// totals and paging, leaving data in the same shape as an unpaged listing.
+func RespondJSONWithMeta(w http.ResponseWriter, data, meta interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(JSONResponse{
+		Success: true,
+		Data:    data,
+		Meta:    meta,
+	})
+}
+
 func RespondError(w http.ResponseWriter, code int, message string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(code)
@@ -28,3 +46,17 @@ func RespondError(w http.ResponseWriter, code int, message string) {
 		Error:   message,
 	})
 }
+
+// RespondValidationError responds 400 with each invalid field in errors[]
+// when err is a models.ValidationErrors, and with just the message otherwise.
+func RespondValidationError(w http.ResponseWriter, err error) {
+	var fields models.ValidationErrors
+	errors.As(err, &fields)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(JSONResponse{
+		Success: false,
+		Error:   err.Error(),
+		Errors:  fields,
+	})
+}
@@ -0,0 +1,425 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+
+	"codeplugs/config"
+	"codeplugs/models"
+	"codeplugs/services"
+)
+
+// apiRoute is one REST path, its handler and the operations the handler
+// accepts. The same table registers the routes and builds /api/openapi.json,
+// so a route can't be served without being documented.
+type apiRoute struct {
+	Path    string
+	Handler http.HandlerFunc
+	Ops     []apiOp
+	Public  bool // served without a login even when accounts exist
+	Global  bool // not per project, so not served under /api/projects/{project}/
+}
+
+// apiOp documents one operation. Several ops may share a method when query
+// parameters select a different response, as ?id= does on most listings;
+// the first is the primary and the rest become x-variants in the spec.
+type apiOp struct {
+	Method  string
+	ID      string // operationId and generated client method name
+	Summary string
+	Params  []apiParam
+	Fixed   map[string]string // query values that select this variant
+	Body    interface{}       // JSON request body type
+	Form    []apiParam        // multipart/form-data fields
+	Data    interface{}       // type of the response's data; nil for none
+	Meta    bool              // response has listing meta
+	Content string            // media type of a raw, non-JSON response
+	Upgrade bool              // switches the connection to a WebSocket
+	Role    models.Role       // role required; read-only for GET and editor otherwise by default
+}
+
+// role is the role an op requires
+func (op apiOp) role() models.Role {
+	switch {
+	case op.Role != "":
+		return op.Role
+	case op.Method == "GET":
+		return models.RoleReadOnly
+	}
+	return models.RoleEditor
+}
+
+// role is the highest role required by the route's ops for method whose
+// Fixed values query has; a nil query considers only the ops without any.
+// Methods the route doesn't document are left to the handler to reject.
+func (route apiRoute) role(method string, query url.Values) (models.Role, bool) {
+	var role models.Role
+	found := false
+	for _, op := range route.Ops {
+		if op.Method != method || !op.selected(query) {
+			continue
+		}
+		if !found || !role.Allows(op.role()) {
+			role, found = op.role(), true
+		}
+	}
+	return role, found
+}
+
+// selected reports whether query has every one of the op's Fixed values
+func (op apiOp) selected(query url.Values) bool {
+	for name, value := range op.Fixed {
+		if query.Get(name) != value {
+			return false
+		}
+	}
+	return true
+}
+
+// apiParam is a query, header or form parameter. Array parameters may be
+// repeated or comma-separated.
+type apiParam struct {
+	Name        string
+	In          string // query, header or form
+	Type        string // string, integer, number, boolean or file
+	Array       bool
+	Required    bool
+	Description string
+}
+
+// ListMeta is the paging metadata of listing responses
+type ListMeta struct {
+	Total      int64  `json:"total"`
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Page is a listing that carries its meta inside data
+type Page[T any] struct {
+	Data []T      `json:"data"`
+	Meta ListMeta `json:"meta"`
+}
+
+// contactListing is the plain contact listing, which nests its data
+type contactListing struct {
+	Data []models.Contact `json:"data"`
+}
+
+// importResult covers the import responses; which fields are set depends on
+// the format. A RadioID sync responds with its ContactSync instead.
+type importResult struct {
+	Message  string `json:"message,omitempty"`
+	Imported int    `json:"imported,omitempty"`
+	Skipped  int    `json:"skipped,omitempty"`
+	Count    int    `json:"count,omitempty"`
+}
+
+// dedupeResult reports a duplicate merge; Merged is set when merging all
+type dedupeResult struct {
+	Merged          []services.DuplicateGroup `json:"merged,omitempty"`
+	ChannelsUpdated int64                     `json:"channels_updated"`
+}
+
+// oneOf documents a response that is one of several types
+type oneOf []interface{}
+
+func queryParam(name, typ, desc string) apiParam {
+	return apiParam{Name: name, In: "query", Type: typ, Description: desc}
+}
+
+func listParam(name, typ, desc string) apiParam {
+	return apiParam{Name: name, In: "query", Type: typ, Array: true, Description: desc}
+}
+
+func formParam(name, typ, desc string) apiParam {
+	return apiParam{Name: name, In: "form", Type: typ, Description: desc}
+}
+
+func required(p apiParam) apiParam {
+	p.Required = true
+	return p
+}
+
+func idParam(what string) apiParam {
+	return required(queryParam("id", "integer", what+" ID"))
+}
+
+var ifMatchParam = apiParam{Name: "If-Match", In: "header", Type: "string",
+	Description: "ETag of the version the write is based on; a stale one gets 412 with the current record"}
+
+var pageParams = []apiParam{
+	queryParam("page", "integer", "Page number, from 1"),
+	queryParam("limit", "integer", "Page size"),
+}
+
+func params(groups ...[]apiParam) []apiParam {
+	var out []apiParam
+	for _, g := range groups {
+		out = append(out, g...)
+	}
+	return out
+}
+
+// versionedOps are the operations of a resource written through
+// writeResource and deleteResource
+func versionedOps(kind, name string, model interface{}) []apiOp {
+	return []apiOp{
+		{Method: "POST", ID: "create" + kind, Summary: "Create a " + name + "; with an ID, replace it like PUT",
+			Params: []apiParam{ifMatchParam}, Body: model, Data: model},
+		{Method: "PUT", ID: "replace" + kind, Summary: "Replace every field of a " + name,
+			Params: []apiParam{idParam(name), ifMatchParam}, Body: model, Data: model},
+		{Method: "PATCH", ID: "update" + kind, Summary: "Change only the fields present in the body",
+			Params: []apiParam{idParam(name), ifMatchParam}, Body: model, Data: model},
+		{Method: "DELETE", ID: "delete" + kind, Summary: "Delete a " + name,
+			Params: []apiParam{idParam(name), ifMatchParam}},
+	}
+}
+
+// crudOps are the operations of the simple POST-to-save resources
+func crudOps(kind, name string, model, list interface{}, listParams ...apiParam) []apiOp {
+	return []apiOp{
+		{Method: "GET", ID: "list" + kind + "s", Summary: "List " + name + "s", Params: listParams, Data: list},
+		{Method: "POST", ID: "save" + kind, Summary: "Create a " + name + ", or update it when ID is set", Body: model, Data: model},
+		{Method: "DELETE", ID: "delete" + kind, Summary: "Delete a " + name, Params: []apiParam{idParam(name)}},
+	}
+}
+
+var channelListParams = []apiParam{
+	listParam("mode", "string", "Channel modes"),
+	listParam("protocol", "string", "Protocols, e.g. DMR,FM"),
+	listParam("band", "string", "Band names such as 2m, 70cm, vhf or uhf"),
+	listParam("zone", "string", "Zone IDs or names"),
+	listParam("contact", "string", "Contact IDs or TX contact names"),
+	queryParam("search", "string", "Substring of the name or notes"),
+	queryParam("min_freq", "number", "Lowest RX frequency in MHz"),
+	queryParam("max_freq", "number", "Highest RX frequency in MHz"),
+	queryParam("skip", "boolean", "Scan skip flag"),
+	queryParam("sort", "string", "id, sort_order, name, rx_frequency, tx_frequency, mode, protocol, power or type"),
+	queryParam("order", "string", "asc or desc"),
+	queryParam("page", "integer", "Page number, from 1"),
+	queryParam("limit", "integer", "Page size; without one every match is returned"),
+	queryParam("cursor", "string", "next_cursor of the previous page"),
+}
+
+var routes = []apiRoute{
+	{Path: "/api/channels", Handler: HandleChannels, Ops: append([]apiOp{
+		{Method: "GET", ID: "listChannels", Summary: "List channels with filters, sorting and paging",
+			Params: channelListParams, Data: []models.Channel{}, Meta: true},
+		{Method: "GET", ID: "getChannel", Summary: "Get a channel", Params: []apiParam{idParam("Channel")}, Data: models.Channel{}},
+	}, versionedOps("Channel", "channel", models.Channel{})...)},
+	{Path: "/api/channels/reorder", Handler: HandleChannelReorder, Ops: []apiOp{
+		{Method: "POST", ID: "reorderChannels", Summary: "Set the channel order", Body: channelReorderRequest{}},
+	}},
+	{Path: "/api/channels/bulk", Handler: HandleChannelBulk, Ops: []apiOp{
+		{Method: "PATCH", ID: "bulkEditChannels", Summary: "Set the same fields on every channel matching a filter",
+			Body: channelBulkRequest{}, Data: services.ChannelBulkResult{}},
+	}},
+	{Path: "/api/import", Handler: HandleImport, Ops: []apiOp{
+		{Method: "POST", ID: "importData", Summary: "Import a file or download into the codeplug", Form: []apiParam{
+			formParam("file", "file", "Uploaded file"),
+			formParam("format", "string", "zip, db (restore the database; requires admin), single, radioid, talkgroup_catalog, last_heard, filter_list, or empty for a channel CSV"),
+			formParam("source_mode", "string", "download fetches RadioID contacts instead of using file"),
+			formParam("import_type", "string", "Table of a single import: channels, talkgroups, contacts, nxdn_talkgroups, nxdn_contacts or zones"),
+			formParam("radio_platform", "string", "dm32uv or at890"),
+			formParam("overwrite", "boolean", "Replace existing records; requires admin"),
+			formParam("sync", "boolean", "Diff RadioID contacts against the stored ones"),
+			formParam("network", "string", "Talkgroup catalog network"),
+			formParam("list_name", "string", "Name of an imported filter list"),
+		}, Data: oneOf{importResult{}, models.ContactSync{}}},
+	}},
+	{Path: "/api/export", Handler: HandleExport, Ops: []apiOp{
+		{Method: "GET", ID: "exportCodeplug", Summary: "Download the codeplug as a radio ZIP, CSV or database. X-Skipped-Channels lists P25 channels the format can't carry", Params: []apiParam{
+			queryParam("format", "string", "db (requires admin), dm32uv, at890, icom (D-Star repeater list), icom_mycall (D-Star own calls), chirp or p25"),
+			queryParam("radio", "string", "Radio for a zip export"),
+			listParam("zone_id", "integer", "Zones to include"),
+			queryParam("use_list", "string", "Filter list limiting exported contacts"),
+			queryParam("limit", "integer", "Maximum digital contacts"),
+			listParam("priority_state", "string", "States whose contacts are kept first"),
+			listParam("priority_country", "string", "Countries whose contacts are kept first"),
+			queryParam("profile", "string", "Saved export profile; the other parameters override it"),
+		}, Content: "application/octet-stream"},
+		{Method: "GET", ID: "exportDatabase", Summary: "Download a copy of the project database, without accounts",
+			Fixed: map[string]string{"format": "db"}, Role: models.RoleAdmin, Content: "application/x-sqlite3"},
+	}},
+	{Path: "/api/export/profiles", Handler: HandleExportProfiles, Global: true, Ops: []apiOp{
+		{Method: "GET", ID: "listExportProfiles", Summary: "List the export profiles saved in codeplugs.yaml", Data: []config.Profile{}},
+	}},
+	{Path: "/api/contacts", Handler: HandleContacts, Ops: append([]apiOp{
+		{Method: "GET", ID: "listContacts", Summary: "List talkgroup and private contacts", Data: contactListing{}},
+		{Method: "GET", ID: "getContact", Summary: "Get a contact", Params: []apiParam{idParam("Contact")}, Data: models.Contact{}},
+		{Method: "GET", ID: "searchRadioIDContacts", Summary: "Search RadioID digital contacts",
+			Fixed: map[string]string{"source": "RadioID"}, Params: params([]apiParam{
+				queryParam("search", "string", `Terms, field-qualified or prefixed, e.g. "state:MI call:KF8*"`),
+				queryParam("sort", "string", "Sort field"),
+				queryParam("order", "string", "asc or desc"),
+				queryParam("cursor", "string", "next_cursor of the previous page"),
+			}, pageParams), Data: Page[models.DigitalContact]{}},
+	}, versionedOps("Contact", "contact", models.Contact{})...)},
+	{Path: "/api/zones", Handler: HandleZones, Ops: []apiOp{
+		{Method: "GET", ID: "listZones", Summary: "List zones with their channels", Data: []models.Zone{}},
+		{Method: "GET", ID: "getZone", Summary: "Get a zone", Params: []apiParam{idParam("Zone")}, Data: models.Zone{}},
+		{Method: "POST", ID: "saveZone", Summary: "Create a zone, or rename it when ID is set",
+			Params: []apiParam{ifMatchParam}, Body: models.Zone{}, Data: models.Zone{}},
+		{Method: "DELETE", ID: "deleteZone", Summary: "Delete a zone", Params: []apiParam{idParam("Zone"), ifMatchParam}},
+	}},
+	{Path: "/api/zones/assign", Handler: HandleZoneAssignment, Ops: []apiOp{
+		{Method: "POST", ID: "assignZoneChannels", Summary: "Replace a zone's channels, in order",
+			Params: []apiParam{idParam("Zone"), ifMatchParam}, Body: []int{}},
+	}},
+	{Path: "/api/scanlists", Handler: HandleScanLists, Ops: []apiOp{
+		{Method: "GET", ID: "listScanLists", Summary: "List scan lists with their channels", Data: []models.ScanList{}},
+		{Method: "GET", ID: "getScanList", Summary: "Get a scan list", Params: []apiParam{idParam("Scan list")}, Data: models.ScanList{}},
+		{Method: "POST", ID: "saveScanList", Summary: "Create a scan list, or rename it when ID is set",
+			Params: []apiParam{ifMatchParam}, Body: models.ScanList{}, Data: models.ScanList{}},
+		{Method: "DELETE", ID: "deleteScanList", Summary: "Delete a scan list", Params: []apiParam{idParam("Scan list"), ifMatchParam}},
+	}},
+	{Path: "/api/scanlists/assign", Handler: HandleScanListAssignment, Ops: []apiOp{
+		{Method: "POST", ID: "assignScanListChannels", Summary: "Replace a scan list's channels",
+			Params: []apiParam{ifMatchParam}, Body: scanListAssignRequest{}},
+	}},
+	{Path: "/api/filter_lists", Handler: HandleFilterLists, Ops: []apiOp{
+		{Method: "GET", ID: "listFilterLists", Summary: "List filter lists with their rules", Data: []models.ContactList{}},
+		{Method: "GET", ID: "getFilterListEntries", Summary: "Page through a filter list's entries",
+			Params: params([]apiParam{idParam("Filter list"), queryParam("search", "string", "DMR ID substring")}, pageParams),
+			Data:   Page[models.ContactListEntry]{}},
+		{Method: "GET", ID: "getFilterListIDs", Summary: "Resolve a filter list to DMR IDs",
+			Params: []apiParam{idParam("Filter list")}, Fixed: map[string]string{"mode": "ids"}, Data: []int{}},
+		{Method: "GET", ID: "getFilterListRules", Summary: "Get a filter list's rules",
+			Params: []apiParam{idParam("Filter list")}, Fixed: map[string]string{"mode": "rules"}, Data: []models.ContactListRule{}},
+		{Method: "POST", ID: "saveFilterList", Summary: "Create a filter list, or update it and replace its rules when ID is set",
+			Params: []apiParam{ifMatchParam}, Body: filterListRequest{}, Data: models.ContactList{}},
+		{Method: "DELETE", ID: "deleteFilterList", Summary: "Delete a filter list", Params: []apiParam{idParam("Filter list"), ifMatchParam}},
+	}},
+	{Path: "/api/filter_lists/ops", Handler: HandleFilterListOps, Ops: []apiOp{
+		{Method: "POST", ID: "runListOperation", Summary: "Combine filter lists by union, intersection and exclusion",
+			Body: services.ListOperation{}, Data: services.ListOperationResult{}},
+	}},
+	{Path: "/api/roaming/channels", Handler: HandleRoamingChannels,
+		Ops: crudOps("RoamingChannel", "roaming channel", models.RoamingChannel{}, []models.RoamingChannel{})},
+	{Path: "/api/roaming/zones", Handler: HandleRoamingZones, Ops: []apiOp{
+		{Method: "GET", ID: "listRoamingZones", Summary: "List roaming zones with their channels", Data: []models.RoamingZone{}},
+		{Method: "GET", ID: "getRoamingZone", Summary: "Get a roaming zone", Params: []apiParam{idParam("Roaming zone")}, Data: models.RoamingZone{}},
+		{Method: "POST", ID: "saveRoamingZone", Summary: "Create a roaming zone, or rename it when ID is set",
+			Body: models.RoamingZone{}, Data: models.RoamingZone{}},
+		{Method: "DELETE", ID: "deleteRoamingZone", Summary: "Delete a roaming zone", Params: []apiParam{idParam("Roaming zone")}},
+	}},
+	{Path: "/api/roaming/zones/assign", Handler: HandleRoamingZoneAssignment, Ops: []apiOp{
+		{Method: "POST", ID: "assignRoamingZoneChannels", Summary: "Replace a roaming zone's channels",
+			Params: []apiParam{idParam("Roaming zone")}, Body: []uint{}},
+	}},
+	{Path: "/api/nxdn/talkgroups", Handler: HandleNXDNTalkgroups,
+		Ops: crudOps("NXDNTalkgroup", "NXDN talkgroup", models.NXDNTalkgroup{}, []models.NXDNTalkgroup{})},
+	{Path: "/api/nxdn/contacts", Handler: HandleNXDNContacts,
+		Ops: crudOps("NXDNContact", "NXDN contact", models.NXDNContact{}, Page[models.NXDNContact]{},
+			params([]apiParam{queryParam("search", "string", "Name, callsign or unit ID substring")}, pageParams)...)},
+	{Path: "/api/dstar/repeaters", Handler: HandleDStarRepeaters,
+		Ops: crudOps("DStarRepeater", "D-Star repeater", models.DStarRepeater{}, []models.DStarRepeater{},
+			queryParam("type", "string", "Repeater or Reflector"))},
+	{Path: "/api/p25/talkgroups", Handler: HandleP25Talkgroups,
+		Ops: crudOps("P25Talkgroup", "P25 talkgroup", models.P25Talkgroup{}, []models.P25Talkgroup{})},
+	{Path: "/api/contacts/syncs", Handler: HandleContactSyncs, Ops: []apiOp{
+		{Method: "GET", ID: "listContactSyncs", Summary: "List RadioID syncs, newest first", Data: []models.ContactSync{}},
+		{Method: "GET", ID: "getContactSync", Summary: "Get a sync with its change log", Params: []apiParam{
+			idParam("Sync"),
+			queryParam("callsign", "string", "Only changes to this callsign"),
+			queryParam("action", "string", "Only changes with this action"),
+		}, Data: models.ContactSync{}},
+	}},
+	{Path: "/api/contacts/pins", Handler: HandleContactPins, Ops: []apiOp{
+		{Method: "GET", ID: "listContactPins", Summary: "List pinned DMR IDs", Data: []models.ContactPin{}},
+		{Method: "POST", ID: "pinContact", Summary: "Pin a DMR ID so exports always keep it", Body: models.ContactPin{}, Data: models.ContactPin{}},
+		{Method: "DELETE", ID: "unpinContact", Summary: "Unpin a DMR ID",
+			Params: []apiParam{required(queryParam("dmr_id", "integer", "DMR ID"))}},
+	}},
+	{Path: "/api/contacts/overrides", Handler: HandleContactOverrides, Ops: []apiOp{
+		{Method: "GET", ID: "listContactOverrides", Summary: "List call alert and remarks overrides",
+			Params: []apiParam{queryParam("dmr_id", "integer", "Only this DMR ID")}, Data: []models.ContactOverride{}},
+		{Method: "POST", ID: "saveContactOverride", Summary: "Set a DMR ID's call alert and remarks",
+			Body: models.ContactOverride{}, Data: models.ContactOverride{}},
+		{Method: "DELETE", ID: "deleteContactOverride", Summary: "Remove a DMR ID's override",
+			Params: []apiParam{required(queryParam("dmr_id", "integer", "DMR ID"))}},
+	}},
+	{Path: "/api/talkgroups/catalog", Handler: HandleTalkgroupCatalog, Ops: []apiOp{
+		{Method: "GET", ID: "listCatalogTalkgroups", Summary: "Search the talkgroup catalog", Params: params([]apiParam{
+			queryParam("network", "string", "Network, e.g. Brandmeister"),
+			queryParam("search", "string", "Name substring or ID prefix"),
+		}, pageParams), Data: Page[models.TalkgroupCatalog]{}},
+	}},
+	{Path: "/api/contacts/resolve_placeholders", Handler: HandleResolvePlaceholders, Ops: []apiOp{
+		{Method: "POST", ID: "resolvePlaceholders", Summary: "Match placeholder contacts against the talkgroup catalog",
+			Params: []apiParam{listParam("network", "string", "Only these networks")}, Data: []services.PlaceholderResolution{}},
+	}},
+	{Path: "/api/contacts/unresolved", Handler: HandleUnresolvedContacts, Ops: []apiOp{
+		{Method: "GET", ID: "listUnresolvedContacts", Summary: "List placeholder contacts with suggested talkgroups",
+			Params: []apiParam{queryParam("suggestions", "integer", "Suggestions per contact (default 5)")}, Data: []services.UnresolvedContact{}},
+	}},
+	{Path: "/api/contacts/merge", Handler: HandleMergeContact, Ops: []apiOp{
+		{Method: "POST", ID: "mergePlaceholder", Summary: "Merge a placeholder into a contact or talkgroup ID",
+			Body: services.MergeRequest{}, Data: services.MergeResult{}},
+	}},
+	{Path: "/api/contacts/duplicates", Handler: HandleContactDuplicates, Ops: []apiOp{
+		{Method: "GET", ID: "listDuplicateContacts", Summary: "List duplicate contact groups", Data: []services.DuplicateGroup{}},
+		{Method: "POST", ID: "mergeDuplicateContacts", Summary: "Merge duplicates into a canonical contact",
+			Body: services.DedupeRequest{}, Data: dedupeResult{}},
+		{Method: "POST", ID: "mergeAllDuplicateContacts", Summary: "Merge every duplicate group",
+			Fixed: map[string]string{"all": "true"}, Data: dedupeResult{}},
+	}},
+	{Path: "/api/ws", Handler: HandleWebSocket, Ops: []apiOp{
+		{Method: "GET", ID: "streamEvents", Summary: "WebSocket change feed", Upgrade: true, Params: []apiParam{
+			listParam("events", "string", "Event patterns, e.g. channel.* or zone.deleted"),
+			queryParam("since", "integer", "Replay events after this seq"),
+		}},
+	}},
+}
+
+// authRoutes log in and manage accounts and API tokens
+var authRoutes = []apiRoute{
+	{Path: "/api/auth/login", Handler: HandleLogin, Global: true, Public: true, Ops: []apiOp{
+		{Method: "POST", ID: "login", Summary: "Log in and set the session cookie", Body: loginRequest{}, Data: sessionInfo{}},
+	}},
+	{Path: "/api/auth/logout", Handler: HandleLogout, Global: true, Public: true, Ops: []apiOp{
+		{Method: "POST", ID: "logout", Summary: "End the session and clear its cookie"},
+	}},
+	{Path: "/api/auth/session", Handler: HandleSession, Global: true, Public: true, Ops: []apiOp{
+		{Method: "GET", ID: "getSession", Summary: "Whether login is required, and the logged-in account", Data: sessionInfo{}},
+	}},
+	{Path: "/api/auth/tokens", Handler: HandleAPITokens, Global: true, Ops: []apiOp{
+		{Method: "GET", ID: "listAPITokens", Summary: "List your API tokens", Data: []models.APIToken{}},
+		{Method: "POST", ID: "createAPIToken", Summary: "Create an API token acting as you; the secret is only shown now",
+			Role: models.RoleReadOnly, Body: tokenRequest{}, Data: createdToken{}},
+		{Method: "DELETE", ID: "revokeAPIToken", Summary: "Revoke one of your API tokens",
+			Role: models.RoleReadOnly, Params: []apiParam{idParam("Token")}},
+	}},
+	{Path: "/api/users", Handler: HandleUsers, Global: true, Ops: []apiOp{
+		{Method: "GET", ID: "listUsers", Summary: "List accounts", Role: models.RoleAdmin, Data: []models.User{}},
+		{Method: "POST", ID: "createUser", Summary: "Create an account; the first one must be an admin and turns login on",
+			Role: models.RoleAdmin, Body: userRequest{}, Data: models.User{}},
+		{Method: "PATCH", ID: "updateUser", Summary: "Change an account's role or password",
+			Role: models.RoleAdmin, Params: []apiParam{idParam("User")}, Body: userRequest{}, Data: models.User{}},
+		{Method: "DELETE", ID: "deleteUser", Summary: "Delete an account with its sessions and tokens",
+			Role: models.RoleAdmin, Params: []apiParam{idParam("User")}},
+	}},
+}
+
+// projectRoutes manage the projects; the routes above, except authRoutes,
+// are also served per project under /api/projects/{project}/
+var projectRoutes = []apiRoute{
+	{Path: "/api/projects", Handler: HandleProjects, Global: true, Ops: []apiOp{
+		{Method: "GET", ID: "listProjects", Summary: "List projects, starting with the default one",
+			Params: []apiParam{queryParam("archived", "boolean", "Include archived projects")}, Data: []models.Project{}},
+		{Method: "POST", ID: "createProject", Summary: "Create a project with an empty database, or a copy of clone_from's",
+			Body: projectRequest{}, Data: models.Project{}},
+		{Method: "PATCH", ID: "updateProject", Summary: "Rename, archive or unarchive a project",
+			Role: models.RoleAdmin, Params: []apiParam{required(queryParam("id", "string", "Project ID"))}, Body: projectRequest{}, Data: models.Project{}},
+	}},
+}
+
+// The spec documents itself; it is added here because HandleOpenAPI reads routes
+func init() {
+	routes = append(routes, authRoutes...)
+	routes = append(routes, projectRoutes...)
+	routes = append(routes, apiRoute{Path: "/api/openapi.json", Handler: HandleOpenAPI, Public: true, Global: true, Ops: []apiOp{
+		{Method: "GET", ID: "getOpenAPI", Summary: "This OpenAPI document", Content: "application/json"},
+	}})
+}
@@ -1,65 +1,138 @@
 package api
 
 import (
-	"fmt"
+	"context"
 	"io"
 	"io/fs"
 	"log"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
+
+	"codeplugs/config"
+	"codeplugs/database"
+
+	"github.com/gorilla/websocket"
+	"gorm.io/gorm"
 )
 
-func StartServer(port string) {
-	// API Routes
-	http.HandleFunc("/api/channels", HandleChannels)
-	http.HandleFunc("/api/channels/reorder", HandleChannelReorder)
-	http.HandleFunc("/api/import", HandleImport)
-	http.HandleFunc("/api/export", HandleExport)
-	http.HandleFunc("/api/contacts", HandleContacts)
-	http.HandleFunc("/api/zones", HandleZones)
-	http.HandleFunc("/api/zones/assign", HandleZoneAssignment)
-	http.HandleFunc("/api/scanlists", HandleScanLists)
-
-	http.HandleFunc("/api/scanlists/assign", HandleScanListAssignment)
-	http.HandleFunc("/api/filter_lists", HandleFilterLists)
-	http.HandleFunc("/api/ws", HandleWebSocket)
-
-	// Static Files
-	// We need to access the embedded FS.
-	// Since embed is in this package now, we can use it directly.
-	// Note: The path inside embed depends on where the go command is run?
-	// Actually embed paths are relative to the file containing the directive.
-	// So if api/server.go is in codeplugs/api/, we need to adjust the path?
-	// frontend/dist is at root: codeplugs/frontend/dist.
-	// So relative to api/server.go, it is ../frontend/dist.
-	// Embed patterns must match files relative to the package directory.
-	// So I cannot embed ../frontend/dist directly in standard Go embed unless I use a workaround or move the file.
-	//
-	// Workaround: Move the embed to main.go (root) and pass the FS to StartServer.
-	// Or put a dummy file in root to embed?
-	// Best practice: Keep the embed in main.go or a root package, and pass fs.FS.
-
-	// I will remove the embed from here and accept it as an argument.
+// Config is how a Server is run
+type Config struct {
+	Addr string // Listen address for ListenAndServe, e.g. :8080
+
+	// AllowedOrigins lists extra origins, e.g. http://localhost:5173, whose
+	// pages may open the WebSocket or write with a session cookie. Pages
+	// served by this server are always allowed.
+	AllowedOrigins []string
+
+	SessionTTL time.Duration // How long a login lasts; a week if zero
+	Frontend   fs.FS         // Built web UI served at /, or nil for the API alone
+
+	Profiles []config.Profile // Export profiles offered by the export modal
+}
+
+// ShutdownTimeout is how long ListenAndServe waits for requests in flight
+// once its context is done
+var ShutdownTimeout = 10 * time.Second
+
+// Server is the web UI and API over a set of project databases. It owns the
+// event hub and import jobs, so several servers, e.g. in tests, don't share
+// state. Handlers find it in the request context.
+type Server struct {
+	Workspaces *database.Workspaces
+	Hub        *WebSocketHub
+	Jobs       *JobManager
+	Config     Config
+
+	mux       *http.ServeMux
+	upgrader  websocket.Upgrader
+	closeOnce sync.Once
+}
+
+// NewServer serves ws with cfg and starts the event hub; Close stops it
+func NewServer(ws *database.Workspaces, cfg Config) *Server {
+	if cfg.SessionTTL == 0 {
+		cfg.SessionTTL = 7 * 24 * time.Hour
+	}
+	s := &Server{Workspaces: ws, Hub: newHub(), Config: cfg}
+	s.Jobs = newJobManager(s.Hub)
+	s.upgrader = websocket.Upgrader{CheckOrigin: s.checkOrigin}
+	s.mux = s.routes()
+	go s.Hub.Run()
+	return s
+}
+
+// NewServerForDB serves db as the only project with the default Config. It
+// suits httptest.NewServer, and serving a single request with ServeHTTP.
+func NewServerForDB(db *gorm.DB) *Server {
+	return NewServer(database.NewWorkspaces(database.NewHandle(db, ""), ""), Config{})
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Handler returns the handler r would be served by and its route pattern,
+// as http.ServeMux.Handler does
+func (s *Server) Handler(r *http.Request) (http.Handler, string) {
+	return s.mux.Handler(r)
+}
+
+// routes registers every API route behind authorization, the same routes
+// per project, and the web UI
+func (s *Server) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	for _, route := range routes {
+		mux.Handle(route.Path, s.authorize(route))
+	}
+	mux.Handle(projectPrefix+"{project}/", s.projectRouter())
+	if s.Config.Frontend != nil {
+		mux.Handle("/", spaHandler(s.Config.Frontend))
+	}
+	return mux
+}
+
+// ListenAndServe serves on Config.Addr until ctx is done, then stops
+// accepting connections and waits up to ShutdownTimeout for requests in
+// flight. WebSockets are left to Close.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	hs := &http.Server{Addr: s.Config.Addr, Handler: s}
+	errc := make(chan error, 1)
+	go func() {
+		errc <- hs.ListenAndServe()
+	}()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Println("Shutting down...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+	return hs.Shutdown(shutdownCtx)
+}
+
+// Close stops the event hub and disconnects its WebSockets. The databases
+// are left open for the caller to close.
+func (s *Server) Close() {
+	s.closeOnce.Do(s.Hub.Close)
 }
 
-func StartServerWithFS(port string, distFS fs.FS) {
-	// API Routes
-	http.HandleFunc("/api/channels", HandleChannels)
-	http.HandleFunc("/api/channels/reorder", HandleChannelReorder)
-	http.HandleFunc("/api/import", HandleImport)
-	http.HandleFunc("/api/export", HandleExport)
-	http.HandleFunc("/api/contacts", HandleContacts)
-	http.HandleFunc("/api/zones", HandleZones)
-	http.HandleFunc("/api/zones/assign", HandleZoneAssignment)
-	http.HandleFunc("/api/scanlists", HandleScanLists)
-
-	http.HandleFunc("/api/scanlists/assign", HandleScanListAssignment)
-	http.HandleFunc("/api/filter_lists", HandleFilterLists)
-	http.HandleFunc("/api/ws", HandleWebSocket)
-
-	// SPA Handler
+type serverKey struct{}
+
+// serverFrom is the server handling the request
+func serverFrom(r *http.Request) *Server {
+	s, _ := r.Context().Value(serverKey{}).(*Server)
+	return s
+}
+
+// spaHandler serves the built web UI, falling back to index.html so the
+// client-side router handles unknown paths
+func spaHandler(distFS fs.FS) http.Handler {
 	fileServer := http.FileServer(http.FS(distFS))
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		path := strings.TrimPrefix(r.URL.Path, "/")
 		if path == "" {
 			path = "index.html"
@@ -83,7 +156,4 @@ func StartServerWithFS(port string, distFS fs.FS) {
 		stat, _ := f.Stat()
 		http.ServeContent(w, r, "index.html", stat.ModTime(), f.(io.ReadSeeker))
 	})
-
-	fmt.Printf("Starting server on http://localhost:%s\n", port)
-	log.Fatal(http.ListenAndServe(":"+port, nil))
 }
@@ -4,71 +4,184 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"strconv"
 	"sync"
 
 	"github.com/gorilla/websocket"
 )
 
-var Upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool { return true },
+// historySize is how many events the hub keeps for reconnecting clients
+const historySize = 1000
+
+// wsClient is one socket and the events it has subscribed to. Its writer
+// goroutine drains send, so the hub never blocks on a slow browser.
+type wsClient struct {
+	conn    *websocket.Conn
+	send    chan []byte
+	filter  eventFilter
+	project string // Only this project's record events are sent
+}
+
+// wants reports whether the client is subscribed to e
+func (c *wsClient) wants(e Event) bool {
+	return (e.Project == "" || e.Project == c.project) && c.filter.match(e.Type)
 }
 
-// WebSocketHub maintains the set of active clients and broadcasts messages
+// WebSocketHub maintains the set of active clients, numbers each event and
+// keeps the most recent ones so a reconnecting client can catch up.
 type WebSocketHub struct {
 	// Registered clients.
-	clients map[*websocket.Conn]bool
+	clients map[*wsClient]bool
 
-	// Inbound messages from the clients.
-	broadcast chan []byte
+	// Events to send to the clients.
+	broadcast chan Event
 
-	// Register requests from the clients.
-	register chan *websocket.Conn
+	// Last sequence number handed out, and the events up to it.
+	seq     uint64
+	history []Event
 
-	// Unregister requests from clients.
-	unregister chan *websocket.Conn
+	// Closed by Close; no clients register after that.
+	done   chan struct{}
+	closed bool
 
 	mu sync.Mutex
 }
 
 func newHub() *WebSocketHub {
 	return &WebSocketHub{
-		broadcast:  make(chan []byte),
-		register:   make(chan *websocket.Conn),
-		unregister: make(chan *websocket.Conn),
-		clients:    make(map[*websocket.Conn]bool),
+		broadcast: make(chan Event, 256),
+		clients:   make(map[*wsClient]bool),
+		done:      make(chan struct{}),
 	}
 }
 
+// Run publishes events until Close
 func (h *WebSocketHub) Run() {
 	for {
 		select {
-		case client := <-h.register:
-			h.mu.Lock()
-			h.clients[client] = true
-			h.mu.Unlock()
-		case client := <-h.unregister:
-			h.mu.Lock()
-			if _, ok := h.clients[client]; ok {
-				delete(h.clients, client)
-				client.Close()
-			}
-			h.mu.Unlock()
-		case message := <-h.broadcast:
-			h.mu.Lock()
-			for client := range h.clients {
-				err := client.WriteMessage(websocket.TextMessage, message)
-				if err != nil {
-					client.Close()
-					delete(h.clients, client)
+		case e := <-h.broadcast:
+			h.publish(e)
+		case <-h.done:
+			return
+		}
+	}
+}
+
+// Close stops Run and disconnects every client
+func (h *WebSocketHub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	h.closed = true
+	close(h.done)
+	for client := range h.clients {
+		h.removeLocked(client)
+	}
+}
+
+// send queues e for Run, dropping it once the hub is closed
+func (h *WebSocketHub) send(e Event) {
+	select {
+	case h.broadcast <- e:
+	case <-h.done:
+	}
+}
+
+// publish sequences e (unless transient), records it and sends it to every
+// client of its project subscribed to its type. Clients that can't keep up
+// are dropped; they reconnect with their last seq and replay what they
+// missed.
+func (h *WebSocketHub) publish(e Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !e.transient {
+		h.seq++
+		e.Seq = h.seq
+		if len(h.history) == historySize {
+			h.history = h.history[1:]
+		}
+		h.history = append(h.history, e)
+	}
+	msg, _ := json.Marshal(e)
+	for client := range h.clients {
+		if client.wants(e) {
+			h.sendLocked(client, msg)
+		}
+	}
+}
+
+// register adds client, unless the hub is closed
+func (h *WebSocketHub) register(client *wsClient) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return false
+	}
+	h.clients[client] = true
+	return true
+}
+
+func (h *WebSocketHub) unregister(client *wsClient) {
+	h.mu.Lock()
+	h.removeLocked(client)
+	h.mu.Unlock()
+}
+
+func (h *WebSocketHub) removeLocked(client *wsClient) {
+	if _, ok := h.clients[client]; ok {
+		delete(h.clients, client)
+		close(client.send)
+	}
+}
+
+func (h *WebSocketHub) sendLocked(client *wsClient, msg []byte) {
+	if !h.clients[client] {
+		return
+	}
+	select {
+	case client.send <- msg:
+	default:
+		h.removeLocked(client)
+	}
+}
+
+// subscribe replaces the client's filter and, when sub has a Since cursor,
+// replays the matching events after it. If the cursor is older than the
+// history the client gets a resync event instead and should reload. Either
+// way it then gets a subscribed event with the current seq to resume from.
+func (h *WebSocketHub) subscribe(client *wsClient, sub Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.clients[client] {
+		return
+	}
+	client.filter = newEventFilter(sub.Events)
+
+	if sub.Since != nil {
+		since := *sub.Since
+		oldest := h.seq + 1
+		if len(h.history) > 0 {
+			oldest = h.history[0].Seq
+		}
+		if since+1 < oldest || since > h.seq {
+			h.sendLocked(client, controlMessage(EventResync, map[string]uint64{"seq": h.seq}))
+		} else {
+			for _, e := range h.history {
+				if e.Seq > since && client.wants(e) {
+					msg, _ := json.Marshal(e)
+					h.sendLocked(client, msg)
 				}
 			}
-			h.mu.Unlock()
 		}
 	}
+	h.sendLocked(client, controlMessage(EventSubscribed, map[string]interface{}{
+		"events": client.filter.patterns(),
+		"seq":    h.seq,
+	}))
 }
 
-var Hub = newHub()
-
 // ImportProgress tracks the status of a running import
 type ImportProgress struct {
 	Total     int    `json:"total"`
@@ -76,38 +189,88 @@ type ImportProgress struct {
 	Status    string `json:"status"` // "running", "completed", "error"
 	Message   string `json:"message"`
 	mu        sync.Mutex
+	hub       *WebSocketHub
 }
 
-var CurrentProgress = &ImportProgress{Status: "idle"}
+// Broadcast sends the current progress. Progress is transient: it has no seq
+// and is not replayed.
+func (p *ImportProgress) Broadcast() {
+	p.mu.Lock()
+	data, _ := json.Marshal(p)
+	p.mu.Unlock()
 
-func BroadcastProgress() {
-	CurrentProgress.mu.Lock()
-	defer CurrentProgress.mu.Unlock()
+	e := newEvent(EventImportProgress, data)
+	e.transient = true
+	p.hub.send(e)
+}
+
+// JobManager tracks the server's long-running work. Imports are the only
+// jobs; they share one progress record, shown to every client.
+type JobManager struct {
+	Import *ImportProgress
+}
 
-	msg, _ := json.Marshal(map[string]interface{}{
-		"type": "import_progress",
-		"data": CurrentProgress,
-	})
-	Hub.broadcast <- msg
+func newJobManager(hub *WebSocketHub) *JobManager {
+	return &JobManager{Import: &ImportProgress{Status: "idle", hub: hub}}
 }
 
+// HandleWebSocket streams events to the browser. The initial subscription
+// comes from ?events=channel.*,zone.* and ?since=<seq>; the client may
+// change it at any time by sending a Subscription message.
 func HandleWebSocket(w http.ResponseWriter, r *http.Request) {
-	conn, err := Upgrader.Upgrade(w, r, nil)
+	if r.Method != "GET" {
+		RespondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	sub := Subscription{Events: splitQueryList(r.URL.Query()["events"])}
+	if v := r.URL.Query().Get("since"); v != "" {
+		since, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, "invalid since "+strconv.Quote(v))
+			return
+		}
+		sub.Since = &since
+	}
+
+	srv := serverFrom(r)
+	conn, err := srv.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Println(err)
 		return
 	}
-	Hub.register <- conn
-
-	defer func() {
-		Hub.unregister <- conn
+	hub := srv.Hub
+	client := &wsClient{conn: conn, send: make(chan []byte, historySize+64), project: projectID(r)}
+	if !hub.register(client) {
 		conn.Close()
-	}()
+		return
+	}
+	go client.writeLoop()
+	hub.subscribe(client, sub)
 
+	defer hub.unregister(client)
 	for {
-		_, _, err := conn.ReadMessage()
+		_, data, err := conn.ReadMessage()
 		if err != nil {
 			break
 		}
+		var msg Subscription
+		if err := json.Unmarshal(data, &msg); err != nil || msg.Action != "subscribe" {
+			hub.mu.Lock()
+			hub.sendLocked(client, controlMessage(EventError, map[string]string{"message": "expected a subscribe message"}))
+			hub.mu.Unlock()
+			continue
+		}
+		hub.subscribe(client, msg)
+	}
+}
+
+// writeLoop writes queued messages until the hub closes send
+func (c *wsClient) writeLoop() {
+	defer c.conn.Close()
+	for msg := range c.send {
+		if err := c.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+			return
+		}
 	}
+	c.conn.WriteMessage(websocket.CloseMessage, []byte{})
 }
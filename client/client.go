@@ -0,0 +1,198 @@
+// Package client is a Go client for the codeplugs REST API, for scripts and
+// automation. The types and methods in client_gen.go are generated from the
+// server's OpenAPI document; run go generate after changing api/routes.go.
+//
+// Generated struct fields are omitempty, so a PATCH can't set a field to its
+// zero value; use the PUT method for that.
+package client
+
+//go:generate go run gen.go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client calls the API at BaseURL, e.g. http://localhost:8080
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	Header     http.Header // Sent with every request
+	Token      string      // API token, needed once the server has accounts
+	Project    string      // Project to act on; empty for the default one
+}
+
+// New returns a client for the server at baseURL
+func New(baseURL string) *Client {
+	return &Client{BaseURL: strings.TrimSuffix(baseURL, "/"), HTTPClient: http.DefaultClient, Header: http.Header{}}
+}
+
+// projectPath moves a project-scoped path under the client's Project
+func (c *Client) projectPath(path string) string {
+	if c.Project == "" {
+		return path
+	}
+	return "/api/projects/" + url.PathEscape(c.Project) + strings.TrimPrefix(path, "/api")
+}
+
+// Error is a failed call, decoded from the JSONResponse envelope
+type Error struct {
+	StatusCode int
+	Message    string
+	Errors     []FieldError    // Field-level validation failures
+	Current    json.RawMessage // The current record when StatusCode is 412
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	for _, fe := range e.Errors {
+		msg += fmt.Sprintf("; %s: %s", fe.Field, fe.Message)
+	}
+	return fmt.Sprintf("codeplugs API: %d %s", e.StatusCode, msg)
+}
+
+type envelope struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Meta    json.RawMessage `json:"meta"`
+	Error   string          `json:"error"`
+	Errors  []FieldError    `json:"errors"`
+}
+
+type request struct {
+	method      string
+	path        string
+	query       url.Values
+	header      http.Header
+	body        io.Reader
+	contentType string
+}
+
+func (r *request) setJSON(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	r.body, r.contentType = bytes.NewReader(b), "application/json"
+	return nil
+}
+
+func (r *request) setMultipart(fields url.Values, file io.Reader, fileName string) error {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for name, values := range fields {
+		for _, v := range values {
+			if err := mw.WriteField(name, v); err != nil {
+				return err
+			}
+		}
+	}
+	if file != nil {
+		if fileName == "" {
+			fileName = "upload"
+		}
+		part, err := mw.CreateFormFile("file", fileName)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(part, file); err != nil {
+			return err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return err
+	}
+	r.body, r.contentType = &buf, mw.FormDataContentType()
+	return nil
+}
+
+// send performs req, turning any non-2xx response into an *Error
+func (c *Client) send(ctx context.Context, req request) (*http.Response, error) {
+	u := c.BaseURL + req.path
+	if len(req.query) > 0 {
+		u += "?" + req.query.Encode()
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, u, req.body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range c.Header {
+		httpReq.Header[k] = v
+	}
+	for k, v := range req.header {
+		httpReq.Header[k] = v
+	}
+	if c.Token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	if req.contentType != "" {
+		httpReq.Header.Set("Content-Type", req.contentType)
+	}
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		apiErr := &Error{StatusCode: resp.StatusCode, Message: resp.Status}
+		var env envelope
+		if json.NewDecoder(resp.Body).Decode(&env) == nil {
+			if env.Error != "" {
+				apiErr.Message = env.Error
+			}
+			apiErr.Errors = env.Errors
+			if resp.StatusCode == http.StatusPreconditionFailed {
+				apiErr.Current = env.Data
+			}
+		}
+		return nil, apiErr
+	}
+	return resp, nil
+}
+
+// call performs req and decodes the envelope's data and meta
+func (c *Client) call(ctx context.Context, req request, data, meta interface{}) error {
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var env envelope
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		return fmt.Errorf("decoding %s %s: %w", req.method, req.path, err)
+	}
+	if !env.Success {
+		return &Error{StatusCode: resp.StatusCode, Message: env.Error, Errors: env.Errors}
+	}
+	if data != nil && len(env.Data) > 0 {
+		if err := json.Unmarshal(env.Data, data); err != nil {
+			return fmt.Errorf("decoding %s %s data: %w", req.method, req.path, err)
+		}
+	}
+	if meta != nil && len(env.Meta) > 0 {
+		if err := json.Unmarshal(env.Meta, meta); err != nil {
+			return fmt.Errorf("decoding %s %s meta: %w", req.method, req.path, err)
+		}
+	}
+	return nil
+}
+
+// download performs req and returns the raw body, which the caller closes
+func (c *Client) download(ctx context.Context, req request) (io.ReadCloser, error) {
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
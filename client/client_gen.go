@@ -0,0 +1,2006 @@
+// Code generated by openapigen from the API's OpenAPI document. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+var (
+	_ = json.RawMessage{}
+	_ = strconv.Itoa
+	_ = time.Time{}
+	_ io.Reader
+)
+
+type APIToken struct {
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	ID         int        `json:"id,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	Name       string     `json:"name,omitempty"`
+	Prefix     string     `json:"prefix,omitempty"`
+	UserID     int        `json:"user_id,omitempty"`
+}
+
+type Channel struct {
+	CreatedAt          *time.Time                   `json:"CreatedAt,omitempty"`
+	DeletedAt          *time.Time                   `json:"DeletedAt,omitempty"`
+	ID                 int                          `json:"ID,omitempty"`
+	UpdatedAt          *time.Time                   `json:"UpdatedAt,omitempty"`
+	AnalogAPRSPTTMode  int                          `json:"analog_aprs_ptt_mode,omitempty"`
+	APRSReceive        bool                         `json:"aprs_receive,omitempty"`
+	APRSReportChannel  int                          `json:"aprs_report_channel,omitempty"`
+	APRSReportType     string                       `json:"aprs_report_type,omitempty"`
+	AutoScan           bool                         `json:"auto_scan,omitempty"`
+	Bandwidth          string                       `json:"bandwidth,omitempty"`
+	ColorCode          int                          `json:"color_code,omitempty"`
+	Contact            *Contact                     `json:"contact,omitempty"`
+	ContactID          *int                         `json:"contact_id,omitempty"`
+	CtcDCSDecode       string                       `json:"ctc_dcs_decode,omitempty"`
+	CtcDCSEncode       string                       `json:"ctc_dcs_encode,omitempty"`
+	DigitalAPRSPTTMode int                          `json:"digital_aprs_ptt_mode,omitempty"`
+	DirectDualMode     bool                         `json:"direct_dual_mode,omitempty"`
+	DstarDvCode        int                          `json:"dstar_dv_code,omitempty"`
+	DstarGateway       string                       `json:"dstar_gateway,omitempty"`
+	DstarOwnCall       string                       `json:"dstar_own_call,omitempty"`
+	DstarRpt1Call      string                       `json:"dstar_rpt1_call,omitempty"`
+	DstarRpt2Call      string                       `json:"dstar_rpt2_call,omitempty"`
+	DstarUrCall        string                       `json:"dstar_ur_call,omitempty"`
+	DTMFID             string                       `json:"dtmf_id,omitempty"`
+	EmergencyAck       bool                         `json:"emergency_ack,omitempty"`
+	EmergencyIndicator bool                         `json:"emergency_indicator,omitempty"`
+	EmergencySystem    string                       `json:"emergency_system,omitempty"`
+	Encryption         string                       `json:"encryption,omitempty"`
+	EncryptionID       int                          `json:"encryption_id,omitempty"`
+	ForbidTalkaround   bool                         `json:"forbid_talkaround,omitempty"`
+	ForbidTx           bool                         `json:"forbid_tx,omitempty"`
+	LoneWork           bool                         `json:"lone_work,omitempty"`
+	Mode               string                       `json:"mode,omitempty"`
+	Name               string                       `json:"name,omitempty"`
+	Notes              string                       `json:"notes,omitempty"`
+	NXDNGroupID        int                          `json:"nxdn_group_id,omitempty"`
+	NXDNRAN            int                          `json:"nxdn_ran,omitempty"`
+	OptionalSignal     string                       `json:"optional_signal,omitempty"`
+	P25Nac             string                       `json:"p25_nac,omitempty"`
+	P25TalkgroupID     int                          `json:"p25_talkgroup_id,omitempty"`
+	P25UnitID          int                          `json:"p25_unit_id,omitempty"`
+	Power              string                       `json:"power,omitempty"`
+	PrivateConfirm     bool                         `json:"private_confirm,omitempty"`
+	Protocol           string                       `json:"protocol,omitempty"`
+	PTTID              string                       `json:"ptt_id,omitempty"`
+	PTTIDDisplay       bool                         `json:"ptt_id_display,omitempty"`
+	RadioID            string                       `json:"radio_id,omitempty"`
+	RepeaterSlot       int                          `json:"repeater_slot,omitempty"`
+	RxDCS              string                       `json:"rx_dcs,omitempty"`
+	RxFrequency        float64                      `json:"rx_frequency,omitempty"`
+	RxGroup            string                       `json:"rx_group,omitempty"`
+	RxSquelchMode      string                       `json:"rx_squelch_mode,omitempty"`
+	RxTone             string                       `json:"rx_tone,omitempty"`
+	ScanList           string                       `json:"scan_list,omitempty"`
+	Scramble           string                       `json:"scramble,omitempty"`
+	ShortDataConfirm   bool                         `json:"short_data_confirm,omitempty"`
+	SignalingType      string                       `json:"signaling_type,omitempty"`
+	Skip               bool                         `json:"skip,omitempty"`
+	SortOrder          int                          `json:"sort_order,omitempty"`
+	SquelchLevel       int                          `json:"squelch_level,omitempty"`
+	SquelchType        string                       `json:"squelch_type,omitempty"`
+	TalkAround         bool                         `json:"talk_around,omitempty"`
+	TimeSlot           int                          `json:"time_slot,omitempty"`
+	Tone               string                       `json:"tone,omitempty"`
+	Tone2ID            string                       `json:"tone2_id,omitempty"`
+	Tone5ID            string                       `json:"tone5_id,omitempty"`
+	TxContact          string                       `json:"tx_contact,omitempty"`
+	TxDCS              string                       `json:"tx_dcs,omitempty"`
+	TxFrequency        float64                      `json:"tx_frequency,omitempty"`
+	TxPermit           string                       `json:"tx_permit,omitempty"`
+	TxTone             string                       `json:"tx_tone,omitempty"`
+	Type               string                       `json:"type,omitempty"`
+	VendorExtras       map[string]map[string]string `json:"vendor_extras,omitempty"`
+	Version            int                          `json:"version,omitempty"`
+	VoxFunction        bool                         `json:"vox_function,omitempty"`
+	WorkAlone          bool                         `json:"work_alone,omitempty"`
+}
+
+type ChannelBulkRequest struct {
+	Filter ChannelBulkRequestFilter   `json:"filter,omitempty"`
+	Set    map[string]json.RawMessage `json:"set,omitempty"`
+}
+
+type ChannelBulkResult struct {
+	Changed int64 `json:"changed,omitempty"`
+	IDs     []int `json:"ids,omitempty"`
+	Matched int64 `json:"matched,omitempty"`
+}
+
+type ChannelRef struct {
+	ID   int    `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type ChannelReorderRequest struct {
+	IDs []int `json:"ids,omitempty"`
+}
+
+type Contact struct {
+	CallAlert    string                       `json:"CallAlert,omitempty"`
+	CreatedAt    *time.Time                   `json:"CreatedAt,omitempty"`
+	DMRID        int                          `json:"DMRID,omitempty"`
+	DeletedAt    *time.Time                   `json:"DeletedAt,omitempty"`
+	ID           int                          `json:"ID,omitempty"`
+	Name         string                       `json:"Name,omitempty"`
+	Type         string                       `json:"Type,omitempty"`
+	UpdatedAt    *time.Time                   `json:"UpdatedAt,omitempty"`
+	VendorExtras map[string]map[string]string `json:"vendor_extras,omitempty"`
+	Version      int                          `json:"version,omitempty"`
+}
+
+type ContactList struct {
+	CreatedAt   *time.Time         `json:"CreatedAt,omitempty"`
+	DeletedAt   *time.Time         `json:"DeletedAt,omitempty"`
+	Description string             `json:"Description,omitempty"`
+	Entries     []ContactListEntry `json:"Entries,omitempty"`
+	ID          int                `json:"ID,omitempty"`
+	Name        string             `json:"Name,omitempty"`
+	Rules       []ContactListRule  `json:"Rules,omitempty"`
+	UpdatedAt   *time.Time         `json:"UpdatedAt,omitempty"`
+	Version     int                `json:"version,omitempty"`
+}
+
+type ContactListEntry struct {
+	ContactListID int        `json:"ContactListID,omitempty"`
+	CreatedAt     *time.Time `json:"CreatedAt,omitempty"`
+	DMRID         int        `json:"DMRID,omitempty"`
+	DeletedAt     *time.Time `json:"DeletedAt,omitempty"`
+	ID            int        `json:"ID,omitempty"`
+	UpdatedAt     *time.Time `json:"UpdatedAt,omitempty"`
+}
+
+type ContactListEntryPage struct {
+	Data []ContactListEntry `json:"data,omitempty"`
+	Meta ListMeta           `json:"meta,omitempty"`
+}
+
+type ContactListRule struct {
+	ContactListID int        `json:"ContactListID,omitempty"`
+	CreatedAt     *time.Time `json:"CreatedAt,omitempty"`
+	DeletedAt     *time.Time `json:"DeletedAt,omitempty"`
+	Field         string     `json:"Field,omitempty"`
+	Group         int        `json:"Group,omitempty"`
+	ID            int        `json:"ID,omitempty"`
+	UpdatedAt     *time.Time `json:"UpdatedAt,omitempty"`
+	Value         string     `json:"Value,omitempty"`
+}
+
+type ContactListing struct {
+	Data []Contact `json:"data,omitempty"`
+}
+
+type ContactOverride struct {
+	CreatedAt *time.Time `json:"CreatedAt,omitempty"`
+	DeletedAt *time.Time `json:"DeletedAt,omitempty"`
+	ID        int        `json:"ID,omitempty"`
+	UpdatedAt *time.Time `json:"UpdatedAt,omitempty"`
+	CallAlert string     `json:"call_alert,omitempty"`
+	DMRID     int        `json:"dmr_id,omitempty"`
+	Remarks   *string    `json:"remarks,omitempty"`
+}
+
+type ContactPin struct {
+	CreatedAt *time.Time `json:"CreatedAt,omitempty"`
+	DeletedAt *time.Time `json:"DeletedAt,omitempty"`
+	ID        int        `json:"ID,omitempty"`
+	UpdatedAt *time.Time `json:"UpdatedAt,omitempty"`
+	DMRID     int        `json:"dmr_id,omitempty"`
+	Note      string     `json:"note,omitempty"`
+}
+
+type ContactSuggestion struct {
+	ContactID int     `json:"contact_id,omitempty"`
+	DMRID     int     `json:"dmr_id,omitempty"`
+	Name      string  `json:"name,omitempty"`
+	Network   string  `json:"network,omitempty"`
+	Score     float64 `json:"score,omitempty"`
+	Source    string  `json:"source,omitempty"`
+}
+
+type ContactSync struct {
+	CreatedAt *time.Time          `json:"CreatedAt,omitempty"`
+	DeletedAt *time.Time          `json:"DeletedAt,omitempty"`
+	ID        int                 `json:"ID,omitempty"`
+	UpdatedAt *time.Time          `json:"UpdatedAt,omitempty"`
+	Added     int                 `json:"added,omitempty"`
+	Changed   int                 `json:"changed,omitempty"`
+	Changes   []ContactSyncChange `json:"changes,omitempty"`
+	Error     string              `json:"error,omitempty"`
+	Removed   int                 `json:"removed,omitempty"`
+	Source    string              `json:"source,omitempty"`
+	Status    string              `json:"status,omitempty"`
+	Unchanged int                 `json:"unchanged,omitempty"`
+}
+
+type ContactSyncChange struct {
+	Action        string `json:"action,omitempty"`
+	Callsign      string `json:"callsign,omitempty"`
+	ContactSyncID int    `json:"contact_sync_id,omitempty"`
+	Details       string `json:"details,omitempty"`
+	DMRID         int    `json:"dmr_id,omitempty"`
+	ID            int    `json:"id,omitempty"`
+}
+
+type CreatedToken struct {
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	ID         int        `json:"id,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	Name       string     `json:"name,omitempty"`
+	Prefix     string     `json:"prefix,omitempty"`
+	Token      string     `json:"token,omitempty"`
+	UserID     int        `json:"user_id,omitempty"`
+}
+
+type DStarRepeater struct {
+	CreatedAt   *time.Time `json:"CreatedAt,omitempty"`
+	DeletedAt   *time.Time `json:"DeletedAt,omitempty"`
+	ID          int        `json:"ID,omitempty"`
+	UpdatedAt   *time.Time `json:"UpdatedAt,omitempty"`
+	CallSign    string     `json:"call_sign,omitempty"`
+	Duplex      string     `json:"duplex,omitempty"`
+	Frequency   float64    `json:"frequency,omitempty"`
+	GatewayCall string     `json:"gateway_call,omitempty"`
+	GroupName   string     `json:"group_name,omitempty"`
+	GroupNo     int        `json:"group_no,omitempty"`
+	Latitude    float64    `json:"latitude,omitempty"`
+	Longitude   float64    `json:"longitude,omitempty"`
+	Mode        string     `json:"mode,omitempty"`
+	Name        string     `json:"name,omitempty"`
+	Offset      float64    `json:"offset,omitempty"`
+	SubName     string     `json:"sub_name,omitempty"`
+	Tone        string     `json:"tone,omitempty"`
+	Type        string     `json:"type,omitempty"`
+	UtcOffset   string     `json:"utc_offset,omitempty"`
+}
+
+type DedupeRequest struct {
+	CanonicalID  int   `json:"canonical_id,omitempty"`
+	DuplicateIDs []int `json:"duplicate_ids,omitempty"`
+}
+
+type DedupeResult struct {
+	ChannelsUpdated int64            `json:"channels_updated,omitempty"`
+	Merged          []DuplicateGroup `json:"merged,omitempty"`
+}
+
+type DigitalContact struct {
+	CallAlert string     `json:"CallAlert,omitempty"`
+	Callsign  string     `json:"Callsign,omitempty"`
+	City      string     `json:"City,omitempty"`
+	Country   string     `json:"Country,omitempty"`
+	CreatedAt *time.Time `json:"CreatedAt,omitempty"`
+	DMRID     int        `json:"DMRID,omitempty"`
+	DeletedAt *time.Time `json:"DeletedAt,omitempty"`
+	ID        int        `json:"ID,omitempty"`
+	Name      string     `json:"Name,omitempty"`
+	Remarks   string     `json:"Remarks,omitempty"`
+	RetiredAt *time.Time `json:"RetiredAt,omitempty"`
+	State     string     `json:"State,omitempty"`
+	UpdatedAt *time.Time `json:"UpdatedAt,omitempty"`
+}
+
+type DigitalContactPage struct {
+	Data []DigitalContact `json:"data,omitempty"`
+	Meta ListMeta         `json:"meta,omitempty"`
+}
+
+type DuplicateGroup struct {
+	Canonical  Contact   `json:"canonical,omitempty"`
+	Channels   int64     `json:"channels,omitempty"`
+	DMRID      int       `json:"dmr_id,omitempty"`
+	Duplicates []Contact `json:"duplicates,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+}
+
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type FilterListRequest struct {
+	Description string            `json:"Description,omitempty"`
+	ID          int               `json:"ID,omitempty"`
+	Name        string            `json:"Name,omitempty"`
+	Rules       []ContactListRule `json:"Rules,omitempty"`
+	Version     int               `json:"version,omitempty"`
+}
+
+type ImportResult struct {
+	Count    int    `json:"count,omitempty"`
+	Imported int    `json:"imported,omitempty"`
+	Message  string `json:"message,omitempty"`
+	Skipped  int    `json:"skipped,omitempty"`
+}
+
+type JSONResponse struct {
+	Data    json.RawMessage `json:"data,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	Errors  []FieldError    `json:"errors,omitempty"`
+	Meta    json.RawMessage `json:"meta,omitempty"`
+	Success bool            `json:"success,omitempty"`
+}
+
+type ListMeta struct {
+	Limit      int    `json:"limit,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Page       int    `json:"page,omitempty"`
+	Total      int64  `json:"total,omitempty"`
+}
+
+type ListOperation struct {
+	Description string   `json:"description,omitempty"`
+	Exclude     []string `json:"exclude,omitempty"`
+	Expression  string   `json:"expression,omitempty"`
+	Intersect   []string `json:"intersect,omitempty"`
+	Into        string   `json:"into,omitempty"`
+	Union       []string `json:"union,omitempty"`
+}
+
+type ListOperationResult struct {
+	Count      int              `json:"count,omitempty"`
+	Expression string           `json:"expression,omitempty"`
+	Inputs     map[string]int64 `json:"inputs,omitempty"`
+	List       *ContactList     `json:"list,omitempty"`
+}
+
+type LoginRequest struct {
+	Password string `json:"password,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+type MergeRequest struct {
+	ContactID     int `json:"contact_id,omitempty"`
+	DMRID         int `json:"dmr_id,omitempty"`
+	PlaceholderID int `json:"placeholder_id,omitempty"`
+}
+
+type MergeResult struct {
+	ChannelsUpdated int64   `json:"channels_updated,omitempty"`
+	Contact         Contact `json:"contact,omitempty"`
+}
+
+type NXDNContact struct {
+	CreatedAt *time.Time `json:"CreatedAt,omitempty"`
+	DeletedAt *time.Time `json:"DeletedAt,omitempty"`
+	ID        int        `json:"ID,omitempty"`
+	UpdatedAt *time.Time `json:"UpdatedAt,omitempty"`
+	Callsign  string     `json:"callsign,omitempty"`
+	City      string     `json:"city,omitempty"`
+	Country   string     `json:"country,omitempty"`
+	Name      string     `json:"name,omitempty"`
+	Remarks   string     `json:"remarks,omitempty"`
+	State     string     `json:"state,omitempty"`
+	UnitID    int        `json:"unit_id,omitempty"`
+}
+
+type NXDNContactPage struct {
+	Data []NXDNContact `json:"data,omitempty"`
+	Meta ListMeta      `json:"meta,omitempty"`
+}
+
+type NXDNTalkgroup struct {
+	CreatedAt *time.Time `json:"CreatedAt,omitempty"`
+	DeletedAt *time.Time `json:"DeletedAt,omitempty"`
+	ID        int        `json:"ID,omitempty"`
+	UpdatedAt *time.Time `json:"UpdatedAt,omitempty"`
+	Name      string     `json:"name,omitempty"`
+	TGID      int        `json:"tg_id,omitempty"`
+}
+
+type P25Talkgroup struct {
+	CreatedAt *time.Time `json:"CreatedAt,omitempty"`
+	DeletedAt *time.Time `json:"DeletedAt,omitempty"`
+	ID        int        `json:"ID,omitempty"`
+	UpdatedAt *time.Time `json:"UpdatedAt,omitempty"`
+	Name      string     `json:"name,omitempty"`
+	TGID      int        `json:"tg_id,omitempty"`
+}
+
+type PlaceholderResolution struct {
+	Candidates []TalkgroupCatalog `json:"candidates,omitempty"`
+	ContactID  int                `json:"contact_id,omitempty"`
+	MergedInto int                `json:"merged_into,omitempty"`
+	Name       string             `json:"name,omitempty"`
+	Network    string             `json:"network,omitempty"`
+	NewDMRID   int                `json:"new_dmr_id,omitempty"`
+	OldDMRID   int                `json:"old_dmr_id,omitempty"`
+	Status     string             `json:"status,omitempty"`
+}
+
+type Profile struct {
+	Description       string   `json:"description,omitempty"`
+	Format            string   `json:"format,omitempty"`
+	Limit             int      `json:"limit,omitempty"`
+	Name              string   `json:"name,omitempty"`
+	PriorityCountries []string `json:"priority_countries,omitempty"`
+	PriorityStates    []string `json:"priority_states,omitempty"`
+	Radio             string   `json:"radio,omitempty"`
+	UseList           string   `json:"use_list,omitempty"`
+	Zones             []string `json:"zones,omitempty"`
+}
+
+type Project struct {
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+	ClonedFrom  string     `json:"cloned_from,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	Description string     `json:"description,omitempty"`
+	ID          string     `json:"id,omitempty"`
+	Name        string     `json:"name,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+type ProjectRequest struct {
+	Archived    *bool   `json:"archived,omitempty"`
+	CloneFrom   string  `json:"clone_from,omitempty"`
+	Description *string `json:"description,omitempty"`
+	ID          string  `json:"id,omitempty"`
+	Name        *string `json:"name,omitempty"`
+}
+
+type RoamingChannel struct {
+	CreatedAt   *time.Time `json:"CreatedAt,omitempty"`
+	DeletedAt   *time.Time `json:"DeletedAt,omitempty"`
+	ID          int        `json:"ID,omitempty"`
+	UpdatedAt   *time.Time `json:"UpdatedAt,omitempty"`
+	ColorCode   int        `json:"color_code,omitempty"`
+	Name        string     `json:"name,omitempty"`
+	RxFrequency float64    `json:"rx_frequency,omitempty"`
+	TimeSlot    int        `json:"time_slot,omitempty"`
+	TxFrequency float64    `json:"tx_frequency,omitempty"`
+}
+
+type RoamingZone struct {
+	CreatedAt *time.Time       `json:"CreatedAt,omitempty"`
+	DeletedAt *time.Time       `json:"DeletedAt,omitempty"`
+	ID        int              `json:"ID,omitempty"`
+	UpdatedAt *time.Time       `json:"UpdatedAt,omitempty"`
+	Channels  []RoamingChannel `json:"channels,omitempty"`
+	Name      string           `json:"name,omitempty"`
+}
+
+type ScanList struct {
+	CreatedAt *time.Time `json:"CreatedAt,omitempty"`
+	DeletedAt *time.Time `json:"DeletedAt,omitempty"`
+	ID        int        `json:"ID,omitempty"`
+	UpdatedAt *time.Time `json:"UpdatedAt,omitempty"`
+	Channels  []Channel  `json:"channels,omitempty"`
+	Name      string     `json:"name,omitempty"`
+	Version   int        `json:"version,omitempty"`
+}
+
+type ScanListAssignRequest struct {
+	ChannelIDs []int `json:"channel_ids,omitempty"`
+	ScanListID int   `json:"scan_list_id,omitempty"`
+}
+
+type SessionInfo struct {
+	AuthEnabled bool       `json:"auth_enabled,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	User        *User      `json:"user,omitempty"`
+}
+
+type TalkgroupCatalog struct {
+	Country     string     `json:"Country,omitempty"`
+	CreatedAt   *time.Time `json:"CreatedAt,omitempty"`
+	DeletedAt   *time.Time `json:"DeletedAt,omitempty"`
+	Description string     `json:"Description,omitempty"`
+	ID          int        `json:"ID,omitempty"`
+	Name        string     `json:"Name,omitempty"`
+	Network     string     `json:"Network,omitempty"`
+	TGID        int        `json:"TGID,omitempty"`
+	UpdatedAt   *time.Time `json:"UpdatedAt,omitempty"`
+}
+
+type TalkgroupCatalogPage struct {
+	Data []TalkgroupCatalog `json:"data,omitempty"`
+	Meta ListMeta           `json:"meta,omitempty"`
+}
+
+type TokenRequest struct {
+	Name string `json:"name,omitempty"`
+}
+
+type UnresolvedContact struct {
+	CallAlert    string                       `json:"CallAlert,omitempty"`
+	CreatedAt    *time.Time                   `json:"CreatedAt,omitempty"`
+	DMRID        int                          `json:"DMRID,omitempty"`
+	DeletedAt    *time.Time                   `json:"DeletedAt,omitempty"`
+	ID           int                          `json:"ID,omitempty"`
+	Name         string                       `json:"Name,omitempty"`
+	Type         string                       `json:"Type,omitempty"`
+	UpdatedAt    *time.Time                   `json:"UpdatedAt,omitempty"`
+	Channels     []ChannelRef                 `json:"channels,omitempty"`
+	Suggestions  []ContactSuggestion          `json:"suggestions,omitempty"`
+	VendorExtras map[string]map[string]string `json:"vendor_extras,omitempty"`
+	Version      int                          `json:"version,omitempty"`
+}
+
+type User struct {
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	ID        int        `json:"id,omitempty"`
+	Role      string     `json:"role,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	Username  string     `json:"username,omitempty"`
+}
+
+type UserRequest struct {
+	Password *string `json:"password,omitempty"`
+	Role     *string `json:"role,omitempty"`
+	Username string  `json:"username,omitempty"`
+}
+
+type Zone struct {
+	CreatedAt    *time.Time                   `json:"CreatedAt,omitempty"`
+	DeletedAt    *time.Time                   `json:"DeletedAt,omitempty"`
+	ID           int                          `json:"ID,omitempty"`
+	UpdatedAt    *time.Time                   `json:"UpdatedAt,omitempty"`
+	Channels     []Channel                    `json:"channels,omitempty"`
+	Name         string                       `json:"name,omitempty"`
+	VendorExtras map[string]map[string]string `json:"vendor_extras,omitempty"`
+	Version      int                          `json:"version,omitempty"`
+}
+
+type ChannelBulkRequestFilter struct {
+	Band     []string `json:"band,omitempty"`
+	Contact  []string `json:"contact,omitempty"`
+	IDs      []int    `json:"ids,omitempty"`
+	MaxFreq  float64  `json:"max_freq,omitempty"`
+	MinFreq  float64  `json:"min_freq,omitempty"`
+	Mode     []string `json:"mode,omitempty"`
+	Protocol []string `json:"protocol,omitempty"`
+	Search   string   `json:"search,omitempty"`
+	Skip     *bool    `json:"skip,omitempty"`
+	Zone     []string `json:"zone,omitempty"`
+}
+
+// Login calls POST /api/auth/login: Log in and set the session cookie.
+func (c *Client) Login(ctx context.Context, body LoginRequest) (SessionInfo, error) {
+	req := request{method: "POST", path: "/api/auth/login", query: url.Values{}, header: http.Header{}}
+	if err := req.setJSON(body); err != nil {
+		return SessionInfo{}, err
+	}
+	var data SessionInfo
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// Logout calls POST /api/auth/logout: End the session and clear its cookie.
+func (c *Client) Logout(ctx context.Context) error {
+	req := request{method: "POST", path: "/api/auth/logout", query: url.Values{}, header: http.Header{}}
+	return c.call(ctx, req, nil, nil)
+}
+
+// GetSession calls GET /api/auth/session: Whether login is required, and the logged-in account.
+func (c *Client) GetSession(ctx context.Context) (SessionInfo, error) {
+	req := request{method: "GET", path: "/api/auth/session", query: url.Values{}, header: http.Header{}}
+	var data SessionInfo
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// ListAPITokens calls GET /api/auth/tokens: List your API tokens.
+func (c *Client) ListAPITokens(ctx context.Context) ([]APIToken, error) {
+	req := request{method: "GET", path: "/api/auth/tokens", query: url.Values{}, header: http.Header{}}
+	var data []APIToken
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// CreateAPIToken calls POST /api/auth/tokens: Create an API token acting as you; the secret is only shown now.
+func (c *Client) CreateAPIToken(ctx context.Context, body TokenRequest) (CreatedToken, error) {
+	req := request{method: "POST", path: "/api/auth/tokens", query: url.Values{}, header: http.Header{}}
+	if err := req.setJSON(body); err != nil {
+		return CreatedToken{}, err
+	}
+	var data CreatedToken
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// RevokeAPITokenParams are the parameters of RevokeAPIToken.
+type RevokeAPITokenParams struct {
+	ID int
+}
+
+// RevokeAPIToken calls DELETE /api/auth/tokens: Revoke one of your API tokens.
+func (c *Client) RevokeAPIToken(ctx context.Context, params *RevokeAPITokenParams) error {
+	req := request{method: "DELETE", path: "/api/auth/tokens", query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+	}
+	return c.call(ctx, req, nil, nil)
+}
+
+// GetChannelParams are the parameters of GetChannel.
+type GetChannelParams struct {
+	ID int
+}
+
+// GetChannel calls GET /api/channels: Get a channel.
+func (c *Client) GetChannel(ctx context.Context, params *GetChannelParams) (Channel, error) {
+	req := request{method: "GET", path: c.projectPath("/api/channels"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+	}
+	var data Channel
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// ListChannelsParams are the parameters of ListChannels.
+type ListChannelsParams struct {
+	Mode     []string
+	Protocol []string
+	Band     []string
+	Zone     []string
+	Contact  []string
+	Search   string
+	MinFreq  float64
+	MaxFreq  float64
+	Skip     *bool
+	Sort     string
+	Order    string
+	Page     int
+	Limit    int
+	Cursor   string
+}
+
+// ListChannels calls GET /api/channels: List channels with filters, sorting and paging.
+func (c *Client) ListChannels(ctx context.Context, params *ListChannelsParams) ([]Channel, *ListMeta, error) {
+	req := request{method: "GET", path: c.projectPath("/api/channels"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		for _, v := range params.Mode {
+			req.query.Add("mode", v)
+		}
+		for _, v := range params.Protocol {
+			req.query.Add("protocol", v)
+		}
+		for _, v := range params.Band {
+			req.query.Add("band", v)
+		}
+		for _, v := range params.Zone {
+			req.query.Add("zone", v)
+		}
+		for _, v := range params.Contact {
+			req.query.Add("contact", v)
+		}
+		if params.Search != "" {
+			req.query.Set("search", params.Search)
+		}
+		if params.MinFreq != 0 {
+			req.query.Set("min_freq", strconv.FormatFloat(params.MinFreq, 'f', -1, 64))
+		}
+		if params.MaxFreq != 0 {
+			req.query.Set("max_freq", strconv.FormatFloat(params.MaxFreq, 'f', -1, 64))
+		}
+		if params.Skip != nil {
+			req.query.Set("skip", strconv.FormatBool(*params.Skip))
+		}
+		if params.Sort != "" {
+			req.query.Set("sort", params.Sort)
+		}
+		if params.Order != "" {
+			req.query.Set("order", params.Order)
+		}
+		if params.Page != 0 {
+			req.query.Set("page", strconv.Itoa(params.Page))
+		}
+		if params.Limit != 0 {
+			req.query.Set("limit", strconv.Itoa(params.Limit))
+		}
+		if params.Cursor != "" {
+			req.query.Set("cursor", params.Cursor)
+		}
+	}
+	var data []Channel
+	meta := new(ListMeta)
+	err := c.call(ctx, req, &data, meta)
+	return data, meta, err
+}
+
+// CreateChannelParams are the parameters of CreateChannel.
+type CreateChannelParams struct {
+	IfMatch string
+}
+
+// CreateChannel calls POST /api/channels: Create a channel; with an ID, replace it like PUT.
+func (c *Client) CreateChannel(ctx context.Context, params *CreateChannelParams, body Channel) (Channel, error) {
+	req := request{method: "POST", path: c.projectPath("/api/channels"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.IfMatch != "" {
+			req.header.Set("If-Match", params.IfMatch)
+		}
+	}
+	if err := req.setJSON(body); err != nil {
+		return Channel{}, err
+	}
+	var data Channel
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// ReplaceChannelParams are the parameters of ReplaceChannel.
+type ReplaceChannelParams struct {
+	ID      int
+	IfMatch string
+}
+
+// ReplaceChannel calls PUT /api/channels: Replace every field of a channel.
+func (c *Client) ReplaceChannel(ctx context.Context, params *ReplaceChannelParams, body Channel) (Channel, error) {
+	req := request{method: "PUT", path: c.projectPath("/api/channels"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+		if params.IfMatch != "" {
+			req.header.Set("If-Match", params.IfMatch)
+		}
+	}
+	if err := req.setJSON(body); err != nil {
+		return Channel{}, err
+	}
+	var data Channel
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// UpdateChannelParams are the parameters of UpdateChannel.
+type UpdateChannelParams struct {
+	ID      int
+	IfMatch string
+}
+
+// UpdateChannel calls PATCH /api/channels: Change only the fields present in the body.
+func (c *Client) UpdateChannel(ctx context.Context, params *UpdateChannelParams, body Channel) (Channel, error) {
+	req := request{method: "PATCH", path: c.projectPath("/api/channels"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+		if params.IfMatch != "" {
+			req.header.Set("If-Match", params.IfMatch)
+		}
+	}
+	if err := req.setJSON(body); err != nil {
+		return Channel{}, err
+	}
+	var data Channel
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// DeleteChannelParams are the parameters of DeleteChannel.
+type DeleteChannelParams struct {
+	ID      int
+	IfMatch string
+}
+
+// DeleteChannel calls DELETE /api/channels: Delete a channel.
+func (c *Client) DeleteChannel(ctx context.Context, params *DeleteChannelParams) error {
+	req := request{method: "DELETE", path: c.projectPath("/api/channels"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+		if params.IfMatch != "" {
+			req.header.Set("If-Match", params.IfMatch)
+		}
+	}
+	return c.call(ctx, req, nil, nil)
+}
+
+// BulkEditChannels calls PATCH /api/channels/bulk: Set the same fields on every channel matching a filter.
+func (c *Client) BulkEditChannels(ctx context.Context, body ChannelBulkRequest) (ChannelBulkResult, error) {
+	req := request{method: "PATCH", path: c.projectPath("/api/channels/bulk"), query: url.Values{}, header: http.Header{}}
+	if err := req.setJSON(body); err != nil {
+		return ChannelBulkResult{}, err
+	}
+	var data ChannelBulkResult
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// ReorderChannels calls POST /api/channels/reorder: Set the channel order.
+func (c *Client) ReorderChannels(ctx context.Context, body ChannelReorderRequest) error {
+	req := request{method: "POST", path: c.projectPath("/api/channels/reorder"), query: url.Values{}, header: http.Header{}}
+	if err := req.setJSON(body); err != nil {
+		return err
+	}
+	return c.call(ctx, req, nil, nil)
+}
+
+// GetContactParams are the parameters of GetContact.
+type GetContactParams struct {
+	ID int
+}
+
+// GetContact calls GET /api/contacts: Get a contact.
+func (c *Client) GetContact(ctx context.Context, params *GetContactParams) (Contact, error) {
+	req := request{method: "GET", path: c.projectPath("/api/contacts"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+	}
+	var data Contact
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// ListContacts calls GET /api/contacts: List talkgroup and private contacts.
+func (c *Client) ListContacts(ctx context.Context) (ContactListing, error) {
+	req := request{method: "GET", path: c.projectPath("/api/contacts"), query: url.Values{}, header: http.Header{}}
+	var data ContactListing
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// SearchRadioIDContactsParams are the parameters of SearchRadioIDContacts.
+type SearchRadioIDContactsParams struct {
+	Search string
+	Sort   string
+	Order  string
+	Cursor string
+	Page   int
+	Limit  int
+}
+
+// SearchRadioIDContacts calls GET /api/contacts: Search RadioID digital contacts.
+func (c *Client) SearchRadioIDContacts(ctx context.Context, params *SearchRadioIDContactsParams) (DigitalContactPage, error) {
+	req := request{method: "GET", path: c.projectPath("/api/contacts"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.Search != "" {
+			req.query.Set("search", params.Search)
+		}
+		if params.Sort != "" {
+			req.query.Set("sort", params.Sort)
+		}
+		if params.Order != "" {
+			req.query.Set("order", params.Order)
+		}
+		if params.Cursor != "" {
+			req.query.Set("cursor", params.Cursor)
+		}
+		if params.Page != 0 {
+			req.query.Set("page", strconv.Itoa(params.Page))
+		}
+		if params.Limit != 0 {
+			req.query.Set("limit", strconv.Itoa(params.Limit))
+		}
+	}
+	req.query.Set("source", "RadioID")
+	var data DigitalContactPage
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// CreateContactParams are the parameters of CreateContact.
+type CreateContactParams struct {
+	IfMatch string
+}
+
+// CreateContact calls POST /api/contacts: Create a contact; with an ID, replace it like PUT.
+func (c *Client) CreateContact(ctx context.Context, params *CreateContactParams, body Contact) (Contact, error) {
+	req := request{method: "POST", path: c.projectPath("/api/contacts"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.IfMatch != "" {
+			req.header.Set("If-Match", params.IfMatch)
+		}
+	}
+	if err := req.setJSON(body); err != nil {
+		return Contact{}, err
+	}
+	var data Contact
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// ReplaceContactParams are the parameters of ReplaceContact.
+type ReplaceContactParams struct {
+	ID      int
+	IfMatch string
+}
+
+// ReplaceContact calls PUT /api/contacts: Replace every field of a contact.
+func (c *Client) ReplaceContact(ctx context.Context, params *ReplaceContactParams, body Contact) (Contact, error) {
+	req := request{method: "PUT", path: c.projectPath("/api/contacts"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+		if params.IfMatch != "" {
+			req.header.Set("If-Match", params.IfMatch)
+		}
+	}
+	if err := req.setJSON(body); err != nil {
+		return Contact{}, err
+	}
+	var data Contact
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// UpdateContactParams are the parameters of UpdateContact.
+type UpdateContactParams struct {
+	ID      int
+	IfMatch string
+}
+
+// UpdateContact calls PATCH /api/contacts: Change only the fields present in the body.
+func (c *Client) UpdateContact(ctx context.Context, params *UpdateContactParams, body Contact) (Contact, error) {
+	req := request{method: "PATCH", path: c.projectPath("/api/contacts"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+		if params.IfMatch != "" {
+			req.header.Set("If-Match", params.IfMatch)
+		}
+	}
+	if err := req.setJSON(body); err != nil {
+		return Contact{}, err
+	}
+	var data Contact
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// DeleteContactParams are the parameters of DeleteContact.
+type DeleteContactParams struct {
+	ID      int
+	IfMatch string
+}
+
+// DeleteContact calls DELETE /api/contacts: Delete a contact.
+func (c *Client) DeleteContact(ctx context.Context, params *DeleteContactParams) error {
+	req := request{method: "DELETE", path: c.projectPath("/api/contacts"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+		if params.IfMatch != "" {
+			req.header.Set("If-Match", params.IfMatch)
+		}
+	}
+	return c.call(ctx, req, nil, nil)
+}
+
+// ListDuplicateContacts calls GET /api/contacts/duplicates: List duplicate contact groups.
+func (c *Client) ListDuplicateContacts(ctx context.Context) ([]DuplicateGroup, error) {
+	req := request{method: "GET", path: c.projectPath("/api/contacts/duplicates"), query: url.Values{}, header: http.Header{}}
+	var data []DuplicateGroup
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// MergeAllDuplicateContacts calls POST /api/contacts/duplicates: Merge every duplicate group.
+func (c *Client) MergeAllDuplicateContacts(ctx context.Context) (DedupeResult, error) {
+	req := request{method: "POST", path: c.projectPath("/api/contacts/duplicates"), query: url.Values{}, header: http.Header{}}
+	req.query.Set("all", "true")
+	var data DedupeResult
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// MergeDuplicateContacts calls POST /api/contacts/duplicates: Merge duplicates into a canonical contact.
+func (c *Client) MergeDuplicateContacts(ctx context.Context, body DedupeRequest) (DedupeResult, error) {
+	req := request{method: "POST", path: c.projectPath("/api/contacts/duplicates"), query: url.Values{}, header: http.Header{}}
+	if err := req.setJSON(body); err != nil {
+		return DedupeResult{}, err
+	}
+	var data DedupeResult
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// MergePlaceholder calls POST /api/contacts/merge: Merge a placeholder into a contact or talkgroup ID.
+func (c *Client) MergePlaceholder(ctx context.Context, body MergeRequest) (MergeResult, error) {
+	req := request{method: "POST", path: c.projectPath("/api/contacts/merge"), query: url.Values{}, header: http.Header{}}
+	if err := req.setJSON(body); err != nil {
+		return MergeResult{}, err
+	}
+	var data MergeResult
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// ListContactOverridesParams are the parameters of ListContactOverrides.
+type ListContactOverridesParams struct {
+	DMRID int
+}
+
+// ListContactOverrides calls GET /api/contacts/overrides: List call alert and remarks overrides.
+func (c *Client) ListContactOverrides(ctx context.Context, params *ListContactOverridesParams) ([]ContactOverride, error) {
+	req := request{method: "GET", path: c.projectPath("/api/contacts/overrides"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.DMRID != 0 {
+			req.query.Set("dmr_id", strconv.Itoa(params.DMRID))
+		}
+	}
+	var data []ContactOverride
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// SaveContactOverride calls POST /api/contacts/overrides: Set a DMR ID's call alert and remarks.
+func (c *Client) SaveContactOverride(ctx context.Context, body ContactOverride) (ContactOverride, error) {
+	req := request{method: "POST", path: c.projectPath("/api/contacts/overrides"), query: url.Values{}, header: http.Header{}}
+	if err := req.setJSON(body); err != nil {
+		return ContactOverride{}, err
+	}
+	var data ContactOverride
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// DeleteContactOverrideParams are the parameters of DeleteContactOverride.
+type DeleteContactOverrideParams struct {
+	DMRID int
+}
+
+// DeleteContactOverride calls DELETE /api/contacts/overrides: Remove a DMR ID's override.
+func (c *Client) DeleteContactOverride(ctx context.Context, params *DeleteContactOverrideParams) error {
+	req := request{method: "DELETE", path: c.projectPath("/api/contacts/overrides"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.DMRID != 0 {
+			req.query.Set("dmr_id", strconv.Itoa(params.DMRID))
+		}
+	}
+	return c.call(ctx, req, nil, nil)
+}
+
+// ListContactPins calls GET /api/contacts/pins: List pinned DMR IDs.
+func (c *Client) ListContactPins(ctx context.Context) ([]ContactPin, error) {
+	req := request{method: "GET", path: c.projectPath("/api/contacts/pins"), query: url.Values{}, header: http.Header{}}
+	var data []ContactPin
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// PinContact calls POST /api/contacts/pins: Pin a DMR ID so exports always keep it.
+func (c *Client) PinContact(ctx context.Context, body ContactPin) (ContactPin, error) {
+	req := request{method: "POST", path: c.projectPath("/api/contacts/pins"), query: url.Values{}, header: http.Header{}}
+	if err := req.setJSON(body); err != nil {
+		return ContactPin{}, err
+	}
+	var data ContactPin
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// UnpinContactParams are the parameters of UnpinContact.
+type UnpinContactParams struct {
+	DMRID int
+}
+
+// UnpinContact calls DELETE /api/contacts/pins: Unpin a DMR ID.
+func (c *Client) UnpinContact(ctx context.Context, params *UnpinContactParams) error {
+	req := request{method: "DELETE", path: c.projectPath("/api/contacts/pins"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.DMRID != 0 {
+			req.query.Set("dmr_id", strconv.Itoa(params.DMRID))
+		}
+	}
+	return c.call(ctx, req, nil, nil)
+}
+
+// ResolvePlaceholdersParams are the parameters of ResolvePlaceholders.
+type ResolvePlaceholdersParams struct {
+	Network []string
+}
+
+// ResolvePlaceholders calls POST /api/contacts/resolve_placeholders: Match placeholder contacts against the talkgroup catalog.
+func (c *Client) ResolvePlaceholders(ctx context.Context, params *ResolvePlaceholdersParams) ([]PlaceholderResolution, error) {
+	req := request{method: "POST", path: c.projectPath("/api/contacts/resolve_placeholders"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		for _, v := range params.Network {
+			req.query.Add("network", v)
+		}
+	}
+	var data []PlaceholderResolution
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// GetContactSyncParams are the parameters of GetContactSync.
+type GetContactSyncParams struct {
+	ID       int
+	Callsign string
+	Action   string
+}
+
+// GetContactSync calls GET /api/contacts/syncs: Get a sync with its change log.
+func (c *Client) GetContactSync(ctx context.Context, params *GetContactSyncParams) (ContactSync, error) {
+	req := request{method: "GET", path: c.projectPath("/api/contacts/syncs"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+		if params.Callsign != "" {
+			req.query.Set("callsign", params.Callsign)
+		}
+		if params.Action != "" {
+			req.query.Set("action", params.Action)
+		}
+	}
+	var data ContactSync
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// ListContactSyncs calls GET /api/contacts/syncs: List RadioID syncs, newest first.
+func (c *Client) ListContactSyncs(ctx context.Context) ([]ContactSync, error) {
+	req := request{method: "GET", path: c.projectPath("/api/contacts/syncs"), query: url.Values{}, header: http.Header{}}
+	var data []ContactSync
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// ListUnresolvedContactsParams are the parameters of ListUnresolvedContacts.
+type ListUnresolvedContactsParams struct {
+	Suggestions int
+}
+
+// ListUnresolvedContacts calls GET /api/contacts/unresolved: List placeholder contacts with suggested talkgroups.
+func (c *Client) ListUnresolvedContacts(ctx context.Context, params *ListUnresolvedContactsParams) ([]UnresolvedContact, error) {
+	req := request{method: "GET", path: c.projectPath("/api/contacts/unresolved"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.Suggestions != 0 {
+			req.query.Set("suggestions", strconv.Itoa(params.Suggestions))
+		}
+	}
+	var data []UnresolvedContact
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// ListDStarRepeatersParams are the parameters of ListDStarRepeaters.
+type ListDStarRepeatersParams struct {
+	Type string
+}
+
+// ListDStarRepeaters calls GET /api/dstar/repeaters: List D-Star repeaters.
+func (c *Client) ListDStarRepeaters(ctx context.Context, params *ListDStarRepeatersParams) ([]DStarRepeater, error) {
+	req := request{method: "GET", path: c.projectPath("/api/dstar/repeaters"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.Type != "" {
+			req.query.Set("type", params.Type)
+		}
+	}
+	var data []DStarRepeater
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// SaveDStarRepeater calls POST /api/dstar/repeaters: Create a D-Star repeater, or update it when ID is set.
+func (c *Client) SaveDStarRepeater(ctx context.Context, body DStarRepeater) (DStarRepeater, error) {
+	req := request{method: "POST", path: c.projectPath("/api/dstar/repeaters"), query: url.Values{}, header: http.Header{}}
+	if err := req.setJSON(body); err != nil {
+		return DStarRepeater{}, err
+	}
+	var data DStarRepeater
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// DeleteDStarRepeaterParams are the parameters of DeleteDStarRepeater.
+type DeleteDStarRepeaterParams struct {
+	ID int
+}
+
+// DeleteDStarRepeater calls DELETE /api/dstar/repeaters: Delete a D-Star repeater.
+func (c *Client) DeleteDStarRepeater(ctx context.Context, params *DeleteDStarRepeaterParams) error {
+	req := request{method: "DELETE", path: c.projectPath("/api/dstar/repeaters"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+	}
+	return c.call(ctx, req, nil, nil)
+}
+
+// ExportCodeplugParams are the parameters of ExportCodeplug.
+type ExportCodeplugParams struct {
+	Format          string
+	Radio           string
+	ZoneID          []int
+	UseList         string
+	Limit           int
+	PriorityState   []string
+	PriorityCountry []string
+	Profile         string
+}
+
+// ExportCodeplug calls GET /api/export: Download the codeplug as a radio ZIP, CSV or database. X-Skipped-Channels lists P25 channels the format can't carry.
+func (c *Client) ExportCodeplug(ctx context.Context, params *ExportCodeplugParams) (io.ReadCloser, error) {
+	req := request{method: "GET", path: c.projectPath("/api/export"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.Format != "" {
+			req.query.Set("format", params.Format)
+		}
+		if params.Radio != "" {
+			req.query.Set("radio", params.Radio)
+		}
+		for _, v := range params.ZoneID {
+			req.query.Add("zone_id", strconv.Itoa(v))
+		}
+		if params.UseList != "" {
+			req.query.Set("use_list", params.UseList)
+		}
+		if params.Limit != 0 {
+			req.query.Set("limit", strconv.Itoa(params.Limit))
+		}
+		for _, v := range params.PriorityState {
+			req.query.Add("priority_state", v)
+		}
+		for _, v := range params.PriorityCountry {
+			req.query.Add("priority_country", v)
+		}
+		if params.Profile != "" {
+			req.query.Set("profile", params.Profile)
+		}
+	}
+	return c.download(ctx, req)
+}
+
+// ExportDatabase calls GET /api/export: Download a copy of the project database, without accounts.
+func (c *Client) ExportDatabase(ctx context.Context) (io.ReadCloser, error) {
+	req := request{method: "GET", path: c.projectPath("/api/export"), query: url.Values{}, header: http.Header{}}
+	req.query.Set("format", "db")
+	return c.download(ctx, req)
+}
+
+// ListExportProfiles calls GET /api/export/profiles: List the export profiles saved in codeplugs.yaml.
+func (c *Client) ListExportProfiles(ctx context.Context) ([]Profile, error) {
+	req := request{method: "GET", path: "/api/export/profiles", query: url.Values{}, header: http.Header{}}
+	var data []Profile
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// GetFilterListEntriesParams are the parameters of GetFilterListEntries.
+type GetFilterListEntriesParams struct {
+	ID     int
+	Search string
+	Page   int
+	Limit  int
+}
+
+// GetFilterListEntries calls GET /api/filter_lists: Page through a filter list's entries.
+func (c *Client) GetFilterListEntries(ctx context.Context, params *GetFilterListEntriesParams) (ContactListEntryPage, error) {
+	req := request{method: "GET", path: c.projectPath("/api/filter_lists"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+		if params.Search != "" {
+			req.query.Set("search", params.Search)
+		}
+		if params.Page != 0 {
+			req.query.Set("page", strconv.Itoa(params.Page))
+		}
+		if params.Limit != 0 {
+			req.query.Set("limit", strconv.Itoa(params.Limit))
+		}
+	}
+	var data ContactListEntryPage
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// GetFilterListIDsParams are the parameters of GetFilterListIDs.
+type GetFilterListIDsParams struct {
+	ID int
+}
+
+// GetFilterListIDs calls GET /api/filter_lists: Resolve a filter list to DMR IDs.
+func (c *Client) GetFilterListIDs(ctx context.Context, params *GetFilterListIDsParams) ([]int, error) {
+	req := request{method: "GET", path: c.projectPath("/api/filter_lists"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+	}
+	req.query.Set("mode", "ids")
+	var data []int
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// GetFilterListRulesParams are the parameters of GetFilterListRules.
+type GetFilterListRulesParams struct {
+	ID int
+}
+
+// GetFilterListRules calls GET /api/filter_lists: Get a filter list's rules.
+func (c *Client) GetFilterListRules(ctx context.Context, params *GetFilterListRulesParams) ([]ContactListRule, error) {
+	req := request{method: "GET", path: c.projectPath("/api/filter_lists"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+	}
+	req.query.Set("mode", "rules")
+	var data []ContactListRule
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// ListFilterLists calls GET /api/filter_lists: List filter lists with their rules.
+func (c *Client) ListFilterLists(ctx context.Context) ([]ContactList, error) {
+	req := request{method: "GET", path: c.projectPath("/api/filter_lists"), query: url.Values{}, header: http.Header{}}
+	var data []ContactList
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// SaveFilterListParams are the parameters of SaveFilterList.
+type SaveFilterListParams struct {
+	IfMatch string
+}
+
+// SaveFilterList calls POST /api/filter_lists: Create a filter list, or update it and replace its rules when ID is set.
+func (c *Client) SaveFilterList(ctx context.Context, params *SaveFilterListParams, body FilterListRequest) (ContactList, error) {
+	req := request{method: "POST", path: c.projectPath("/api/filter_lists"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.IfMatch != "" {
+			req.header.Set("If-Match", params.IfMatch)
+		}
+	}
+	if err := req.setJSON(body); err != nil {
+		return ContactList{}, err
+	}
+	var data ContactList
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// DeleteFilterListParams are the parameters of DeleteFilterList.
+type DeleteFilterListParams struct {
+	ID      int
+	IfMatch string
+}
+
+// DeleteFilterList calls DELETE /api/filter_lists: Delete a filter list.
+func (c *Client) DeleteFilterList(ctx context.Context, params *DeleteFilterListParams) error {
+	req := request{method: "DELETE", path: c.projectPath("/api/filter_lists"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+		if params.IfMatch != "" {
+			req.header.Set("If-Match", params.IfMatch)
+		}
+	}
+	return c.call(ctx, req, nil, nil)
+}
+
+// RunListOperation calls POST /api/filter_lists/ops: Combine filter lists by union, intersection and exclusion.
+func (c *Client) RunListOperation(ctx context.Context, body ListOperation) (ListOperationResult, error) {
+	req := request{method: "POST", path: c.projectPath("/api/filter_lists/ops"), query: url.Values{}, header: http.Header{}}
+	if err := req.setJSON(body); err != nil {
+		return ListOperationResult{}, err
+	}
+	var data ListOperationResult
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// ImportDataParams are the parameters of ImportData.
+type ImportDataParams struct {
+	File          io.Reader
+	FileName      string
+	Format        string
+	ImportType    string
+	ListName      string
+	Network       string
+	Overwrite     *bool
+	RadioPlatform string
+	SourceMode    string
+	Sync          *bool
+}
+
+// ImportData calls POST /api/import: Import a file or download into the codeplug.
+func (c *Client) ImportData(ctx context.Context, params *ImportDataParams) (json.RawMessage, error) {
+	req := request{method: "POST", path: c.projectPath("/api/import"), query: url.Values{}, header: http.Header{}}
+	fields := url.Values{}
+	var file io.Reader
+	var fileName string
+	if params != nil {
+		file, fileName = params.File, params.FileName
+		if params.Format != "" {
+			fields.Set("format", params.Format)
+		}
+		if params.ImportType != "" {
+			fields.Set("import_type", params.ImportType)
+		}
+		if params.ListName != "" {
+			fields.Set("list_name", params.ListName)
+		}
+		if params.Network != "" {
+			fields.Set("network", params.Network)
+		}
+		if params.Overwrite != nil {
+			fields.Set("overwrite", strconv.FormatBool(*params.Overwrite))
+		}
+		if params.RadioPlatform != "" {
+			fields.Set("radio_platform", params.RadioPlatform)
+		}
+		if params.SourceMode != "" {
+			fields.Set("source_mode", params.SourceMode)
+		}
+		if params.Sync != nil {
+			fields.Set("sync", strconv.FormatBool(*params.Sync))
+		}
+	}
+	if err := req.setMultipart(fields, file, fileName); err != nil {
+		return nil, err
+	}
+	var data json.RawMessage
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// ListNXDNContactsParams are the parameters of ListNXDNContacts.
+type ListNXDNContactsParams struct {
+	Search string
+	Page   int
+	Limit  int
+}
+
+// ListNXDNContacts calls GET /api/nxdn/contacts: List NXDN contacts.
+func (c *Client) ListNXDNContacts(ctx context.Context, params *ListNXDNContactsParams) (NXDNContactPage, error) {
+	req := request{method: "GET", path: c.projectPath("/api/nxdn/contacts"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.Search != "" {
+			req.query.Set("search", params.Search)
+		}
+		if params.Page != 0 {
+			req.query.Set("page", strconv.Itoa(params.Page))
+		}
+		if params.Limit != 0 {
+			req.query.Set("limit", strconv.Itoa(params.Limit))
+		}
+	}
+	var data NXDNContactPage
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// SaveNXDNContact calls POST /api/nxdn/contacts: Create a NXDN contact, or update it when ID is set.
+func (c *Client) SaveNXDNContact(ctx context.Context, body NXDNContact) (NXDNContact, error) {
+	req := request{method: "POST", path: c.projectPath("/api/nxdn/contacts"), query: url.Values{}, header: http.Header{}}
+	if err := req.setJSON(body); err != nil {
+		return NXDNContact{}, err
+	}
+	var data NXDNContact
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// DeleteNXDNContactParams are the parameters of DeleteNXDNContact.
+type DeleteNXDNContactParams struct {
+	ID int
+}
+
+// DeleteNXDNContact calls DELETE /api/nxdn/contacts: Delete a NXDN contact.
+func (c *Client) DeleteNXDNContact(ctx context.Context, params *DeleteNXDNContactParams) error {
+	req := request{method: "DELETE", path: c.projectPath("/api/nxdn/contacts"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+	}
+	return c.call(ctx, req, nil, nil)
+}
+
+// ListNXDNTalkgroups calls GET /api/nxdn/talkgroups: List NXDN talkgroups.
+func (c *Client) ListNXDNTalkgroups(ctx context.Context) ([]NXDNTalkgroup, error) {
+	req := request{method: "GET", path: c.projectPath("/api/nxdn/talkgroups"), query: url.Values{}, header: http.Header{}}
+	var data []NXDNTalkgroup
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// SaveNXDNTalkgroup calls POST /api/nxdn/talkgroups: Create a NXDN talkgroup, or update it when ID is set.
+func (c *Client) SaveNXDNTalkgroup(ctx context.Context, body NXDNTalkgroup) (NXDNTalkgroup, error) {
+	req := request{method: "POST", path: c.projectPath("/api/nxdn/talkgroups"), query: url.Values{}, header: http.Header{}}
+	if err := req.setJSON(body); err != nil {
+		return NXDNTalkgroup{}, err
+	}
+	var data NXDNTalkgroup
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// DeleteNXDNTalkgroupParams are the parameters of DeleteNXDNTalkgroup.
+type DeleteNXDNTalkgroupParams struct {
+	ID int
+}
+
+// DeleteNXDNTalkgroup calls DELETE /api/nxdn/talkgroups: Delete a NXDN talkgroup.
+func (c *Client) DeleteNXDNTalkgroup(ctx context.Context, params *DeleteNXDNTalkgroupParams) error {
+	req := request{method: "DELETE", path: c.projectPath("/api/nxdn/talkgroups"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+	}
+	return c.call(ctx, req, nil, nil)
+}
+
+// GetOpenAPI calls GET /api/openapi.json: This OpenAPI document.
+func (c *Client) GetOpenAPI(ctx context.Context) (io.ReadCloser, error) {
+	req := request{method: "GET", path: "/api/openapi.json", query: url.Values{}, header: http.Header{}}
+	return c.download(ctx, req)
+}
+
+// ListP25Talkgroups calls GET /api/p25/talkgroups: List P25 talkgroups.
+func (c *Client) ListP25Talkgroups(ctx context.Context) ([]P25Talkgroup, error) {
+	req := request{method: "GET", path: c.projectPath("/api/p25/talkgroups"), query: url.Values{}, header: http.Header{}}
+	var data []P25Talkgroup
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// SaveP25Talkgroup calls POST /api/p25/talkgroups: Create a P25 talkgroup, or update it when ID is set.
+func (c *Client) SaveP25Talkgroup(ctx context.Context, body P25Talkgroup) (P25Talkgroup, error) {
+	req := request{method: "POST", path: c.projectPath("/api/p25/talkgroups"), query: url.Values{}, header: http.Header{}}
+	if err := req.setJSON(body); err != nil {
+		return P25Talkgroup{}, err
+	}
+	var data P25Talkgroup
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// DeleteP25TalkgroupParams are the parameters of DeleteP25Talkgroup.
+type DeleteP25TalkgroupParams struct {
+	ID int
+}
+
+// DeleteP25Talkgroup calls DELETE /api/p25/talkgroups: Delete a P25 talkgroup.
+func (c *Client) DeleteP25Talkgroup(ctx context.Context, params *DeleteP25TalkgroupParams) error {
+	req := request{method: "DELETE", path: c.projectPath("/api/p25/talkgroups"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+	}
+	return c.call(ctx, req, nil, nil)
+}
+
+// ListProjectsParams are the parameters of ListProjects.
+type ListProjectsParams struct {
+	Archived *bool
+}
+
+// ListProjects calls GET /api/projects: List projects, starting with the default one.
+func (c *Client) ListProjects(ctx context.Context, params *ListProjectsParams) ([]Project, error) {
+	req := request{method: "GET", path: "/api/projects", query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.Archived != nil {
+			req.query.Set("archived", strconv.FormatBool(*params.Archived))
+		}
+	}
+	var data []Project
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// CreateProject calls POST /api/projects: Create a project with an empty database, or a copy of clone_from's.
+func (c *Client) CreateProject(ctx context.Context, body ProjectRequest) (Project, error) {
+	req := request{method: "POST", path: "/api/projects", query: url.Values{}, header: http.Header{}}
+	if err := req.setJSON(body); err != nil {
+		return Project{}, err
+	}
+	var data Project
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// UpdateProjectParams are the parameters of UpdateProject.
+type UpdateProjectParams struct {
+	ID string
+}
+
+// UpdateProject calls PATCH /api/projects: Rename, archive or unarchive a project.
+func (c *Client) UpdateProject(ctx context.Context, params *UpdateProjectParams, body ProjectRequest) (Project, error) {
+	req := request{method: "PATCH", path: "/api/projects", query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != "" {
+			req.query.Set("id", params.ID)
+		}
+	}
+	if err := req.setJSON(body); err != nil {
+		return Project{}, err
+	}
+	var data Project
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// ListRoamingChannels calls GET /api/roaming/channels: List roaming channels.
+func (c *Client) ListRoamingChannels(ctx context.Context) ([]RoamingChannel, error) {
+	req := request{method: "GET", path: c.projectPath("/api/roaming/channels"), query: url.Values{}, header: http.Header{}}
+	var data []RoamingChannel
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// SaveRoamingChannel calls POST /api/roaming/channels: Create a roaming channel, or update it when ID is set.
+func (c *Client) SaveRoamingChannel(ctx context.Context, body RoamingChannel) (RoamingChannel, error) {
+	req := request{method: "POST", path: c.projectPath("/api/roaming/channels"), query: url.Values{}, header: http.Header{}}
+	if err := req.setJSON(body); err != nil {
+		return RoamingChannel{}, err
+	}
+	var data RoamingChannel
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// DeleteRoamingChannelParams are the parameters of DeleteRoamingChannel.
+type DeleteRoamingChannelParams struct {
+	ID int
+}
+
+// DeleteRoamingChannel calls DELETE /api/roaming/channels: Delete a roaming channel.
+func (c *Client) DeleteRoamingChannel(ctx context.Context, params *DeleteRoamingChannelParams) error {
+	req := request{method: "DELETE", path: c.projectPath("/api/roaming/channels"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+	}
+	return c.call(ctx, req, nil, nil)
+}
+
+// GetRoamingZoneParams are the parameters of GetRoamingZone.
+type GetRoamingZoneParams struct {
+	ID int
+}
+
+// GetRoamingZone calls GET /api/roaming/zones: Get a roaming zone.
+func (c *Client) GetRoamingZone(ctx context.Context, params *GetRoamingZoneParams) (RoamingZone, error) {
+	req := request{method: "GET", path: c.projectPath("/api/roaming/zones"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+	}
+	var data RoamingZone
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// ListRoamingZones calls GET /api/roaming/zones: List roaming zones with their channels.
+func (c *Client) ListRoamingZones(ctx context.Context) ([]RoamingZone, error) {
+	req := request{method: "GET", path: c.projectPath("/api/roaming/zones"), query: url.Values{}, header: http.Header{}}
+	var data []RoamingZone
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// SaveRoamingZone calls POST /api/roaming/zones: Create a roaming zone, or rename it when ID is set.
+func (c *Client) SaveRoamingZone(ctx context.Context, body RoamingZone) (RoamingZone, error) {
+	req := request{method: "POST", path: c.projectPath("/api/roaming/zones"), query: url.Values{}, header: http.Header{}}
+	if err := req.setJSON(body); err != nil {
+		return RoamingZone{}, err
+	}
+	var data RoamingZone
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// DeleteRoamingZoneParams are the parameters of DeleteRoamingZone.
+type DeleteRoamingZoneParams struct {
+	ID int
+}
+
+// DeleteRoamingZone calls DELETE /api/roaming/zones: Delete a roaming zone.
+func (c *Client) DeleteRoamingZone(ctx context.Context, params *DeleteRoamingZoneParams) error {
+	req := request{method: "DELETE", path: c.projectPath("/api/roaming/zones"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+	}
+	return c.call(ctx, req, nil, nil)
+}
+
+// AssignRoamingZoneChannelsParams are the parameters of AssignRoamingZoneChannels.
+type AssignRoamingZoneChannelsParams struct {
+	ID int
+}
+
+// AssignRoamingZoneChannels calls POST /api/roaming/zones/assign: Replace a roaming zone's channels.
+func (c *Client) AssignRoamingZoneChannels(ctx context.Context, params *AssignRoamingZoneChannelsParams, body []int) error {
+	req := request{method: "POST", path: c.projectPath("/api/roaming/zones/assign"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+	}
+	if err := req.setJSON(body); err != nil {
+		return err
+	}
+	return c.call(ctx, req, nil, nil)
+}
+
+// GetScanListParams are the parameters of GetScanList.
+type GetScanListParams struct {
+	ID int
+}
+
+// GetScanList calls GET /api/scanlists: Get a scan list.
+func (c *Client) GetScanList(ctx context.Context, params *GetScanListParams) (ScanList, error) {
+	req := request{method: "GET", path: c.projectPath("/api/scanlists"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+	}
+	var data ScanList
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// ListScanLists calls GET /api/scanlists: List scan lists with their channels.
+func (c *Client) ListScanLists(ctx context.Context) ([]ScanList, error) {
+	req := request{method: "GET", path: c.projectPath("/api/scanlists"), query: url.Values{}, header: http.Header{}}
+	var data []ScanList
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// SaveScanListParams are the parameters of SaveScanList.
+type SaveScanListParams struct {
+	IfMatch string
+}
+
+// SaveScanList calls POST /api/scanlists: Create a scan list, or rename it when ID is set.
+func (c *Client) SaveScanList(ctx context.Context, params *SaveScanListParams, body ScanList) (ScanList, error) {
+	req := request{method: "POST", path: c.projectPath("/api/scanlists"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.IfMatch != "" {
+			req.header.Set("If-Match", params.IfMatch)
+		}
+	}
+	if err := req.setJSON(body); err != nil {
+		return ScanList{}, err
+	}
+	var data ScanList
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// DeleteScanListParams are the parameters of DeleteScanList.
+type DeleteScanListParams struct {
+	ID      int
+	IfMatch string
+}
+
+// DeleteScanList calls DELETE /api/scanlists: Delete a scan list.
+func (c *Client) DeleteScanList(ctx context.Context, params *DeleteScanListParams) error {
+	req := request{method: "DELETE", path: c.projectPath("/api/scanlists"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+		if params.IfMatch != "" {
+			req.header.Set("If-Match", params.IfMatch)
+		}
+	}
+	return c.call(ctx, req, nil, nil)
+}
+
+// AssignScanListChannelsParams are the parameters of AssignScanListChannels.
+type AssignScanListChannelsParams struct {
+	IfMatch string
+}
+
+// AssignScanListChannels calls POST /api/scanlists/assign: Replace a scan list's channels.
+func (c *Client) AssignScanListChannels(ctx context.Context, params *AssignScanListChannelsParams, body ScanListAssignRequest) error {
+	req := request{method: "POST", path: c.projectPath("/api/scanlists/assign"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.IfMatch != "" {
+			req.header.Set("If-Match", params.IfMatch)
+		}
+	}
+	if err := req.setJSON(body); err != nil {
+		return err
+	}
+	return c.call(ctx, req, nil, nil)
+}
+
+// ListCatalogTalkgroupsParams are the parameters of ListCatalogTalkgroups.
+type ListCatalogTalkgroupsParams struct {
+	Network string
+	Search  string
+	Page    int
+	Limit   int
+}
+
+// ListCatalogTalkgroups calls GET /api/talkgroups/catalog: Search the talkgroup catalog.
+func (c *Client) ListCatalogTalkgroups(ctx context.Context, params *ListCatalogTalkgroupsParams) (TalkgroupCatalogPage, error) {
+	req := request{method: "GET", path: c.projectPath("/api/talkgroups/catalog"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.Network != "" {
+			req.query.Set("network", params.Network)
+		}
+		if params.Search != "" {
+			req.query.Set("search", params.Search)
+		}
+		if params.Page != 0 {
+			req.query.Set("page", strconv.Itoa(params.Page))
+		}
+		if params.Limit != 0 {
+			req.query.Set("limit", strconv.Itoa(params.Limit))
+		}
+	}
+	var data TalkgroupCatalogPage
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// ListUsers calls GET /api/users: List accounts.
+func (c *Client) ListUsers(ctx context.Context) ([]User, error) {
+	req := request{method: "GET", path: "/api/users", query: url.Values{}, header: http.Header{}}
+	var data []User
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// CreateUser calls POST /api/users: Create an account; the first one must be an admin and turns login on.
+func (c *Client) CreateUser(ctx context.Context, body UserRequest) (User, error) {
+	req := request{method: "POST", path: "/api/users", query: url.Values{}, header: http.Header{}}
+	if err := req.setJSON(body); err != nil {
+		return User{}, err
+	}
+	var data User
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// UpdateUserParams are the parameters of UpdateUser.
+type UpdateUserParams struct {
+	ID int
+}
+
+// UpdateUser calls PATCH /api/users: Change an account's role or password.
+func (c *Client) UpdateUser(ctx context.Context, params *UpdateUserParams, body UserRequest) (User, error) {
+	req := request{method: "PATCH", path: "/api/users", query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+	}
+	if err := req.setJSON(body); err != nil {
+		return User{}, err
+	}
+	var data User
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// DeleteUserParams are the parameters of DeleteUser.
+type DeleteUserParams struct {
+	ID int
+}
+
+// DeleteUser calls DELETE /api/users: Delete an account with its sessions and tokens.
+func (c *Client) DeleteUser(ctx context.Context, params *DeleteUserParams) error {
+	req := request{method: "DELETE", path: "/api/users", query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+	}
+	return c.call(ctx, req, nil, nil)
+}
+
+// GetZoneParams are the parameters of GetZone.
+type GetZoneParams struct {
+	ID int
+}
+
+// GetZone calls GET /api/zones: Get a zone.
+func (c *Client) GetZone(ctx context.Context, params *GetZoneParams) (Zone, error) {
+	req := request{method: "GET", path: c.projectPath("/api/zones"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+	}
+	var data Zone
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// ListZones calls GET /api/zones: List zones with their channels.
+func (c *Client) ListZones(ctx context.Context) ([]Zone, error) {
+	req := request{method: "GET", path: c.projectPath("/api/zones"), query: url.Values{}, header: http.Header{}}
+	var data []Zone
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// SaveZoneParams are the parameters of SaveZone.
+type SaveZoneParams struct {
+	IfMatch string
+}
+
+// SaveZone calls POST /api/zones: Create a zone, or rename it when ID is set.
+func (c *Client) SaveZone(ctx context.Context, params *SaveZoneParams, body Zone) (Zone, error) {
+	req := request{method: "POST", path: c.projectPath("/api/zones"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.IfMatch != "" {
+			req.header.Set("If-Match", params.IfMatch)
+		}
+	}
+	if err := req.setJSON(body); err != nil {
+		return Zone{}, err
+	}
+	var data Zone
+	err := c.call(ctx, req, &data, nil)
+	return data, err
+}
+
+// DeleteZoneParams are the parameters of DeleteZone.
+type DeleteZoneParams struct {
+	ID      int
+	IfMatch string
+}
+
+// DeleteZone calls DELETE /api/zones: Delete a zone.
+func (c *Client) DeleteZone(ctx context.Context, params *DeleteZoneParams) error {
+	req := request{method: "DELETE", path: c.projectPath("/api/zones"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+		if params.IfMatch != "" {
+			req.header.Set("If-Match", params.IfMatch)
+		}
+	}
+	return c.call(ctx, req, nil, nil)
+}
+
+// AssignZoneChannelsParams are the parameters of AssignZoneChannels.
+type AssignZoneChannelsParams struct {
+	ID      int
+	IfMatch string
+}
+
+// AssignZoneChannels calls POST /api/zones/assign: Replace a zone's channels, in order.
+func (c *Client) AssignZoneChannels(ctx context.Context, params *AssignZoneChannelsParams, body []int) error {
+	req := request{method: "POST", path: c.projectPath("/api/zones/assign"), query: url.Values{}, header: http.Header{}}
+	if params != nil {
+		if params.ID != 0 {
+			req.query.Set("id", strconv.Itoa(params.ID))
+		}
+		if params.IfMatch != "" {
+			req.header.Set("If-Match", params.IfMatch)
+		}
+	}
+	if err := req.setJSON(body); err != nil {
+		return err
+	}
+	return c.call(ctx, req, nil, nil)
+}
@@ -0,0 +1,21 @@
+//go:build ignore
+
+// gen writes client_gen.go from the server's OpenAPI document
+package main
+
+import (
+	"codeplugs/api"
+	"codeplugs/internal/openapigen"
+	"log"
+	"os"
+)
+
+func main() {
+	src, err := openapigen.Generate(api.OpenAPISpec(), "client")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("client_gen.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
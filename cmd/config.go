@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"log"
+
+	"codeplugs/config"
+)
+
+// defaultDB is the -db default: the db in codeplugs.yaml or CODEPLUGS_DB
+func defaultDB() string {
+	cfg, err := config.Load("")
+	if err != nil {
+		log.Printf("Warning: %v", err)
+		return "codeplugs.db"
+	}
+	return cfg.DB
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+
+	"codeplugs/database"
+	"codeplugs/services"
+)
+
+// Contacts implements `codeplugs contacts -unresolved` and `-duplicates`.
+// It lists the placeholder contacts auto-created with negative DMR IDs,
+// the channels using them and suggested real talkgroups, and merges a
+// placeholder into a chosen contact or talkgroup ID. It also reports
+// contacts duplicated across imports and merges them into one record.
+//
+// Flags:
+//   - db: Path to SQLite database
+//   - unresolved: Report placeholder contacts
+//   - suggestions: Maximum suggestions per placeholder
+//   - merge: ID of the placeholder contact to merge
+//   - into: Contact ID to merge the placeholder into
+//   - dmr-id: Talkgroup ID to give the placeholder instead of -into
+//   - duplicates: Report contacts sharing a DMR ID or normalized name
+//   - dedupe: Merge every duplicate group into its proposed canonical contact
+func Contacts(args []string) error {
+	fs := flag.NewFlagSet("contacts", flag.ExitOnError)
+	dbPath := fs.String("db", defaultDB(), "Path to SQLite database")
+	unresolved := fs.Bool("unresolved", false, "Report placeholder contacts with negative DMR IDs")
+	maxSuggestions := fs.Int("suggestions", 3, "Maximum suggestions per placeholder")
+	merge := fs.Uint("merge", 0, "ID of the placeholder contact to merge")
+	into := fs.Uint("into", 0, "Contact ID to merge the placeholder into")
+	dmrID := fs.Int("dmr-id", 0, "Talkgroup ID to give the placeholder (uses the existing contact if there is one)")
+	duplicates := fs.Bool("duplicates", false, "Report contacts sharing a DMR ID or normalized name")
+	dedupe := fs.Bool("dedupe", false, "Merge every duplicate group into its proposed canonical contact")
+	fs.Parse(args)
+
+	if !*unresolved && *merge == 0 && !*duplicates && !*dedupe {
+		return fmt.Errorf("-unresolved, -merge, -duplicates or -dedupe is required")
+	}
+
+	database.Connect(*dbPath)
+
+	if *dedupe {
+		groups, channels, err := services.DedupeAllContacts(database.DB)
+		if err != nil {
+			return err
+		}
+		printDuplicateGroups(groups)
+		fmt.Printf("Merged %d duplicate groups, %d channels updated.\n", len(groups), channels)
+		return nil
+	}
+
+	if *duplicates {
+		groups, err := services.FindDuplicateContacts(database.DB)
+		if err != nil {
+			return err
+		}
+		printDuplicateGroups(groups)
+		fmt.Printf("%d duplicate groups. Merge them all with: codeplugs contacts -dedupe\n", len(groups))
+		return nil
+	}
+
+	if *merge != 0 {
+		result, err := services.MergePlaceholder(database.DB, services.MergeRequest{
+			PlaceholderID: uint(*merge),
+			ContactID:     uint(*into),
+			DMRID:         *dmrID,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Merged contact #%d into '%s' (%d), %d channels updated.\n",
+			*merge, result.Contact.Name, result.Contact.DMRID, result.ChannelsUpdated)
+		return nil
+	}
+
+	report, err := services.UnresolvedContacts(database.DB, *maxSuggestions)
+	if err != nil {
+		return err
+	}
+	if len(report) == 0 {
+		fmt.Println("No unresolved contacts.")
+		return nil
+	}
+	for _, u := range report {
+		fmt.Printf("#%d %s (%d), used by %d channels\n", u.ID, u.Name, u.DMRID, len(u.Channels))
+		for _, ch := range u.Channels {
+			fmt.Printf("    channel #%d %s\n", ch.ID, ch.Name)
+		}
+		for _, s := range u.Suggestions {
+			if s.Source == services.SuggestionContact {
+				fmt.Printf("  ? %-20s %-8d contact #%d (%.0f%%)\n", s.Name, s.DMRID, s.ContactID, s.Score*100)
+			} else {
+				fmt.Printf("  ? %-20s %-8d %s (%.0f%%)\n", s.Name, s.DMRID, s.Network, s.Score*100)
+			}
+		}
+	}
+	fmt.Printf("%d unresolved contacts. Merge with: codeplugs contacts -merge <id> -into <contact id> | -dmr-id <tg>\n", len(report))
+	return nil
+}
+
+func printDuplicateGroups(groups []services.DuplicateGroup) {
+	for _, g := range groups {
+		fmt.Printf("%-8d keep #%d %s (%s), %d channels\n", g.DMRID, g.Canonical.ID, g.Canonical.Name, g.Canonical.Type, g.Channels)
+		for _, d := range g.Duplicates {
+			fmt.Printf("         merge #%d %s (%s, %d) [%s]\n", d.ID, d.Name, d.Type, d.DMRID, g.Reason)
+		}
+	}
+}
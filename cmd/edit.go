@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"codeplugs/database"
+	"codeplugs/services"
+)
+
+// Edit implements `codeplugs edit -where "zone=Detroit Area" -set power=Low`.
+// It sets the same fields on every matching channel in one transaction and
+// prints how many channels matched and changed. Both flags repeat; -where
+// selectors are ANDed and a comma-separated value matches any of its parts.
+//
+// Flags:
+//   - db: Path to SQLite database
+//   - where: Channel selector: zone, mode, protocol, band, contact, search, id, min_freq, max_freq or skip
+//   - set: Field assignment using the channel's JSON field names, e.g. power=Low
+func Edit(args []string) error {
+	fs := flag.NewFlagSet("edit", flag.ExitOnError)
+	dbPath := fs.String("db", defaultDB(), "Path to SQLite database")
+	var where, set repeatedFlag
+	fs.Var(&where, "where", "Channel selector (repeatable), e.g. \"zone=Detroit Area\" or \"band=2m,70cm\"")
+	fs.Var(&set, "set", "Field to set (repeatable), e.g. power=Low or skip=true")
+	fs.Parse(args)
+
+	if len(where) == 0 || len(set) == 0 {
+		return fmt.Errorf("-where and -set are required")
+	}
+	filter, err := services.ParseChannelSelectors(where)
+	if err != nil {
+		return err
+	}
+	values, err := services.ParseChannelAssignments(set)
+	if err != nil {
+		return err
+	}
+
+	database.Connect(*dbPath)
+
+	result, err := services.BulkEditChannels(database.DB, services.ChannelBulkEdit{Filter: filter, Set: values})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d channels matched, %d changed.\n", result.Matched, result.Changed)
+	return nil
+}
+
+// repeatedFlag collects every value of a repeatable flag
+type repeatedFlag []string
+
+func (f *repeatedFlag) String() string { return strings.Join(*f, ", ") }
+
+func (f *repeatedFlag) Set(v string) error {
+	*f = append(*f, v)
+	return nil
+}
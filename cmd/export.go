@@ -0,0 +1,323 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"codeplugs/config"
+	"codeplugs/database"
+	"codeplugs/exporter"
+	"codeplugs/importer"
+	"codeplugs/models"
+	"codeplugs/services"
+
+	"gorm.io/gorm"
+)
+
+// Export implements `codeplugs export -profile my-890`, which runs an export
+// profile saved in codeplugs.yaml. The --export flag builds the same
+// profile from its flags, so a saved profile reproduces that run exactly.
+//
+// Flags:
+//   - config: Path to the config file (default codeplugs.yaml or CODEPLUGS_CONFIG)
+//   - db: Path to SQLite database (default from the config)
+//   - profile: Name of the export profile to run
+//   - output: Write here instead of the profile's output
+//   - list: List the saved profiles
+func Export(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	configPath := fs.String("config", "", "Path to the config file (default codeplugs.yaml or $CODEPLUGS_CONFIG)")
+	dbPath := fs.String("db", "", "Path to SQLite database (default from the config)")
+	name := fs.String("profile", "", "Export profile to run")
+	output := fs.String("output", "", "Write here instead of the profile's output")
+	list := fs.Bool("list", false, "List the saved export profiles")
+	fs.Parse(args)
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return err
+	}
+	if *list || *name == "" {
+		profiles := cfg.ProfileList()
+		for _, p := range profiles {
+			fmt.Printf(" %-20s %-7s %s\n", p.Name, p.Radio, p.Description)
+		}
+		if len(profiles) == 0 {
+			fmt.Println("No export profiles; add them under profiles: in codeplugs.yaml.")
+		}
+		if !*list {
+			return fmt.Errorf("-profile is required")
+		}
+		return nil
+	}
+
+	profile, err := cfg.Profile(*name)
+	if err != nil {
+		return err
+	}
+	if *output != "" {
+		profile.Output = *output
+	}
+	if *dbPath == "" {
+		*dbPath = cfg.DB
+	}
+	database.Connect(*dbPath)
+	return RunExport(database.DB, profile)
+}
+
+// RunExport writes the export p describes to p.Output
+func RunExport(db *gorm.DB, p config.Profile) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if p.Output == "" {
+		return fmt.Errorf("no output path; set the profile's output or pass -output")
+	}
+	zones, err := profileZones(db, p.Zones)
+	if err != nil {
+		return err
+	}
+
+	switch p.Radio {
+	case "dm32uv":
+		return exportDM32UV(db, p, zones)
+	case "at890":
+		// AnyTone 890 Export Logic
+		fmt.Printf("Exporting AnyTone 890 to directory %s...\n", p.Output)
+		contactFilter, err := profileContactFilter(db, p)
+		if err != nil {
+			return err
+		}
+		skipped, err := exporter.ExportAnyTone890Filtered(db, p.Output, contactFilter)
+		if err != nil {
+			return fmt.Errorf("exporting 890: %w", err)
+		}
+		reportSkipped(skipped)
+		fmt.Println("Export complete.")
+		return nil
+	}
+
+	if p.Format == "icom" {
+		fmt.Printf("Exporting D-Star repeater list to %s...\n", p.Output)
+		var repeaters []models.DStarRepeater
+		db.Where("type <> ?", models.DStarTypeReflector).Order("group_no asc, id asc").Find(&repeaters)
+
+		f, err := os.Create(p.Output)
+		if err != nil {
+			return fmt.Errorf("creating export file: %w", err)
+		}
+		defer f.Close()
+		if err := exporter.ExportIcomRepeaterList(repeaters, f); err != nil {
+			return fmt.Errorf("exporting CSV: %w", err)
+		}
+		fmt.Printf("Exported %d repeaters to %s.\n", len(repeaters), p.Output)
+		return nil
+	}
+
+	// DB25-D and the generic CSV formats
+	fmt.Printf("Exporting to %s (format: %s)...\n", p.Output, p.Format)
+	channels := zoneChannels(db, zones)
+
+	f, err := os.Create(p.Output)
+	if err != nil {
+		return fmt.Errorf("creating export file: %w", err)
+	}
+	defer f.Close()
+
+	var skipped []string
+	switch p.Format {
+	case "chirp":
+		skipped, err = exporter.ExportChirpCSV(channels, f)
+	case "p25":
+		var talkgroups []models.P25Talkgroup
+		db.Find(&talkgroups)
+		err = exporter.ExportP25Channels(channels, talkgroups, f)
+	case "icom_mycall":
+		err = exporter.ExportIcomMyCallSigns(channels, f)
+	default:
+		exporter.ExportDB25D(channels, f, false)
+	}
+	if err != nil {
+		return fmt.Errorf("exporting CSV: %w", err)
+	}
+	reportSkipped(skipped)
+	fmt.Printf("Exported %d channels to %s.\n", len(channels)-len(skipped), p.Output)
+	return nil
+}
+
+// exportDM32UV writes <output>_channels.csv and the other DM32UV CSVs
+func exportDM32UV(db *gorm.DB, p config.Profile, zones []models.Zone) error {
+	// Remove .csv extension if present to append suffixes
+	baseFilename := strings.TrimSuffix(p.Output, ".csv")
+	fmt.Printf("Exporting for DM32UV to %s-*.csv ...\n", baseFilename)
+
+	// 1. Export Channels
+	channels := zoneChannels(db, zones)
+	var skipped []string
+	if err := writeCSV(baseFilename+"_channels.csv", func(f *os.File) (err error) {
+		skipped, err = exporter.ExportDM32UVChannels(channels, f)
+		return err
+	}); err != nil {
+		return fmt.Errorf("exporting channels: %w", err)
+	}
+	fmt.Printf(" - Channels: %s_channels.csv\n", baseFilename)
+	reportSkipped(skipped)
+
+	// 2. Export Zones
+	var exported []models.Zone
+	query := db.Preload("Channels")
+	if len(zones) > 0 {
+		query = query.Where("id IN ?", zoneIDs(zones))
+	}
+	query.Find(&exported)
+	if err := writeCSV(baseFilename+"_zones.csv", func(f *os.File) error {
+		return exporter.ExportDM32UVZones(exported, f)
+	}); err != nil {
+		return fmt.Errorf("exporting zones: %w", err)
+	}
+	fmt.Printf(" - Zones: %s_zones.csv\n", baseFilename)
+
+	// 3. Export Talk Groups (Local Contacts)
+	var talkgroups []models.Contact
+	db.Where("type = ?", models.ContactTypeGroup).Find(&talkgroups)
+	if err := writeCSV(baseFilename+"_talkgroups.csv", func(f *os.File) error {
+		return exporter.ExportDM32UVTalkgroups(talkgroups, f)
+	}); err != nil {
+		return fmt.Errorf("exporting talkgroups: %w", err)
+	}
+	fmt.Printf(" - TalkGroups: %s_talkgroups.csv\n", baseFilename)
+
+	// 4. Export Digital Contacts (CSV Contacts)
+	contactFilter, err := profileContactFilter(db, p)
+	if err != nil {
+		return err
+	}
+	var allowedIDs map[int]bool
+	if p.FilterFile != "" {
+		allowedIDs, err = importer.LoadFilterList(p.FilterFile)
+		if err != nil {
+			return fmt.Errorf("loading filter list: %w", err)
+		}
+		fmt.Printf("Loaded %d allowed IDs from filter list.\n", len(allowedIDs))
+	}
+
+	var digitalContacts []models.DigitalContact
+	queryDC := db.Model(&models.DigitalContact{}).Where("retired_at IS NULL")
+	if contactFilter != nil {
+		queryDC = queryDC.Where("dmr_id IN (?)", contactFilter)
+	}
+	queryDC.Find(&digitalContacts)
+
+	filteredContacts := []models.DigitalContact{}
+	for _, c := range digitalContacts {
+		if allowedIDs != nil && !allowedIDs[c.DMRID] {
+			continue
+		}
+		filteredContacts = append(filteredContacts, c)
+	}
+
+	// Apply Limit
+	maxContacts := 50000 // Default for DM32UV
+	if p.Limit > 0 {
+		maxContacts = p.Limit
+	}
+	if len(filteredContacts) > maxContacts {
+		fmt.Printf("Warning: Contact count %d exceeds limit %d. Keeping the top %d by activity.\n", len(filteredContacts), maxContacts, maxContacts)
+		kept, dropped, err := services.TruncateContacts(db, filteredContacts, services.ContactRankOptions{
+			Limit:             maxContacts,
+			PriorityStates:    p.PriorityStates,
+			PriorityCountries: p.PriorityCountries,
+		})
+		if err != nil {
+			return fmt.Errorf("ranking contacts: %w", err)
+		}
+		filteredContacts = kept
+
+		if err := writeCSV(baseFilename+"_dropped_contacts.csv", func(f *os.File) error {
+			return services.WriteTruncationReport(dropped, f)
+		}); err != nil {
+			return fmt.Errorf("creating dropped contacts report: %w", err)
+		}
+		fmt.Printf(" - Dropped %d contacts, see %s_dropped_contacts.csv\n", len(dropped), baseFilename)
+	}
+
+	if err := models.ApplyContactOverrides(db, filteredContacts); err != nil {
+		return fmt.Errorf("applying contact overrides: %w", err)
+	}
+	if err := writeCSV(baseFilename+"_digital_contacts.csv", func(f *os.File) error {
+		return exporter.ExportDM32UVDigitalContacts(filteredContacts, f)
+	}); err != nil {
+		return fmt.Errorf("exporting digital contacts: %w", err)
+	}
+	fmt.Printf(" - Digital Contacts: %s_digital_contacts.csv (%d records)\n", baseFilename, len(filteredContacts))
+	return nil
+}
+
+// reportSkipped lists the P25 channels an exporter left out
+func reportSkipped(skipped []string) {
+	if len(skipped) > 0 {
+		fmt.Printf("Skipped %d P25 channels the radio can't carry: %s\n", len(skipped), strings.Join(skipped, ", "))
+	}
+}
+
+// profileZones looks up zones by name
+func profileZones(db *gorm.DB, names []string) ([]models.Zone, error) {
+	var zones []models.Zone
+	for _, name := range names {
+		var zone models.Zone
+		if err := db.Where("name = ?", name).First(&zone).Error; err != nil {
+			return nil, fmt.Errorf("zone not found: %s", name)
+		}
+		zones = append(zones, zone)
+	}
+	return zones, nil
+}
+
+func zoneIDs(zones []models.Zone) []uint {
+	ids := make([]uint, len(zones))
+	for i, z := range zones {
+		ids[i] = z.ID
+	}
+	return ids
+}
+
+// zoneChannels returns the channels to export: those in zones, or every
+// channel when zones is empty. Skipped channels are left out.
+func zoneChannels(db *gorm.DB, zones []models.Zone) []models.Channel {
+	var channels []models.Channel
+	query := db.Model(&models.Channel{}).Preload("Contact").Where("skip = ?", false)
+	if len(zones) > 0 {
+		query = query.Where("channels.id IN (?)",
+			db.Table("zone_channels").Select("channel_id").Where("zone_id IN ?", zoneIDs(zones)))
+	}
+	query.Find(&channels)
+	return channels
+}
+
+// profileContactFilter evaluates the profile's filter list expression
+func profileContactFilter(db *gorm.DB, p config.Profile) (*gorm.DB, error) {
+	if p.UseList == "" {
+		return nil, nil
+	}
+	members, err := services.ContactListExprFilter(db, p.UseList)
+	if err != nil {
+		return nil, fmt.Errorf("evaluating filter list: %w", err)
+	}
+	fmt.Printf("Filtering contacts using list '%s'\n", p.UseList)
+	return members, nil
+}
+
+// writeCSV creates path and fills it with write
+func writeCSV(path string, write func(f *os.File) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"codeplugs/database"
+	"codeplugs/services"
+)
+
+// ListOp implements `codeplugs list-op -union a,b -intersect c -exclude d -into combined`.
+// It computes a new contact list from existing ones and prints the size of
+// every input and of the result. Without -into it only reports the counts.
+//
+// Flags:
+//   - db: Path to SQLite database
+//   - union: Comma-separated lists whose contacts are combined
+//   - intersect: Comma-separated lists the result must also be in
+//   - exclude: Comma-separated lists whose contacts are removed
+//   - expr: Set expression instead of -union/-intersect/-exclude
+//   - into: Name of the list to write the result to
+//   - description: Description for the -into list
+func ListOp(args []string) error {
+	fs := flag.NewFlagSet("list-op", flag.ExitOnError)
+	dbPath := fs.String("db", defaultDB(), "Path to SQLite database")
+	union := fs.String("union", "", "Comma-separated lists to combine")
+	intersect := fs.String("intersect", "", "Comma-separated lists the result must also be in")
+	exclude := fs.String("exclude", "", "Comma-separated lists to remove from the result")
+	expr := fs.String("expr", "", "Set expression, e.g. \"club, statewide & heard - banned\"")
+	into := fs.String("into", "", "Save the result as this list (replacing its contents)")
+	description := fs.String("description", "", "Description for the -into list")
+	fs.Parse(args)
+
+	database.Connect(*dbPath)
+
+	result, err := services.RunListOperation(database.DB, services.ListOperation{
+		Union:       splitNames(*union),
+		Intersect:   splitNames(*intersect),
+		Exclude:     splitNames(*exclude),
+		Expression:  *expr,
+		Into:        *into,
+		Description: *description,
+	})
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(result.Inputs))
+	for name := range result.Inputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf(" %-20s %d\n", name, result.Inputs[name])
+	}
+	fmt.Printf("%s = %d contacts\n", result.Expression, result.Count)
+	if result.List != nil {
+		fmt.Printf("Saved to list '%s'.\n", result.List.Name)
+	}
+	return nil
+}
+
+func splitNames(s string) []string {
+	var names []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			names = append(names, part)
+		}
+	}
+	return names
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"codeplugs/database"
+	"codeplugs/models"
+	"codeplugs/services"
+)
+
+// SyncContacts implements `codeplugs sync-contacts -file user.csv`.
+// It incrementally syncs digital contacts against a RadioID.net dump and
+// prints the added/changed/removed totals.
+//
+// Flags:
+//   - db: Path to SQLite database
+//   - file: RadioID.net user.csv to sync from
+//   - changes: Also print the per-callsign change log
+func SyncContacts(args []string) error {
+	fs := flag.NewFlagSet("sync-contacts", flag.ExitOnError)
+	dbPath := fs.String("db", defaultDB(), "Path to SQLite database")
+	file := fs.String("file", "", "RadioID.net user.csv to sync from")
+	showChanges := fs.Bool("changes", false, "Print the per-callsign change log")
+	fs.Parse(args)
+
+	if *file == "" {
+		return fmt.Errorf("-file is required")
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", *file, err)
+	}
+	defer f.Close()
+
+	database.Connect(*dbPath)
+
+	fmt.Printf("Syncing contacts from %s...\n", *file)
+	sync, err := services.SyncRadioIDContacts(database.DB, f, *file, nil)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Sync #%d complete: %d added, %d changed, %d removed, %d unchanged\n",
+		sync.ID, sync.Added, sync.Changed, sync.Removed, sync.Unchanged)
+
+	if *showChanges {
+		var changes []models.ContactSyncChange
+		database.DB.Where("contact_sync_id = ?", sync.ID).Order("callsign").Find(&changes)
+		for _, c := range changes {
+			if c.Details != "" {
+				fmt.Printf(" %-8s %-10s %d (%s)\n", c.Action, c.Callsign, c.DMRID, c.Details)
+			} else {
+				fmt.Printf(" %-8s %-10s %d\n", c.Action, c.Callsign, c.DMRID)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"codeplugs/database"
+	"codeplugs/models"
+	"codeplugs/services"
+)
+
+// Users implements `codeplugs users`, which manages the web server's local
+// accounts. Creating the first account, which must be an admin, makes the
+// server require a login; deleting the last one makes it open again.
+//
+// Flags:
+//   - db: Path to SQLite database
+//   - add: Username of an account to create
+//   - role: Role of the -add account, or the new role of the -set account
+//   - set: Username of an account whose -role or password to change
+//   - password: Also change the -set account's password
+//   - delete: Username of an account to delete
+//   - token: Username to issue an API token for
+//   - token-name: Name of the -token token
+//
+// Passwords are read from standard input, never from flags.
+func Users(args []string) error {
+	fs := flag.NewFlagSet("users", flag.ExitOnError)
+	dbPath := fs.String("db", defaultDB(), "Path to SQLite database")
+	add := fs.String("add", "", "Create an account with this username")
+	role := fs.String("role", "", "Role: read-only, editor or admin")
+	set := fs.String("set", "", "Change this account's -role, or its password with -password")
+	password := fs.Bool("password", false, "Change the -set account's password")
+	del := fs.String("delete", "", "Delete the account with this username")
+	token := fs.String("token", "", "Issue an API token for this username")
+	tokenName := fs.String("token-name", "cli", "Name of the -token token")
+	fs.Parse(args)
+
+	database.Connect(*dbPath)
+
+	switch {
+	case *add != "":
+		r := models.Role(*role)
+		if r == "" {
+			r = models.RoleReadOnly
+		}
+		pw, err := readPassword()
+		if err != nil {
+			return err
+		}
+		user, err := services.CreateUser(database.DB, *add, pw, r)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created %s account '%s'.\n", user.Role, user.Username)
+	case *set != "":
+		user, err := findUser(*set)
+		if err != nil {
+			return err
+		}
+		var newRole *models.Role
+		var newPassword *string
+		if *role != "" {
+			r := models.Role(*role)
+			newRole = &r
+		}
+		if *password {
+			pw, err := readPassword()
+			if err != nil {
+				return err
+			}
+			newPassword = &pw
+		}
+		if newRole == nil && newPassword == nil {
+			return fmt.Errorf("-set needs -role or -password")
+		}
+		if user, err = services.UpdateUser(database.DB, user.ID, newRole, newPassword); err != nil {
+			return err
+		}
+		fmt.Printf("Updated '%s' (%s).\n", user.Username, user.Role)
+	case *del != "":
+		user, err := findUser(*del)
+		if err != nil {
+			return err
+		}
+		if err := services.DeleteUser(database.DB, user.ID); err != nil {
+			return err
+		}
+		fmt.Printf("Deleted '%s'.\n", user.Username)
+	case *token != "":
+		user, err := findUser(*token)
+		if err != nil {
+			return err
+		}
+		secret, _, err := services.CreateAPIToken(database.DB, user.ID, *tokenName)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("API token for '%s' (%s); it won't be shown again:\n%s\n", user.Username, user.Role, secret)
+	default:
+		var users []models.User
+		database.DB.Order("username").Find(&users)
+		for _, u := range users {
+			fmt.Printf(" %-20s %s\n", u.Username, u.Role)
+		}
+		if len(users) == 0 {
+			fmt.Println("No accounts; the web server does not require a login.")
+		}
+	}
+	return nil
+}
+
+func findUser(username string) (*models.User, error) {
+	var user models.User
+	if err := database.DB.Where("username = ?", username).First(&user).Error; err != nil {
+		return nil, fmt.Errorf("no account named '%s'", username)
+	}
+	return &user, nil
+}
+
+// readPassword reads one line from standard input
+func readPassword() (string, error) {
+	fmt.Fprint(os.Stderr, "Password: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("reading password: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
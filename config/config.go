@@ -0,0 +1,216 @@
+// Package config loads codeplugs.yaml, which holds defaults for the CLI and
+// web server and named export profiles. Environment variables override the
+// file, and command-line flags override both.
+//
+//	db: club.db
+//	port: "8080"
+//	profiles:
+//	  my-890:
+//	    radio: at890
+//	    use_list: club, statewide - banned
+//	    output: exports/890
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPath is read when neither -config nor CODEPLUGS_CONFIG names a
+// file. Unlike a named file, it may be missing.
+const DefaultPath = "codeplugs.yaml"
+
+// Config is the merged file and environment settings
+type Config struct {
+	DB             string             `yaml:"db"`              // CODEPLUGS_DB
+	ProjectsDir    string             `yaml:"projects_dir"`    // CODEPLUGS_PROJECTS_DIR
+	Port           string             `yaml:"port"`            // CODEPLUGS_PORT
+	AllowedOrigins []string           `yaml:"allowed_origins"` // CODEPLUGS_ALLOWED_ORIGINS, comma-separated
+	Profiles       map[string]Profile `yaml:"profiles"`
+}
+
+// Profile is a saved export. `codeplugs export -profile <name>` and the web
+// UI's export modal reproduce it. Output is a path on the machine running the
+// CLI, so the server ignores it and sends a download instead.
+type Profile struct {
+	Name              string   `yaml:"-" json:"name"`
+	Description       string   `yaml:"description" json:"description,omitempty"`
+	Radio             string   `yaml:"radio" json:"radio"`                 // db25d, dm32uv or at890
+	Format            string   `yaml:"format" json:"format,omitempty"`     // For db25d: db25d, chirp, p25, icom or icom_mycall
+	Zones             []string `yaml:"zones" json:"zones,omitempty"`       // Zone names; every zone when empty
+	UseList           string   `yaml:"use_list" json:"use_list,omitempty"` // Filter list name or expression
+	FilterFile        string   `yaml:"filter_file" json:"-"`               // File of allowed DMR IDs
+	Limit             int      `yaml:"limit" json:"limit,omitempty"`       // Maximum digital contacts; 0 for the radio's capacity
+	PriorityStates    []string `yaml:"priority_states" json:"priority_states,omitempty"`
+	PriorityCountries []string `yaml:"priority_countries" json:"priority_countries,omitempty"`
+	Output            string   `yaml:"output" json:"-"` // File, or base name or directory for zip radios
+}
+
+var profileFormats = map[string][]string{
+	"db25d":  {"db25d", "chirp", "p25", "icom", "icom_mycall"},
+	"dm32uv": {"dm32uv"},
+	"at890":  {"at890"},
+}
+
+// profileOptions are the options each radio's export honors. Validate
+// rejects the others rather than let a profile silently ignore them.
+var profileOptions = map[string][]string{
+	"db25d":  {"zones"},
+	"dm32uv": {"zones", "use_list", "filter_file", "limit", "priority_states", "priority_countries"},
+	"at890":  {"use_list"},
+}
+
+// Validate checks the radio and format, filling in their defaults
+func (p *Profile) Validate() error {
+	if p.Radio == "" {
+		p.Radio = "db25d"
+	}
+	formats, ok := profileFormats[p.Radio]
+	if !ok {
+		return fmt.Errorf("profile %s: unknown radio %q (db25d, dm32uv or at890)", p.Name, p.Radio)
+	}
+	if p.Format == "" {
+		p.Format = formats[0]
+	}
+	if !slices.Contains(formats, p.Format) {
+		return fmt.Errorf("profile %s: radio %s can't export format %q (%s)", p.Name, p.Radio, p.Format, strings.Join(formats, ", "))
+	}
+	if p.Limit < 0 {
+		return fmt.Errorf("profile %s: limit can't be negative", p.Name)
+	}
+	if unsupported := p.unsupported(); len(unsupported) > 0 {
+		return fmt.Errorf("profile %s: radio %s doesn't support %s", p.Name, p.Radio, unsupported[0])
+	}
+	return nil
+}
+
+// unsupported lists the options set on p that its radio's export ignores
+func (p *Profile) unsupported() []string {
+	options := []struct {
+		name string
+		set  bool
+	}{
+		{"zones", len(p.Zones) > 0},
+		{"use_list", p.UseList != ""},
+		{"filter_file", p.FilterFile != ""},
+		{"limit", p.Limit > 0},
+		{"priority_states", len(p.PriorityStates) > 0},
+		{"priority_countries", len(p.PriorityCountries) > 0},
+	}
+	var names []string
+	for _, o := range options {
+		if o.set && !slices.Contains(profileOptions[p.Radio], o.name) {
+			names = append(names, o.name)
+		}
+	}
+	return names
+}
+
+// DropUnsupported clears the options set on p that its radio ignores and
+// returns their names. Profiles built from the legacy -export flags use it to
+// warn instead of failing Validate, since those flags are shared by all radios.
+func (p *Profile) DropUnsupported() []string {
+	if _, ok := profileOptions[p.Radio]; !ok {
+		return nil
+	}
+	dropped := p.unsupported()
+	for _, name := range dropped {
+		switch name {
+		case "zones":
+			p.Zones = nil
+		case "use_list":
+			p.UseList = ""
+		case "filter_file":
+			p.FilterFile = ""
+		case "limit":
+			p.Limit = 0
+		case "priority_states":
+			p.PriorityStates = nil
+		case "priority_countries":
+			p.PriorityCountries = nil
+		}
+	}
+	return dropped
+}
+
+// Load reads the config file at path, CODEPLUGS_CONFIG or DefaultPath, then
+// applies environment overrides and defaults
+func Load(path string) (*Config, error) {
+	cfg := &Config{}
+	if path == "" {
+		path = os.Getenv("CODEPLUGS_CONFIG")
+	}
+	optional := path == ""
+	if optional {
+		path = DefaultPath
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist) && optional:
+	case err != nil:
+		return nil, fmt.Errorf("reading config: %w", err)
+	default:
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+	}
+
+	if v := os.Getenv("CODEPLUGS_DB"); v != "" {
+		cfg.DB = v
+	}
+	if v := os.Getenv("CODEPLUGS_PROJECTS_DIR"); v != "" {
+		cfg.ProjectsDir = v
+	}
+	if v := os.Getenv("CODEPLUGS_PORT"); v != "" {
+		cfg.Port = v
+	}
+	if v := os.Getenv("CODEPLUGS_ALLOWED_ORIGINS"); v != "" {
+		cfg.AllowedOrigins = nil
+		for _, origin := range strings.Split(v, ",") {
+			if origin = strings.TrimSpace(origin); origin != "" {
+				cfg.AllowedOrigins = append(cfg.AllowedOrigins, origin)
+			}
+		}
+	}
+	if cfg.DB == "" {
+		cfg.DB = "codeplugs.db"
+	}
+	if cfg.Port == "" {
+		cfg.Port = "8080"
+	}
+
+	for name, p := range cfg.Profiles {
+		p.Name = name
+		if err := p.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		cfg.Profiles[name] = p
+	}
+	return cfg, nil
+}
+
+// Profile returns the named export profile
+func (c *Config) Profile(name string) (Profile, error) {
+	p, ok := c.Profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("no export profile named %q", name)
+	}
+	return p, nil
+}
+
+// ProfileList returns the export profiles by name
+func (c *Config) ProfileList() []Profile {
+	list := make([]Profile, 0, len(c.Profiles))
+	for _, p := range c.Profiles {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
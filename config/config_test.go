@@ -0,0 +1,131 @@
+package config_test
+
+import (
+	"codeplugs/config"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, yaml string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "codeplugs.yaml")
+	if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeConfig(t, `
+db: club.db
+port: "9090"
+allowed_origins: [http://localhost:5173]
+profiles:
+  my-890:
+    description: Mobile
+    radio: at890
+    use_list: club, statewide - banned
+    output: exports/890
+  chirp:
+    format: chirp
+    zones: [Detroit, Ann Arbor]
+    output: chirp.csv
+`)
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.DB != "club.db" || cfg.Port != "9090" || !slices.Equal(cfg.AllowedOrigins, []string{"http://localhost:5173"}) {
+		t.Errorf("Unexpected settings: %+v", cfg)
+	}
+
+	p, err := cfg.Profile("my-890")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "my-890" || p.Radio != "at890" || p.Format != "at890" || p.UseList != "club, statewide - banned" || p.Output != "exports/890" {
+		t.Errorf("Unexpected profile: %+v", p)
+	}
+	// The radio defaults to db25d
+	if p, _ := cfg.Profile("chirp"); p.Radio != "db25d" || p.Format != "chirp" || len(p.Zones) != 2 {
+		t.Errorf("Unexpected chirp profile: %+v", p)
+	}
+	if _, err := cfg.Profile("missing"); err == nil {
+		t.Error("Expected an error for an unknown profile")
+	}
+
+	list := cfg.ProfileList()
+	if len(list) != 2 || list[0].Name != "chirp" || list[1].Name != "my-890" {
+		t.Errorf("Expected profiles sorted by name, got %+v", list)
+	}
+}
+
+func TestLoad_EnvOverrides(t *testing.T) {
+	path := writeConfig(t, "db: club.db\nport: \"9090\"\n")
+	t.Setenv("CODEPLUGS_CONFIG", path)
+	t.Setenv("CODEPLUGS_DB", "env.db")
+	t.Setenv("CODEPLUGS_PROJECTS_DIR", "env-projects")
+	t.Setenv("CODEPLUGS_ALLOWED_ORIGINS", "http://a.example, ,http://b.example")
+
+	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.DB != "env.db" || cfg.ProjectsDir != "env-projects" || cfg.Port != "9090" {
+		t.Errorf("Expected env to override the file, got %+v", cfg)
+	}
+	if !slices.Equal(cfg.AllowedOrigins, []string{"http://a.example", "http://b.example"}) {
+		t.Errorf("Unexpected origins: %v", cfg.AllowedOrigins)
+	}
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	// The default file is optional
+	t.Chdir(t.TempDir())
+	t.Setenv("CODEPLUGS_CONFIG", "")
+	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatalf("Expected defaults without codeplugs.yaml, got %v", err)
+	}
+	if cfg.DB != "codeplugs.db" || cfg.Port != "8080" || len(cfg.Profiles) != 0 {
+		t.Errorf("Unexpected defaults: %+v", cfg)
+	}
+
+	// A named one isn't
+	if _, err := config.Load("missing.yaml"); err == nil {
+		t.Error("Expected an error for a missing -config file")
+	}
+}
+
+func TestLoad_InvalidProfile(t *testing.T) {
+	for yaml, want := range map[string]string{
+		"profiles:\n  bad:\n    radio: ht\n":                          "unknown radio",
+		"profiles:\n  bad:\n    radio: at890\n    format: chirp\n":    "can't export format",
+		"profiles:\n  bad:\n    limit: -1\n":                          "negative",
+		"profiles:\n  bad:\n    radio: at890\n    zones: [Detroit]\n": "radio at890 doesn't support zones",
+		"profiles:\n  bad:\n    radio: at890\n    limit: 100\n":       "radio at890 doesn't support limit",
+		"profiles:\n  bad:\n    use_list: club\n":                     "radio db25d doesn't support use_list",
+	} {
+		_, err := config.Load(writeConfig(t, yaml))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q error for %q, got %v", want, yaml, err)
+		}
+	}
+}
+
+func TestProfile_DropUnsupported(t *testing.T) {
+	p := config.Profile{Radio: "at890", UseList: "club", Zones: []string{"Detroit"}, Limit: 100}
+	dropped := p.DropUnsupported()
+	if !slices.Equal(dropped, []string{"zones", "limit"}) {
+		t.Errorf("Expected zones and limit dropped, got %v", dropped)
+	}
+	if p.Zones != nil || p.Limit != 0 || p.UseList != "club" {
+		t.Errorf("Expected only unsupported options cleared, got %+v", p)
+	}
+	if err := p.Validate(); err != nil {
+		t.Errorf("Validate failed after DropUnsupported: %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"fmt"
 	"log"
 
 	"codeplugs/models"
@@ -14,7 +15,16 @@ var DB *gorm.DB
 
 func Connect(dbPath string) {
 	var err error
-	DB, err = gorm.Open(sqlite.Dialector{
+	DB, err = Open(dbPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Open opens the SQLite database at dbPath, creating it if needed, and
+// migrates it to the current schema
+func Open(dbPath string) (*gorm.DB, error) {
+	db, err := gorm.Open(sqlite.Dialector{
 		DriverName: "sqlite",
 		DSN:        dbPath + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)",
 	}, &gorm.Config{
@@ -24,23 +34,34 @@ func Connect(dbPath string) {
 	})
 
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
 	// Register Join Table for Ordering
-	DB.SetupJoinTable(&models.Zone{}, "Channels", &models.ZoneChannel{})
-	DB.SetupJoinTable(&models.ScanList{}, "Channels", &models.ScanListChannel{})
+	db.SetupJoinTable(&models.Zone{}, "Channels", &models.ZoneChannel{})
+	db.SetupJoinTable(&models.ScanList{}, "Channels", &models.ScanListChannel{})
 
 	// Auto Migrate
-	err = DB.AutoMigrate(&models.Channel{}, &models.Contact{}, &models.Zone{}, &models.DigitalContact{}, &models.ZoneChannel{}, &models.ScanList{}, &models.ScanListChannel{}, &models.ContactList{}, &models.ContactListEntry{}, &models.RoamingChannel{}, &models.RoamingZone{})
+	err = db.AutoMigrate(&models.Channel{}, &models.Contact{}, &models.Zone{}, &models.DigitalContact{}, &models.ZoneChannel{}, &models.ScanList{}, &models.ScanListChannel{}, &models.ContactList{}, &models.ContactListEntry{}, &models.ContactListRule{}, &models.RoamingChannel{}, &models.RoamingZone{}, &models.NXDNTalkgroup{}, &models.NXDNContact{}, &models.DStarRepeater{}, &models.P25Talkgroup{}, &models.ContactSync{}, &models.ContactSyncChange{}, &models.ContactActivity{}, &models.ContactPin{}, &models.TalkgroupCatalog{}, &models.ContactOverride{}, &models.User{}, &models.APIToken{}, &models.Session{}, &models.Project{})
 	if err != nil {
-		log.Fatal("Failed to migrate database:", err)
+		closeDB(db)
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
+
+	if err := models.SetupDigitalContactFTS(db); err != nil {
+		closeDB(db)
+		return nil, fmt.Errorf("failed to set up contact search index: %w", err)
+	}
+	return db, nil
 }
 
 func Close() {
-	if DB != nil {
-		sqlDB, err := DB.DB()
+	closeDB(DB)
+}
+
+func closeDB(db *gorm.DB) {
+	if db != nil {
+		sqlDB, err := db.DB()
 		if err != nil {
 			log.Println("Error getting SQL DB from GORM:", err)
 			return
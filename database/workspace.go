@@ -0,0 +1,371 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"codeplugs/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrProjectNotFound  = errors.New("project not found")
+	ErrProjectArchived  = errors.New("project is archived")
+	ErrProjectExists    = errors.New("a project with that id already exists")
+	ErrProjectsDisabled = errors.New("the server has no projects directory")
+	ErrDefaultProject   = errors.New("the default project can't be changed")
+)
+
+// Handle is one open SQLite database. Handlers take DB() once per request;
+// Restore swaps the underlying connection without invalidating the handle.
+type Handle struct {
+	Path string // File on disk, or empty for an in-memory database
+
+	mu     sync.RWMutex
+	db     *gorm.DB
+	server bool // Holds the accounts and project registry, see serverRows
+}
+
+// NewHandle wraps an open database whose file is path
+func NewHandle(db *gorm.DB, path string) *Handle {
+	return &Handle{Path: path, db: db}
+}
+
+// DB returns the current connection
+func (h *Handle) DB() *gorm.DB {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.db
+}
+
+// Close closes the connection
+func (h *Handle) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	closeDB(h.db)
+}
+
+// Backup writes a consistent copy of the codeplug to dst, which must not
+// exist yet. Accounts and the project registry are left out, so the copy
+// carries no password, session or token hashes.
+func (h *Handle) Backup(dst string) error {
+	if err := h.DB().Exec("VACUUM INTO ?", dst).Error; err != nil {
+		return err
+	}
+	db, err := Open(dst)
+	if err != nil {
+		return err
+	}
+	defer closeDB(db)
+	return (&serverRows{}).replace(db)
+}
+
+// Restore replaces the database file with a copy of src and reopens it. The
+// old file is kept as Path+".bak", and put back if src can't be opened.
+// Accounts and the project registry aren't part of a codeplug, so the main
+// database keeps its own and a project drops any that src carries.
+func (h *Handle) Restore(src string) error {
+	if h.Path == "" {
+		return errors.New("an in-memory database can't be restored")
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var keep serverRows
+	if h.server {
+		if err := keep.load(h.db); err != nil {
+			return fmt.Errorf("reading accounts: %w", err)
+		}
+	}
+	closeDB(h.db)
+	bak := h.Path + ".bak"
+	if err := os.Rename(h.Path, bak); err != nil {
+		h.db, _ = Open(h.Path)
+		return fmt.Errorf("keeping a backup: %w", err)
+	}
+	// A journal left beside the old file would be replayed into the new one
+	os.Remove(h.Path + "-wal")
+	os.Remove(h.Path + "-shm")
+
+	var db *gorm.DB
+	err := copyFile(src, h.Path)
+	if err == nil {
+		db, err = Open(h.Path)
+	}
+	if err == nil {
+		if err = keep.replace(db); err != nil {
+			closeDB(db)
+		}
+	}
+	if err != nil {
+		os.Remove(h.Path + "-wal")
+		os.Remove(h.Path + "-shm")
+		os.Rename(bak, h.Path)
+		h.db, _ = Open(h.Path)
+		return err
+	}
+	h.db = db
+	return nil
+}
+
+// serverRows are the tables the main database keeps for the whole server
+// rather than for its codeplug
+type serverRows struct {
+	Users    []models.User
+	Tokens   []models.APIToken
+	Sessions []models.Session
+	Projects []models.Project
+}
+
+// tables lists the rows in the order they can be inserted
+func (s *serverRows) tables() []interface{} {
+	return []interface{}{&s.Users, &s.Tokens, &s.Sessions, &s.Projects}
+}
+
+func (s *serverRows) load(db *gorm.DB) error {
+	for _, rows := range s.tables() {
+		if err := db.Find(rows).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replace swaps db's server tables for s, then vacuums so no deleted
+// password or token hashes are left in free pages
+func (s *serverRows) replace(db *gorm.DB) error {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		all := tx.Session(&gorm.Session{AllowGlobalUpdate: true})
+		for _, m := range []interface{}{&models.Session{}, &models.APIToken{}, &models.User{}, &models.Project{}} {
+			if err := all.Delete(m).Error; err != nil {
+				return err
+			}
+		}
+		for _, rows := range s.tables() {
+			if err := tx.Omit(clause.Associations).Create(rows).Error; err != nil && !errors.Is(err, gorm.ErrEmptySlice) {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return db.Exec("VACUUM").Error
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// Workspaces is the server's main database plus the project databases in
+// dir, opened on first use. The project registry is kept in the main
+// database. With an empty dir only the main database is available.
+type Workspaces struct {
+	main *Handle
+	dir  string
+
+	mu   sync.Mutex
+	open map[string]*Handle
+}
+
+// NewWorkspaces serves main as the default project and keeps project files
+// in dir
+func NewWorkspaces(main *Handle, dir string) *Workspaces {
+	main.server = true
+	return &Workspaces{main: main, dir: dir, open: map[string]*Handle{}}
+}
+
+// Main returns the main database, which also holds accounts
+func (ws *Workspaces) Main() *Handle {
+	return ws.main
+}
+
+func (ws *Workspaces) path(id string) string {
+	return filepath.Join(ws.dir, id+".db")
+}
+
+// defaultProject describes the main database as a project
+func (ws *Workspaces) defaultProject() *models.Project {
+	return &models.Project{ID: models.DefaultProject, Name: "Default"}
+}
+
+// Project returns an open handle on the project's database
+func (ws *Workspaces) Project(id string) (*Handle, *models.Project, error) {
+	if id == models.DefaultProject {
+		return ws.main, ws.defaultProject(), nil
+	}
+	if ws.dir == "" {
+		return nil, nil, ErrProjectNotFound
+	}
+	var p models.Project
+	if err := ws.main.DB().First(&p, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, ErrProjectNotFound
+		}
+		return nil, nil, err
+	}
+	if p.Archived() {
+		return nil, &p, ErrProjectArchived
+	}
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if h, ok := ws.open[id]; ok {
+		return h, &p, nil
+	}
+	db, err := Open(ws.path(id))
+	if err != nil {
+		return nil, nil, err
+	}
+	h := NewHandle(db, ws.path(id))
+	ws.open[id] = h
+	return h, &p, nil
+}
+
+// Projects lists the default project followed by the others by name,
+// leaving out archived ones unless includeArchived
+func (ws *Workspaces) Projects(includeArchived bool) ([]models.Project, error) {
+	projects := []models.Project{*ws.defaultProject()}
+	if ws.dir == "" {
+		return projects, nil
+	}
+	var rows []models.Project
+	query := ws.main.DB().Order("name, id")
+	if !includeArchived {
+		query = query.Where("archived_at IS NULL")
+	}
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return append(projects, rows...), nil
+}
+
+// CreateProject registers p and creates its database, empty or as a copy of
+// the cloneFrom project
+func (ws *Workspaces) CreateProject(p *models.Project, cloneFrom string) error {
+	if ws.dir == "" {
+		return ErrProjectsDisabled
+	}
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	var count int64
+	ws.main.DB().Model(&models.Project{}).Where("id = ?", p.ID).Count(&count)
+	if count > 0 {
+		return ErrProjectExists
+	}
+	if err := os.MkdirAll(ws.dir, 0o755); err != nil {
+		return err
+	}
+	path := ws.path(p.ID)
+	if _, err := os.Stat(path); err == nil {
+		return ErrProjectExists
+	}
+
+	p.ClonedFrom = ""
+	if cloneFrom != "" {
+		src, _, err := ws.Project(cloneFrom)
+		if err != nil {
+			return fmt.Errorf("clone_from %s: %w", cloneFrom, err)
+		}
+		if err := src.Backup(path); err != nil {
+			os.Remove(path)
+			return fmt.Errorf("copying %s: %w", cloneFrom, err)
+		}
+		p.ClonedFrom = cloneFrom
+	}
+	db, err := Open(path)
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+	p.ArchivedAt = nil
+	if err := ws.main.DB().Create(p).Error; err != nil {
+		closeDB(db)
+		os.Remove(path)
+		return err
+	}
+
+	ws.mu.Lock()
+	ws.open[p.ID] = NewHandle(db, path)
+	ws.mu.Unlock()
+	return nil
+}
+
+// UpdateProject renames, describes, archives or unarchives a project; nil
+// arguments are left unchanged. Archiving closes the project's database but
+// keeps its file.
+func (ws *Workspaces) UpdateProject(id string, name, description *string, archived *bool) (*models.Project, error) {
+	if id == models.DefaultProject {
+		return nil, ErrDefaultProject
+	}
+	var p models.Project
+	if err := ws.main.DB().First(&p, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrProjectNotFound
+		}
+		return nil, err
+	}
+	if name != nil {
+		p.Name = *name
+	}
+	if description != nil {
+		p.Description = *description
+	}
+	if archived != nil {
+		if *archived && !p.Archived() {
+			now := time.Now()
+			p.ArchivedAt = &now
+		} else if !*archived {
+			p.ArchivedAt = nil
+		}
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	if err := ws.main.DB().Save(&p).Error; err != nil {
+		return nil, err
+	}
+
+	if p.Archived() {
+		ws.mu.Lock()
+		if h, ok := ws.open[id]; ok {
+			h.Close()
+			delete(ws.open, id)
+		}
+		ws.mu.Unlock()
+	}
+	return &p, nil
+}
+
+// Close closes every project database and the main one
+func (ws *Workspaces) Close() {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	for id, h := range ws.open {
+		h.Close()
+		delete(ws.open, id)
+	}
+	ws.main.Close()
+}
@@ -1,10 +1,12 @@
 package exporter
 
 import (
+	"bufio"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -13,37 +15,54 @@ import (
 	"gorm.io/gorm"
 )
 
-func ExportAnyTone890(db *gorm.DB, outputDir string, filterListID uint) error {
+// ExportAnyTone890 writes the CPS import CSVs to outputDir, returning the
+// names of the P25 channels it skipped
+func ExportAnyTone890(db *gorm.DB, outputDir string, filterListID uint) ([]string, error) {
+	var contactFilter *gorm.DB
+	if filterListID > 0 {
+		members, err := models.ContactListFilter(db, filterListID)
+		if err != nil {
+			return nil, err
+		}
+		contactFilter = members
+	}
+	return ExportAnyTone890Filtered(db, outputDir, contactFilter)
+}
+
+// ExportAnyTone890Filtered exports like ExportAnyTone890, restricting digital
+// contacts to the DMR IDs selected by contactFilter (nil exports all).
+func ExportAnyTone890Filtered(db *gorm.DB, outputDir string, contactFilter *gorm.DB) ([]string, error) {
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return err
+		return nil, err
 	}
 
 	f1, err := os.Create(filepath.Join(outputDir, "Channel.CSV"))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer f1.Close()
 	var channels []models.Channel
 	if err := db.Find(&channels).Error; err != nil {
-		return err
+		return nil, err
 	}
 	// Fetch contacts for lookup
 	var contacts []models.Contact
 	if err := db.Find(&contacts).Error; err != nil {
-		return err
+		return nil, err
 	}
 	contactMap := make(map[string]models.Contact)
 	for _, c := range contacts {
 		contactMap[c.Name] = c
 	}
 
-	if err := ExportAnyTone890Channels(channels, contactMap, f1); err != nil {
-		return err
+	skipped, err := ExportAnyTone890Channels(channels, contactMap, f1)
+	if err != nil {
+		return nil, err
 	}
 
 	f2, err := os.Create(filepath.Join(outputDir, "DMRTalkGroups.CSV"))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer f2.Close()
 	// Filter for talkgroups export
@@ -54,112 +73,147 @@ func ExportAnyTone890(db *gorm.DB, outputDir string, filterListID uint) error {
 		}
 	}
 	if err := ExportAnyTone890Talkgroups(talkgroups, f2); err != nil {
-		return err
+		return nil, err
 	}
 
 	f3, err := os.Create(filepath.Join(outputDir, "DMRZone.CSV"))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer f3.Close()
 	var zones []models.Zone
 	if err := db.Preload("Channels").Find(&zones).Error; err != nil {
-		return err
+		return nil, err
 	}
 	if err := ExportAnyTone890Zones(zones, f3); err != nil {
-		return err
+		return nil, err
 	}
 
 	f4, err := os.Create(filepath.Join(outputDir, "DMRDigitalContactList.CSV"))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer f4.Close()
 
 	// Digital Contacts Logic with Filter
-	var digitalContacts []models.DigitalContact
-	query := db.Model(&models.DigitalContact{})
+	query := db.Model(&models.DigitalContact{}).Where("retired_at IS NULL")
 
-	if filterListID > 0 {
+	if contactFilter != nil {
 		// Apply subquery filter
-		query = query.Where("dmr_id IN (?)", db.Model(&models.ContactListEntry{}).Select("dmr_id").Where("contact_list_id = ?", filterListID))
-	} else {
-		// Default limit if no filter? Or full dump?
-		// AnyTone limit is high (500k in 878UVII/890).
-		// Let's cap at 200k or just find all
-		// query = query.Limit(200000)
+		query = query.Where("dmr_id IN (?)", contactFilter)
 	}
+	// No default cap: AnyTone limit is high (500k in 878UVII/890), and
+	// contacts are streamed in batches rather than loaded at once.
 
-	if err := query.Find(&digitalContacts).Error; err != nil {
-		return err
+	bw := bufio.NewWriter(f4)
+	if err := StreamAnyTone890DigitalContacts(query, bw, ContactExportBatchSize); err != nil {
+		return nil, err
 	}
-	if err := ExportAnyTone890DigitalContacts(digitalContacts, f4); err != nil {
-		return err
+	if err := bw.Flush(); err != nil {
+		return nil, err
+	}
+
+	f8, err := os.Create(filepath.Join(outputDir, "NXDNTalkGroups.CSV"))
+	if err != nil {
+		return nil, err
+	}
+	defer f8.Close()
+	var nxdnTalkgroups []models.NXDNTalkgroup
+	if err := db.Order("tg_id asc").Find(&nxdnTalkgroups).Error; err != nil {
+		return nil, err
+	}
+	if err := ExportAnyTone890NXDNTalkgroups(nxdnTalkgroups, f8); err != nil {
+		return nil, err
+	}
+
+	f9, err := os.Create(filepath.Join(outputDir, "NXDNDigitalContactList.CSV"))
+	if err != nil {
+		return nil, err
+	}
+	defer f9.Close()
+	var nxdnContacts []models.NXDNContact
+	if err := db.Order("unit_id asc").Find(&nxdnContacts).Error; err != nil {
+		return nil, err
+	}
+	if err := ExportAnyTone890NXDNContacts(nxdnContacts, f9); err != nil {
+		return nil, err
 	}
 
 	f5, err := os.Create(filepath.Join(outputDir, "ScanList.CSV"))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer f5.Close()
 	var scanLists []models.ScanList
 	if err := db.Preload("Channels").Find(&scanLists).Error; err != nil {
-		return err
+		return nil, err
 	}
 	if err := ExportAnyTone890ScanLists(scanLists, f5); err != nil {
-		return err
+		return nil, err
 	}
 
 	f6, err := os.Create(filepath.Join(outputDir, "RoamChannel.CSV"))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer f6.Close()
 	var roamChans []models.RoamingChannel
 	if err := db.Find(&roamChans).Error; err != nil {
-		return err
+		return nil, err
 	}
 	if err := ExportAnyTone890RoamingChannels(roamChans, f6); err != nil {
-		return err
+		return nil, err
 	}
 
 	f7, err := os.Create(filepath.Join(outputDir, "RoamZone.CSV"))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer f7.Close()
 	var roamZones []models.RoamingZone
 	if err := db.Preload("Channels").Find(&roamZones).Error; err != nil {
-		return err
+		return nil, err
 	}
 	if err := ExportAnyTone890RoamingZones(roamZones, f7); err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return skipped, nil
 }
 
-func ExportAnyTone890Channels(channels []models.Channel, contactMap map[string]models.Contact, w io.Writer) error {
+// ExportAnyTone890Channels writes Channel.CSV. P25 channels are skipped, and
+// their names returned, rather than written as DMR.
+func ExportAnyTone890Channels(channels []models.Channel, contactMap map[string]models.Contact, w io.Writer) ([]string, error) {
+	channels, skipped := withoutP25(channels, "AnyTone 890")
 	header := []string{
 		"No.", "Channel Name", "Receive Frequency", "Transmit Frequency", "Channel Type", "Transmit Power", "Band Width", "CTCSS/DCS Decode", "CTCSS/DCS Encode", "Contact/Talk Group", "Contact/Talk Group Call Type", "Contact/Talk Group TG/DMR ID", "Radio ID", "Busy Lock/TX Permit", "Squelch Mode", "Optional Signal", "DTMF ID", "2Tone ID", "5Tone ID", "PTT ID", "RX Color Code", "Slot", "Scan List", "Receive Group List", "PTT Prohibit", "Reverse", "Digital Duplex", "Slot Suit", "AES Digital Encryption", "Digital Encryption", "Call Confirmation", "Talk Around(Simplex)", "Work Alone", "Custom CTCSS", "2TONE Decode", "Ranging", "Idle TX", "APRS RX", "Analog APRS PTT Mode", "Digital APRS PTT Mode", "APRS Report Type", "Digital APRS Report Channel", "Correct Frequency[Hz]", "SMS Confirmation", "Exclude channel from roaming", "DMR MODE", "DataACK Disable", "R5toneBot", "R5ToneEot", "Auto Scan", "Ana APRS Mute", "Send Talker Alias DMR/NX", "AnaAprsTxPath", "ARC4", "ex_emg_kind", "Rpga_Mdc", "DisturEn", "DisturFreq", "dmr_crc_ignore", "compand", "tx_talkalaes", "dup_call", "tx_int", "BtRxState", "idle_tx", "nxdn_wn", "NxdnRpga", "nxdnSqCon", "NxdnTxBusy", "NxDnPttId", "EnRan", "DeRan", "NxdnEncry", "NxdnGroupId", "NxdnIdNum", "NxdnStateNum", "txcc",
 	}
 
 	// Using manual writer for forced quotes
 	if err := writeAnyToneRecord(w, header); err != nil {
-		return err
+		return nil, err
 	}
 
 	// channels slice passed in
 
-	for i, c := range channels {
+	number := 0
+	for _, c := range channels {
 		record := make([]string, len(header))
-		record[0] = strconv.Itoa(i + 1)
+		if vfo := anyTone890VFONumber(c); vfo != "" {
+			record[0] = vfo
+		} else {
+			number++
+			record[0] = strconv.Itoa(number)
+		}
 		record[1] = c.Name
 		record[2] = fmt.Sprintf("%.5f", c.RxFrequency)
 		record[3] = fmt.Sprintf("%.5f", c.TxFrequency)
-		if c.IsDigital() {
+		switch {
+		case c.Protocol == models.ProtocolNXDN:
+			record[4] = "N-NXDN"
+		case c.IsDigital():
 			record[4] = "D-Digital"
-		} else {
+		default:
 			record[4] = "A-Analog"
 		}
 		record[5] = c.Power
@@ -173,18 +227,9 @@ func ExportAnyTone890Channels(channels []models.Channel, contactMap map[string]m
 		} else {
 			record[6] = strings.TrimSuffix(record[6], "Hz")
 		}
-		record[7] = c.RxTone
-		if record[7] == "" || record[7] == "None" {
-			record[7] = "Off"
-		}
-		record[8] = c.TxTone
-		if record[8] == "" || record[8] == "None" {
-			record[8] = "Off"
-		}
-		record[9] = c.TxContact
-		if record[9] == "" {
-			record[9] = "None"
-		}
+		record[7] = anyToneTone(c.RxTone, c.RxDCS)
+		record[8] = anyToneTone(c.TxTone, c.TxDCS)
+		record[9] = emptyToNone(c.TxContact)
 
 		// Lookup Contact
 		record[10] = "Group Call" // Default
@@ -201,28 +246,25 @@ func ExportAnyTone890Channels(channels []models.Channel, contactMap map[string]m
 			}
 		}
 
-		record[12] = "" // Radio ID (Blank in working for Analog)
+		record[12] = c.RadioID // Radio ID (Blank in working for Analog)
 		// For Digital, usually needs a value. If generic logic:
 		if c.IsDigital() && record[12] == "" {
 			record[12] = "1" // Default Radio ID for Digital
 		}
 
-		record[13] = c.TxPermit // Busy Lock/TX Permit
-		if record[13] == "" {
-			record[13] = "Off"
-		}
+		record[13] = radioTxPermit(c, models.VendorProfileAT890, "Busy Lock/TX Permit", c.TxPermit.AnyTone(c.IsDigital()))
 		record[14] = "Carrier" // Squelch Mode
-		record[15] = "Off"     // Optional Signal
-		record[16] = "1"       // DTMF ID
-		record[17] = "1"       // 2Tone ID
-		record[18] = "1"       // 5Tone ID
-		record[19] = "Off"     // PTT ID
+		record[15] = defaultString(c.OptionalSignal, "Off")
+		record[16] = defaultString(c.DtmfID, "1")
+		record[17] = defaultString(c.Tone2ID, "1")
+		record[18] = defaultString(c.Tone5ID, "1")
+		record[19] = defaultString(c.PttId, "Off")
 		record[20] = strconv.Itoa(c.ColorCode)
 		record[21] = strconv.Itoa(c.TimeSlot)
 		if c.TimeSlot == 0 {
 			record[21] = "1"
 		}
-		record[22] = "None" // Scan List
+		record[22] = emptyToNone(c.ScanList)
 		record[23] = c.RxGroup
 		if record[23] == "" {
 			record[23] = "None"
@@ -268,19 +310,25 @@ func ExportAnyTone890Channels(channels []models.Channel, contactMap map[string]m
 				record[j] = "0"
 			}
 		}
-		record[41] = "1" // Digital APRS Report Channel
-		record[76] = "1" // txcc
+		record[41] = "1"                         // Digital APRS Report Channel
+		record[70] = strconv.Itoa(c.NxdnRAN)     // EnRan
+		record[71] = strconv.Itoa(c.NxdnRAN)     // DeRan
+		record[73] = strconv.Itoa(c.NxdnGroupID) // NxdnGroupId
+		record[76] = "1"                         // txcc
+
+		applyVendorExtras(record, header, c.VendorExtras, "No.", "Busy Lock/TX Permit")
 
 		if err := writeAnyToneRecord(w, record); err != nil {
-			return err
+			return nil, err
 		}
 	}
-	return nil
+	return skipped, nil
 }
 
 func ExportAnyTone890Talkgroups(contacts []models.Contact, w io.Writer) error {
 	// Manual write for quotes
-	if err := writeAnyToneRecord(w, []string{"No.", "Radio ID", "Name", "Call Type", "Call Alert"}); err != nil {
+	talkgroupHeader := []string{"No.", "Radio ID", "Name", "Call Type", "Call Alert"}
+	if err := writeAnyToneRecord(w, talkgroupHeader); err != nil {
 		return err
 	}
 
@@ -294,7 +342,9 @@ func ExportAnyTone890Talkgroups(contacts []models.Contact, w io.Writer) error {
 		case models.ContactTypeAllCall:
 			cType = "All Call"
 		}
-		if err := writeAnyToneRecord(w, []string{strconv.Itoa(i + 1), strconv.Itoa(c.DMRID), c.Name, cType, "None"}); err != nil {
+		record := []string{strconv.Itoa(i + 1), strconv.Itoa(c.DMRID), c.Name, cType, c.CallAlert.AnyTone()}
+		applyVendorExtras(record, talkgroupHeader, c.VendorExtras, "No.", "Call Alert")
+		if err := writeAnyToneRecord(w, record); err != nil {
 			return err
 		}
 	}
@@ -302,13 +352,15 @@ func ExportAnyTone890Talkgroups(contacts []models.Contact, w io.Writer) error {
 }
 
 func ExportAnyTone890Zones(zones []models.Zone, w io.Writer) error {
-	if err := writeAnyToneRecord(w, []string{"No.", "Zone Name", "Zone Channel Member", "Zone Channel Member RX Frequency", "Zone Channel Member TX Frequency", "A Channel", "A Channel RX Frequency", "A Channel TX Frequency", "B Channel", "B Channel RX Frequency", "B Channel TX Frequency", "Zone Hide "}); err != nil {
+	zoneHeader := []string{"No.", "Zone Name", "Zone Channel Member", "Zone Channel Member RX Frequency", "Zone Channel Member TX Frequency", "A Channel", "A Channel RX Frequency", "A Channel TX Frequency", "B Channel", "B Channel RX Frequency", "B Channel TX Frequency", "Zone Hide "}
+	if err := writeAnyToneRecord(w, zoneHeader); err != nil {
 		return err
 	}
 
 	// zones slice passed in
 
 	for i, z := range zones {
+		z.Channels = memberChannels(z.Channels)
 		var chanNames []string
 		for _, c := range z.Channels {
 			chanNames = append(chanNames, c.Name)
@@ -357,7 +409,7 @@ func ExportAnyTone890Zones(zones []models.Zone, w io.Writer) error {
 			bTx = fmt.Sprintf("%.5f", z.Channels[0].TxFrequency)
 		}
 
-		if err := writeAnyToneRecord(w, []string{
+		record := []string{
 			strconv.Itoa(i + 1),
 			z.Name,
 			memberStr,
@@ -365,26 +417,54 @@ func ExportAnyTone890Zones(zones []models.Zone, w io.Writer) error {
 			aChan, aRx, aTx, // A Channel
 			bChan, bRx, bTx, // B Channel
 			"0", // Zone Hide
-		}); err != nil {
+		}
+		applyVendorExtras(record, zoneHeader, z.VendorExtras, zoneHeader[:11]...)
+		if err := writeAnyToneRecord(w, record); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// ContactExportBatchSize is the number of digital contacts loaded per query when streaming an export
+const ContactExportBatchSize = 5000
+
+var anyTone890DigitalContactHeader = []string{"No.", "Radio ID", "Callsign", "Name", "City", "State", "Country", "Remarks", "Call Type", "Call Alert"}
+
 func ExportAnyTone890DigitalContacts(contacts []models.DigitalContact, w io.Writer) error {
-	if err := writeAnyToneRecord(w, []string{"No.", "Radio ID", "Callsign", "Name", "City", "State", "Country", "Remarks", "Call Type", "Call Alert"}); err != nil {
+	if err := writeAnyToneRecord(w, anyTone890DigitalContactHeader); err != nil {
 		return err
 	}
+	return writeAnyTone890DigitalContactRows(contacts, 0, w)
+}
 
-	// contacts slice passed in
-	// Use manual loop
+// StreamAnyTone890DigitalContacts writes the contacts matched by query,
+// loading them batchSize rows at a time so memory stays bounded. Contact
+// overrides are applied per batch.
+func StreamAnyTone890DigitalContacts(query *gorm.DB, w io.Writer, batchSize int) error {
+	if err := writeAnyToneRecord(w, anyTone890DigitalContactHeader); err != nil {
+		return err
+	}
+
+	written := 0
+	var batch []models.DigitalContact
+	overrides := query.Session(&gorm.Session{NewDB: true})
+	return query.FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		if err := models.ApplyContactOverrides(overrides, batch); err != nil {
+			return err
+		}
+		if err := writeAnyTone890DigitalContactRows(batch, written, w); err != nil {
+			return err
+		}
+		written += len(batch)
+		return nil
+	}).Error
+}
+
+func writeAnyTone890DigitalContactRows(contacts []models.DigitalContact, offset int, w io.Writer) error {
 	for i, c := range contacts {
-		// Offset index by 0 as we iterate slice
-		// But note: batch logic in original version. Here we just stream.
-		idx := i + 1
 		if err := writeAnyToneRecord(w, []string{
-			strconv.Itoa(idx),
+			strconv.Itoa(offset + i + 1),
 			strconv.Itoa(c.DMRID),
 			c.Callsign,
 			c.Name,
@@ -393,7 +473,7 @@ func ExportAnyTone890DigitalContacts(contacts []models.DigitalContact, w io.Writ
 			c.Country,
 			c.Remarks,
 			"Private Call",
-			"None",
+			c.CallAlert.AnyTone(),
 		}); err != nil {
 			return err
 		}
@@ -401,7 +481,90 @@ func ExportAnyTone890DigitalContacts(contacts []models.DigitalContact, w io.Writ
 	return nil
 }
 
-// Helper to write a record with forced quotes around every field
+func ExportAnyTone890NXDNTalkgroups(talkgroups []models.NXDNTalkgroup, w io.Writer) error {
+	if err := writeAnyToneRecord(w, []string{"No.", "ID", "Name"}); err != nil {
+		return err
+	}
+
+	for i, tg := range talkgroups {
+		if err := writeAnyToneRecord(w, []string{strconv.Itoa(i + 1), strconv.Itoa(tg.TGID), tg.Name}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ExportAnyTone890NXDNContacts(contacts []models.NXDNContact, w io.Writer) error {
+	if err := writeAnyToneRecord(w, []string{"No.", "ID", "Callsign", "Name", "City", "State", "Country", "Remarks"}); err != nil {
+		return err
+	}
+
+	for i, c := range contacts {
+		if err := writeAnyToneRecord(w, []string{
+			strconv.Itoa(i + 1),
+			strconv.Itoa(c.UnitID),
+			c.Callsign,
+			c.Name,
+			c.City,
+			c.State,
+			c.Country,
+			c.Remarks,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyVendorExtras overwrites columns with values kept from an AnyTone import.
+// Computed columns are skipped, since older imports kept stale values for them.
+func applyVendorExtras(record, header []string, extras models.VendorExtras, computed ...string) {
+	for i, h := range header {
+		if slices.Contains(computed, h) {
+			continue
+		}
+		if val, ok := extras.Get(models.VendorProfileAT890, strings.TrimSpace(h)); ok {
+			record[i] = val
+		}
+	}
+}
+
+// radioTxPermit is rendered unless the radio's own TX permit setting, kept
+// in its extras under column on import, still reads as the channel's permit
+func radioTxPermit(c models.Channel, profile, column, rendered string) string {
+	if raw, ok := c.VendorExtras.Get(profile, column); ok {
+		if permit, _ := models.ParseTxPermit(raw); permit == c.TxPermit {
+			return raw
+		}
+	}
+	return rendered
+}
+
+// anyTone890VFONumber is the fixed channel number of an imported VFO channel,
+// or "" for a channel numbered by position
+func anyTone890VFONumber(c models.Channel) string {
+	no, ok := c.VendorExtras.Get(models.VendorProfileAT890, "No.")
+	if n, err := strconv.Atoi(no); !ok || err != nil || n < models.AT890VFOChannelNo {
+		return ""
+	}
+	return no
+}
+
+// anyToneTone renders a CTCSS tone or DCS code for the CTCSS/DCS columns.
+func anyToneTone(tone, dcs string) string {
+	if v := dm32uvTone(tone, dcs); v != "None" {
+		return v
+	}
+	return "Off"
+}
+
+func defaultString(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
 // Helper to write a record with forced quotes around every field
 func writeAnyToneRecord(w io.Writer, record []string) error {
 	for i, field := range record {
@@ -432,7 +595,7 @@ func ExportAnyTone890ScanLists(lists []models.ScanList, w io.Writer) error {
 		var chanNames []string
 		var rxFreqs []string
 		var txFreqs []string
-		for _, c := range l.Channels {
+		for _, c := range memberChannels(l.Channels) {
 			chanNames = append(chanNames, c.Name)
 			rxFreqs = append(rxFreqs, fmt.Sprintf("%.5f", c.RxFrequency))
 			txFreqs = append(txFreqs, fmt.Sprintf("%.5f", c.TxFrequency))
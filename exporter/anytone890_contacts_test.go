@@ -0,0 +1,128 @@
+package exporter
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"codeplugs/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestStreamAnyTone890DigitalContacts_MatchesSliceExport(t *testing.T) {
+	db := setupAnyToneTestDB(t)
+	db.Exec("DELETE FROM digital_contacts")
+
+	var contacts []models.DigitalContact
+	for i := 0; i < 12; i++ {
+		contacts = append(contacts, models.DigitalContact{
+			DMRID:    3100000 + i,
+			Callsign: fmt.Sprintf("K%dTST", i),
+			Name:     fmt.Sprintf("Op %d", i),
+			Country:  "United States",
+		})
+	}
+	if err := db.Create(&contacts).Error; err != nil {
+		t.Fatalf("seed failed: %v", err)
+	}
+
+	var want bytes.Buffer
+	if err := ExportAnyTone890DigitalContacts(contacts, &want); err != nil {
+		t.Fatalf("slice export failed: %v", err)
+	}
+
+	var got bytes.Buffer
+	if err := StreamAnyTone890DigitalContacts(db.Model(&models.DigitalContact{}), &got, 5); err != nil {
+		t.Fatalf("stream export failed: %v", err)
+	}
+
+	if !bytes.Equal(want.Bytes(), got.Bytes()) {
+		t.Errorf("Streamed export differs from slice export:\n%s\nvs\n%s", got.String(), want.String())
+	}
+}
+
+func TestContactOverrides_AppliedToExports(t *testing.T) {
+	db := setupAnyToneTestDB(t)
+	db.Exec("DELETE FROM digital_contacts")
+	db.Exec("DELETE FROM contact_overrides")
+
+	db.Create(&[]models.DigitalContact{
+		{DMRID: 3126001, Callsign: "KF8AAA", Name: "Alice", Remarks: "RadioID note"},
+		{DMRID: 3126002, Callsign: "KF8BBB", Name: "Bob", Remarks: "Keep me"},
+	})
+	remarks := "Club president"
+	db.Create(&models.ContactOverride{DMRID: 3126001, CallAlert: models.CallAlertRing, Remarks: &remarks})
+
+	var at bytes.Buffer
+	if err := StreamAnyTone890DigitalContacts(db.Model(&models.DigitalContact{}).Order("dmr_id"), &at, 1); err != nil {
+		t.Fatalf("stream export failed: %v", err)
+	}
+	want := `"1","3126001","KF8AAA","Alice","","","","Club president","Private Call","Ring"`
+	if !bytes.Contains(at.Bytes(), []byte(want)) {
+		t.Errorf("AnyTone export missing override row %s:\n%s", want, at.String())
+	}
+	want = `"2","3126002","KF8BBB","Bob","","","","Keep me","Private Call","None"`
+	if !bytes.Contains(at.Bytes(), []byte(want)) {
+		t.Errorf("AnyTone export changed contact without override, want %s:\n%s", want, at.String())
+	}
+
+	var contacts []models.DigitalContact
+	db.Order("dmr_id").Find(&contacts)
+	if err := models.ApplyContactOverrides(db, contacts); err != nil {
+		t.Fatal(err)
+	}
+	var dm bytes.Buffer
+	if err := ExportDM32UVDigitalContacts(contacts, &dm); err != nil {
+		t.Fatal(err)
+	}
+	want = "1,3126001,KF8AAA,Alice,,,,Club president,Private Call,1\n2,3126002,KF8BBB,Bob,,,,Keep me,Private Call,0\n"
+	if !bytes.HasSuffix(dm.Bytes(), []byte(want)) {
+		t.Errorf("DM32UV export = %q, want suffix %q", dm.String(), want)
+	}
+}
+
+// BenchmarkStreamAnyTone890DigitalContacts_300k exports 300k contacts in batches.
+// Run with: go test ./exporter -run x -bench StreamAnyTone890 -benchmem
+func BenchmarkStreamAnyTone890DigitalContacts_300k(b *testing.B) {
+	db, err := gorm.Open(sqlite.Dialector{
+		DriverName: "sqlite",
+		DSN:        "file:memdb_at890_contacts_bench?mode=memory&cache=shared",
+	}, &gorm.Config{})
+	if err != nil {
+		b.Fatalf("failed to connect database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.DigitalContact{}, &models.ContactOverride{}); err != nil {
+		b.Fatalf("failed to migrate database: %v", err)
+	}
+	db.Exec("DELETE FROM digital_contacts")
+
+	batch := make([]models.DigitalContact, 0, 100)
+	for i := 0; i < 300000; i++ {
+		batch = append(batch, models.DigitalContact{
+			DMRID:    1000000 + i,
+			Callsign: fmt.Sprintf("K%dABC", i%10),
+			Name:     fmt.Sprintf("First%d Last", i),
+			Country:  "United States",
+		})
+		if len(batch) == cap(batch) {
+			if err := db.Create(&batch).Error; err != nil {
+				b.Fatal(err)
+			}
+			batch = batch[:0]
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w := bufio.NewWriter(io.Discard)
+		if err := StreamAnyTone890DigitalContacts(db.Model(&models.DigitalContact{}), w, ContactExportBatchSize); err != nil {
+			b.Fatal(err)
+		}
+		w.Flush()
+	}
+}
@@ -1,11 +1,15 @@
 package exporter
 
 import (
+	"bytes"
 	"encoding/csv"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
+	"codeplugs/importer"
 	"codeplugs/models"
 
 	"gorm.io/driver/sqlite"
@@ -30,6 +34,10 @@ func setupAnyToneTestDB(t *testing.T) *gorm.DB {
 		&models.RoamingZone{},
 		&models.ScanList{},
 		&models.ContactListEntry{},
+		&models.ContactListRule{},
+		&models.NXDNTalkgroup{},
+		&models.NXDNContact{},
+		&models.ContactOverride{},
 	)
 	if err != nil {
 		t.Fatalf("failed to migrate database: %v", err)
@@ -55,7 +63,7 @@ func TestExportAnyTone890(t *testing.T) {
 	}
 	defer os.RemoveAll(tmpDir)
 
-	if err = ExportAnyTone890(db, tmpDir, 0); err != nil {
+	if _, err = ExportAnyTone890(db, tmpDir, 0); err != nil {
 		t.Fatalf("ExportAnyTone890 failed: %v", err)
 	}
 
@@ -92,3 +100,271 @@ func TestExportAnyTone890(t *testing.T) {
 		t.Errorf("DMRZone.CSV not generated")
 	}
 }
+
+func TestAnyTone890_ByteForByteRoundTrip(t *testing.T) {
+	db, err := gorm.Open(sqlite.Dialector{
+		DriverName: "sqlite",
+		DSN:        "file:memdb_at890_golden?mode=memory&cache=shared",
+	}, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to connect database: %v", err)
+	}
+	db.SetupJoinTable(&models.Zone{}, "Channels", &models.ZoneChannel{})
+	if err := db.AutoMigrate(&models.Channel{}, &models.Contact{}, &models.Zone{}, &models.ZoneChannel{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	sampleDir := filepath.Join("..", "anytone-890", "anytone890-kf8s-2025-12-11")
+	read := func(name string) []byte {
+		raw, err := os.ReadFile(filepath.Join(sampleDir, name))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", name, err)
+		}
+		return raw
+	}
+	tgRaw, chRaw, zoneRaw := read("DMRTalkGroups.CSV"), read("Channel.CSV"), read("DMRZone.CSV")
+
+	if err := importer.ImportAnyTone890Talkgroups(db, bytes.NewReader(tgRaw)); err != nil {
+		t.Fatalf("ImportAnyTone890Talkgroups failed: %v", err)
+	}
+	if err := importer.ImportAnyTone890Channels(db, bytes.NewReader(chRaw)); err != nil {
+		t.Fatalf("ImportAnyTone890Channels failed: %v", err)
+	}
+	if err := importer.ImportAnyTone890Zones(db, bytes.NewReader(zoneRaw)); err != nil {
+		t.Fatalf("ImportAnyTone890Zones failed: %v", err)
+	}
+
+	var contacts []models.Contact
+	db.Order("id asc").Find(&contacts)
+	contactMap := make(map[string]models.Contact)
+	for _, c := range contacts {
+		contactMap[c.Name] = c
+	}
+	var channels []models.Channel
+	db.Order("id asc").Find(&channels)
+	var zones []models.Zone
+	db.Preload("Channels").Order("id asc").Find(&zones)
+
+	var chOut, tgOut, zoneOut bytes.Buffer
+	if _, err := ExportAnyTone890Channels(channels, contactMap, &chOut); err != nil {
+		t.Fatalf("ExportAnyTone890Channels failed: %v", err)
+	}
+	if err := ExportAnyTone890Talkgroups(contacts, &tgOut); err != nil {
+		t.Fatalf("ExportAnyTone890Talkgroups failed: %v", err)
+	}
+	if err := ExportAnyTone890Zones(zones, &zoneOut); err != nil {
+		t.Fatalf("ExportAnyTone890Zones failed: %v", err)
+	}
+
+	for name, pair := range map[string][2][]byte{
+		"Channel.CSV":       {chRaw, chOut.Bytes()},
+		"DMRTalkGroups.CSV": {tgRaw, tgOut.Bytes()},
+		"DMRZone.CSV":       {zoneRaw, zoneOut.Bytes()},
+	} {
+		if !bytes.Equal(pair[0], pair[1]) {
+			t.Errorf("%s differs after round trip:\nwant:\n%s\ngot:\n%s", name, pair[0], pair[1])
+		}
+	}
+}
+
+func TestAnyTone890_EditAfterImport(t *testing.T) {
+	db, err := gorm.Open(sqlite.Dialector{
+		DriverName: "sqlite",
+		DSN:        "file:memdb_at890_edit?mode=memory&cache=shared",
+	}, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to connect database: %v", err)
+	}
+	db.SetupJoinTable(&models.Zone{}, "Channels", &models.ZoneChannel{})
+	if err := db.AutoMigrate(&models.Channel{}, &models.Zone{}, &models.ZoneChannel{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	channelCSV := `"No.","Channel Name","Receive Frequency","Transmit Frequency","Channel Type","Squelch Mode"
+"1","Alpha","440.00000","445.00000","A-Analog","Carrier"
+"2","Bravo","441.00000","446.00000","A-Analog","Carrier"
+"3","Charlie","442.00000","447.00000","A-Analog","Carrier"
+"4001","Channel VFO A","440.00000","440.00000","D-Digital","Carrier"`
+	zoneCSV := `"No.","Zone Name","Zone Channel Member","Zone Channel Member RX Frequency","Zone Channel Member TX Frequency","A Channel","A Channel RX Frequency","A Channel TX Frequency","B Channel","B Channel RX Frequency","B Channel TX Frequency","Zone Hide "
+"1","Repeaters","Alpha|Bravo","440.00000|441.00000","445.00000|446.00000","Alpha","440.00000","445.00000","Bravo","441.00000","446.00000","0"`
+	if err := importer.ImportAnyTone890Channels(db, strings.NewReader(channelCSV)); err != nil {
+		t.Fatalf("ImportAnyTone890Channels failed: %v", err)
+	}
+	if err := importer.ImportAnyTone890Zones(db, strings.NewReader(zoneCSV)); err != nil {
+		t.Fatalf("ImportAnyTone890Zones failed: %v", err)
+	}
+
+	byName := map[string]models.Channel{}
+	var imported []models.Channel
+	db.Order("id asc").Find(&imported)
+	for _, c := range imported {
+		byName[c.Name] = c
+	}
+
+	// Add a channel at the top and reorder the rest
+	channels := []models.Channel{
+		{Name: "New", RxFrequency: 146.52, TxFrequency: 146.52},
+		byName["Charlie"], byName["Bravo"], byName["Alpha"], byName["Channel VFO A"],
+	}
+	var chOut bytes.Buffer
+	if _, err := ExportAnyTone890Channels(channels, nil, &chOut); err != nil {
+		t.Fatalf("ExportAnyTone890Channels failed: %v", err)
+	}
+	records, err := csv.NewReader(&chOut).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var numbers []string
+	for _, rec := range records[1:] {
+		numbers = append(numbers, rec[0]+"="+rec[1])
+	}
+	want := []string{"1=New", "2=Charlie", "3=Bravo", "4=Alpha", "4001=Channel VFO A"}
+	if !slices.Equal(numbers, want) {
+		t.Errorf("Expected channels numbered by position with the VFO kept, got %v", numbers)
+	}
+
+	// Replace the zone's members; A and B follow them, not the import
+	var zone models.Zone
+	if err := db.Where("name = ?", "Repeaters").First(&zone).Error; err != nil {
+		t.Fatal(err)
+	}
+	zone.Channels = []models.Channel{byName["Charlie"], byName["Bravo"]}
+	var zoneOut bytes.Buffer
+	if err := ExportAnyTone890Zones([]models.Zone{zone}, &zoneOut); err != nil {
+		t.Fatalf("ExportAnyTone890Zones failed: %v", err)
+	}
+	records, err = csv.NewReader(&zoneOut).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	row := records[1]
+	if row[2] != "Charlie|Bravo" || row[5] != "Charlie" || row[6] != "442.00000" || row[8] != "Bravo" {
+		t.Errorf("Expected A/B channels from the edited members, got %v", row)
+	}
+	if row[11] != "0" {
+		t.Errorf("Expected Zone Hide kept from the import, got %q", row[11])
+	}
+}
+
+func TestAnyTone890_SplitRAN(t *testing.T) {
+	db, err := gorm.Open(sqlite.Dialector{
+		DriverName: "sqlite",
+		DSN:        "file:memdb_at890_ran?mode=memory&cache=shared",
+	}, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to connect database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Channel{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	db.Exec("DELETE FROM channels")
+
+	channelCSV := `"No.","Channel Name","Receive Frequency","Transmit Frequency","Channel Type","EnRan","DeRan"
+"1","Split","440.00000","445.00000","N-Digital","3","5"
+"2","Same","441.00000","446.00000","N-Digital","7","7"`
+	if err := importer.ImportAnyTone890Channels(db, strings.NewReader(channelCSV)); err != nil {
+		t.Fatalf("ImportAnyTone890Channels failed: %v", err)
+	}
+	var channels []models.Channel
+	db.Order("id asc").Find(&channels)
+	if len(channels) != 2 {
+		t.Fatalf("Expected 2 channels, got %d", len(channels))
+	}
+	// Editing the RAN of a channel whose RX and TX RANs matched moves both
+	channels[1].NxdnRAN = 9
+
+	var out bytes.Buffer
+	if _, err := ExportAnyTone890Channels(channels, nil, &out); err != nil {
+		t.Fatalf("ExportAnyTone890Channels failed: %v", err)
+	}
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	en, de := slices.Index(records[0], "EnRan"), slices.Index(records[0], "DeRan")
+	if got := records[1][en] + "/" + records[1][de]; got != "3/5" {
+		t.Errorf("Expected the split RAN kept as 3/5, got %s", got)
+	}
+	if got := records[2][en] + "/" + records[2][de]; got != "9/9" {
+		t.Errorf("Expected the edited RAN on both columns, got %s", got)
+	}
+}
+
+func TestAnyTone890Talkgroups_StaleCallAlertExtra(t *testing.T) {
+	// Imports before call alerts were modelled kept the column as an extra
+	c := models.Contact{Name: "TG 91", DMRID: 91, Type: models.ContactTypeGroup, CallAlert: models.CallAlertRing}
+	c.VendorExtras.Set(models.VendorProfileAT890, "Call Alert", "None")
+
+	var out bytes.Buffer
+	if err := ExportAnyTone890Talkgroups([]models.Contact{c}, &out); err != nil {
+		t.Fatalf("ExportAnyTone890Talkgroups failed: %v", err)
+	}
+	want := `"1","91","TG 91","Group Call","Ring"`
+	if !strings.Contains(out.String(), want) {
+		t.Errorf("Expected %s, got:\n%s", want, out.String())
+	}
+}
+
+func TestTxPermit_AcrossRadios(t *testing.T) {
+	db, err := gorm.Open(sqlite.Dialector{
+		DriverName: "sqlite",
+		DSN:        "file:memdb_tx_permit?mode=memory&cache=shared",
+	}, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to connect database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Channel{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	db.Exec("DELETE FROM channels")
+
+	dm32uvCSV := `No.,Channel Name,Channel Type,RX Frequency[MHz],TX Frequency[MHz],TX Admit
+1,DM Analog,Analog,146.52000,146.52000,Allow TX
+2,DM Color Code,Digital,441.00000,446.00000,Color Code Idle`
+	if err := importer.ImportDM32UVChannels(db, strings.NewReader(dm32uvCSV)); err != nil {
+		t.Fatalf("ImportDM32UVChannels failed: %v", err)
+	}
+	anyToneCSV := `"No.","Channel Name","Receive Frequency","Transmit Frequency","Channel Type","Busy Lock/TX Permit"
+"1","AT Free","442.00000","447.00000","D-Digital","Channel Free"
+"2","AT Repeater","146.94000","146.34000","A-Analog","Repeater"
+"3","AT Edited","443.00000","448.00000","D-Digital","Different Color Code"`
+	if err := importer.ImportAnyTone890Channels(db, strings.NewReader(anyToneCSV)); err != nil {
+		t.Fatalf("ImportAnyTone890Channels failed: %v", err)
+	}
+	var channels []models.Channel
+	db.Order("id asc").Find(&channels)
+	if len(channels) != 5 {
+		t.Fatalf("Expected 5 channels, got %d", len(channels))
+	}
+	// An edited permit drops the radio's own setting it was imported with
+	channels[4].TxPermit = models.TxPermitAlways
+
+	var atOut, dmOut bytes.Buffer
+	if _, err := ExportAnyTone890Channels(channels, nil, &atOut); err != nil {
+		t.Fatalf("ExportAnyTone890Channels failed: %v", err)
+	}
+	if _, err := ExportDM32UVChannels(channels, &dmOut); err != nil {
+		t.Fatalf("ExportDM32UVChannels failed: %v", err)
+	}
+	column := func(out *bytes.Buffer, name string) []string {
+		records, err := csv.NewReader(out).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		i := slices.Index(records[0], name)
+		var values []string
+		for _, r := range records[1:] {
+			values = append(values, r[i])
+		}
+		return values
+	}
+
+	wantAT := []string{"Off", "Same Color Code", "Channel Free", "Repeater", "Always"}
+	if got := column(&atOut, "Busy Lock/TX Permit"); !slices.Equal(got, wantAT) {
+		t.Errorf("Expected AnyTone permits %v, got %v", wantAT, got)
+	}
+	wantDM := []string{"Allow TX", "Color Code Idle", "Channel Idle", "Channel Idle", "Always"}
+	if got := column(&dmOut, "TX Admit"); !slices.Equal(got, wantDM) {
+		t.Errorf("Expected DM32UV permits %v, got %v", wantDM, got)
+	}
+}
@@ -9,17 +9,21 @@ import (
 	"codeplugs/models"
 )
 
-// ExportChirpCSV exports channels to a Chirp-formatted CSV file.
-func ExportChirpCSV(channels []models.Channel, w io.Writer) error {
+// ExportChirpCSV exports channels to a Chirp-formatted CSV file. CHIRP has no
+// P25 support, so P25 channels are skipped rather than written as analog;
+// their names are returned.
+func ExportChirpCSV(channels []models.Channel, w io.Writer) ([]string, error) {
 	writer := csv.NewWriter(w)
 	defer writer.Flush()
 
+	channels, skipped := withoutP25(channels, "CHIRP")
+
 	// Chirp Header
 	header := []string{
 		"Location", "Name", "Frequency", "Duplex", "Offset", "Tone", "rToneFreq", "cToneFreq", "DtcsCode", "DtcsPolarity", "RxDtcsCode", "CrossMode", "Mode", "TStep", "Skip", "Power", "Comment", "URCALL", "RPT1CALL", "RPT2CALL", "DVCODE",
 	}
 	if err := writer.Write(header); err != nil {
-		return err
+		return nil, err
 	}
 
 	for i, ch := range channels {
@@ -145,10 +149,25 @@ func ExportChirpCSV(channels []models.Channel, w io.Writer) error {
 
 		record[16] = ch.Notes
 
+		// D-Star routing
+		if ch.Protocol == models.ProtocolDStar || ch.Mode == "DV" {
+			record[12] = "DV"
+			record[17] = ch.DStarURCall
+			if record[17] == "" {
+				record[17] = "CQCQCQ"
+			}
+			record[18] = ch.DStarRPT1Call
+			record[19] = ch.DStarRPT2Call
+			if record[19] == "" {
+				record[19] = ch.DStarGateway
+			}
+			record[20] = strconv.Itoa(ch.DStarDVCode)
+		}
+
 		if err := writer.Write(record); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	return nil
+	return skipped, nil
 }
@@ -50,7 +50,7 @@ func TestExportChirpCSV_GenericFormat(t *testing.T) {
 	}
 
 	buf := new(bytes.Buffer)
-	err := ExportChirpCSV(channels, buf)
+	_, err := ExportChirpCSV(channels, buf)
 	if err != nil {
 		t.Fatalf("Export failed: %v", err)
 	}
@@ -119,3 +119,75 @@ func TestExportChirpCSV_GenericFormat(t *testing.T) {
 		t.Errorf("R4 RxTone mismatch: expected 88.5, got %s", r4[headerMap["cToneFreq"]])
 	}
 }
+
+func TestExportChirpCSV_DStar(t *testing.T) {
+	channels := []models.Channel{
+		{
+			Name:          "W8DET B",
+			Mode:          "DV",
+			Type:          models.ChannelTypeDigitalDStar,
+			Protocol:      models.ProtocolDStar,
+			RxFrequency:   145.33,
+			TxFrequency:   144.73,
+			DStarRPT1Call: "W8DET  B",
+			DStarGateway:  "W8DET  G",
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	if _, err := ExportChirpCSV(channels, buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	records, err := csv.NewReader(buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+
+	row := records[1]
+	if row[12] != "DV" {
+		t.Errorf("Expected mode DV, got %s", row[12])
+	}
+	if row[17] != "CQCQCQ" {
+		t.Errorf("Expected default URCALL CQCQCQ, got %q", row[17])
+	}
+	if row[18] != "W8DET  B" {
+		t.Errorf("Expected RPT1CALL 'W8DET  B', got %q", row[18])
+	}
+	if row[19] != "W8DET  G" {
+		t.Errorf("Expected RPT2CALL to fall back to gateway, got %q", row[19])
+	}
+	if row[20] != "0" {
+		t.Errorf("Expected DVCODE 0, got %q", row[20])
+	}
+}
+
+func TestExportChirpCSV_SkipsP25(t *testing.T) {
+	channels := []models.Channel{
+		{Name: "P25 Ch", Mode: "P25", Type: models.ChannelTypeDigitalP25, Protocol: models.ProtocolP25, RxFrequency: 851.0125},
+		{Name: "Simplex", Mode: "FM", Protocol: models.ProtocolFM, RxFrequency: 146.52, TxFrequency: 146.52},
+	}
+
+	buf := new(bytes.Buffer)
+	skipped, err := ExportChirpCSV(channels, buf)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if len(skipped) != 1 || skipped[0] != "P25 Ch" {
+		t.Errorf("Expected P25 Ch to be reported as skipped, got %v", skipped)
+	}
+
+	records, err := csv.NewReader(buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected header + 1 channel, got %d records", len(records))
+	}
+	if records[1][1] != "Simplex" {
+		t.Errorf("Expected only Simplex to be exported, got %s", records[1][1])
+	}
+}
@@ -14,110 +14,120 @@ import (
 	"gorm.io/gorm"
 )
 
-func ExportDM32UV(db *gorm.DB, outputDir string) error {
+// ExportDM32UV writes the DM32UV CSVs to outputDir, returning the names of
+// the P25 channels it skipped
+func ExportDM32UV(db *gorm.DB, outputDir string) ([]string, error) {
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return err
+		return nil, err
 	}
 
 	f1, err := os.Create(filepath.Join(outputDir, "channels.csv"))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer f1.Close()
 	var channels []models.Channel
 	if err := db.Find(&channels).Error; err != nil {
-		return err
+		return nil, err
 	}
-	if err := ExportDM32UVChannels(channels, f1); err != nil {
-		return err
+	skipped, err := ExportDM32UVChannels(channels, f1)
+	if err != nil {
+		return nil, err
 	}
 
 	f2, err := os.Create(filepath.Join(outputDir, "talkgroups.csv"))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer f2.Close()
 	var talkgroups []models.Contact
 	if err := db.Where("type IN ?", []models.ContactType{models.ContactTypeGroup, models.ContactTypePrivate, models.ContactTypeAllCall}).Find(&talkgroups).Error; err != nil {
-		return err
+		return nil, err
 	}
 	if err := ExportDM32UVTalkgroups(talkgroups, f2); err != nil {
-		return err
+		return nil, err
 	}
 
 	f3, err := os.Create(filepath.Join(outputDir, "zones.csv"))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer f3.Close()
 	var zones []models.Zone
 	if err := db.Preload("Channels").Find(&zones).Error; err != nil {
-		return err
+		return nil, err
 	}
 	if err := ExportDM32UVZones(zones, f3); err != nil {
-		return err
+		return nil, err
 	}
 
 	f4, err := os.Create(filepath.Join(outputDir, "digital_contacts.csv"))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer f4.Close()
 	var digitalContacts []models.DigitalContact
 	// Fetch all for bulk export - might be large
 	if err := db.Find(&digitalContacts).Error; err != nil {
-		return err
+		return nil, err
+	}
+	if err := models.ApplyContactOverrides(db, digitalContacts); err != nil {
+		return nil, err
 	}
 	if err := ExportDM32UVDigitalContacts(digitalContacts, f4); err != nil {
-		return err
+		return nil, err
 	}
 
 	f5, err := os.Create(filepath.Join(outputDir, "scan_lists.csv"))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer f5.Close()
 	var scanLists []models.ScanList
 	if err := db.Preload("Channels").Find(&scanLists).Error; err != nil {
-		return err
+		return nil, err
 	}
 	if err := ExportDM32UVScanLists(scanLists, f5); err != nil {
-		return err
+		return nil, err
 	}
 
 	f6, err := os.Create(filepath.Join(outputDir, "roaming_channels.csv"))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer f6.Close()
 	var roamChans []models.RoamingChannel
 	if err := db.Find(&roamChans).Error; err != nil {
-		return err
+		return nil, err
 	}
 	if err := ExportDM32UVRoamingChannels(roamChans, f6); err != nil {
-		return err
+		return nil, err
 	}
 
 	f7, err := os.Create(filepath.Join(outputDir, "roaming_zones.csv"))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer f7.Close()
 	var roamZones []models.RoamingZone
 	if err := db.Preload("Channels").Find(&roamZones).Error; err != nil {
-		return err
+		return nil, err
 	}
 	if err := ExportDM32UVRoamingZones(roamZones, f7); err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return skipped, nil
 }
 
-func ExportDM32UVChannels(channels []models.Channel, w io.Writer) error {
+// ExportDM32UVChannels writes channels.csv. The DM32UV has no P25, so P25
+// channels are skipped, and their names returned, rather than written as DMR.
+func ExportDM32UVChannels(channels []models.Channel, w io.Writer) ([]string, error) {
 	writer := csv.NewWriter(w)
 	defer writer.Flush()
 
+	channels, skipped := withoutP25(channels, "DM32UV")
+
 	// Header from sample
 	header := []string{
 		"No.", "Channel Name", "Channel Type", "RX Frequency[MHz]", "TX Frequency[MHz]", "Power", "Band Width", "Scan List", "TX Admit", "Emergency System", "Squelch Level", "APRS Report Type", "Forbid TX", "APRS Receive", "Forbid Talkaround", "Auto Scan", "Lone Work", "Emergency Indicator", "Emergency ACK", "Analog APRS PTT Mode", "Digital APRS PTT Mode", "TX Contact", "RX Group List", "Color Code", "Time Slot", "Encryption", "Encryption ID", "APRS Report Channel", "Direct Dual Mode", "Private Confirm", "Short Data Confirm", "DMR ID", "CTC/DCS Decode", "CTC/DCS Encode", "Scramble", "RX Squelch Mode", "Signaling Type", "PTT ID", "VOX Function", "PTT ID Display",
@@ -140,16 +150,10 @@ func ExportDM32UVChannels(channels []models.Channel, w io.Writer) error {
 		if c.Power == "" {
 			record[5] = "High"
 		} // Default
-		record[6] = c.Bandwidth
-		if c.Bandwidth == "" {
-			record[6] = "12.5KHz"
-		}
-		record[7] = "None"     // Scan List support todo
-		record[8] = "Allow TX" // Default
-		if c.ForbidTx {
-			record[8] = "Forbid TX"
-		} // Logic check needed
-		record[9] = "None"
+		record[6] = dm32uvBandwidth(c.Bandwidth)
+		record[7] = emptyToNone(c.ScanList)
+		record[8] = radioTxPermit(c, models.VendorProfileDM32UV, "TX Admit", c.TxPermit.DM32UV(c.IsDigital()))
+		record[9] = emptyToNone(c.EmergencySystem)
 		record[10] = strconv.Itoa(c.SquelchLevel)
 		if c.SquelchLevel == 0 {
 			record[10] = "3"
@@ -167,44 +171,47 @@ func ExportDM32UVChannels(channels []models.Channel, w io.Writer) error {
 		record[18] = boolToIntStr(c.EmergencyAck)
 		record[19] = strconv.Itoa(c.AnalogAprsPttMode)
 		record[20] = strconv.Itoa(c.DigitalAprsPttMode)
-		record[21] = c.TxContact
-		if c.TxContact == "" {
-			record[21] = "None"
-		}
-		record[22] = c.RxGroup
-		if c.RxGroup == "" {
-			record[22] = "None"
-		}
+		record[21] = emptyToNone(c.TxContact)
+		record[22] = emptyToNone(c.RxGroup)
 		record[23] = strconv.Itoa(c.ColorCode)
 		record[24] = fmt.Sprintf("Slot %d", c.TimeSlot)
 		if c.TimeSlot == 0 {
 			record[24] = "Slot 1"
 		}
-		record[25] = "0" // Encryption default
+		record[25] = c.Encryption
+		if record[25] == "" {
+			record[25] = "0"
+		}
 		record[26] = "None"
-		record[27] = "1" // APRS Report Channel default
+		if c.EncryptionID > 0 {
+			record[26] = strconv.Itoa(c.EncryptionID)
+		}
+		record[27] = strconv.Itoa(c.AprsReportChannel)
+		if c.AprsReportChannel == 0 {
+			record[27] = "1"
+		}
 		record[28] = boolToIntStr(c.DirectDualMode)
 		record[29] = boolToIntStr(c.PrivateConfirm)
 		record[30] = boolToIntStr(c.ShortDataConfirm)
-		record[31] = "None" // DMR ID todo - should be from global settings or per channel? Sample has "KF8S Dave"
-		record[32] = c.RxTone
-		if c.RxTone == "" {
-			record[32] = "None"
+		record[31] = emptyToNone(c.RadioID)
+		record[32] = dm32uvTone(c.RxTone, c.RxDCS)
+		record[33] = dm32uvTone(c.TxTone, c.TxDCS)
+		record[34] = emptyToNone(c.Scramble)
+		record[35] = c.RxSquelchMode
+		if record[35] == "" {
+			record[35] = "Carrier/CTC"
 		}
-		record[33] = c.TxTone
-		if c.TxTone == "" {
-			record[33] = "None"
+		record[36] = emptyToNone(c.SignalingType)
+		record[37] = c.PttId
+		if record[37] == "" {
+			record[37] = "OFF"
 		}
-		record[34] = "None"
-		record[35] = "Carrier/CTC" // Default
-		record[36] = "None"
-		record[37] = "OFF"
-		record[38] = "0"
-		record[39] = "0"
+		record[38] = boolToIntStr(c.VoxFunction)
+		record[39] = boolToIntStr(c.PttIdDisplay)
 
 		writer.Write(record)
 	}
-	return nil
+	return skipped, nil
 }
 
 func boolToIntStr(b bool) string {
@@ -214,6 +221,38 @@ func boolToIntStr(b bool) string {
 	return "0"
 }
 
+func emptyToNone(s string) string {
+	if s == "" {
+		return "None"
+	}
+	return s
+}
+
+// dm32uvBandwidth normalizes stored bandwidths ("12.5", "25K", "25KHz") to the CPS format.
+func dm32uvBandwidth(bw string) string {
+	if bw == "" {
+		return "12.5KHz"
+	}
+	bw = strings.TrimSuffix(strings.TrimSuffix(bw, "Hz"), "K")
+	return bw + "KHz"
+}
+
+// dm32uvTone renders a CTCSS tone or DCS code for the CTC/DCS columns.
+// DCS codes without a polarity suffix are written as normal ("N").
+func dm32uvTone(tone, dcs string) string {
+	if tone != "" && tone != "None" {
+		return tone
+	}
+	if dcs == "" || dcs == "None" {
+		return "None"
+	}
+	dcs = strings.TrimPrefix(dcs, "D")
+	if last := dcs[len(dcs)-1]; last != 'N' && last != 'I' {
+		dcs += "N"
+	}
+	return "D" + dcs
+}
+
 func ExportDM32UVTalkgroups(contacts []models.Contact, w io.Writer) error {
 	writer := csv.NewWriter(w)
 	defer writer.Flush()
@@ -245,7 +284,7 @@ func ExportDM32UVZones(zones []models.Zone, w io.Writer) error {
 
 	for i, z := range zones {
 		var chanNames []string
-		for _, c := range z.Channels {
+		for _, c := range memberChannels(z.Channels) {
 			chanNames = append(chanNames, c.Name)
 		}
 		writer.Write([]string{strconv.Itoa(i + 1), z.Name, strings.Join(chanNames, "|")})
@@ -274,7 +313,7 @@ func ExportDM32UVDigitalContacts(contacts []models.DigitalContact, w io.Writer)
 			c.Country,
 			c.Remarks,
 			"Private Call",
-			"0",
+			c.CallAlert.DM32UV(),
 		})
 	}
 	return nil
@@ -288,7 +327,7 @@ func ExportDM32UVScanLists(lists []models.ScanList, w io.Writer) error {
 
 	for i, l := range lists {
 		var chanNames []string
-		for _, c := range l.Channels {
+		for _, c := range memberChannels(l.Channels) {
 			chanNames = append(chanNames, c.Name)
 		}
 		writer.Write([]string{strconv.Itoa(i + 1), l.Name, strings.Join(chanNames, "|")})
@@ -1,11 +1,14 @@
 package exporter
 
 import (
+	"bytes"
 	"encoding/csv"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
+	"codeplugs/importer"
 	"codeplugs/models"
 
 	"gorm.io/driver/sqlite"
@@ -29,6 +32,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 		&models.ScanList{},
 		&models.RoamingChannel{},
 		&models.RoamingZone{},
+		&models.ContactOverride{},
 	)
 	if err != nil {
 		t.Fatalf("failed to migrate database: %v", err)
@@ -76,7 +80,7 @@ func TestExportDM32UV(t *testing.T) {
 		t.Fatalf("Pre-export check: 0 channels in DB")
 	}
 
-	if err := ExportDM32UV(db, tmpDir); err != nil {
+	if _, err := ExportDM32UV(db, tmpDir); err != nil {
 		t.Errorf("ExportDM32UV failed: %v", err)
 	}
 
@@ -111,3 +115,56 @@ func TestExportDM32UV(t *testing.T) {
 		t.Errorf("zones.csv not generated")
 	}
 }
+
+func TestDM32UVChannels_GoldenRoundTrip(t *testing.T) {
+	db, err := gorm.Open(sqlite.Dialector{
+		DriverName: "sqlite",
+		DSN:        "file:memdb_dm32uv_golden?mode=memory&cache=shared",
+	}, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to connect database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.Channel{}, &models.Contact{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+
+	golden := filepath.Join("..", "dm32uv", "samples", "dm32uv-kf8s-2025-12-11", "channels.csv")
+	raw, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+
+	if err := importer.ImportDM32UVChannels(db, bytes.NewReader(raw)); err != nil {
+		t.Fatalf("ImportDM32UVChannels failed: %v", err)
+	}
+
+	var channels []models.Channel
+	db.Order("id asc").Find(&channels)
+
+	var out bytes.Buffer
+	if _, err := ExportDM32UVChannels(channels, &out); err != nil {
+		t.Fatalf("ExportDM32UVChannels failed: %v", err)
+	}
+
+	want, err := csv.NewReader(bytes.NewReader(raw)).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse golden file: %v", err)
+	}
+	got, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse export: %v", err)
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("expected %d rows, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			for j := range want[i] {
+				if j < len(got[i]) && got[i][j] != want[i][j] {
+					t.Errorf("row %d column %q: expected %q, got %q", i, want[0][j], want[i][j], got[i][j])
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+
+	"codeplugs/models"
+)
+
+// ExportIcomRepeaterList exports D-Star repeaters in the Icom repeater list CSV
+// layout used by CS-51/CS-52 (ID-51, ID-52, IC-705 and friends).
+// Reflectors have no RF side and are skipped.
+func ExportIcomRepeaterList(repeaters []models.DStarRepeater, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.UseCRLF = true
+	defer writer.Flush()
+
+	header := []string{
+		"Group No", "Group Name", "Name", "Sub Name", "Repeater Call Sign", "Gateway Call Sign",
+		"Frequency", "Dup", "Offset", "Mode", "TONE", "Repeater Tone", "RPT1USE", "Position",
+		"Latitude", "Longitude", "UTC Offset",
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, r := range repeaters {
+		if r.Type == models.DStarTypeReflector {
+			continue
+		}
+
+		record := make([]string, len(header))
+
+		groupNo := r.GroupNo
+		if groupNo == 0 {
+			groupNo = 1
+		}
+		record[0] = strconv.Itoa(groupNo)
+		record[1] = r.GroupName
+		record[2] = r.Name
+		record[3] = r.SubName
+
+		mode := r.Mode
+		if mode == "" {
+			mode = "DV"
+		}
+		if mode == "DV" {
+			record[4] = r.CallSign
+			record[5] = r.GatewayCall
+		}
+
+		record[6] = fmt.Sprintf("%.6f", r.Frequency)
+
+		switch r.Duplex {
+		case "+":
+			record[7] = "DUP+"
+		case "-":
+			record[7] = "DUP-"
+		default:
+			record[7] = "OFF"
+		}
+		record[8] = fmt.Sprintf("%.6f", r.Offset)
+		record[9] = mode
+
+		record[10] = "OFF"
+		record[11] = "88.5Hz"
+		if mode == "FM" && r.Tone != "" {
+			record[10] = "TONE"
+			record[11] = r.Tone + "Hz"
+		}
+
+		record[12] = "Yes"
+		if r.Latitude != 0 || r.Longitude != 0 {
+			record[13] = "Approximate"
+			record[14] = fmt.Sprintf("%.6f", r.Latitude)
+			record[15] = fmt.Sprintf("%.6f", r.Longitude)
+		} else {
+			record[13] = "None"
+			record[14] = "0.000000"
+			record[15] = "0.000000"
+		}
+
+		record[16] = r.UTCOffset
+		if record[16] == "" {
+			record[16] = "--:--"
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IcomMyCallSlots is how many own call signs an Icom D-Star radio holds
+const IcomMyCallSlots = 6
+
+// ExportIcomMyCallSigns exports the distinct own calls (MYCALL) of D-Star
+// channels in the Icom "My Call Sign" CSV layout, in channel order. Calls
+// past IcomMyCallSlots are skipped with a warning.
+func ExportIcomMyCallSigns(channels []models.Channel, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.UseCRLF = true
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"No", "Call Sign", "Memo"}); err != nil {
+		return err
+	}
+	seen := make(map[string]bool)
+	for _, ch := range channels {
+		call := strings.ToUpper(strings.TrimSpace(ch.DStarOwnCall))
+		if call == "" || seen[call] || (ch.Protocol != models.ProtocolDStar && ch.Type != models.ChannelTypeDigitalDStar) {
+			continue
+		}
+		seen[call] = true
+		if len(seen) > IcomMyCallSlots {
+			log.Printf("Warning: skipping own call %q from channel %q; the radio holds %d", call, ch.Name, IcomMyCallSlots)
+			continue
+		}
+		if err := writer.Write([]string{strconv.Itoa(len(seen)), call, ""}); err != nil {
+			return err
+		}
+	}
+	return writer.Error()
+}
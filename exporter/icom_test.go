@@ -0,0 +1,100 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"codeplugs/models"
+)
+
+func TestExportIcomRepeaterList(t *testing.T) {
+	repeaters := []models.DStarRepeater{
+		{
+			Type:        models.DStarTypeRepeater,
+			GroupNo:     3,
+			GroupName:   "Michigan",
+			Name:        "Detroit",
+			SubName:     "W8DET B",
+			CallSign:    "W8DET  B",
+			GatewayCall: "W8DET  G",
+			Frequency:   145.33,
+			Duplex:      "-",
+			Offset:      0.6,
+			Mode:        "DV",
+			Latitude:    42.331,
+			Longitude:   -83.046,
+			UTCOffset:   "-5:00",
+		},
+		{
+			Type:     models.DStarTypeReflector,
+			Name:     "REF030 C",
+			CallSign: "REF030 C",
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	if err := ExportIcomRepeaterList(repeaters, buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	if !bytes.Contains(buf.Bytes(), []byte("\r\n")) {
+		t.Error("Expected CRLF line endings")
+	}
+
+	records, err := csv.NewReader(buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected header + 1 repeater (reflector skipped), got %d records", len(records))
+	}
+
+	row := records[1]
+	expected := map[int]string{
+		0:  "3",
+		4:  "W8DET  B",
+		5:  "W8DET  G",
+		6:  "145.330000",
+		7:  "DUP-",
+		8:  "0.600000",
+		9:  "DV",
+		10: "OFF",
+		13: "Approximate",
+		16: "-5:00",
+	}
+	for idx, want := range expected {
+		if row[idx] != want {
+			t.Errorf("Column %s: expected %q, got %q", records[0][idx], want, row[idx])
+		}
+	}
+}
+
+func TestExportIcomMyCallSigns(t *testing.T) {
+	channels := []models.Channel{
+		{Name: "Detroit", Protocol: models.ProtocolDStar, DStarOwnCall: "kf8abc"},
+		{Name: "Simplex", Protocol: models.ProtocolFM, DStarOwnCall: "W8FM"},
+		{Name: "Ann Arbor", Protocol: models.ProtocolDStar, DStarOwnCall: "KF8ABC"},
+		{Name: "Portable", Type: models.ChannelTypeDigitalDStar, DStarOwnCall: "KF8ABC P"},
+		{Name: "No Call", Protocol: models.ProtocolDStar},
+	}
+	for i := 0; i < IcomMyCallSlots; i++ {
+		channels = append(channels, models.Channel{Name: "Extra", Protocol: models.ProtocolDStar, DStarOwnCall: "W8X" + string(rune('A'+i))})
+	}
+
+	buf := new(bytes.Buffer)
+	if err := ExportIcomMyCallSigns(channels, buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	records, err := csv.NewReader(buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+	if len(records) != 1+IcomMyCallSlots {
+		t.Fatalf("Expected header + %d calls, got %d records", IcomMyCallSlots, len(records))
+	}
+	// Deduplicated, upper-cased and in channel order; FM channels don't count
+	if records[1][0] != "1" || records[1][1] != "KF8ABC" || records[2][1] != "KF8ABC P" || records[3][1] != "W8XA" {
+		t.Errorf("Unexpected own calls: %v", records)
+	}
+}
@@ -0,0 +1,105 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+
+	"codeplugs/models"
+)
+
+func isP25(ch models.Channel) bool {
+	return ch.Protocol == models.ProtocolP25 || ch.Type == models.ChannelTypeDigitalP25
+}
+
+// withoutP25 drops the P25 channels, which radios without P25 can't carry,
+// returning their names for the caller to report
+func withoutP25(channels []models.Channel, format string) (kept []models.Channel, skipped []string) {
+	for _, ch := range channels {
+		if isP25(ch) {
+			log.Printf("Warning: skipping P25 channel %q in %s export", ch.Name, format)
+			skipped = append(skipped, ch.Name)
+			continue
+		}
+		kept = append(kept, ch)
+	}
+	return kept, skipped
+}
+
+// memberChannels drops P25 channels from a zone or scan list, since
+// withoutP25 left them out of the channel list
+func memberChannels(channels []models.Channel) []models.Channel {
+	var kept []models.Channel
+	for _, ch := range channels {
+		if !isP25(ch) {
+			kept = append(kept, ch)
+		}
+	}
+	return kept
+}
+
+// ExportP25Channels exports P25 conventional channels to a CSV file.
+// Columns use the generic channel import headers so the file can be read back
+// with ImportChannelsCSV. Non-P25 channels are skipped.
+func ExportP25Channels(channels []models.Channel, talkgroups []models.P25Talkgroup, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	defer writer.Flush()
+
+	header := []string{
+		"No.", "Name", "Mode", "RX Freq", "TX Freq", "Power", "Bandwidth", "NAC", "Talkgroup", "Talkgroup ID", "Unit ID",
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	tgNames := make(map[int]string)
+	for _, tg := range talkgroups {
+		tgNames[tg.TGID] = tg.Name
+	}
+
+	count := 0
+	for _, ch := range channels {
+		if !isP25(ch) {
+			continue
+		}
+		count++
+
+		record := make([]string, len(header))
+		record[0] = strconv.Itoa(count)
+		record[1] = ch.Name
+		record[2] = "P25"
+		record[3] = fmt.Sprintf("%.6f", ch.RxFrequency)
+		record[4] = fmt.Sprintf("%.6f", ch.TxFrequency)
+
+		record[5] = "High"
+		if ch.Power != "" {
+			record[5] = ch.Power
+		}
+
+		record[6] = "12.5"
+		if ch.Bandwidth != "" {
+			record[6] = ch.Bandwidth
+		}
+
+		record[7] = ch.P25NAC
+		if record[7] == "" {
+			record[7] = models.P25DefaultNAC
+		}
+
+		if ch.P25TalkgroupID > 0 {
+			record[8] = tgNames[ch.P25TalkgroupID]
+			record[9] = strconv.Itoa(ch.P25TalkgroupID)
+		}
+		if ch.P25UnitID > 0 {
+			record[10] = strconv.Itoa(ch.P25UnitID)
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package exporter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"codeplugs/importer"
+	"codeplugs/models"
+)
+
+func TestExportP25Channels_RoundTrip(t *testing.T) {
+	channels := []models.Channel{
+		{
+			Name:           "MPSCS Tac",
+			Type:           models.ChannelTypeDigitalP25,
+			Protocol:       models.ProtocolP25,
+			RxFrequency:    851.0125,
+			TxFrequency:    806.0125,
+			P25NAC:         "F7E",
+			P25TalkgroupID: 1001,
+			P25UnitID:      1234567,
+		},
+		{
+			Name:        "Simplex",
+			Type:        models.ChannelTypeAnalog,
+			Protocol:    models.ProtocolFM,
+			RxFrequency: 146.52,
+			TxFrequency: 146.52,
+		},
+	}
+	talkgroups := []models.P25Talkgroup{{Name: "Statewide", TGID: 1001}}
+
+	buf := new(bytes.Buffer)
+	if err := ExportP25Channels(channels, talkgroups, buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	if !bytes.Contains(buf.Bytes(), []byte("Statewide")) {
+		t.Error("Expected talkgroup name in export")
+	}
+
+	imported, err := importer.ImportChannelsCSV(buf)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if len(imported) != 1 {
+		t.Fatalf("Expected 1 P25 channel, got %d", len(imported))
+	}
+
+	ch := imported[0]
+	if ch.Protocol != models.ProtocolP25 {
+		t.Errorf("Expected P25 protocol, got %s", ch.Protocol)
+	}
+	if ch.P25NAC != "F7E" || ch.P25TalkgroupID != 1001 || ch.P25UnitID != 1234567 {
+		t.Errorf("Unexpected P25 fields: NAC=%s TG=%d Unit=%d", ch.P25NAC, ch.P25TalkgroupID, ch.P25UnitID)
+	}
+	if ch.TxFrequency != 806.0125 {
+		t.Errorf("Expected TX 806.0125, got %f", ch.TxFrequency)
+	}
+}
+
+func TestDMRExports_SkipP25(t *testing.T) {
+	p25 := models.Channel{Name: "MPSCS Tac", Type: models.ChannelTypeDigitalP25, Protocol: models.ProtocolP25, RxFrequency: 851.0125}
+	dmr := models.Channel{Name: "DMR Ch", Type: models.ChannelTypeDigitalDMR, Protocol: models.ProtocolDMR, RxFrequency: 442.1, ColorCode: 1, TimeSlot: 1}
+	channels := []models.Channel{p25, dmr}
+	zones := []models.Zone{{Name: "Mixed", Channels: channels}}
+
+	var dmChannels, dmZones bytes.Buffer
+	skipped, err := ExportDM32UVChannels(channels, &dmChannels)
+	if err != nil {
+		t.Fatalf("ExportDM32UVChannels failed: %v", err)
+	}
+	if len(skipped) != 1 || skipped[0] != "MPSCS Tac" {
+		t.Errorf("Expected the DM32UV export to skip MPSCS Tac, got %v", skipped)
+	}
+	ExportDM32UVZones(zones, &dmZones)
+
+	var atChannels, atZones bytes.Buffer
+	skipped, err = ExportAnyTone890Channels(channels, nil, &atChannels)
+	if err != nil {
+		t.Fatalf("ExportAnyTone890Channels failed: %v", err)
+	}
+	if len(skipped) != 1 || skipped[0] != "MPSCS Tac" {
+		t.Errorf("Expected the AnyTone export to skip MPSCS Tac, got %v", skipped)
+	}
+	ExportAnyTone890Zones(zones, &atZones)
+
+	for name, out := range map[string]string{
+		"DM32UV channels":  dmChannels.String(),
+		"DM32UV zones":     dmZones.String(),
+		"AnyTone channels": atChannels.String(),
+		"AnyTone zones":    atZones.String(),
+	} {
+		if strings.Contains(out, "MPSCS Tac") {
+			t.Errorf("Expected no P25 channel in %s, got:\n%s", name, out)
+		}
+		if !strings.Contains(out, "DMR Ch") {
+			t.Errorf("Expected the DMR channel in %s, got:\n%s", name, out)
+		}
+	}
+}
@@ -11,6 +11,8 @@ import DMRContactsView from '../views/DMRContactsView.vue'
 import NXDNTalkgroupsView from '../views/NXDNTalkgroupsView.vue'
 import NXDNContactsView from '../views/NXDNContactsView.vue'
 import FilterListsView from '../views/FilterListsView.vue'
+import LoginView from '../views/LoginView.vue'
+import { useAuthStore } from '../stores/auth'
 
 const router = createRouter({
     history: createWebHistory(import.meta.env.BASE_URL),
@@ -68,8 +70,27 @@ const router = createRouter({
             path: '/filter-lists',
             name: 'filter-lists',
             component: FilterListsView
+        },
+        {
+            path: '/login',
+            name: 'login',
+            component: LoginView
         }
     ]
 })
 
+// Send the user to the login page once the server has accounts
+router.beforeEach(async (to) => {
+    const auth = useAuthStore()
+    if (!auth.loaded) {
+        await auth.fetchSession()
+    }
+    if (auth.needsLogin && to.name !== 'login') {
+        return { name: 'login', query: { redirect: to.fullPath } }
+    }
+    if (!auth.needsLogin && to.name === 'login') {
+        return '/'
+    }
+})
+
 export default router
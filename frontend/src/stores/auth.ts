@@ -0,0 +1,47 @@
+import { defineStore } from 'pinia'
+import { ref, computed } from 'vue'
+
+export interface User {
+    id: number
+    username: string
+    role: 'read-only' | 'editor' | 'admin'
+}
+
+// Login state. Auth is only enforced once the server has an account.
+export const useAuthStore = defineStore('auth', () => {
+    const loaded = ref(false)
+    const authEnabled = ref(false)
+    const user = ref<User | null>(null)
+
+    const needsLogin = computed(() => authEnabled.value && !user.value)
+    const isAdmin = computed(() => !authEnabled.value || user.value?.role === 'admin')
+
+    async function fetchSession() {
+        const res = await fetch('/api/auth/session')
+        const json = await res.json()
+        authEnabled.value = json.data?.auth_enabled ?? false
+        user.value = json.data?.user ?? null
+        loaded.value = true
+    }
+
+    async function login(username: string, password: string) {
+        const res = await fetch('/api/auth/login', {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ username, password })
+        })
+        const json = await res.json()
+        if (!res.ok) {
+            throw new Error(json.error || 'Login failed')
+        }
+        authEnabled.value = true
+        user.value = json.data.user
+    }
+
+    async function logout() {
+        await fetch('/api/auth/logout', { method: 'POST' })
+        user.value = null
+    }
+
+    return { loaded, authEnabled, user, needsLogin, isAdmin, fetchSession, login, logout }
+})
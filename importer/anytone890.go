@@ -12,6 +12,20 @@ import (
 	"gorm.io/gorm"
 )
 
+// anyTone890ChannelColumns lists the Channel.CSV columns mapped onto (or derived from)
+// model fields. Every other column is kept in VendorExtras. The channel number is
+// the export position, so only the VFO channels' fixed numbers are kept, and the
+// RX RAN follows NxdnRAN unless it differed from the TX RAN.
+var anyTone890ChannelColumns = map[string]bool{
+	"No.": true, "Channel Name": true, "Receive Frequency": true, "Transmit Frequency": true, "Channel Type": true,
+	"Transmit Power": true, "Band Width": true, "CTCSS/DCS Decode": true, "CTCSS/DCS Encode": true,
+	"Contact/Talk Group": true, "Contact/Talk Group Call Type": true, "Contact/Talk Group TG/DMR ID": true,
+	"Radio ID": true, "Busy Lock/TX Permit": true, "Optional Signal": true, "DTMF ID": true, "2Tone ID": true,
+	"5Tone ID": true, "PTT ID": true, "RX Color Code": true, "Slot": true, "Scan List": true,
+	"Receive Group List": true, "Talk Around(Simplex)": true, "Work Alone": true,
+	"EnRan": true, "DeRan": true, "NxdnGroupId": true,
+}
+
 func ImportAnyTone890Channels(db *gorm.DB, r io.Reader) error {
 	reader := csv.NewReader(r)
 
@@ -46,10 +60,14 @@ func ImportAnyTone890Channels(db *gorm.DB, r io.Reader) error {
 			c.TxFrequency, _ = strconv.ParseFloat(record[idx], 64)
 		}
 		if idx, ok := headerMap["Channel Type"]; ok {
-			if record[idx] == "D-Digital" {
+			switch record[idx] {
+			case "D-Digital":
 				c.Type = models.ChannelTypeDigitalDMR
 				c.Protocol = models.ProtocolDMR
-			} else {
+			case "N-NXDN":
+				c.Type = models.ChannelTypeDigitalNXDN
+				c.Protocol = models.ProtocolNXDN
+			default:
 				c.Type = models.ChannelTypeAnalog
 				c.Protocol = models.ProtocolFM
 			}
@@ -60,6 +78,22 @@ func ImportAnyTone890Channels(db *gorm.DB, r io.Reader) error {
 		if idx, ok := headerMap["Band Width"]; ok {
 			c.Bandwidth = record[idx]
 		}
+		if idx, ok := headerMap["CTCSS/DCS Decode"]; ok {
+			c.RxTone, c.RxDCS = parseAnyToneTone(record[idx])
+		}
+		if idx, ok := headerMap["CTCSS/DCS Encode"]; ok {
+			c.TxTone, c.TxDCS = parseAnyToneTone(record[idx])
+		}
+		switch {
+		case c.RxTone != "":
+			c.SquelchType = "TSQL"
+		case c.TxTone != "":
+			c.SquelchType = "Tone"
+		case c.RxDCS != "" || c.TxDCS != "":
+			c.SquelchType = "DCS"
+		default:
+			c.SquelchType = "None"
+		}
 		if idx, ok := headerMap["RX Color Code"]; ok {
 			c.ColorCode, _ = strconv.Atoi(record[idx])
 		}
@@ -67,13 +101,19 @@ func ImportAnyTone890Channels(db *gorm.DB, r io.Reader) error {
 			c.TimeSlot, _ = strconv.Atoi(record[idx])
 		}
 		if idx, ok := headerMap["Receive Group List"]; ok {
-			c.RxGroup = record[idx]
+			c.RxGroup = noneToEmpty(record[idx])
 		}
 		if idx, ok := headerMap["Contact/Talk Group"]; ok {
-			c.TxContact = record[idx]
+			c.TxContact = noneToEmpty(record[idx])
+		}
+		if idx, ok := headerMap["Radio ID"]; ok {
+			c.RadioID = record[idx]
+		}
+		if idx, ok := headerMap["Busy Lock/TX Permit"]; ok {
+			setTxPermit(&c, models.VendorProfileAT890, "Busy Lock/TX Permit", record[idx], models.TxPermit.AnyTone)
 		}
 		if idx, ok := headerMap["Scan List"]; ok {
-			c.ScanList = record[idx]
+			c.ScanList = noneToEmpty(record[idx])
 		}
 		if idx, ok := headerMap["Optional Signal"]; ok {
 			c.OptionalSignal = record[idx]
@@ -97,23 +137,81 @@ func ImportAnyTone890Channels(db *gorm.DB, r io.Reader) error {
 		if idx, ok := headerMap["Work Alone"]; ok {
 			c.WorkAlone = parseAnyToneBool(record[idx])
 		}
+		// NXDN (TX and RX RAN share a field; a different RX RAN is kept below)
+		if idx, ok := headerMap["EnRan"]; ok {
+			c.NxdnRAN, _ = strconv.Atoi(record[idx])
+		}
+		if idx, ok := headerMap["NxdnGroupId"]; ok {
+			c.NxdnGroupID, _ = strconv.Atoi(record[idx])
+		}
+
+		storeVendorExtras(&c.VendorExtras, models.VendorProfileAT890, header, record, anyTone890ChannelColumns)
+		if idx, ok := headerMap["No."]; ok {
+			if no, _ := strconv.Atoi(record[idx]); no >= models.AT890VFOChannelNo {
+				c.VendorExtras.Set(models.VendorProfileAT890, "No.", record[idx])
+			}
+		}
+		if idx, ok := headerMap["DeRan"]; ok {
+			if ran, err := strconv.Atoi(record[idx]); err != nil || ran != c.NxdnRAN {
+				c.VendorExtras.Set(models.VendorProfileAT890, "DeRan", record[idx])
+			}
+		}
 
 		channels = append(channels, c)
 	}
 	return db.Create(&channels).Error
 }
 
+// storeVendorExtras keeps every column not listed in mapped so the radio's exporter can replay it.
+func storeVendorExtras(extras *models.VendorExtras, profile string, header, record []string, mapped map[string]bool) {
+	for i, h := range header {
+		col := strings.TrimSpace(h)
+		if col == "" || mapped[col] || i >= len(record) {
+			continue
+		}
+		extras.Set(profile, col, record[i])
+	}
+}
+
+// setTxPermit reads a radio's TX permit column into c, keeping the raw value
+// in the radio's extras when render doesn't give it back, e.g. AnyTone's
+// "Repeater" busy lock
+func setTxPermit(c *models.Channel, profile, column, raw string, render func(models.TxPermit, bool) string) {
+	c.TxPermit, _ = models.ParseTxPermit(raw)
+	if render(c.TxPermit, c.IsDigital()) != raw {
+		c.VendorExtras.Set(profile, column, raw)
+	}
+}
+
+// parseAnyToneTone splits a CTCSS/DCS column value, treating "Off" as unset.
+func parseAnyToneTone(s string) (tone, dcs string) {
+	if strings.EqualFold(strings.TrimSpace(s), "Off") {
+		return "", ""
+	}
+	return parseDM32UVTone(s)
+}
+
 func parseAnyToneBool(s string) bool {
 	return strings.ToLower(s) == "on"
 }
 
+// anyTone890TalkgroupColumns lists the DMRTalkGroups.CSV columns mapped onto Contact fields.
+var anyTone890TalkgroupColumns = map[string]bool{
+	"No.": true, "Radio ID": true, "Name": true, "Call Type": true, "Call Alert": true,
+}
+
 func ImportAnyTone890Talkgroups(db *gorm.DB, r io.Reader) error {
 	reader := csv.NewReader(r)
-	_, err := reader.Read() // skip header
+	header, err := reader.Read()
 	if err != nil {
 		return err
 	}
 
+	headerMap := make(map[string]int)
+	for i, h := range header {
+		headerMap[strings.TrimSpace(h)] = i
+	}
+
 	var contacts []models.Contact
 	for {
 		record, err := reader.Read()
@@ -124,34 +222,65 @@ func ImportAnyTone890Talkgroups(db *gorm.DB, r io.Reader) error {
 			return err
 		}
 
-		// "No.","Radio ID","Name","Call Type","Call Alert"
-		if len(record) < 3 {
-			continue
+		c := models.Contact{}
+		if idx, ok := headerMap["Radio ID"]; ok {
+			c.DMRID, _ = strconv.Atoi(record[idx])
 		}
-
-		id, _ := strconv.Atoi(record[1])
-		c := models.Contact{
-			Name:  record[2],
-			DMRID: id,
+		if idx, ok := headerMap["Name"]; ok {
+			c.Name = record[idx]
 		}
-		if strings.EqualFold(record[3], "Group Call") {
+		callType := ""
+		if idx, ok := headerMap["Call Type"]; ok {
+			callType = record[idx]
+		}
+		if strings.EqualFold(callType, "Group Call") {
 			c.Type = models.ContactTypeGroup
-		} else if strings.EqualFold(record[3], "Private Call") {
+		} else if strings.EqualFold(callType, "Private Call") {
 			c.Type = models.ContactTypePrivate
 		} else {
 			c.Type = models.ContactTypeAllCall
 		}
+		if idx, ok := headerMap["Call Alert"]; ok {
+			c.CallAlert, _ = models.ParseCallAlert(record[idx])
+		}
+		storeVendorExtras(&c.VendorExtras, models.VendorProfileAT890, header, record, anyTone890TalkgroupColumns)
 		contacts = append(contacts, c)
 	}
 
 	for _, c := range contacts {
+		extras := c.VendorExtras
+		alert := c.CallAlert
 		if err := db.Where("dmr_id = ? AND type = ?", c.DMRID, c.Type).FirstOrCreate(&c).Error; err != nil {
 			fmt.Printf("Error importing contact %s: %v\n", c.Name, err)
+			continue
+		}
+		if c.CallAlert != alert {
+			if err := db.Model(&c).Update("call_alert", alert).Error; err != nil {
+				return err
+			}
+		}
+		// Merge extras into an existing contact without touching other radio profiles
+		if len(extras) > 0 {
+			for col, val := range extras[models.VendorProfileAT890] {
+				c.VendorExtras.Set(models.VendorProfileAT890, col, val)
+			}
+			if err := db.Model(&c).Update("vendor_extras", c.VendorExtras).Error; err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
 
+// anyTone890ZoneColumns lists the DMRZone.CSV columns mapped onto (or derived from) Zone fields.
+// The A and B channels are derived from the zone's first two members.
+var anyTone890ZoneColumns = map[string]bool{
+	"No.": true, "Zone Name": true, "Zone Channel Member": true,
+	"Zone Channel Member RX Frequency": true, "Zone Channel Member TX Frequency": true,
+	"A Channel": true, "A Channel RX Frequency": true, "A Channel TX Frequency": true,
+	"B Channel": true, "B Channel RX Frequency": true, "B Channel TX Frequency": true,
+}
+
 func ImportAnyTone890Zones(db *gorm.DB, r io.Reader) error {
 	reader := csv.NewReader(r)
 	header, err := reader.Read()
@@ -187,6 +316,13 @@ func ImportAnyTone890Zones(db *gorm.DB, r io.Reader) error {
 			return err
 		}
 
+		storeVendorExtras(&zone.VendorExtras, models.VendorProfileAT890, header, record, anyTone890ZoneColumns)
+		if len(zone.VendorExtras) > 0 {
+			if err := db.Model(zone).Update("vendor_extras", zone.VendorExtras).Error; err != nil {
+				return err
+			}
+		}
+
 		if idx, ok := headerMap["Zone Channel Member"]; ok {
 			rawMembers := record[idx]
 			if rawMembers != "" {
@@ -209,6 +345,7 @@ func ImportAnyTone890DigitalContacts(db *gorm.DB, r io.Reader) error {
 
 	var contacts []models.DigitalContact
 	batchSize := 1000
+	alerts := make(map[int]models.CallAlert)
 
 	for {
 		record, err := reader.Read()
@@ -234,6 +371,7 @@ func ImportAnyTone890DigitalContacts(db *gorm.DB, r io.Reader) error {
 			Country:  record[6],
 			Remarks:  record[7],
 		}
+		recordCallAlert(alerts, id, record, 9)
 		contacts = append(contacts, dc)
 
 		if len(contacts) >= batchSize {
@@ -244,9 +382,11 @@ func ImportAnyTone890DigitalContacts(db *gorm.DB, r io.Reader) error {
 		}
 	}
 	if len(contacts) > 0 {
-		return db.Save(&contacts).Error
+		if err := db.Save(&contacts).Error; err != nil {
+			return err
+		}
 	}
-	return nil
+	return saveCallAlerts(db, alerts)
 }
 
 func ImportAnyTone890ScanLists(db *gorm.DB, r io.Reader) error {
@@ -0,0 +1,52 @@
+package importer
+
+import (
+	"codeplugs/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// saveCallAlerts records call alerts read from a radio's contact file as
+// contact overrides, so they survive RadioID re-syncs and are exported again.
+// An alert the file sets to none clears an existing override's alert without
+// creating one for every plain contact. Remarks overrides on the same IDs are
+// left alone.
+func saveCallAlerts(db *gorm.DB, alerts map[int]models.CallAlert) error {
+	const chunk = 500
+	var overrides []models.ContactOverride
+	var cleared []int
+	for id, alert := range alerts {
+		if alert == models.CallAlertNone {
+			cleared = append(cleared, id)
+			continue
+		}
+		overrides = append(overrides, models.ContactOverride{DMRID: id, CallAlert: alert})
+	}
+	for start := 0; start < len(cleared); start += chunk {
+		end := min(start+chunk, len(cleared))
+		if err := db.Model(&models.ContactOverride{}).
+			Where("dmr_id IN ? AND call_alert <> ?", cleared[start:end], models.CallAlertNone).
+			Update("call_alert", models.CallAlertNone).Error; err != nil {
+			return err
+		}
+	}
+	if len(overrides) == 0 {
+		return nil
+	}
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "dmr_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"call_alert", "updated_at"}),
+	}).CreateInBatches(&overrides, 100).Error
+}
+
+// recordCallAlert notes the alert from column i of record, none included, so
+// saveCallAlerts can clear overrides the file turned off
+func recordCallAlert(alerts map[int]models.CallAlert, id int, record []string, i int) {
+	if len(record) <= i {
+		return
+	}
+	if alert, err := models.ParseCallAlert(record[i]); err == nil {
+		alerts[id] = alert
+	}
+}
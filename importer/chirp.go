@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
 
 	"codeplugs/models"
 )
@@ -106,7 +107,7 @@ func ImportChirpCSV(r io.Reader) ([]models.Channel, error) {
 			channel.Bandwidth = "12.5" // Typical D-Star width
 		case "P25":
 			channel.Type = models.ChannelTypeDigitalP25
-			channel.Protocol = "P25" // Assuming ProtocolP25 exists or string match
+			channel.Protocol = models.ProtocolP25
 			channel.Bandwidth = "12.5"
 		default:
 			// Fallback
@@ -218,6 +219,14 @@ func ImportChirpCSV(r io.Reader) ([]models.Channel, error) {
 			channel.Tone = fmt.Sprintf("D%s", channel.TxDCS)
 		}
 
+		// D-Star routing
+		if channel.Protocol == models.ProtocolDStar {
+			channel.DStarURCall = strings.TrimSpace(getVal("URCALL"))
+			channel.DStarRPT1Call = strings.TrimSpace(getVal("RPT1CALL"))
+			channel.DStarRPT2Call = strings.TrimSpace(getVal("RPT2CALL"))
+			channel.DStarDVCode, _ = strconv.Atoi(getVal("DVCODE"))
+		}
+
 		channel.Notes = getVal("Comment")
 
 		channels = append(channels, channel)
@@ -92,3 +92,32 @@ func TestImportChirpCSV_GenericFormat(t *testing.T) {
 		t.Errorf("Channel 4 RxTone mismatch: expected 88.5, got %s", c4.RxTone)
 	}
 }
+
+func TestImportChirpCSV_DStar(t *testing.T) {
+	csvData := `Location,Name,Frequency,Duplex,Offset,Tone,rToneFreq,cToneFreq,DtcsCode,DtcsPolarity,RxDtcsCode,CrossMode,Mode,TStep,Skip,Power,Comment,URCALL,RPT1CALL,RPT2CALL,DVCODE
+0,W8DET B,145.330000,-,0.600000,,88.5,88.5,023,NN,023,,DV,5.00,,50W,,CQCQCQ,W8DET  B,W8DET  G,7
+1,Simplex,146.520000,,0.000000,,88.5,88.5,023,NN,023,,NFM,5.00,,50W,,,,,
+`
+	channels, err := ImportChirpCSV(strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("ImportChirpCSV failed: %v", err)
+	}
+	if len(channels) != 2 {
+		t.Fatalf("Expected 2 channels, got %d", len(channels))
+	}
+
+	dv := channels[0]
+	if dv.Protocol != models.ProtocolDStar {
+		t.Errorf("Expected D-Star protocol, got %s", dv.Protocol)
+	}
+	if dv.DStarURCall != "CQCQCQ" || dv.DStarRPT1Call != "W8DET  B" || dv.DStarRPT2Call != "W8DET  G" {
+		t.Errorf("Unexpected D-Star routing: UR=%q RPT1=%q RPT2=%q", dv.DStarURCall, dv.DStarRPT1Call, dv.DStarRPT2Call)
+	}
+	if dv.DStarDVCode != 7 {
+		t.Errorf("Expected DV code 7, got %d", dv.DStarDVCode)
+	}
+
+	if channels[1].DStarURCall != "" {
+		t.Errorf("Expected no D-Star routing on FM channel, got %q", channels[1].DStarURCall)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
 
 	"codeplugs/models"
 )
@@ -138,7 +139,7 @@ func ImportChannelsCSV(r io.Reader) ([]models.Channel, error) {
 		case "P25":
 			channel.Mode = "P25"
 			channel.Type = models.ChannelTypeDigitalP25
-			channel.Protocol = "P25"
+			channel.Protocol = models.ProtocolP25
 			channel.Bandwidth = "12.5"
 		case "Digital": // Generic Digital fallback logic
 			channel.Mode = "DMR" // Assume DMR logic for generic "Digital"
@@ -210,6 +211,13 @@ func ImportChannelsCSV(r io.Reader) ([]models.Channel, error) {
 			channel.TxContact = getVal("Contacts")
 		}
 
+		// P25 specific
+		if channel.Protocol == models.ProtocolP25 {
+			channel.P25NAC = strings.ToUpper(strings.TrimSpace(getVal("NAC")))
+			channel.P25TalkgroupID, _ = strconv.Atoi(getVal("Talkgroup ID"))
+			channel.P25UnitID, _ = strconv.Atoi(getVal("Unit ID"))
+		}
+
 		// Squelch Mapping (Chirp & DB25-D)
 		// 1. Try generic "Tone" field for TX Tone (DB25-D TX QT/DQT)
 		// DB25-D "TX QT/DQT" could be CTCSS or DCS. Format usually "88.5" or "D023N"
@@ -69,6 +69,15 @@ func ImportDM32UVChannels(db *gorm.DB, r io.Reader) error {
 		if idx, ok := headerMap["Band Width"]; ok {
 			channel.Bandwidth = record[idx]
 		}
+		if idx, ok := headerMap["Scan List"]; ok {
+			channel.ScanList = noneToEmpty(record[idx])
+		}
+		if idx, ok := headerMap["TX Admit"]; ok {
+			setTxPermit(&channel, models.VendorProfileDM32UV, "TX Admit", record[idx], models.TxPermit.DM32UV)
+		}
+		if idx, ok := headerMap["Emergency System"]; ok {
+			channel.EmergencySystem = noneToEmpty(record[idx])
+		}
 		if idx, ok := headerMap["Color Code"]; ok {
 			cc, _ := strconv.Atoi(record[idx])
 			channel.ColorCode = cc
@@ -82,26 +91,40 @@ func ImportDM32UVChannels(db *gorm.DB, r io.Reader) error {
 			}
 		}
 		if idx, ok := headerMap["RX Group List"]; ok {
-			channel.RxGroup = record[idx]
+			channel.RxGroup = noneToEmpty(record[idx])
 		}
 		if idx, ok := headerMap["TX Contact"]; ok {
-			channel.TxContact = record[idx]
+			channel.TxContact = noneToEmpty(record[idx])
 		}
 		// Squelch Level
 		if idx, ok := headerMap["Squelch Level"]; ok {
 			sl, _ := strconv.Atoi(record[idx])
 			channel.SquelchLevel = sl
 		}
-		// ... Map other fields ...
+
+		// CTC/DCS columns hold either a CTCSS frequency ("88.5"), a DCS code ("D023N") or "None"
 		if idx, ok := headerMap["CTC/DCS Decode"]; ok {
-			channel.RxTone = record[idx]
-			channel.RxDCS = record[idx] // Logic to differentiate might be needed
 			channel.CtcDcsDecode = record[idx]
+			channel.RxTone, channel.RxDCS = parseDM32UVTone(record[idx])
 		}
 		if idx, ok := headerMap["CTC/DCS Encode"]; ok {
-			channel.TxTone = record[idx]
-			channel.TxDCS = record[idx]
 			channel.CtcDcsEncode = record[idx]
+			channel.TxTone, channel.TxDCS = parseDM32UVTone(record[idx])
+		}
+		switch {
+		case channel.RxTone != "":
+			channel.SquelchType = "TSQL"
+		case channel.TxTone != "":
+			channel.SquelchType = "Tone"
+		case channel.RxDCS != "" || channel.TxDCS != "":
+			channel.SquelchType = "DCS"
+		default:
+			channel.SquelchType = "None"
+		}
+		if channel.TxTone != "" {
+			channel.Tone = channel.TxTone
+		} else if channel.TxDCS != "" {
+			channel.Tone = "D" + channel.TxDCS
 		}
 
 		// Additional DM32UV fields
@@ -129,7 +152,56 @@ func ImportDM32UVChannels(db *gorm.DB, r io.Reader) error {
 		if idx, ok := headerMap["Emergency ACK"]; ok {
 			channel.EmergencyAck = parseBool(record[idx])
 		}
-		// ... more fields mapping
+		if idx, ok := headerMap["Analog APRS PTT Mode"]; ok {
+			channel.AnalogAprsPttMode, _ = strconv.Atoi(record[idx])
+		}
+		if idx, ok := headerMap["Digital APRS PTT Mode"]; ok {
+			channel.DigitalAprsPttMode, _ = strconv.Atoi(record[idx])
+		}
+
+		// Encryption
+		if idx, ok := headerMap["Encryption"]; ok {
+			channel.Encryption = record[idx]
+		}
+		if idx, ok := headerMap["Encryption ID"]; ok {
+			channel.EncryptionID, _ = strconv.Atoi(record[idx]) // "None" -> 0
+		}
+
+		if idx, ok := headerMap["APRS Report Channel"]; ok {
+			channel.AprsReportChannel, _ = strconv.Atoi(record[idx])
+		}
+		if idx, ok := headerMap["Direct Dual Mode"]; ok {
+			channel.DirectDualMode = parseBool(record[idx])
+		}
+		if idx, ok := headerMap["Private Confirm"]; ok {
+			channel.PrivateConfirm = parseBool(record[idx])
+		}
+		if idx, ok := headerMap["Short Data Confirm"]; ok {
+			channel.ShortDataConfirm = parseBool(record[idx])
+		}
+		if idx, ok := headerMap["DMR ID"]; ok {
+			channel.RadioID = noneToEmpty(record[idx])
+		}
+
+		// Signaling
+		if idx, ok := headerMap["Scramble"]; ok {
+			channel.Scramble = record[idx]
+		}
+		if idx, ok := headerMap["RX Squelch Mode"]; ok {
+			channel.RxSquelchMode = record[idx]
+		}
+		if idx, ok := headerMap["Signaling Type"]; ok {
+			channel.SignalingType = record[idx]
+		}
+		if idx, ok := headerMap["PTT ID"]; ok {
+			channel.PttId = record[idx]
+		}
+		if idx, ok := headerMap["VOX Function"]; ok {
+			channel.VoxFunction = parseBool(record[idx])
+		}
+		if idx, ok := headerMap["PTT ID Display"]; ok {
+			channel.PttIdDisplay = parseBool(record[idx])
+		}
 
 		channels = append(channels, channel)
 	}
@@ -144,6 +216,28 @@ func parseBool(s string) bool {
 	return s == "1" || s == "on" || s == "true" || s == "allow tx" // "Allow TX" logic might be inverted for "Forbid TX"
 }
 
+// noneToEmpty maps the CPS placeholder "None" to an empty string so references
+// (contacts, group lists, scan lists) stay unset in the database.
+func noneToEmpty(s string) string {
+	if strings.EqualFold(strings.TrimSpace(s), "None") {
+		return ""
+	}
+	return s
+}
+
+// parseDM32UVTone splits a CTC/DCS column value into a CTCSS tone or a DCS code.
+// DCS codes are stored without the leading "D" (e.g. "023N").
+func parseDM32UVTone(s string) (tone, dcs string) {
+	s = noneToEmpty(strings.TrimSpace(s))
+	if s == "" {
+		return "", ""
+	}
+	if s[0] == 'D' || s[0] == 'd' {
+		return "", s[1:]
+	}
+	return s, ""
+}
+
 func ImportDM32UVTalkgroups(db *gorm.DB, r io.Reader) error {
 	reader := csv.NewReader(r)
 	header, err := reader.Read() // skip header
@@ -242,6 +336,7 @@ func ImportDM32UVDigitalContacts(db *gorm.DB, r io.Reader) error {
 	// Batch insert for performance
 	var contacts []models.DigitalContact
 	batchSize := 1000
+	alerts := make(map[int]models.CallAlert)
 
 	for {
 		record, err := reader.Read()
@@ -267,6 +362,7 @@ func ImportDM32UVDigitalContacts(db *gorm.DB, r io.Reader) error {
 			Country:  record[6],
 			Remarks:  record[7],
 		}
+		recordCallAlert(alerts, id, record, 9)
 		contacts = append(contacts, contact)
 
 		if len(contacts) >= batchSize {
@@ -278,9 +374,11 @@ func ImportDM32UVDigitalContacts(db *gorm.DB, r io.Reader) error {
 	}
 
 	if len(contacts) > 0 {
-		return db.Save(&contacts).Error
+		if err := db.Save(&contacts).Error; err != nil {
+			return err
+		}
 	}
-	return nil
+	return saveCallAlerts(db, alerts)
 }
 
 func ImportDM32UVScanLists(db *gorm.DB, r io.Reader) error {
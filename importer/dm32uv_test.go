@@ -2,6 +2,7 @@ package importer
 
 import (
 	"os"
+	"strings"
 	"testing"
 
 	"codeplugs/models"
@@ -20,7 +21,7 @@ func setupDM32UVTestDB(t *testing.T) *gorm.DB {
 		t.Fatalf("failed to connect database: %v", err)
 	}
 	// Migrate the schema
-	err = db.AutoMigrate(&models.Channel{}, &models.Contact{}, &models.DigitalContact{}, &models.Zone{})
+	err = db.AutoMigrate(&models.Channel{}, &models.Contact{}, &models.DigitalContact{}, &models.Zone{}, &models.ContactOverride{})
 	if err != nil {
 		t.Fatalf("failed to migrate database: %v", err)
 	}
@@ -170,3 +171,61 @@ func TestImportDM32UVDigitalContacts(t *testing.T) {
 		t.Errorf("Expected 99999, got %d", dc.DMRID)
 	}
 }
+
+func TestImportDigitalContacts_CallAlertSurvivesRadioIDSync(t *testing.T) {
+	db := setupDM32UVTestDB(t)
+	db.Exec("DELETE FROM digital_contacts")
+	db.Exec("DELETE FROM contact_overrides")
+
+	content := `No.,ID,Repeater,Name,City,Province,Country,Remark,Type,Alert Call
+1,3126001,KF8AAA,Alice,Detroit,Michigan,United States,,Private Call,1
+2,3126002,KF8BBB,Bob,Detroit,Michigan,United States,,Private Call,0`
+	if err := ImportDM32UVDigitalContacts(db, strings.NewReader(content)); err != nil {
+		t.Fatalf("ImportDM32UVDigitalContacts failed: %v", err)
+	}
+
+	update := "RADIO_ID,CALLSIGN,FIRST_NAME,LAST_NAME,CITY,STATE,COUNTRY\n3126001,KF8AAA,Alice,Smith,Lansing,Michigan,United States\n"
+	if _, err := ImportRadioIDToDB(db, strings.NewReader(update), nil, nil); err != nil {
+		t.Fatalf("ImportRadioIDToDB failed: %v", err)
+	}
+
+	var overrides []models.ContactOverride
+	db.Find(&overrides)
+	if len(overrides) != 1 || overrides[0].DMRID != 3126001 || overrides[0].CallAlert != models.CallAlertRing {
+		t.Errorf("Expected one Ring override for 3126001, got %+v", overrides)
+	}
+}
+
+func TestImportDigitalContacts_CallAlertCleared(t *testing.T) {
+	db := setupDM32UVTestDB(t)
+	db.Exec("DELETE FROM digital_contacts")
+	db.Exec("DELETE FROM contact_overrides")
+
+	remarks := "Net control"
+	db.Create(&models.ContactOverride{DMRID: 3126002, Remarks: &remarks})
+
+	header := "No.,ID,Repeater,Name,City,Province,Country,Remark,Type,Alert Call\n"
+	ring := header + "1,3126001,KF8AAA,Alice,Detroit,Michigan,United States,,Private Call,1\n"
+	if err := ImportDM32UVDigitalContacts(db, strings.NewReader(ring)); err != nil {
+		t.Fatalf("ImportDM32UVDigitalContacts failed: %v", err)
+	}
+	// Re-imported with the alert turned off, as an overwriting import does
+	db.Exec("DELETE FROM digital_contacts")
+	none := header + "1,3126001,KF8AAA,Alice,Detroit,Michigan,United States,,Private Call,0\n" +
+		"2,3126002,KF8BBB,Bob,Detroit,Michigan,United States,,Private Call,0\n"
+	if err := ImportDM32UVDigitalContacts(db, strings.NewReader(none)); err != nil {
+		t.Fatalf("ImportDM32UVDigitalContacts failed: %v", err)
+	}
+
+	var overrides []models.ContactOverride
+	db.Order("dmr_id").Find(&overrides)
+	if len(overrides) != 2 {
+		t.Fatalf("Expected 2 overrides, got %+v", overrides)
+	}
+	if overrides[0].CallAlert != models.CallAlertNone {
+		t.Errorf("Expected 3126001 alert cleared, got %q", overrides[0].CallAlert)
+	}
+	if overrides[1].Remarks == nil || *overrides[1].Remarks != remarks {
+		t.Errorf("Expected 3126002 remarks kept, got %+v", overrides[1])
+	}
+}
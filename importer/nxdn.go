@@ -0,0 +1,87 @@
+package importer
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"codeplugs/models"
+)
+
+// ImportNXDNCSV imports NXDN unit IDs from a RadioID.net nxdn.csv.
+// The dump shares the user.csv layout, so parsing is delegated to ImportRadioIDCSV.
+// IDs outside the NXDN range are skipped.
+func ImportNXDNCSV(r io.Reader) ([]models.NXDNContact, error) {
+	users, err := ImportRadioIDCSV(r, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	contacts := make([]models.NXDNContact, 0, len(users))
+	for _, u := range users {
+		c := models.NXDNContact{
+			UnitID:   u.DMRID,
+			Callsign: u.Callsign,
+			Name:     u.Name,
+			City:     u.City,
+			State:    u.State,
+			Country:  u.Country,
+			Remarks:  u.Remarks,
+		}
+		if c.Validate() != nil {
+			continue
+		}
+		contacts = append(contacts, c)
+	}
+	return contacts, nil
+}
+
+// ImportNXDNTalkgroups imports NXDN talkgroups from a simple CSV (Name,ID).
+func ImportNXDNTalkgroups(r io.Reader) ([]models.NXDNTalkgroup, error) {
+	csvReader := csv.NewReader(r)
+	csvReader.FieldsPerRecord = -1
+	csvReader.LazyQuotes = true
+	headers, err := csvReader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	headerMap := make(map[string]int)
+	for i, h := range headers {
+		headerMap[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+
+	var talkgroups []models.NXDNTalkgroup
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		getVal := func(keys ...string) string {
+			for _, k := range keys {
+				if idx, ok := headerMap[k]; ok && idx < len(record) {
+					return strings.TrimSpace(record[idx])
+				}
+			}
+			return ""
+		}
+
+		name := getVal("name", "talkgroup")
+		id, err := strconv.Atoi(getVal("id", "tg id", "tgid", "talkgroup id"))
+		if name == "" || err != nil {
+			continue
+		}
+
+		tg := models.NXDNTalkgroup{Name: name, TGID: id}
+		if tg.Validate() != nil {
+			continue
+		}
+		talkgroups = append(talkgroups, tg)
+	}
+	return talkgroups, nil
+}
@@ -0,0 +1,45 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestImportNXDNCSV_SkipsOutOfRange(t *testing.T) {
+	csvContent := `radio_id,callsign,first_name,last_name,city,state,country,remarks
+1234,W1AW,Hiram,Maxim,Newington,CT,United States,
+65520,K1BAD,Reserved,,Boston,MA,United States,
+3123456,K1DMR,Too,Long,Boston,MA,United States,
+`
+	contacts, err := ImportNXDNCSV(strings.NewReader(csvContent))
+	if err != nil {
+		t.Fatalf("ImportNXDNCSV failed: %v", err)
+	}
+
+	if len(contacts) != 1 {
+		t.Fatalf("Expected 1 contact, got %d", len(contacts))
+	}
+	if contacts[0].UnitID != 1234 || contacts[0].Callsign != "W1AW" || contacts[0].Name != "Hiram Maxim" {
+		t.Errorf("Unexpected contact: %+v", contacts[0])
+	}
+}
+
+func TestImportNXDNTalkgroups(t *testing.T) {
+	csvContent := `Name,ID
+Local,1
+Statewide,26
+Bad,0
+,100
+`
+	talkgroups, err := ImportNXDNTalkgroups(strings.NewReader(csvContent))
+	if err != nil {
+		t.Fatalf("ImportNXDNTalkgroups failed: %v", err)
+	}
+
+	if len(talkgroups) != 2 {
+		t.Fatalf("Expected 2 talkgroups, got %d", len(talkgroups))
+	}
+	if talkgroups[1].Name != "Statewide" || talkgroups[1].TGID != 26 {
+		t.Errorf("Unexpected talkgroup: %+v", talkgroups[1])
+	}
+}
@@ -6,26 +6,65 @@ import (
 	"io"
 	"strconv"
 	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// RadioIDBatchSize is the number of contacts handed to a batch callback when
+// streaming a RadioID.net dump.
+const RadioIDBatchSize = 1000
+
+// radioIDInsertBatchSize caps rows per upsert statement. The SQLite driver's
+// parameter binding grows quadratically with statement size, so many small
+// statements beat one large one.
+const radioIDInsertBatchSize = 100
+
+// RadioIDUpsertColumns are the columns refreshed when a contact with an existing DMR ID is re-imported
+var RadioIDUpsertColumns = []string{
+	"name", "callsign", "city", "state", "country", "remarks",
+	"retired_at", "deleted_at", "updated_at",
+}
+
 // ImportRadioIDCSV imports contacts from a RadioID.net user.csv
 // processedIDs is an optional map of IDs to filter by (if nil, all are imported)
 func ImportRadioIDCSV(r io.Reader, processedIDs map[int]bool) ([]models.DigitalContact, error) {
+	var contacts []models.DigitalContact
+	err := StreamRadioIDCSV(r, processedIDs, RadioIDBatchSize, func(batch []models.DigitalContact) error {
+		contacts = append(contacts, batch...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return contacts, nil
+}
+
+// StreamRadioIDCSV parses a RadioID.net user.csv and hands contacts to fn in
+// batches of up to batchSize, so the whole dump is never held in memory.
+// The batch slice is reused between calls; fn must not retain it.
+func StreamRadioIDCSV(r io.Reader, processedIDs map[int]bool, batchSize int, fn func([]models.DigitalContact) error) error {
+	if batchSize <= 0 {
+		batchSize = RadioIDBatchSize
+	}
+
 	reader := csv.NewReader(r)
 	// Allow for variable number of fields, though typically standard
 	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
 
 	// Read and parse headers
 	headers, err := reader.Read()
 	if err != nil {
-		return nil, err
+		return err
 	}
 	headerMap := make(map[string]int)
 	for i, h := range headers {
 		headerMap[strings.ToLower(strings.TrimSpace(h))] = i
 	}
 
-	var contacts []models.DigitalContact
+	batch := make([]models.DigitalContact, 0, batchSize)
 
 	for {
 		record, err := reader.Read()
@@ -33,7 +72,7 @@ func ImportRadioIDCSV(r io.Reader, processedIDs map[int]bool) ([]models.DigitalC
 			break
 		}
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		// Helper to try multiple keys
@@ -75,7 +114,7 @@ func ImportRadioIDCSV(r io.Reader, processedIDs map[int]bool) ([]models.DigitalC
 			fullName = callsign // Fallback
 		}
 
-		contact := models.DigitalContact{
+		batch = append(batch, models.DigitalContact{
 			Name:     fullName,
 			Callsign: callsign,
 			City:     city,
@@ -83,11 +122,61 @@ func ImportRadioIDCSV(r io.Reader, processedIDs map[int]bool) ([]models.DigitalC
 			Country:  country,
 			Remarks:  remarks,
 			DMRID:    id,
+		})
+
+		if len(batch) == batchSize {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
 		}
-		contacts = append(contacts, contact)
 	}
 
-	return contacts, nil
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
+
+// ImportRadioIDToDB streams a RadioID.net user.csv into the digital contacts
+// table, upserting by DMR ID one batch at a time inside a single transaction.
+// progress, if set, is called after each batch with the running contact count.
+func ImportRadioIDToDB(db *gorm.DB, r io.Reader, processedIDs map[int]bool, progress func(imported int)) (int, error) {
+	imported := 0
+	err := db.Transaction(func(tx *gorm.DB) error {
+		return models.WithDigitalContactFTSSuspended(tx, func() error {
+			return StreamRadioIDCSV(r, processedIDs, RadioIDBatchSize, func(batch []models.DigitalContact) error {
+				if err := tx.Clauses(clause.OnConflict{
+					Columns:   []clause.Column{{Name: "dmr_id"}},
+					DoUpdates: clause.AssignmentColumns(RadioIDUpsertColumns),
+				}).CreateInBatches(&batch, radioIDInsertBatchSize).Error; err != nil {
+					return err
+				}
+				imported += len(batch)
+				if progress != nil {
+					progress(imported)
+				}
+				return nil
+			})
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+	return imported, nil
+}
+
+// CountingReader wraps a reader and counts the bytes read through it, so
+// streaming imports can report progress against the size of the source.
+type CountingReader struct {
+	R io.Reader
+	N int64
+}
+
+func (c *CountingReader) Read(p []byte) (int, error) {
+	n, err := c.R.Read(p)
+	c.N += int64(n)
+	return n, err
 }
 
 // ParseBrandmeisterLastHeard parses the active IDs from a BM CSV
@@ -136,3 +225,125 @@ func ParseBrandmeisterLastHeard(r io.Reader) (map[int]bool, error) {
 	}
 	return ids, nil
 }
+
+// lastHeardLayouts are the timestamp formats seen in last-heard exports
+var lastHeardLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ParseBrandmeisterActivity parses a BM last-heard CSV into per-ID activity.
+// Each row counts as one transmission unless a "count" column is present;
+// the most recent timestamp seen for an ID becomes its LastHeard.
+func ParseBrandmeisterActivity(r io.Reader) (map[int]*models.ContactActivity, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	headers, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	idIdx, timeIdx, countIdx := -1, -1, -1
+	for i, h := range headers {
+		switch strings.ToLower(strings.TrimSpace(h)) {
+		case "sending id", "radio id", "id":
+			if idIdx == -1 {
+				idIdx = i
+			}
+		case "time", "timestamp", "last heard", "last_heard", "date":
+			if timeIdx == -1 {
+				timeIdx = i
+			}
+		case "count", "qso count", "heard":
+			countIdx = i
+		}
+	}
+	if idIdx == -1 {
+		idIdx = 0
+	}
+
+	activity := make(map[int]*models.ContactActivity)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			continue // Best effort, same as ParseBrandmeisterLastHeard
+		}
+		if len(record) <= idIdx {
+			continue
+		}
+
+		id, err := strconv.Atoi(strings.TrimSpace(record[idIdx]))
+		if err != nil {
+			continue
+		}
+
+		a, ok := activity[id]
+		if !ok {
+			a = &models.ContactActivity{DMRID: id}
+			activity[id] = a
+		}
+
+		count := 1
+		if countIdx >= 0 && countIdx < len(record) {
+			if n, err := strconv.Atoi(strings.TrimSpace(record[countIdx])); err == nil {
+				count = n
+			}
+		}
+		a.HeardCount += count
+
+		if timeIdx >= 0 && timeIdx < len(record) {
+			if ts, ok := parseLastHeard(strings.TrimSpace(record[timeIdx])); ok {
+				if a.LastHeard == nil || ts.After(*a.LastHeard) {
+					a.LastHeard = &ts
+				}
+			}
+		}
+	}
+	return activity, nil
+}
+
+// ImportContactActivity stores parsed activity, replacing any previous
+// counts and timestamps for the same IDs. Returns the number of IDs written.
+func ImportContactActivity(db *gorm.DB, r io.Reader) (int, error) {
+	activity, err := ParseBrandmeisterActivity(r)
+	if err != nil {
+		return 0, err
+	}
+
+	rows := make([]models.ContactActivity, 0, len(activity))
+	for _, a := range activity {
+		rows = append(rows, *a)
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	err = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "dmr_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"heard_count", "last_heard", "updated_at", "deleted_at"}),
+	}).CreateInBatches(&rows, radioIDInsertBatchSize).Error
+	return len(rows), err
+}
+
+func parseLastHeard(s string) (time.Time, bool) {
+	if s == "" {
+		return time.Time{}, false
+	}
+	if secs, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(secs, 0).UTC(), true
+	}
+	for _, layout := range lastHeardLayouts {
+		if ts, err := time.Parse(layout, s); err == nil {
+			return ts, true
+		}
+	}
+	return time.Time{}, false
+}
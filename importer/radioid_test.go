@@ -1,8 +1,18 @@
 package importer
 
 import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"codeplugs/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
 )
 
 func TestImportRadioIDCSV_NameSplitting(t *testing.T) {
@@ -31,3 +41,147 @@ func TestImportRadioIDCSV_NameSplitting(t *testing.T) {
 		t.Errorf("Expected Name 'John', got '%s'", c2.Name)
 	}
 }
+
+func setupRadioIDTestDB(t testing.TB, dsn string) *gorm.DB {
+	db, err := gorm.Open(sqlite.Dialector{
+		DriverName: "sqlite",
+		DSN:        dsn,
+	}, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to connect database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.DigitalContact{}); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	db.Exec("DELETE FROM digital_contacts")
+	return db
+}
+
+// writeSyntheticUserCSV writes a RadioID.net style user.csv with the given number of rows
+func writeSyntheticUserCSV(w io.Writer, rows int) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "RADIO_ID,CALLSIGN,FIRST_NAME,LAST_NAME,CITY,STATE,COUNTRY")
+	for i := 0; i < rows; i++ {
+		fmt.Fprintf(bw, "%d,K%dABC,First%d,Last,City %d,State %d,United States\n", 1000000+i, i%10, i, i%500, i%50)
+	}
+	return bw.Flush()
+}
+
+func TestStreamRadioIDCSV_Batches(t *testing.T) {
+	var buf strings.Builder
+	writeSyntheticUserCSV(&buf, 25)
+
+	var sizes []int
+	total := 0
+	err := StreamRadioIDCSV(strings.NewReader(buf.String()), nil, 10, func(batch []models.DigitalContact) error {
+		sizes = append(sizes, len(batch))
+		total += len(batch)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamRadioIDCSV failed: %v", err)
+	}
+
+	if fmt.Sprint(sizes) != "[10 10 5]" {
+		t.Errorf("Expected batches [10 10 5], got %v", sizes)
+	}
+	if total != 25 {
+		t.Errorf("Expected 25 contacts, got %d", total)
+	}
+}
+
+func TestImportRadioIDToDB_Upsert(t *testing.T) {
+	db := setupRadioIDTestDB(t, "file:memdb_radioid_stream?mode=memory&cache=shared")
+
+	var buf strings.Builder
+	writeSyntheticUserCSV(&buf, 2500)
+
+	counter := &CountingReader{R: strings.NewReader(buf.String())}
+	calls := 0
+	imported, err := ImportRadioIDToDB(db, counter, nil, func(n int) { calls++ })
+	if err != nil {
+		t.Fatalf("ImportRadioIDToDB failed: %v", err)
+	}
+	if imported != 2500 {
+		t.Errorf("Expected 2500 imported, got %d", imported)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 progress callbacks, got %d", calls)
+	}
+	if counter.N != int64(buf.Len()) {
+		t.Errorf("Expected %d bytes read, got %d", buf.Len(), counter.N)
+	}
+
+	// Re-import with a changed callsign updates in place
+	update := "RADIO_ID,CALLSIGN,FIRST_NAME,LAST_NAME,CITY,STATE,COUNTRY\n1000000,W1NEW,First0,Last,City 0,State 0,United States\n"
+	if _, err := ImportRadioIDToDB(db, strings.NewReader(update), nil, nil); err != nil {
+		t.Fatalf("Re-import failed: %v", err)
+	}
+
+	var count int64
+	db.Model(&models.DigitalContact{}).Count(&count)
+	if count != 2500 {
+		t.Errorf("Expected 2500 contacts after upsert, got %d", count)
+	}
+	var c models.DigitalContact
+	db.Where("dmr_id = ?", 1000000).First(&c)
+	if c.Callsign != "W1NEW" {
+		t.Errorf("Expected updated callsign W1NEW, got %s", c.Callsign)
+	}
+}
+
+// BenchmarkImportRadioIDToDB_300k streams a generated 300k-row user.csv into SQLite.
+// Run with: go test ./importer -run x -bench RadioIDToDB -benchmem
+func BenchmarkImportRadioIDToDB_300k(b *testing.B) {
+	path := filepath.Join(b.TempDir(), "user.csv")
+	f, err := os.Create(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := writeSyntheticUserCSV(f, 300000); err != nil {
+		b.Fatal(err)
+	}
+	f.Close()
+
+	db := setupRadioIDTestDB(b, "file:memdb_radioid_bench?mode=memory&cache=shared")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f, err := os.Open(path)
+		if err != nil {
+			b.Fatal(err)
+		}
+		n, err := ImportRadioIDToDB(db, f, nil, nil)
+		f.Close()
+		if err != nil {
+			b.Fatal(err)
+		}
+		if n != 300000 {
+			b.Fatalf("Expected 300000 contacts, got %d", n)
+		}
+	}
+}
+
+func TestParseBrandmeisterActivity(t *testing.T) {
+	csvContent := `Time,Sending ID,Callsign,Destination
+2026-05-01 10:00:00,3126001,KF8S,31261
+2026-05-03 12:30:00,3126001,KF8S,31266
+2026-04-20 08:00:00,3126002,W8ABC,31261
+`
+	activity, err := ParseBrandmeisterActivity(strings.NewReader(csvContent))
+	if err != nil {
+		t.Fatalf("ParseBrandmeisterActivity failed: %v", err)
+	}
+
+	a := activity[3126001]
+	if a == nil || a.HeardCount != 2 {
+		t.Fatalf("Expected 2 transmissions for 3126001, got %+v", a)
+	}
+	if a.LastHeard == nil || a.LastHeard.Day() != 3 {
+		t.Errorf("Expected last heard on the 3rd, got %v", a.LastHeard)
+	}
+	if activity[3126002].HeardCount != 1 {
+		t.Errorf("Expected 1 transmission for 3126002, got %d", activity[3126002].HeardCount)
+	}
+}
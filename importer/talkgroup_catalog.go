@@ -0,0 +1,226 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"codeplugs/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Column/key aliases used by network talkgroup dumps
+var (
+	catalogIDKeys          = []string{"id", "tgid", "tg", "talkgroup", "talkgroup id", "tg id", "tg#"}
+	catalogNameKeys        = []string{"name", "callsign", "title", "talkgroup name"}
+	catalogCountryKeys     = []string{"country"}
+	catalogDescriptionKeys = []string{"description", "info", "comment", "notes"}
+)
+
+// ParseTalkgroupCatalog reads an offline talkgroup list for a network. It
+// accepts the Brandmeister v2 JSON object ({"91": "World-wide", ...}), JSON
+// arrays of talkgroup objects as published by TGIF, and CSV exports with
+// an ID and name column such as DMR-MARC's. Entries without a valid ID or
+// name are skipped.
+func ParseTalkgroupCatalog(r io.Reader, network string) ([]models.TalkgroupCatalog, error) {
+	br := bufio.NewReader(r)
+	first, err := peekNonSpace(br)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []models.TalkgroupCatalog
+	switch first {
+	case '{':
+		entries, err = parseCatalogJSONObject(br)
+	case '[':
+		entries, err = parseCatalogJSONArray(br)
+	default:
+		entries, err = parseCatalogCSV(br)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	valid := entries[:0]
+	for _, e := range entries {
+		e.Network = network
+		if e.Validate() == nil {
+			valid = append(valid, e)
+		}
+	}
+	sort.Slice(valid, func(i, j int) bool { return valid[i].TGID < valid[j].TGID })
+	return valid, nil
+}
+
+// ImportTalkgroupCatalog parses a network talkgroup list and upserts it into
+// the catalog, keyed by network and talkgroup ID.
+func ImportTalkgroupCatalog(db *gorm.DB, r io.Reader, network string) (int, error) {
+	if strings.TrimSpace(network) == "" {
+		return 0, fmt.Errorf("network is required")
+	}
+	entries, err := ParseTalkgroupCatalog(r, network)
+	if err != nil {
+		return 0, err
+	}
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	err = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "network"}, {Name: "tg_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "country", "description", "updated_at", "deleted_at"}),
+	}).CreateInBatches(&entries, radioIDInsertBatchSize).Error
+	return len(entries), err
+}
+
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n', 0xEF, 0xBB, 0xBF: // Whitespace and UTF-8 BOM
+			br.ReadByte()
+		default:
+			return b[0], nil
+		}
+	}
+}
+
+func parseCatalogJSONObject(r io.Reader) ([]models.TalkgroupCatalog, error) {
+	var raw map[string]interface{}
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid talkgroup JSON: %w", err)
+	}
+
+	var entries []models.TalkgroupCatalog
+	for key, val := range raw {
+		id, err := strconv.Atoi(strings.TrimSpace(key))
+		if err != nil {
+			continue
+		}
+		switch v := val.(type) {
+		case string:
+			entries = append(entries, models.TalkgroupCatalog{TGID: id, Name: strings.TrimSpace(v)})
+		case map[string]interface{}:
+			e := catalogFromJSON(v)
+			if e.TGID == 0 {
+				e.TGID = id
+			}
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+func parseCatalogJSONArray(r io.Reader) ([]models.TalkgroupCatalog, error) {
+	var raw []map[string]interface{}
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid talkgroup JSON: %w", err)
+	}
+	entries := make([]models.TalkgroupCatalog, 0, len(raw))
+	for _, obj := range raw {
+		entries = append(entries, catalogFromJSON(obj))
+	}
+	return entries, nil
+}
+
+func catalogFromJSON(obj map[string]interface{}) models.TalkgroupCatalog {
+	fields := make(map[string]string, len(obj))
+	for k, v := range obj {
+		switch val := v.(type) {
+		case string:
+			fields[strings.ToLower(k)] = strings.TrimSpace(val)
+		case float64:
+			fields[strings.ToLower(k)] = strconv.FormatFloat(val, 'f', -1, 64)
+		}
+	}
+	id, _ := strconv.Atoi(firstField(fields, catalogIDKeys))
+	return models.TalkgroupCatalog{
+		TGID:        id,
+		Name:        firstField(fields, catalogNameKeys),
+		Country:     firstField(fields, catalogCountryKeys),
+		Description: firstField(fields, catalogDescriptionKeys),
+	}
+}
+
+func parseCatalogCSV(r io.Reader) ([]models.TalkgroupCatalog, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read talkgroup CSV header: %w", err)
+	}
+	cols := make(map[string]int, len(header))
+	for i, h := range header {
+		cols[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	idIdx := firstColumn(cols, catalogIDKeys)
+	nameIdx := firstColumn(cols, catalogNameKeys)
+	if idIdx == -1 || nameIdx == -1 {
+		return nil, fmt.Errorf("talkgroup CSV needs ID and Name columns")
+	}
+	countryIdx := firstColumn(cols, catalogCountryKeys)
+	descIdx := firstColumn(cols, catalogDescriptionKeys)
+
+	get := func(rec []string, idx int) string {
+		if idx < 0 || idx >= len(rec) {
+			return ""
+		}
+		return strings.TrimSpace(rec[idx])
+	}
+
+	var entries []models.TalkgroupCatalog
+	for {
+		rec, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		id, err := strconv.Atoi(get(rec, idIdx))
+		if err != nil {
+			continue
+		}
+		entries = append(entries, models.TalkgroupCatalog{
+			TGID:        id,
+			Name:        get(rec, nameIdx),
+			Country:     get(rec, countryIdx),
+			Description: get(rec, descIdx),
+		})
+	}
+	return entries, nil
+}
+
+func firstField(fields map[string]string, keys []string) string {
+	for _, k := range keys {
+		if v, ok := fields[k]; ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func firstColumn(cols map[string]int, keys []string) int {
+	for _, k := range keys {
+		if i, ok := cols[k]; ok {
+			return i
+		}
+	}
+	return -1
+}
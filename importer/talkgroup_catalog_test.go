@@ -0,0 +1,99 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"codeplugs/models"
+)
+
+func TestParseTalkgroupCatalog_Formats(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		network string
+		want    []models.TalkgroupCatalog
+	}{
+		{
+			name:    "Brandmeister object",
+			input:   "\ufeff{\"91\": \"World-wide\", \"3126\": \"Michigan\", \"x\": \"bad key\", \"0\": \"Zero\"}",
+			network: models.NetworkBrandmeister,
+			want: []models.TalkgroupCatalog{
+				{TGID: 91, Name: "World-wide"},
+				{TGID: 3126, Name: "Michigan"},
+			},
+		},
+		{
+			name: "TGIF array",
+			input: `[
+				{"id": "31665", "name": "TGIF Network", "description": "Main TG"},
+				{"id": 9, "callsign": "Local"},
+				{"id": "", "name": "Missing ID"}
+			]`,
+			network: models.NetworkTGIF,
+			want: []models.TalkgroupCatalog{
+				{TGID: 9, Name: "Local"},
+				{TGID: 31665, Name: "TGIF Network", Description: "Main TG"},
+			},
+		},
+		{
+			name: "DMR-MARC CSV",
+			input: "TG,Name,Country\n" +
+				"3100,USA Nationwide,United States\n" +
+				"3126,Michigan,United States\n" +
+				"abc,Bad,United States\n",
+			network: models.NetworkDMRMARC,
+			want: []models.TalkgroupCatalog{
+				{TGID: 3100, Name: "USA Nationwide", Country: "United States"},
+				{TGID: 3126, Name: "Michigan", Country: "United States"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTalkgroupCatalog(strings.NewReader(tt.input), tt.network)
+			if err != nil {
+				t.Fatalf("ParseTalkgroupCatalog failed: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %d talkgroups, got %d: %+v", len(tt.want), len(got), got)
+			}
+			for i, w := range tt.want {
+				g := got[i]
+				if g.Network != tt.network || g.TGID != w.TGID || g.Name != w.Name || g.Country != w.Country || g.Description != w.Description {
+					t.Errorf("Talkgroup %d: got %+v, want %+v", i, g, w)
+				}
+			}
+		})
+	}
+}
+
+func TestImportTalkgroupCatalog_UpsertsPerNetwork(t *testing.T) {
+	db := setupRadioIDTestDB(t, "file:memdb_tg_catalog?mode=memory&cache=shared")
+	db.AutoMigrate(&models.TalkgroupCatalog{})
+	db.Exec("DELETE FROM talkgroup_catalogs")
+
+	if _, err := ImportTalkgroupCatalog(db, strings.NewReader(`{"3126": "Michigan"}`), models.NetworkBrandmeister); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if _, err := ImportTalkgroupCatalog(db, strings.NewReader(`{"3126": "Michigan Statewide"}`), models.NetworkBrandmeister); err != nil {
+		t.Fatalf("Re-import failed: %v", err)
+	}
+	if _, err := ImportTalkgroupCatalog(db, strings.NewReader(`[{"id": "3126", "name": "MI TGIF"}]`), models.NetworkTGIF); err != nil {
+		t.Fatalf("TGIF import failed: %v", err)
+	}
+
+	var rows []models.TalkgroupCatalog
+	db.Order("network").Find(&rows)
+	if len(rows) != 2 {
+		t.Fatalf("Expected one row per network, got %d", len(rows))
+	}
+	if rows[0].Network != models.NetworkBrandmeister || rows[0].Name != "Michigan Statewide" {
+		t.Errorf("Expected Brandmeister entry to be updated, got %+v", rows[0])
+	}
+
+	if _, err := ImportTalkgroupCatalog(db, strings.NewReader(`{}`), ""); err == nil {
+		t.Error("Expected error without a network")
+	}
+}
@@ -0,0 +1,535 @@
+// Package openapigen generates the Go client in package client from the
+// API's OpenAPI document. It understands the subset of OpenAPI 3 that
+// api.OpenAPISpec produces, including its x-variants extension.
+package openapigen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+)
+
+type document struct {
+	Paths      map[string]map[string]*operation `json:"paths"`
+	Components struct {
+		Schemas map[string]*schema `json:"schemas"`
+	} `json:"components"`
+}
+
+type operation struct {
+	OperationID string                                   `json:"operationId"`
+	Summary     string                                   `json:"summary"`
+	Parameters  []*parameter                             `json:"parameters"`
+	RequestBody *struct{ Content contentMap }            `json:"requestBody"`
+	Responses   map[string]*struct{ Content contentMap } `json:"responses"`
+	Variants    []*variant                               `json:"x-variants"`
+	Scoped      bool                                     `json:"x-project-scoped"`
+}
+
+type contentMap map[string]struct {
+	Schema *schema `json:"schema"`
+}
+
+type variant struct {
+	OperationID string            `json:"operationId"`
+	Summary     string            `json:"summary"`
+	Parameters  []string          `json:"parameters"`
+	Fixed       map[string]string `json:"fixed"`
+	RequestBody *schema           `json:"requestBody"`
+	Response    *schema           `json:"response"`
+}
+
+type parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *schema `json:"schema"`
+}
+
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Format               string             `json:"format"`
+	Nullable             bool               `json:"nullable"`
+	Description          string             `json:"description"`
+	Items                *schema            `json:"items"`
+	Properties           map[string]*schema `json:"properties"`
+	AdditionalProperties *schema            `json:"additionalProperties"`
+	AllOf                []*schema          `json:"allOf"`
+	OneOf                []*schema          `json:"oneOf"`
+}
+
+// call is one generated client method
+type call struct {
+	Name     string
+	Summary  string
+	Method   string
+	Path     string
+	Params   []*parameter
+	Fixed    map[string]string
+	Body     *schema
+	Form     *schema
+	Response *schema // JSONResponse envelope; nil for a raw download
+	Binary   bool
+	Scoped   bool // served per project
+}
+
+type generator struct {
+	out     bytes.Buffer
+	types   map[string]*schema // named structs still to be written
+	written map[string]bool
+}
+
+// Generate returns the source of the client package for spec
+func Generate(spec []byte, pkg string) ([]byte, error) {
+	var doc document
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, err
+	}
+	g := &generator{types: map[string]*schema{}, written: map[string]bool{}}
+	for name, s := range doc.Components.Schemas {
+		g.types[name] = s
+	}
+
+	calls, err := collectCalls(&doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var methods bytes.Buffer
+	for _, c := range calls {
+		if err := g.method(&methods, c); err != nil {
+			return nil, fmt.Errorf("%s %s: %w", c.Method, c.Path, err)
+		}
+	}
+
+	fmt.Fprintf(&g.out, "// Code generated by openapigen from the API's OpenAPI document. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&g.out, "package %s\n\n", pkg)
+	g.out.WriteString("import (\n\"context\"\n\"encoding/json\"\n\"io\"\n\"net/http\"\n\"net/url\"\n\"strconv\"\n\"time\"\n)\n\n")
+	g.out.WriteString("var (\n_ = json.RawMessage{}\n_ = strconv.Itoa\n_ = time.Time{}\n_ io.Reader\n)\n\n")
+	if err := g.structs(); err != nil {
+		return nil, err
+	}
+	g.out.Write(methods.Bytes())
+
+	src, err := format.Source(g.out.Bytes())
+	if err != nil {
+		return g.out.Bytes(), fmt.Errorf("formatting generated client: %w", err)
+	}
+	return src, nil
+}
+
+// collectCalls lists a call per operation, or per variant of one, sorted by
+// path and method
+func collectCalls(doc *document) ([]*call, error) {
+	var calls []*call
+	for path, item := range doc.Paths {
+		for method, op := range item {
+			resp := op.Responses["200"]
+			if resp == nil {
+				continue // e.g. the WebSocket upgrade
+			}
+			base := call{Method: strings.ToUpper(method), Path: path, Scoped: op.Scoped}
+			if op.RequestBody != nil {
+				if c, ok := op.RequestBody.Content["application/json"]; ok {
+					base.Body = c.Schema
+				}
+				if c, ok := op.RequestBody.Content["multipart/form-data"]; ok {
+					base.Form = c.Schema
+				}
+			}
+			if c, ok := resp.Content["application/json"]; ok && path != "/api/openapi.json" {
+				base.Response = c.Schema
+			} else {
+				base.Binary = true
+			}
+
+			if len(op.Variants) == 0 {
+				c := base
+				c.Name, c.Summary, c.Params = op.OperationID, op.Summary, op.Parameters
+				calls = append(calls, &c)
+				continue
+			}
+			byName := map[string]*parameter{}
+			for _, p := range op.Parameters {
+				byName[p.Name] = p
+			}
+			for i, v := range op.Variants {
+				c := base
+				c.Name, c.Summary, c.Fixed, c.Response = v.OperationID, v.Summary, v.Fixed, v.Response
+				c.Body = v.RequestBody
+				for _, name := range v.Parameters {
+					p, ok := byName[name]
+					if !ok {
+						return nil, fmt.Errorf("%s: variant %s has unknown parameter %s", path, v.OperationID, name)
+					}
+					c.Params = append(c.Params, p)
+				}
+				if i > 0 && len(c.Params) == 0 && len(c.Fixed) == 0 {
+					return nil, fmt.Errorf("%s: variant %s has nothing to select it", path, v.OperationID)
+				}
+				calls = append(calls, &c)
+			}
+		}
+	}
+	order := map[string]int{"GET": 0, "POST": 1, "PUT": 2, "PATCH": 3, "DELETE": 4}
+	sort.Slice(calls, func(i, j int) bool {
+		a, b := calls[i], calls[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.Method != b.Method {
+			return order[a.Method] < order[b.Method]
+		}
+		return a.Name < b.Name
+	})
+	return calls, nil
+}
+
+// structs writes every named type, including the inline objects that were
+// named while writing others
+func (g *generator) structs() error {
+	for len(g.written) < len(g.types) {
+		var names []string
+		for name := range g.types {
+			if !g.written[name] {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			g.written[name] = true
+			if err := g.writeStruct(name, g.types[name]); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (g *generator) writeStruct(name string, s *schema) error {
+	fmt.Fprintf(&g.out, "type %s struct {\n", name)
+	var props []string
+	for p := range s.Properties {
+		props = append(props, p)
+	}
+	sort.Strings(props)
+	seen := map[string]bool{}
+	for _, p := range props {
+		field := goName(p)
+		if seen[field] {
+			return fmt.Errorf("fields %q clash as %s", p, field)
+		}
+		seen[field] = true
+		typ, err := g.goType(s.Properties[p], name+field)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&g.out, "%s %s `json:\"%s,omitempty\"`\n", field, typ, p)
+	}
+	g.out.WriteString("}\n\n")
+	return nil
+}
+
+// goType maps a schema to a Go type, naming inline objects after hint
+func (g *generator) goType(s *schema, hint string) (string, error) {
+	if s == nil {
+		return "json.RawMessage", nil
+	}
+	ptr := ""
+	if s.Nullable {
+		ptr = "*"
+	}
+	switch {
+	case s.Ref != "":
+		return strings.TrimPrefix(s.Ref, "#/components/schemas/"), nil
+	case len(s.AllOf) == 1:
+		t, err := g.goType(s.AllOf[0], hint)
+		return ptr + t, err
+	case len(s.OneOf) > 0:
+		return "json.RawMessage", nil
+	}
+	switch s.Type {
+	case "boolean":
+		return ptr + "bool", nil
+	case "integer":
+		if s.Format == "int64" {
+			return ptr + "int64", nil
+		}
+		return ptr + "int", nil
+	case "number":
+		return ptr + "float64", nil
+	case "string":
+		switch s.Format {
+		case "date-time":
+			return "*time.Time", nil
+		case "byte":
+			return "[]byte", nil
+		case "binary":
+			return "io.Reader", nil
+		}
+		return ptr + "string", nil
+	case "array":
+		t, err := g.goType(s.Items, hint+"Item")
+		return "[]" + t, err
+	case "object":
+		if len(s.Properties) > 0 {
+			if _, ok := g.types[hint]; ok {
+				return "", fmt.Errorf("inline object %s clashes with a component", hint)
+			}
+			g.types[hint] = s
+			return hint, nil
+		}
+		if s.AdditionalProperties != nil {
+			t, err := g.goType(s.AdditionalProperties, hint+"Value")
+			return "map[string]" + t, err
+		}
+	}
+	return "json.RawMessage", nil
+}
+
+// method writes the client method for c
+func (g *generator) method(w *bytes.Buffer, c *call) error {
+	name := goName(c.Name)
+	paramsType := ""
+	if len(c.Params) > 0 || c.Form != nil {
+		paramsType = name + "Params"
+		if err := g.paramsStruct(w, paramsType, c); err != nil {
+			return err
+		}
+	}
+
+	var bodyType, dataType string
+	var hasMeta bool
+	var err error
+	if c.Body != nil {
+		if bodyType, err = g.goType(c.Body, name+"Body"); err != nil {
+			return err
+		}
+	}
+	if c.Response != nil {
+		if data := c.Response.Properties["data"]; data != nil {
+			if dataType, err = g.goType(data, name+"Data"); err != nil {
+				return err
+			}
+		} else if len(c.Response.OneOf) > 0 {
+			dataType = "json.RawMessage"
+		}
+		_, hasMeta = c.Response.Properties["meta"]
+	}
+
+	args := []string{"ctx context.Context"}
+	if paramsType != "" {
+		args = append(args, "params *"+paramsType)
+	}
+	if bodyType != "" {
+		args = append(args, "body "+bodyType)
+	}
+	var results []string
+	switch {
+	case c.Binary:
+		results = append(results, "io.ReadCloser")
+	case dataType != "":
+		results = append(results, dataType)
+	}
+	if hasMeta {
+		results = append(results, "*ListMeta")
+	}
+	results = append(results, "error")
+
+	fmt.Fprintf(w, "// %s calls %s %s: %s.\n", name, c.Method, c.Path, strings.TrimSuffix(c.Summary, "."))
+	fmt.Fprintf(w, "func (c *Client) %s(%s) (%s) {\n", name, strings.Join(args, ", "), strings.Join(results, ", "))
+	if c.Scoped {
+		fmt.Fprintf(w, "req := request{method: %q, path: c.projectPath(%q), query: url.Values{}, header: http.Header{}}\n", c.Method, c.Path)
+	} else {
+		fmt.Fprintf(w, "req := request{method: %q, path: %q, query: url.Values{}, header: http.Header{}}\n", c.Method, c.Path)
+	}
+	if len(c.Params) > 0 {
+		w.WriteString("if params != nil {\n")
+		for _, p := range c.Params {
+			writeParamEncode(w, p, "req.query", "req.header")
+		}
+		w.WriteString("}\n")
+	}
+	var fixed []string
+	for k := range c.Fixed {
+		fixed = append(fixed, k)
+	}
+	sort.Strings(fixed)
+	for _, k := range fixed {
+		fmt.Fprintf(w, "req.query.Set(%q, %q)\n", k, c.Fixed[k])
+	}
+
+	zeroReturn := func() string {
+		var zeros []string
+		for _, r := range results[:len(results)-1] {
+			zeros = append(zeros, zeroValue(r))
+		}
+		return strings.Join(append(zeros, "err"), ", ")
+	}
+	if bodyType != "" {
+		w.WriteString("if err := req.setJSON(body); err != nil {\n")
+		fmt.Fprintf(w, "return %s\n", zeroReturn())
+		w.WriteString("}\n")
+	}
+	if c.Form != nil {
+		w.WriteString("fields := url.Values{}\nvar file io.Reader\nvar fileName string\nif params != nil {\n")
+		for _, p := range sortedProps(c.Form) {
+			s := c.Form.Properties[p]
+			if s.Format == "binary" {
+				fmt.Fprintf(w, "file, fileName = params.%s, params.%sName\n", goName(p), goName(p))
+				continue
+			}
+			writeParamEncode(w, &parameter{Name: p, In: "form", Schema: s}, "fields", "")
+		}
+		w.WriteString("}\n")
+		w.WriteString("if err := req.setMultipart(fields, file, fileName); err != nil {\n")
+		fmt.Fprintf(w, "return %s\n", zeroReturn())
+		w.WriteString("}\n")
+	}
+
+	switch {
+	case c.Binary:
+		w.WriteString("return c.download(ctx, req)\n")
+	case dataType == "" && !hasMeta:
+		w.WriteString("return c.call(ctx, req, nil, nil)\n")
+	default:
+		if dataType != "" {
+			fmt.Fprintf(w, "var data %s\n", dataType)
+		}
+		if hasMeta {
+			w.WriteString("meta := new(ListMeta)\n")
+		}
+		dataArg, metaArg := "nil", "nil"
+		var rets []string
+		if dataType != "" {
+			dataArg = "&data"
+			rets = append(rets, "data")
+		}
+		if hasMeta {
+			metaArg = "meta"
+			rets = append(rets, "meta")
+		}
+		fmt.Fprintf(w, "err := c.call(ctx, req, %s, %s)\n", dataArg, metaArg)
+		fmt.Fprintf(w, "return %s, err\n", strings.Join(rets, ", "))
+	}
+	w.WriteString("}\n\n")
+	return nil
+}
+
+func (g *generator) paramsStruct(w *bytes.Buffer, name string, c *call) error {
+	fmt.Fprintf(w, "// %s are the parameters of %s.\ntype %s struct {\n", name, goName(c.Name), name)
+	for _, p := range c.Params {
+		fmt.Fprintf(w, "%s %s\n", goName(p.Name), paramType(p.Schema))
+	}
+	if c.Form != nil {
+		for _, p := range sortedProps(c.Form) {
+			s := c.Form.Properties[p]
+			if s.Format == "binary" {
+				fmt.Fprintf(w, "%s io.Reader\n%sName string\n", goName(p), goName(p))
+				continue
+			}
+			fmt.Fprintf(w, "%s %s\n", goName(p), paramType(s))
+		}
+	}
+	w.WriteString("}\n\n")
+	return nil
+}
+
+// paramType is the Go type of a parameter; booleans are pointers so false
+// can be sent
+func paramType(s *schema) string {
+	switch s.Type {
+	case "array":
+		return "[]" + paramType(s.Items)
+	case "integer":
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "*bool"
+	}
+	return "string"
+}
+
+// writeParamEncode writes code adding a set parameter to values, or to
+// header for header parameters
+func writeParamEncode(w *bytes.Buffer, p *parameter, values, header string) {
+	field := "params." + goName(p.Name)
+	target, verb := values, "Set"
+	if p.In == "header" {
+		target = header
+	}
+	s := p.Schema
+	if s.Type == "array" {
+		verb = "Add"
+		fmt.Fprintf(w, "for _, v := range %s {\n%s.%s(%q, %s)\n}\n", field, target, verb, p.Name, formatValue(s.Items, "v"))
+		return
+	}
+	switch s.Type {
+	case "boolean":
+		fmt.Fprintf(w, "if %s != nil {\n%s.%s(%q, %s)\n}\n", field, target, verb, p.Name, formatValue(s, "*"+field))
+	case "integer", "number":
+		fmt.Fprintf(w, "if %s != 0 {\n%s.%s(%q, %s)\n}\n", field, target, verb, p.Name, formatValue(s, field))
+	default:
+		fmt.Fprintf(w, "if %s != \"\" {\n%s.%s(%q, %s)\n}\n", field, target, verb, p.Name, field)
+	}
+}
+
+func formatValue(s *schema, expr string) string {
+	switch s.Type {
+	case "boolean":
+		return "strconv.FormatBool(" + expr + ")"
+	case "integer":
+		return "strconv.Itoa(" + expr + ")"
+	case "number":
+		return "strconv.FormatFloat(" + expr + ", 'f', -1, 64)"
+	}
+	return expr
+}
+
+func zeroValue(t string) string {
+	switch {
+	case strings.HasPrefix(t, "*"), strings.HasPrefix(t, "[]"), strings.HasPrefix(t, "map["),
+		t == "json.RawMessage", t == "io.ReadCloser", t == "io.Reader":
+		return "nil"
+	case t == "string":
+		return `""`
+	case t == "bool":
+		return "false"
+	case t == "int", t == "int64", t == "float64":
+		return "0"
+	}
+	return t + "{}"
+}
+
+func sortedProps(s *schema) []string {
+	var names []string
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// initialisms are name parts written in capitals
+var initialisms = map[string]string{
+	"id": "ID", "ids": "IDs", "dmr": "DMR", "url": "URL", "api": "API", "nxdn": "NXDN", "ran": "RAN",
+	"dcs": "DCS", "ctcss": "CTCSS", "aprs": "APRS", "ptt": "PTT", "dtmf": "DTMF", "tg": "TG", "json": "JSON",
+}
+
+// goName converts a JSON, parameter or operation name to an exported Go name
+func goName(s string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == '_' || r == '-' || r == '.' }) {
+		if up, ok := initialisms[strings.ToLower(part)]; ok && part == strings.ToLower(part) {
+			b.WriteString(up)
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
@@ -1,19 +1,23 @@
 package main
 
 import (
+	"context"
 	"embed"
 	"flag"
 	"fmt"
 	"io/fs"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"syscall"
 
 	"codeplugs/api"
 	"codeplugs/cmd"
+	"codeplugs/config"
 	"codeplugs/database"
-	"codeplugs/exporter"
 	"codeplugs/importer"
 	"codeplugs/models"
 	"codeplugs/services"
@@ -23,12 +27,51 @@ import (
 var frontendDist embed.FS
 
 func main() {
+	// Subcommands
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "contacts":
+			if err := cmd.Contacts(os.Args[2:]); err != nil {
+				log.Fatalf("Error: %v", err)
+			}
+			return
+		case "edit":
+			if err := cmd.Edit(os.Args[2:]); err != nil {
+				log.Fatalf("Error editing channels: %v", err)
+			}
+			return
+		case "list-op":
+			if err := cmd.ListOp(os.Args[2:]); err != nil {
+				log.Fatalf("Error running list operation: %v", err)
+			}
+			return
+		case "sync-contacts":
+			if err := cmd.SyncContacts(os.Args[2:]); err != nil {
+				log.Fatalf("Error syncing contacts: %v", err)
+			}
+			return
+		case "export":
+			if err := cmd.Export(os.Args[2:]); err != nil {
+				log.Fatalf("Error exporting: %v", err)
+			}
+			return
+		case "users":
+			if err := cmd.Users(os.Args[2:]); err != nil {
+				log.Fatalf("Error managing users: %v", err)
+			}
+			return
+		}
+	}
+
+	configPath := flag.String("config", "", "Path to the config file (default: codeplugs.yaml or $CODEPLUGS_CONFIG)")
 	dbPath := flag.String("db", "codeplugs.db", "Path to SQLite database")
 	importFile := flag.String("import", "", "Path to CSV file to import")
 	exportFile := flag.String("export", "", "Path to CSV file to export to")
-	format := flag.String("format", "db25d", "Export format: db25d, chirp")
+	format := flag.String("format", "db25d", "Export format: db25d, chirp, p25, icom (D-Star repeater list), icom_mycall (D-Star own calls)")
 	serve := flag.Bool("serve", false, "Start Web UI server")
 	port := flag.String("port", "8080", "Port for Web UI server")
+	projectsDir := flag.String("projects-dir", "", "Directory of project databases served under /api/projects/ (default: projects/ beside --db)")
+	allowedOrigins := flag.String("allowed-origins", "", "Comma-separated extra origins allowed to use the WebSocket and session cookie, e.g. http://localhost:5173")
 	zoneName := flag.String("zone", "", "Zone name to assign imported channels to or filter export by")
 
 	// Additional flags
@@ -41,7 +84,18 @@ func main() {
 	importList := flag.String("import-list", "", "Path to filter list CSV to import (overwrites existing list)")
 	listName := flag.String("list-name", "", "Name for the filter list (required if importing list or viewing specific list)")
 	viewList := flag.String("view-list", "", "View filter list stats (use 'all' for summary, or specify name)")
-	useList := flag.String("use-list", "", "Filter export using this named list from the database")
+	useList := flag.String("use-list", "", "Filter export using a named list from the database, or an expression over lists, e.g. \"club, statewide & heard - banned\"")
+	var listRules stringSlice
+	flag.Var(&listRules, "list-rule", "Rule group for a dynamic filter list (repeatable; groups are ORed), e.g. \"state=Michigan\" or \"state=Ohio;heard_within=30\". Fields: country, state, callsign (regex), dmr_id (ranges), heard_within (days), list. Requires --list-name")
+
+	// Contact Ranking Flags
+	importActivity := flag.String("import-activity", "", "Path to Brandmeister last-heard CSV to record contact activity from")
+	importTalkgroups := flag.String("import-talkgroups", "", "Path to a network talkgroup list (Brandmeister JSON, TGIF JSON or CSV) to load into the talkgroup catalog")
+	network := flag.String("network", "", "Network of the --import-talkgroups file (Brandmeister, TGIF, DMR-MARC), or comma-separated networks for --resolve-placeholders")
+	resolvePlaceholders := flag.Bool("resolve-placeholders", false, "Replace auto-created negative-ID contacts with matching talkgroups from the catalog")
+	pinIDs := flag.String("pin", "", "Comma-separated DMR IDs to always keep when contacts are truncated")
+	priorityStates := flag.String("priority-states", "", "Comma-separated states to favor when contacts are truncated")
+	priorityCountries := flag.String("priority-countries", "", "Comma-separated countries to favor when contacts are truncated")
 
 	// Contact Generation Flags
 	generateContacts := flag.Bool("generate-contacts", false, "Generate filtered contact list from source CSV")
@@ -52,6 +106,26 @@ func main() {
 
 	flag.Parse()
 
+	// codeplugs.yaml and CODEPLUGS_* fill in flags that weren't given
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("Error loading config: %v", err)
+	}
+	setFlags := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { setFlags[f.Name] = true })
+	if !setFlags["db"] {
+		*dbPath = cfg.DB
+	}
+	if !setFlags["port"] {
+		*port = cfg.Port
+	}
+	if !setFlags["projects-dir"] {
+		*projectsDir = cfg.ProjectsDir
+	}
+	if !setFlags["allowed-origins"] {
+		*allowedOrigins = strings.Join(cfg.AllowedOrigins, ",")
+	}
+
 	// Handle Contact Generation (no DB required)
 	if *generateContacts {
 		if *genFilterFile == "" {
@@ -77,6 +151,68 @@ func main() {
 		return
 	}
 
+	if *importTalkgroups != "" || *resolvePlaceholders {
+		if *importTalkgroups != "" {
+			if *network == "" {
+				log.Fatal("Error: --network is required when importing talkgroups.")
+			}
+			f, err := os.Open(*importTalkgroups)
+			if err != nil {
+				log.Fatalf("Error opening talkgroup file: %v", err)
+			}
+			n, err := importer.ImportTalkgroupCatalog(database.DB, f, *network)
+			f.Close()
+			if err != nil {
+				log.Fatalf("Error importing talkgroups: %v", err)
+			}
+			fmt.Printf("Imported %d %s talkgroups.\n", n, *network)
+		}
+		if *resolvePlaceholders {
+			results, err := services.ResolvePlaceholderContacts(database.DB, splitList(*network))
+			if err != nil {
+				log.Fatalf("Error resolving placeholder contacts: %v", err)
+			}
+			for _, res := range results {
+				switch res.Status {
+				case services.PlaceholderResolved:
+					fmt.Printf(" %-20s %d -> %d (%s)\n", res.Name, res.OldDMRID, res.NewDMRID, res.Network)
+				case services.PlaceholderMerged:
+					fmt.Printf(" %-20s %d -> %d (%s, merged into contact #%d)\n", res.Name, res.OldDMRID, res.NewDMRID, res.Network, res.MergedInto)
+				case services.PlaceholderAmbiguous:
+					fmt.Printf(" %-20s ambiguous: %d catalog matches\n", res.Name, len(res.Candidates))
+				default:
+					fmt.Printf(" %-20s no match\n", res.Name)
+				}
+			}
+			fmt.Printf("Checked %d placeholder contacts.\n", len(results))
+		}
+		return
+	}
+
+	if *importActivity != "" || *pinIDs != "" {
+		if *importActivity != "" {
+			f, err := os.Open(*importActivity)
+			if err != nil {
+				log.Fatalf("Error opening activity file: %v", err)
+			}
+			n, err := importer.ImportContactActivity(database.DB, f)
+			f.Close()
+			if err != nil {
+				log.Fatalf("Error importing activity: %v", err)
+			}
+			fmt.Printf("Recorded activity for %d DMR IDs.\n", n)
+		}
+		for _, idStr := range splitList(*pinIDs) {
+			id, err := strconv.Atoi(idStr)
+			if err != nil {
+				log.Fatalf("Invalid DMR ID to pin: %s", idStr)
+			}
+			database.DB.Where(models.ContactPin{DMRID: id}).FirstOrCreate(&models.ContactPin{DMRID: id})
+			fmt.Printf("Pinned %d.\n", id)
+		}
+		return
+	}
+
 	// 1. Handle List Import
 	if *importList != "" {
 		if *listName == "" {
@@ -89,6 +225,33 @@ func main() {
 		return
 	}
 
+	// 1b. Handle Rule-Based List Definition
+	if len(listRules) > 0 {
+		if *listName == "" {
+			log.Fatal("Error: --list-name is required when defining list rules.")
+		}
+		rules, err := models.ParseContactListRules(listRules)
+		if err != nil {
+			log.Fatalf("Error parsing list rules: %v", err)
+		}
+
+		var list models.ContactList
+		if err := database.DB.Where(models.ContactList{Name: *listName}).FirstOrCreate(&list).Error; err != nil {
+			log.Fatalf("Error creating list: %v", err)
+		}
+		err = models.SetContactListRules(database.DB, list.ID, rules)
+		if err != nil {
+			log.Fatalf("Error saving list rules: %v", err)
+		}
+
+		ids, err := models.ResolveContactList(database.DB, list.ID)
+		if err != nil {
+			log.Fatalf("Error evaluating list: %v", err)
+		}
+		fmt.Printf("Saved %d rules to list '%s' (currently matches %d contacts).\n", len(rules), list.Name, len(ids))
+		return
+	}
+
 	// 2. Handle View List
 	if *viewList != "" {
 		if *viewList == "all" {
@@ -110,6 +273,18 @@ func main() {
 			database.DB.Model(&models.ContactListEntry{}).Where("contact_list_id = ?", list.ID).Count(&count)
 			fmt.Printf("List: %s\nDescription: %s\nTotal Entries: %d\n", list.Name, list.Description, count)
 
+			var rules []models.ContactListRule
+			database.DB.Where("contact_list_id = ?", list.ID).Order("rule_group, id").Find(&rules)
+			if len(rules) > 0 {
+				fmt.Println("Rules:")
+				for _, r := range rules {
+					fmt.Printf(" - group %d: %s = %s\n", r.Group+1, r.Field, r.Value)
+				}
+				if ids, err := models.ResolveContactList(database.DB, list.ID); err == nil {
+					fmt.Printf("Currently matches %d contacts.\n", len(ids))
+				}
+			}
+
 			// Show first 10
 			var entries []models.ContactListEntry
 			database.DB.Where("contact_list_id = ?", list.ID).Limit(10).Find(&entries)
@@ -123,15 +298,38 @@ func main() {
 		return
 	}
 
-	// Start WebSocket Hub (if serving)
 	if *serve {
-		go api.Hub.Run()
+		if *projectsDir == "" {
+			*projectsDir = filepath.Join(filepath.Dir(*dbPath), "projects")
+		}
+		if enabled, err := services.AuthEnabled(database.DB); err != nil {
+			log.Fatalf("Checking accounts: %v", err)
+		} else if !enabled {
+			log.Println("Warning: no accounts exist, so the web server does not require a login. Create an admin with: codeplugs users -add admin -role admin")
+		}
 
 		distFS, err := fs.Sub(frontendDist, "frontend/dist")
 		if err != nil {
 			log.Fatal(err)
 		}
-		api.StartServerWithFS(*port, distFS)
+		workspaces := database.NewWorkspaces(database.NewHandle(database.DB, *dbPath), *projectsDir)
+		srv := api.NewServer(workspaces, api.Config{
+			Addr:           ":" + *port,
+			AllowedOrigins: splitList(*allowedOrigins),
+			Frontend:       distFS,
+			Profiles:       cfg.ProfileList(),
+		})
+
+		// Ctrl-C finishes requests in flight and closes the databases cleanly
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		fmt.Printf("Starting server on http://localhost:%s\n", *port)
+		err = srv.ListenAndServe(ctx)
+		stop()
+		srv.Close()
+		workspaces.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
 		return
 	}
 
@@ -285,203 +483,37 @@ func main() {
 		fmt.Printf("Imported %d channels (skipped %d duplicates).\n", count, skipped)
 
 	} else if *exportFile != "" {
-		if *radio == "dm32uv" {
-			// DM32UV Export Logic
-			baseFilename := *exportFile
-			// Remove .csv extension if present to append suffixes
-			if len(baseFilename) > 4 && baseFilename[len(baseFilename)-4:] == ".csv" {
-				baseFilename = baseFilename[:len(baseFilename)-4]
-			}
-
-			fmt.Printf("Exporting for DM32UV to %s-*.csv ...\n", baseFilename)
-
-			// 1. Export Channels
-			var channels []models.Channel
-			query := database.DB.Model(&models.Channel{}).Preload("Contact").Where("skip = ?", false)
-			if *zoneName != "" {
-				var zone models.Zone
-				if err := database.DB.Where("name = ?", *zoneName).First(&zone).Error; err != nil {
-					log.Fatalf("Zone not found: %s", *zoneName)
-				}
-				query = query.Joins("JOIN zone_channels ON zone_channels.channel_id = channels.id").
-					Where("zone_channels.zone_id = ?", zone.ID)
-			}
-			query.Find(&channels)
-
-			chanFile, err := os.Create(baseFilename + "_channels.csv")
-			if err != nil {
-				log.Fatalf("Error creating channels file: %v", err)
-			}
-			defer chanFile.Close()
-			err = exporter.ExportDM32UVChannels(channels, chanFile)
-			if err != nil {
-				log.Fatalf("Error exporting channels: %v", err)
-			}
-			fmt.Printf(" - Channels: %s_channels.csv\n", baseFilename)
-
-			// 2. Export Zones
-			var zones []models.Zone
-			database.DB.Preload("Channels").Find(&zones)
-			if *zoneName != "" {
-				// Filter to only this zone
-				filteredZones := []models.Zone{}
-				for _, z := range zones {
-					if z.Name == *zoneName {
-						filteredZones = append(filteredZones, z)
-						break
-					}
-				}
-				zones = filteredZones
-			}
-
-			zoneFile, err := os.Create(baseFilename + "_zones.csv")
-			if err != nil {
-				log.Fatalf("Error creating zones file: %v", err)
-			}
-			defer zoneFile.Close()
-			err = exporter.ExportDM32UVZones(zones, zoneFile)
-			if err != nil {
-				log.Fatalf("Error exporting zones: %v", err)
-			}
-			fmt.Printf(" - Zones: %s_zones.csv\n", baseFilename)
-
-			// 3. Export Talk Groups (Local Contacts)
-			var talkgroups []models.Contact
-			database.DB.Where("type = ?", models.ContactTypeGroup).Find(&talkgroups)
-
-			tgFile, err := os.Create(baseFilename + "_talkgroups.csv")
-			if err != nil {
-				log.Fatalf("Error creating talkgroups file: %v", err)
-			}
-			defer tgFile.Close()
-			err = exporter.ExportDM32UVTalkgroups(talkgroups, tgFile)
-			if err != nil {
-				log.Fatalf("Error exporting talkgroups: %v", err)
-			}
-			fmt.Printf(" - TalkGroups: %s_talkgroups.csv\n", baseFilename)
-
-			// 4. Export Digital Contacts (CSV Contacts)
-			// Filter logic
-			var filterListID uint
-			if *useList != "" {
-				var list models.ContactList
-				if err := database.DB.Where("name = ?", *useList).First(&list).Error; err == nil {
-					filterListID = list.ID
-					fmt.Printf("Filtering contacts using list '%s'\n", *useList)
-				} else {
-					log.Printf("Warning: Filter list '%s' not found.", *useList)
-				}
-			}
-
-			var allowedIDs map[int]bool
-			if *filterList != "" {
-				var err error
-				allowedIDs, err = importer.LoadFilterList(*filterList)
-				if err != nil {
-					log.Fatalf("Error loading filter list: %v", err)
-				}
-				fmt.Printf("Loaded %d allowed IDs from filter list.\n", len(allowedIDs))
-			}
-
-			// Fetch Digital Contacts
-			var digitalContacts []models.DigitalContact
-
-			queryDC := database.DB.Model(&models.DigitalContact{})
-
-			// Filter by DB List if requested
-			if filterListID > 0 {
-				queryDC = queryDC.Where("dmr_id IN (?)", database.DB.Model(&models.ContactListEntry{}).Select("dmr_id").Where("contact_list_id = ?", filterListID))
-			}
-
-			queryDC.Find(&digitalContacts)
-
-			filteredContacts := []models.DigitalContact{}
-			for _, c := range digitalContacts {
-				if allowedIDs != nil {
-					if !allowedIDs[c.DMRID] {
-						continue
-					}
-				}
-				filteredContacts = append(filteredContacts, c)
-			}
-
-			// Apply Limit
-			maxContacts := 50000 // Default for DM32UV
-			if *limit > 0 {
-				maxContacts = *limit
-			}
-
-			if len(filteredContacts) > maxContacts {
-				fmt.Printf("Warning: Contact count %d exceeds limit %d. Truncating.\n", len(filteredContacts), maxContacts)
-				filteredContacts = filteredContacts[:maxContacts]
-			}
-
-			dcFile, err := os.Create(baseFilename + "_digital_contacts.csv")
-			if err != nil {
-				log.Fatalf("Error creating digital contacts file: %v", err)
-			}
-			defer dcFile.Close()
-			err = exporter.ExportDM32UVDigitalContacts(filteredContacts, dcFile)
-			if err != nil {
-				log.Fatalf("Error exporting digital contacts: %v", err)
-			}
-			fmt.Printf(" - Digital Contacts: %s_digital_contacts.csv (%d records)\n", baseFilename, len(filteredContacts))
-
-		} else if *radio == "at890" {
-			// AnyTone 890 Export Logic
-			fmt.Printf("Exporting AnyTone 890 to directory %s...\n", *exportFile)
-
-			var filterListID uint
-			if *useList != "" {
-				var list models.ContactList
-				if err := database.DB.Where("name = ?", *useList).First(&list).Error; err == nil {
-					filterListID = list.ID
-					fmt.Printf("Filtering contacts using list '%s'\n", *useList)
-				} else {
-					log.Printf("Warning: Filter list '%s' not found.", *useList)
-				}
-			}
-
-			if err := exporter.ExportAnyTone890(database.DB, *exportFile, filterListID); err != nil {
-				log.Fatalf("Error exporting 890: %v", err)
-			}
-			fmt.Println("Export complete.")
-			return
-		} else {
-			// Existing DB25-D Export
-			fmt.Printf("Exporting to %s (format: %s)...\n", *exportFile, *format)
-			var channels []models.Channel
-			query := database.DB.Model(&models.Channel{}).Preload("Contact").Where("skip = ?", false)
-
-			if *zoneName != "" {
-				var zone models.Zone
-				if err := database.DB.Where("name = ?", *zoneName).First(&zone).Error; err != nil {
-					log.Fatalf("Zone not found: %s", *zoneName)
-				}
-				query = query.Joins("JOIN zone_channels ON zone_channels.channel_id = channels.id").
-					Where("zone_channels.zone_id = ?", zone.ID)
-			}
-
-			query.Find(&channels)
-
-			var err error
-
-			f, err := os.Create(*exportFile)
-			if err != nil {
-				log.Fatalf("Error creating export file: %v", err)
-			}
-			defer f.Close()
-
-			if *format == "chirp" {
-				err = exporter.ExportChirpCSV(channels, f)
-			} else {
-				exporter.ExportDB25D(channels, f, false)
-			}
-
-			if err != nil {
-				log.Fatalf("Error exporting CSV: %v", err)
-			}
-			fmt.Printf("Exported %d channels to %s.\n", len(channels), *exportFile)
+		// The same profile `codeplugs export -profile` loads from codeplugs.yaml
+		profile := config.Profile{
+			Radio:             *radio,
+			UseList:           *useList,
+			FilterFile:        *filterList,
+			Limit:             *limit,
+			PriorityStates:    splitList(*priorityStates),
+			PriorityCountries: splitList(*priorityCountries),
+			Output:            *exportFile,
+		}
+		if *radio == "db25d" {
+			profile.Format = *format
+		}
+		if *zoneName != "" {
+			profile.Zones = []string{*zoneName}
+		}
+		// These flags predate per-radio profiles, so options a radio ignores
+		// only warn here; codeplugs.yaml profiles still reject them
+		flagNames := map[string]string{
+			"zones":              "-zone",
+			"use_list":           "-use-list",
+			"filter_file":        "-filter-list",
+			"limit":              "-limit",
+			"priority_states":    "-priority-states",
+			"priority_countries": "-priority-countries",
+		}
+		for _, name := range profile.DropUnsupported() {
+			log.Printf("Warning: -radio %s ignores %s", profile.Radio, flagNames[name])
+		}
+		if err := cmd.RunExport(database.DB, profile); err != nil {
+			log.Fatalf("Error: %v", err)
 		}
 	} else {
 		var channelCount int64
@@ -489,3 +521,24 @@ func main() {
 		fmt.Printf("Database contains %d channels.\n", channelCount)
 	}
 }
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// stringSlice collects a repeatable string flag
+type stringSlice []string
+
+func (s *stringSlice) String() string { return strings.Join(*s, ", ") }
+
+func (s *stringSlice) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
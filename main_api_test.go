@@ -16,6 +16,24 @@ import (
 )
 
 // setupTestDB creates an in-memory SQLite DB for testing
+// serveAPI serves one request with a server over the test database
+func serveAPI(w http.ResponseWriter, r *http.Request) {
+	srv := api.NewServerForDB(database.DB)
+	defer srv.Close()
+	srv.ServeHTTP(w, r)
+}
+
+// newTestServer listens on a local port with s, which it closes after the test
+func newTestServer(t *testing.T, s *api.Server) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(s)
+	t.Cleanup(func() {
+		ts.Close()
+		s.Close()
+	})
+	return ts
+}
+
 func setupTestDB() {
 	var err error
 	database.DB, err = gorm.Open(sqlite.Dialector{
@@ -40,7 +58,16 @@ func setupTestDB() {
 		&models.ScanList{},
 		&models.RoamingChannel{},
 		&models.RoamingZone{},
+		&models.NXDNTalkgroup{},
+		&models.NXDNContact{},
+		&models.TalkgroupCatalog{},
+		&models.ContactOverride{},
+		&models.User{},
+		&models.APIToken{},
+		&models.Session{},
+		&models.Project{},
 	)
+	models.SetupDigitalContactFTS(database.DB)
 }
 
 func TestZoneAPI_CRUD(t *testing.T) {
@@ -53,7 +80,7 @@ func TestZoneAPI_CRUD(t *testing.T) {
 	rr := httptest.NewRecorder()
 
 	// Handler to be implemented
-	api.HandleZones(rr, req)
+	serveAPI(rr, req)
 
 	if status := rr.Code; status != http.StatusOK {
 		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
@@ -79,7 +106,7 @@ func TestZoneAPI_CRUD(t *testing.T) {
 	// 2. List Zones
 	req, _ = http.NewRequest("GET", "/api/zones", nil)
 	rr = httptest.NewRecorder()
-	api.HandleZones(rr, req)
+	serveAPI(rr, req)
 
 	json.Unmarshal(rr.Body.Bytes(), &resp)
 	var zones []models.Zone
@@ -95,7 +122,7 @@ func TestZoneAPI_CRUD(t *testing.T) {
 	deleteURL := fmt.Sprintf("/api/zones?id=%d", createdZone.ID)
 	req, _ = http.NewRequest("DELETE", deleteURL, nil)
 	rr = httptest.NewRecorder()
-	api.HandleZones(rr, req)
+	serveAPI(rr, req)
 
 	if rr.Code != http.StatusOK {
 		t.Errorf("delete failed")
@@ -140,7 +167,7 @@ func TestZoneAssignment_Ordering(t *testing.T) {
 
 	// Handler to be implemented
 
-	api.HandleZoneAssignment(rr, req)
+	serveAPI(rr, req)
 
 	if rr.Code != http.StatusOK {
 		t.Errorf("assignment failed: code %d", rr.Code)
@@ -158,7 +185,7 @@ func TestZoneAssignment_Ordering(t *testing.T) {
 	// Let's Fetch via API to confirm API respects it
 	req, _ = http.NewRequest("GET", fmt.Sprintf("/api/zones?id=%d", z.ID), nil)
 	rr = httptest.NewRecorder()
-	api.HandleZones(rr, req)
+	serveAPI(rr, req)
 
 	var resp ResponseWrapper
 	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
@@ -190,7 +217,7 @@ func TestExportAPI_Zip(t *testing.T) {
 	req, _ := http.NewRequest("GET", "/api/export?radio=dm32uv", nil)
 	rr := httptest.NewRecorder()
 
-	api.HandleExport(rr, req)
+	serveAPI(rr, req)
 
 	if rr.Code != http.StatusOK {
 		t.Errorf("export failed")
@@ -204,6 +231,60 @@ func TestExportAPI_Zip(t *testing.T) {
 	// Could verify zip contents here
 }
 
+func TestExportAPI_ReportsSkippedP25(t *testing.T) {
+	setupTestDB()
+	database.DB.Exec("DELETE FROM channels")
+	database.DB.Create(&models.Channel{Name: "TestChan", RxFrequency: 146.52})
+	database.DB.Create(&models.Channel{Name: "P25 Tac", Type: models.ChannelTypeDigitalP25, Protocol: models.ProtocolP25, RxFrequency: 851.0125})
+
+	for _, url := range []string{"/api/export?format=chirp", "/api/export?radio=dm32uv", "/api/export?radio=at890"} {
+		rr := httptest.NewRecorder()
+		serveAPI(rr, httptest.NewRequest("GET", url, nil))
+		if rr.Code != http.StatusOK || rr.Header().Get("X-Skipped-Channels") != "P25 Tac" {
+			t.Errorf("%s: expected P25 Tac reported as skipped, got %d %q", url, rr.Code, rr.Header().Get("X-Skipped-Channels"))
+		}
+	}
+}
+
+func TestFilterListsAPI_BadRulesRollBack(t *testing.T) {
+	setupTestDB()
+	database.DB.AutoMigrate(&models.ContactList{}, &models.ContactListEntry{}, &models.ContactListRule{})
+	for _, table := range []string{"contact_lists", "contact_list_entries", "contact_list_rules"} {
+		database.DB.Exec("DELETE FROM " + table)
+	}
+	post := func(body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		serveAPI(rr, httptest.NewRequest("POST", "/api/filter_lists", bytes.NewBufferString(body)))
+		return rr
+	}
+
+	// A rule naming a missing list only fails once resolved
+	if rr := post(`{"name": "Broken", "rules": [{"Field": "list", "Value": "Nowhere"}]}`); rr.Code != http.StatusBadRequest {
+		t.Fatalf("Expected 400, got %d %s", rr.Code, rr.Body.String())
+	}
+	var count int64
+	database.DB.Model(&models.ContactList{}).Count(&count)
+	if count != 0 {
+		t.Errorf("Expected the failed create to leave no list, got %d", count)
+	}
+
+	rr := post(`{"name": "Ohio", "rules": [{"Field": "state", "Value": "Ohio"}]}`)
+	var created struct{ Data models.ContactList }
+	json.Unmarshal(rr.Body.Bytes(), &created)
+	if rr.Code != http.StatusOK || created.Data.ID == 0 {
+		t.Fatalf("Expected the list to be created, got %d %s", rr.Code, rr.Body.String())
+	}
+	body := fmt.Sprintf(`{"ID": %d, "version": %d, "name": "Renamed", "rules": [{"Field": "list", "Value": "Nowhere"}]}`, created.Data.ID, created.Data.Version)
+	if rr := post(body); rr.Code != http.StatusBadRequest {
+		t.Fatalf("Expected 400, got %d %s", rr.Code, rr.Body.String())
+	}
+	var stored models.ContactList
+	database.DB.Preload("Rules").First(&stored, created.Data.ID)
+	if stored.Name != "Ohio" || stored.Version != created.Data.Version || len(stored.Rules) != 1 || stored.Rules[0].Field != "state" {
+		t.Errorf("Expected the failed update to change nothing, got %+v", stored)
+	}
+}
+
 // ResponseWrapper matches api.JSONResponse generic structure for tests
 type ResponseWrapper struct {
 	Success bool            `json:"success"`
@@ -227,7 +308,7 @@ func TestRoamingAPI_CRUD(t *testing.T) {
 	req, _ := http.NewRequest("POST", "/api/roaming/channels", bytes.NewBuffer(reqBody))
 	rr := httptest.NewRecorder()
 
-	api.HandleRoamingChannels(rr, req)
+	serveAPI(rr, req)
 
 	if rr.Code != http.StatusOK {
 		t.Errorf("create roaming channel failed: %d", rr.Code)
@@ -250,7 +331,7 @@ func TestRoamingAPI_CRUD(t *testing.T) {
 	req, _ = http.NewRequest("POST", "/api/roaming/zones", bytes.NewBuffer(reqBody))
 	rr = httptest.NewRecorder()
 
-	api.HandleRoamingZones(rr, req)
+	serveAPI(rr, req)
 	if rr.Code != http.StatusOK {
 		t.Errorf("create roaming zone failed: %d", rr.Code)
 	}
@@ -269,7 +350,7 @@ func TestRoamingAPI_CRUD(t *testing.T) {
 	req, _ = http.NewRequest("POST", url, bytes.NewBuffer(reqBody))
 	rr = httptest.NewRecorder()
 
-	api.HandleRoamingZoneAssignment(rr, req)
+	serveAPI(rr, req)
 	if rr.Code != http.StatusOK {
 		t.Errorf("roaming zone assign failed: %d", rr.Code)
 	}
@@ -277,7 +358,7 @@ func TestRoamingAPI_CRUD(t *testing.T) {
 	// 4. Verify Assignment
 	req, _ = http.NewRequest("GET", fmt.Sprintf("/api/roaming/zones?id=%d", createdRZ.ID), nil)
 	rr = httptest.NewRecorder()
-	api.HandleRoamingZones(rr, req)
+	serveAPI(rr, req)
 
 	json.Unmarshal(rr.Body.Bytes(), &resp)
 	var fetchedRZ models.RoamingZone
@@ -298,7 +379,7 @@ func TestScanListAPI_CRUD(t *testing.T) {
 	req, _ := http.NewRequest("POST", "/api/scanlists", bytes.NewBuffer(reqBody))
 	rr := httptest.NewRecorder()
 
-	api.HandleScanLists(rr, req)
+	serveAPI(rr, req)
 	if rr.Code != http.StatusOK {
 		t.Errorf("create scanlist failed: %d", rr.Code)
 	}
@@ -312,3 +393,411 @@ func TestScanListAPI_CRUD(t *testing.T) {
 		t.Errorf("Expected %s, got %s", slName, createdSL.Name)
 	}
 }
+
+func TestNXDNAPI_CRUD(t *testing.T) {
+	setupTestDB()
+
+	// 1. Reject out-of-range talkgroup
+	reqBody, _ := json.Marshal(models.NXDNTalkgroup{Name: "Bad", TGID: 70000})
+	req, _ := http.NewRequest("POST", "/api/nxdn/talkgroups", bytes.NewBuffer(reqBody))
+	rr := httptest.NewRecorder()
+	serveAPI(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid TG, got %d", rr.Code)
+	}
+
+	// 2. Create Talkgroup
+	reqBody, _ = json.Marshal(models.NXDNTalkgroup{Name: "Local", TGID: 100})
+	req, _ = http.NewRequest("POST", "/api/nxdn/talkgroups", bytes.NewBuffer(reqBody))
+	rr = httptest.NewRecorder()
+	serveAPI(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("create NXDN talkgroup failed: %d", rr.Code)
+	}
+
+	var resp ResponseWrapper
+	json.Unmarshal(rr.Body.Bytes(), &resp)
+	var tg models.NXDNTalkgroup
+	json.Unmarshal(resp.Data, &tg)
+	if tg.ID == 0 || tg.TGID != 100 {
+		t.Errorf("Unexpected talkgroup: %+v", tg)
+	}
+
+	// 3. Create Contact and search
+	reqBody, _ = json.Marshal(models.NXDNContact{UnitID: 1234, Callsign: "KF8S", Name: "Test"})
+	req, _ = http.NewRequest("POST", "/api/nxdn/contacts", bytes.NewBuffer(reqBody))
+	rr = httptest.NewRecorder()
+	serveAPI(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("create NXDN contact failed: %d", rr.Code)
+	}
+
+	req, _ = http.NewRequest("GET", "/api/nxdn/contacts?search=KF8", nil)
+	rr = httptest.NewRecorder()
+	serveAPI(rr, req)
+	json.Unmarshal(rr.Body.Bytes(), &resp)
+	var page struct {
+		Data []models.NXDNContact `json:"data"`
+		Meta struct {
+			Total int64 `json:"total"`
+		} `json:"meta"`
+	}
+	json.Unmarshal(resp.Data, &page)
+	if page.Meta.Total != 1 || len(page.Data) != 1 || page.Data[0].UnitID != 1234 {
+		t.Errorf("Unexpected search result: %+v", page)
+	}
+
+	// 4. Delete Talkgroup
+	req, _ = http.NewRequest("DELETE", fmt.Sprintf("/api/nxdn/talkgroups?id=%d", tg.ID), nil)
+	rr = httptest.NewRecorder()
+	serveAPI(rr, req)
+
+	var count int64
+	database.DB.Model(&models.NXDNTalkgroup{}).Count(&count)
+	if count != 0 {
+		t.Errorf("Expected 0 talkgroups after delete, got %d", count)
+	}
+}
+
+func TestUnresolvedContactsAPI_Merge(t *testing.T) {
+	setupTestDB()
+	database.DB.Exec("DELETE FROM contacts")
+	database.DB.Exec("DELETE FROM channels")
+
+	real := models.Contact{Name: "Michigan", DMRID: 3126, Type: models.ContactTypeGroup}
+	database.DB.Create(&real)
+	placeholder := models.Contact{Name: "Michigan Statewide", DMRID: -1, Type: models.ContactTypeGroup}
+	database.DB.Create(&placeholder)
+	database.DB.Create(&models.Channel{Name: "MI", TxContact: placeholder.Name, ContactID: &placeholder.ID})
+
+	// 1. Report lists the placeholder with its channel and suggestion
+	req, _ := http.NewRequest("GET", "/api/contacts/unresolved", nil)
+	rr := httptest.NewRecorder()
+	serveAPI(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("unresolved report failed: %d", rr.Code)
+	}
+	var resp ResponseWrapper
+	json.Unmarshal(rr.Body.Bytes(), &resp)
+	var report []struct {
+		ID          uint
+		Channels    []struct{ Name string } `json:"channels"`
+		Suggestions []struct {
+			ContactID uint `json:"contact_id"`
+		} `json:"suggestions"`
+	}
+	json.Unmarshal(resp.Data, &report)
+	if len(report) != 1 || len(report[0].Channels) != 1 || len(report[0].Suggestions) == 0 || report[0].Suggestions[0].ContactID != real.ID {
+		t.Fatalf("Unexpected report: %s", rr.Body.String())
+	}
+
+	// 2. Unknown placeholder is a 404
+	reqBody, _ := json.Marshal(map[string]uint{"placeholder_id": 9999, "contact_id": real.ID})
+	req, _ = http.NewRequest("POST", "/api/contacts/merge", bytes.NewBuffer(reqBody))
+	rr = httptest.NewRecorder()
+	serveAPI(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown placeholder, got %d", rr.Code)
+	}
+
+	// 3. Merge repoints the channel and deletes the placeholder
+	reqBody, _ = json.Marshal(map[string]uint{"placeholder_id": placeholder.ID, "contact_id": real.ID})
+	req, _ = http.NewRequest("POST", "/api/contacts/merge", bytes.NewBuffer(reqBody))
+	rr = httptest.NewRecorder()
+	serveAPI(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("merge failed: %d %s", rr.Code, rr.Body.String())
+	}
+
+	var ch models.Channel
+	database.DB.Where("name = ?", "MI").First(&ch)
+	if ch.ContactID == nil || *ch.ContactID != real.ID {
+		t.Errorf("Expected channel on contact %d, got %v", real.ID, ch.ContactID)
+	}
+	var count int64
+	database.DB.Unscoped().Model(&models.Contact{}).Where("dmr_id < 0").Count(&count)
+	if count != 0 {
+		t.Errorf("Expected placeholder deleted, %d remain", count)
+	}
+}
+
+func TestContactsAPI_RadioIDSearch(t *testing.T) {
+	setupTestDB()
+	database.DB.Exec("DELETE FROM digital_contacts")
+	database.DB.Create(&[]models.DigitalContact{
+		{DMRID: 3126001, Callsign: "KF8AAA", Name: "Alice", State: "Michigan"},
+		{DMRID: 3126002, Callsign: "KF8BBB", Name: "Bob", State: "Michigan"},
+		{DMRID: 3139001, Callsign: "KF8CCC", Name: "Carol", State: "Ohio"},
+	})
+
+	// 1. Field-qualified prefix search with a cursor
+	req, _ := http.NewRequest("GET", "/api/contacts?source=RadioID&search=state:MI+call:KF8*&sort=callsign&limit=1", nil)
+	rr := httptest.NewRecorder()
+	serveAPI(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("search failed: %d %s", rr.Code, rr.Body.String())
+	}
+	var resp ResponseWrapper
+	json.Unmarshal(rr.Body.Bytes(), &resp)
+	var page struct {
+		Data []models.DigitalContact `json:"data"`
+		Meta struct {
+			Total      int64  `json:"total"`
+			NextCursor string `json:"next_cursor"`
+		} `json:"meta"`
+	}
+	json.Unmarshal(resp.Data, &page)
+	if page.Meta.Total != 2 || len(page.Data) != 1 || page.Data[0].Callsign != "KF8AAA" || page.Meta.NextCursor == "" {
+		t.Fatalf("Unexpected first page: %s", rr.Body.String())
+	}
+
+	req, _ = http.NewRequest("GET", "/api/contacts?source=RadioID&search=state:MI+call:KF8*&sort=callsign&limit=1&cursor="+page.Meta.NextCursor, nil)
+	rr = httptest.NewRecorder()
+	serveAPI(rr, req)
+	json.Unmarshal(rr.Body.Bytes(), &resp)
+	page.Data = nil
+	json.Unmarshal(resp.Data, &page)
+	if len(page.Data) != 1 || page.Data[0].Callsign != "KF8BBB" {
+		t.Errorf("Unexpected second page: %s", rr.Body.String())
+	}
+
+	// 2. Sort columns are whitelisted
+	req, _ = http.NewRequest("GET", "/api/contacts?source=RadioID&sort=remarks", nil)
+	rr = httptest.NewRecorder()
+	serveAPI(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for unknown sort column, got %d", rr.Code)
+	}
+}
+
+func TestChannelsAPI_QueryAndMeta(t *testing.T) {
+	setupTestDB()
+	database.DB.Exec("DELETE FROM channels")
+	database.DB.Create(&[]models.Channel{
+		{Name: "2m A", SortOrder: 1, RxFrequency: 145.11, Mode: "FM"},
+		{Name: "2m B", SortOrder: 2, RxFrequency: 146.52, Mode: "FM", Skip: true},
+		{Name: "70cm A", SortOrder: 3, RxFrequency: 442.1, Mode: "DMR"},
+	})
+
+	var resp struct {
+		Data []models.Channel `json:"data"`
+		Meta struct {
+			Total      int64  `json:"total"`
+			NextCursor string `json:"next_cursor"`
+		} `json:"meta"`
+	}
+	req, _ := http.NewRequest("GET", "/api/channels?band=2m&limit=1", nil)
+	rr := httptest.NewRecorder()
+	serveAPI(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("query failed: %d %s", rr.Code, rr.Body.String())
+	}
+	json.Unmarshal(rr.Body.Bytes(), &resp)
+	if resp.Meta.Total != 2 || len(resp.Data) != 1 || resp.Data[0].Name != "2m A" || resp.Meta.NextCursor == "" {
+		t.Fatalf("Unexpected first page: %s", rr.Body.String())
+	}
+
+	req, _ = http.NewRequest("GET", "/api/channels?band=2m&limit=1&cursor="+resp.Meta.NextCursor, nil)
+	rr = httptest.NewRecorder()
+	serveAPI(rr, req)
+	resp.Data = nil
+	json.Unmarshal(rr.Body.Bytes(), &resp)
+	if len(resp.Data) != 1 || resp.Data[0].Name != "2m B" || resp.Meta.NextCursor != "" {
+		t.Errorf("Unexpected second page: %s", rr.Body.String())
+	}
+
+	for _, bad := range []string{"sort=notes", "skip=maybe", "min_freq=abc", "band=11m"} {
+		req, _ = http.NewRequest("GET", "/api/channels?"+bad, nil)
+		rr = httptest.NewRecorder()
+		serveAPI(rr, req)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", bad, rr.Code)
+		}
+	}
+}
+
+func TestChannelsAPI_BulkEdit(t *testing.T) {
+	setupTestDB()
+	database.DB.Exec("DELETE FROM channels")
+	database.DB.Create(&[]models.Channel{
+		{Name: "FM 1", RxFrequency: 145.11, Mode: "FM", Power: "High"},
+		{Name: "FM 2", RxFrequency: 146.52, Mode: "FM", Power: "High"},
+		{Name: "DMR 1", RxFrequency: 442.1, Mode: "DMR", Power: "High"},
+	})
+
+	body := `{"filter": {"mode": ["FM"]}, "set": {"power": "Low"}}`
+	req, _ := http.NewRequest("PATCH", "/api/channels/bulk", bytes.NewBufferString(body))
+	rr := httptest.NewRecorder()
+	serveAPI(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("bulk edit failed: %d %s", rr.Code, rr.Body.String())
+	}
+	var resp ResponseWrapper
+	json.Unmarshal(rr.Body.Bytes(), &resp)
+	var result struct {
+		Matched int64 `json:"matched"`
+		Changed int64 `json:"changed"`
+	}
+	json.Unmarshal(resp.Data, &result)
+	if result.Matched != 2 || result.Changed != 2 {
+		t.Errorf("Unexpected result: %s", rr.Body.String())
+	}
+	var dmr models.Channel
+	database.DB.Where("name = ?", "DMR 1").First(&dmr)
+	if dmr.Power != "High" {
+		t.Errorf("Unselected channel changed to %s", dmr.Power)
+	}
+
+	for _, bad := range []string{`{"set": {"power": "Low"}}`, `{"filter": {"mode": ["FM"]}, "set": {"nope": 1}}`} {
+		req, _ = http.NewRequest("PATCH", "/api/channels/bulk", bytes.NewBufferString(bad))
+		rr = httptest.NewRecorder()
+		serveAPI(rr, req)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", bad, rr.Code)
+		}
+	}
+}
+
+func TestChannelsAPI_PutPatchValidation(t *testing.T) {
+	setupTestDB()
+	database.DB.Exec("DELETE FROM channels")
+	ch := models.Channel{Name: "Repeater", RxFrequency: 442.1, Power: "High", Notes: "keep", Protocol: models.ProtocolDMR, ColorCode: 1}
+	database.DB.Create(&ch)
+
+	send := func(method, url, body string) (*httptest.ResponseRecorder, models.Channel) {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		rr := httptest.NewRecorder()
+		serveAPI(rr, req)
+		var resp ResponseWrapper
+		json.Unmarshal(rr.Body.Bytes(), &resp)
+		var out models.Channel
+		json.Unmarshal(resp.Data, &out)
+		return rr, out
+	}
+	id := fmt.Sprint(ch.ID)
+
+	// PATCH merges only the given fields
+	rr, out := send("PATCH", "/api/channels?id="+id, `{"power": "Low"}`)
+	if rr.Code != http.StatusOK || out.Power != "Low" || out.Notes != "keep" || out.ColorCode != 1 {
+		t.Fatalf("PATCH did not merge: %d %s", rr.Code, rr.Body.String())
+	}
+
+	// PUT replaces the whole row
+	rr, out = send("PUT", "/api/channels?id="+id, `{"name": "Renamed", "rx_frequency": 442.1, "protocol": "FM"}`)
+	if rr.Code != http.StatusOK || out.Name != "Renamed" || out.Power != "" || out.Notes != "" || out.ID != ch.ID {
+		t.Fatalf("PUT did not replace: %d %s", rr.Code, rr.Body.String())
+	}
+
+	// Field-level validation errors
+	rr, _ = send("PATCH", "/api/channels?id="+id, `{"protocol": "DMR", "color_code": 99}`)
+	var failed struct {
+		Errors []models.FieldError `json:"errors"`
+	}
+	json.Unmarshal(rr.Body.Bytes(), &failed)
+	if rr.Code != http.StatusBadRequest || len(failed.Errors) != 1 || failed.Errors[0].Field != "color_code" {
+		t.Errorf("Expected color_code error, got %d %s", rr.Code, rr.Body.String())
+	}
+
+	// Unknown IDs are 404, not new rows
+	for _, method := range []string{"PUT", "PATCH", "POST"} {
+		rr, _ = send(method, "/api/channels", `{"ID": 99999, "name": "Ghost"}`)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("%s unknown ID: expected 404, got %d", method, rr.Code)
+		}
+	}
+	rr, _ = send("DELETE", "/api/channels?id=99999", "")
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("DELETE unknown ID: expected 404, got %d", rr.Code)
+	}
+	var count int64
+	database.DB.Model(&models.Channel{}).Count(&count)
+	if count != 1 {
+		t.Errorf("Expected 1 channel, got %d", count)
+	}
+}
+
+func TestContactsAPI_Validation(t *testing.T) {
+	setupTestDB()
+	database.DB.Exec("DELETE FROM contacts")
+
+	post := func(method, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, "/api/contacts", bytes.NewBufferString(body))
+		rr := httptest.NewRecorder()
+		serveAPI(rr, req)
+		return rr
+	}
+	if rr := post("POST", `{"Name": "Local", "DMRID": 9, "Type": "Group"}`); rr.Code != http.StatusOK {
+		t.Fatalf("create failed: %d %s", rr.Code, rr.Body.String())
+	}
+	rr := post("POST", `{"Name": "Bad", "DMRID": 0, "Type": "Unknown"}`)
+	var failed struct {
+		Errors []models.FieldError `json:"errors"`
+	}
+	json.Unmarshal(rr.Body.Bytes(), &failed)
+	if rr.Code != http.StatusBadRequest || len(failed.Errors) != 2 {
+		t.Errorf("Expected 2 field errors, got %d %s", rr.Code, rr.Body.String())
+	}
+	if rr := post("POST", `{"Name": "Dup", "DMRID": 9, "Type": "Group"}`); rr.Code != http.StatusConflict {
+		t.Errorf("Expected 409 for duplicate contact, got %d", rr.Code)
+	}
+}
+
+func TestChannelsAPI_OptimisticConcurrency(t *testing.T) {
+	setupTestDB()
+	database.DB.Exec("DELETE FROM channels")
+	ch := models.Channel{Name: "Simplex", RxFrequency: 146.52, Protocol: models.ProtocolFM}
+	database.DB.Create(&ch)
+	id := fmt.Sprint(ch.ID)
+
+	send := func(method, url, ifMatch, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		rr := httptest.NewRecorder()
+		serveAPI(rr, req)
+		return rr
+	}
+
+	rr := send("GET", "/api/channels?id="+id, "", "")
+	if rr.Code != http.StatusOK || rr.Header().Get("ETag") != `"1"` {
+		t.Fatalf("Expected ETag \"1\", got %d %q", rr.Code, rr.Header().Get("ETag"))
+	}
+
+	// A matching If-Match writes and bumps the version
+	rr = send("PATCH", "/api/channels?id="+id, `"1"`, `{"power": "Low"}`)
+	if rr.Code != http.StatusOK || rr.Header().Get("ETag") != `"2"` {
+		t.Fatalf("PATCH with current ETag failed: %d %s", rr.Code, rr.Body.String())
+	}
+
+	// A stale If-Match gets 412 with the current record
+	rr = send("PATCH", "/api/channels?id="+id, `"1"`, `{"power": "High"}`)
+	var resp ResponseWrapper
+	json.Unmarshal(rr.Body.Bytes(), &resp)
+	var current models.Channel
+	json.Unmarshal(resp.Data, &current)
+	if rr.Code != http.StatusPreconditionFailed || current.Power != "Low" || current.Version != 2 {
+		t.Errorf("Expected 412 with current record, got %d %s", rr.Code, rr.Body.String())
+	}
+
+	// So does a stale version in the body
+	rr = send("POST", "/api/channels", "", fmt.Sprintf(`{"ID": %d, "version": 1, "name": "Lost", "protocol": "FM"}`, ch.ID))
+	if rr.Code != http.StatusPreconditionFailed {
+		t.Errorf("Expected 412 for stale body version, got %d", rr.Code)
+	}
+
+	// And a delete against an old ETag
+	rr = send("DELETE", "/api/channels?id="+id, `"1"`, "")
+	if rr.Code != http.StatusPreconditionFailed {
+		t.Errorf("Expected 412 for stale delete, got %d", rr.Code)
+	}
+	rr = send("DELETE", "/api/channels?id="+id, `"2"`, "")
+	if rr.Code != http.StatusOK {
+		t.Errorf("Delete with current ETag failed: %d %s", rr.Code, rr.Body.String())
+	}
+
+	var stored models.Channel
+	if err := database.DB.Unscoped().First(&stored, ch.ID).Error; err != nil || stored.Name != "Simplex" || stored.Power != "Low" {
+		t.Errorf("Stale writes changed the channel: %+v", stored)
+	}
+}
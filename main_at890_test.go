@@ -3,7 +3,6 @@ package main
 import (
 	"archive/zip"
 	"bytes"
-	"codeplugs/api"
 	"codeplugs/database"
 	"codeplugs/models"
 
@@ -35,6 +34,9 @@ func TestHandleExport_AnyTone890(t *testing.T) {
 		&models.ScanList{},
 		&models.RoamingChannel{},
 		&models.RoamingZone{},
+		&models.NXDNTalkgroup{},
+		&models.NXDNContact{},
+		&models.User{},
 	)
 
 	// Seed Data
@@ -49,7 +51,7 @@ func TestHandleExport_AnyTone890(t *testing.T) {
 	rr := httptest.NewRecorder()
 
 	// Call handler
-	handler := http.HandlerFunc(api.HandleExport)
+	handler := http.HandlerFunc(serveAPI)
 	handler.ServeHTTP(rr, req)
 
 	// Check status code
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bytes"
+	"codeplugs/api"
+	"codeplugs/database"
+	"codeplugs/models"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestAuth_RolesSessionsAndTokens(t *testing.T) {
+	setupTestDB()
+	clearAccounts := func() {
+		database.DB.Exec("DELETE FROM sessions")
+		database.DB.Exec("DELETE FROM api_tokens")
+		database.DB.Exec("DELETE FROM users")
+	}
+	clearAccounts()
+	t.Cleanup(clearAccounts)
+
+	srv := newTestServer(t, api.NewServerForDB(database.DB))
+
+	// do sends a request as client, with an optional bearer token
+	do := func(client *http.Client, method, path, token, body string) (*http.Response, ResponseWrapper) {
+		t.Helper()
+		req, _ := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var wrapper ResponseWrapper
+		json.NewDecoder(resp.Body).Decode(&wrapper)
+		return resp, wrapper
+	}
+	anon := http.DefaultClient
+	login := func(username, password string) *http.Client {
+		t.Helper()
+		jar, _ := cookiejar.New(nil)
+		client := &http.Client{Jar: jar}
+		if resp, w := do(client, "POST", "/api/auth/login", "", `{"username": "`+username+`", "password": "`+password+`"}`); resp.StatusCode != http.StatusOK {
+			t.Fatalf("login as %s: %d %s", username, resp.StatusCode, w.Error)
+		}
+		return client
+	}
+	newToken := func(client *http.Client) string {
+		t.Helper()
+		_, w := do(client, "POST", "/api/auth/tokens", "", `{"name": "script"}`)
+		var created struct {
+			Token string `json:"token"`
+		}
+		json.Unmarshal(w.Data, &created)
+		if !strings.HasPrefix(created.Token, "cpt_") {
+			t.Fatalf("Expected an API token, got %s", w.Data)
+		}
+		return created.Token
+	}
+
+	// Without accounts the API stays open
+	if resp, _ := do(anon, "GET", "/api/channels", "", ""); resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected open API without accounts, got %d", resp.StatusCode)
+	}
+	if resp, _ := do(anon, "POST", "/api/users", "", `{"username": "ed", "password": "editor-pass", "role": "editor"}`); resp.StatusCode != http.StatusConflict {
+		t.Errorf("Expected 409 for a first account that isn't admin, got %d", resp.StatusCode)
+	}
+	if resp, w := do(anon, "POST", "/api/users", "", `{"username": "root", "password": "admin-pass", "role": "admin"}`); resp.StatusCode != http.StatusOK {
+		t.Fatalf("Creating the first admin failed: %d %s", resp.StatusCode, w.Error)
+	}
+
+	// The first account turns auth on
+	if resp, _ := do(anon, "GET", "/api/channels", "", ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without credentials, got %d", resp.StatusCode)
+	}
+	if resp, _ := do(anon, "GET", "/api/auth/session", "", ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected the session endpoint to stay public, got %d", resp.StatusCode)
+	}
+	if resp, _ := do(anon, "POST", "/api/auth/login", "", `{"username": "root", "password": "wrong-pass"}`); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a wrong password, got %d", resp.StatusCode)
+	}
+
+	admin := login("root", "admin-pass")
+	if resp, _ := do(admin, "GET", "/api/channels", "", ""); resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected admin session to read channels, got %d", resp.StatusCode)
+	}
+	do(admin, "POST", "/api/users", "", `{"username": "ed", "password": "editor-pass", "role": "editor"}`)
+	do(admin, "POST", "/api/users", "", `{"username": "viewer", "password": "viewer-pass", "role": "read-only"}`)
+
+	// Read-only accounts can read but not write
+	readOnly := newToken(login("viewer", "viewer-pass"))
+	if resp, _ := do(anon, "GET", "/api/channels", readOnly, ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected read-only token to read channels, got %d", resp.StatusCode)
+	}
+	if resp, _ := do(anon, "POST", "/api/channels", readOnly, `{"name": "Nope", "rx_frequency": 146.52, "protocol": "FM"}`); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected 403 for a read-only write, got %d", resp.StatusCode)
+	}
+	if resp, _ := do(anon, "GET", "/api/users", readOnly, ""); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected 403 listing users as read-only, got %d", resp.StatusCode)
+	}
+	if resp, _ := do(anon, "GET", "/api/export?format=db", readOnly, ""); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected 403 downloading the database as read-only, got %d", resp.StatusCode)
+	}
+	if resp, _ := do(anon, "GET", "/api/export?format=chirp", readOnly, ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected read-only to export channels, got %d", resp.StatusCode)
+	}
+
+	// An admin's download leaves out the accounts and their hashes
+	resp, err := admin.Get(srv.URL + "/api/export?format=db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot := filepath.Join(t.TempDir(), "snapshot.db")
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || os.WriteFile(snapshot, body, 0o644) != nil {
+		t.Fatalf("Downloading the database failed: %d", resp.StatusCode)
+	}
+	copied, err := gorm.Open(sqlite.Open(snapshot), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, table := range []string{"users", "sessions", "api_tokens"} {
+		var n int64
+		if err := copied.Table(table).Count(&n).Error; err != nil || n != 0 {
+			t.Errorf("Expected no %s in the download, got %d %v", table, n, err)
+		}
+	}
+	if sqlDB, err := copied.DB(); err == nil {
+		sqlDB.Close()
+	}
+
+	// Editors can write, but restore and overwrite need admin
+	editor := newToken(login("ed", "editor-pass"))
+	if resp, w := do(anon, "POST", "/api/channels", editor, `{"name": "Simplex", "rx_frequency": 146.52, "protocol": "FM"}`); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected editor to create a channel, got %d %s", resp.StatusCode, w.Error)
+	}
+	importAs := func(client *http.Client, token string, fields map[string]string) int {
+		body := &bytes.Buffer{}
+		mw := multipart.NewWriter(body)
+		for k, v := range fields {
+			mw.WriteField(k, v)
+		}
+		mw.Close()
+		req, _ := http.NewRequest("POST", srv.URL+"/api/import", body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	if code := importAs(anon, editor, map[string]string{"format": "db"}); code != http.StatusForbidden {
+		t.Errorf("Expected 403 for an editor restore, got %d", code)
+	}
+	if code := importAs(anon, editor, map[string]string{"overwrite": "true"}); code != http.StatusForbidden {
+		t.Errorf("Expected 403 for an editor overwrite, got %d", code)
+	}
+	// Admins get past the role check to the missing file
+	if code := importAs(admin, "", map[string]string{"overwrite": "true"}); code != http.StatusBadRequest {
+		t.Errorf("Expected admin overwrite to reach the handler, got %d", code)
+	}
+
+	// Cookie-authenticated writes from another origin are refused
+	req, _ := http.NewRequest("POST", srv.URL+"/api/zones", strings.NewReader(`{"name": "CSRF"}`))
+	req.Header.Set("Origin", "https://evil.example")
+	resp, err = admin.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected 403 for a cross-origin cookie write, got %d", resp.StatusCode)
+	}
+
+	// The WebSocket needs credentials and a same-host origin
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/ws"
+	header := http.Header{"Authorization": {"Bearer " + readOnly}, "Origin": {"https://evil.example"}}
+	if _, resp, err := websocket.DefaultDialer.Dial(wsURL, header); err == nil || resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected cross-origin WebSocket to be refused, got %v", err)
+	}
+	header.Set("Origin", srv.URL)
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, header)
+	if err != nil {
+		t.Fatalf("Expected same-origin WebSocket to connect: %v", err)
+	}
+	conn.Close()
+
+	// The last admin can't be demoted, and logout ends the session
+	var root models.User
+	database.DB.Where("username = ?", "root").First(&root)
+	if resp, _ := do(admin, "PATCH", fmt.Sprintf("/api/users?id=%d", root.ID), "", `{"role": "editor"}`); resp.StatusCode != http.StatusConflict {
+		t.Errorf("Expected 409 demoting the last admin, got %d", resp.StatusCode)
+	}
+	do(admin, "POST", "/api/auth/logout", "", "")
+	if resp, _ := do(admin, "GET", "/api/channels", "", ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 after logout, got %d", resp.StatusCode)
+	}
+}
+
+func TestAuth_FailsClosed(t *testing.T) {
+	// Without a users table the account check fails, which mustn't open the API
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "noaccounts.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.AutoMigrate(&models.Channel{})
+	srv := api.NewServerForDB(db)
+	defer srv.Close()
+
+	for _, path := range []string{"/api/channels", "/api/auth/session"} {
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, httptest.NewRequest("GET", path, nil))
+		if rr.Code != http.StatusInternalServerError {
+			t.Errorf("%s: expected 500 when accounts can't be checked, got %d", path, rr.Code)
+		}
+	}
+}
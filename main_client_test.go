@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"codeplugs/api"
+	"codeplugs/client"
+	"codeplugs/database"
+	"codeplugs/internal/openapigen"
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"testing"
+)
+
+func TestClient_GeneratedUpToDate(t *testing.T) {
+	want, err := openapigen.Generate(api.OpenAPISpec(), "client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile("client/client_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("client/client_gen.go is stale; run go generate ./client")
+	}
+}
+
+func TestClient_Channels(t *testing.T) {
+	setupTestDB()
+	database.DB.Exec("DELETE FROM channels")
+	srv := newTestServer(t, api.NewServerForDB(database.DB))
+
+	ctx := context.Background()
+	c := client.New(srv.URL)
+	created, err := c.CreateChannel(ctx, nil, client.Channel{Name: "Simplex", RxFrequency: 146.52, Protocol: "FM"})
+	if err != nil || created.ID == 0 || created.Version != 1 {
+		t.Fatalf("CreateChannel: %+v %v", created, err)
+	}
+
+	channels, meta, err := c.ListChannels(ctx, &client.ListChannelsParams{Protocol: []string{"FM"}, Limit: 10})
+	if err != nil || len(channels) != 1 || meta.Total != 1 {
+		t.Fatalf("ListChannels: %+v %+v %v", channels, meta, err)
+	}
+
+	updated, err := c.UpdateChannel(ctx, &client.UpdateChannelParams{ID: created.ID}, client.Channel{Power: "Low"})
+	if err != nil || updated.Power != "Low" || updated.Name != "Simplex" {
+		t.Fatalf("UpdateChannel: %+v %v", updated, err)
+	}
+
+	// A stale If-Match surfaces as an *Error carrying the current record
+	err = c.DeleteChannel(ctx, &client.DeleteChannelParams{ID: created.ID, IfMatch: `"1"`})
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusPreconditionFailed || len(apiErr.Current) == 0 {
+		t.Fatalf("Expected 412 from stale delete, got %v", err)
+	}
+
+	// Validation failures carry their field errors
+	_, err = c.CreateChannel(ctx, nil, client.Channel{Name: "Bad DMR", RxFrequency: 439.5, Protocol: "DMR", ColorCode: 16})
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || len(apiErr.Errors) == 0 {
+		t.Fatalf("Expected field errors, got %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"codeplugs/api"
+	"codeplugs/cmd"
+	"codeplugs/config"
+	"codeplugs/database"
+	"codeplugs/models"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportProfiles(t *testing.T) {
+	setupTestDB()
+	database.DB.Exec("DELETE FROM zone_channels")
+	database.DB.Exec("DELETE FROM zones")
+	database.DB.Exec("DELETE FROM channels")
+
+	in, out := models.Channel{Name: "InZone", RxFrequency: 146.52}, models.Channel{Name: "OutOfZone", RxFrequency: 146.55}
+	database.DB.Create(&in)
+	database.DB.Create(&out)
+	zone := models.Zone{Name: "Detroit"}
+	database.DB.Create(&zone)
+	if err := database.DB.Model(&zone).Association("Channels").Append(&in); err != nil {
+		t.Fatal(err)
+	}
+
+	profiles := []config.Profile{
+		{Name: "detroit-chirp", Description: "Handheld", Format: "chirp", Zones: []string{"Detroit"}, Output: "/srv/secret.csv"},
+		{Name: "missing-zone", Zones: []string{"Nowhere"}},
+	}
+	for i := range profiles {
+		if err := profiles[i].Validate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	srv := api.NewServer(database.NewWorkspaces(database.NewHandle(database.DB, ""), ""), api.Config{Profiles: profiles})
+	defer srv.Close()
+	get := func(url string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, httptest.NewRequest("GET", url, nil))
+		return rr
+	}
+
+	// The modal lists the profiles, without paths on the server
+	rr := get("/api/export/profiles")
+	var listing struct{ Data []map[string]any }
+	json.Unmarshal(rr.Body.Bytes(), &listing)
+	if rr.Code != http.StatusOK || len(listing.Data) != 2 || listing.Data[0]["name"] != "detroit-chirp" {
+		t.Fatalf("Unexpected profiles: %d %s", rr.Code, rr.Body.String())
+	}
+	if strings.Contains(rr.Body.String(), "secret") {
+		t.Errorf("Expected output paths to be left out, got %s", rr.Body.String())
+	}
+
+	// A profile export matches the same options given by hand
+	byProfile := get("/api/export?profile=detroit-chirp")
+	byHand := get(fmt.Sprintf("/api/export?format=chirp&zone_id=%d", zone.ID))
+	if byProfile.Code != http.StatusOK || byProfile.Body.String() != byHand.Body.String() {
+		t.Fatalf("Expected the profile to match format=chirp&zone_id, got %d:\n%s\nvs\n%s", byProfile.Code, byProfile.Body.String(), byHand.Body.String())
+	}
+	if !strings.Contains(byProfile.Body.String(), "InZone") || strings.Contains(byProfile.Body.String(), "OutOfZone") {
+		t.Errorf("Expected only the zone's channels, got %s", byProfile.Body.String())
+	}
+	// Explicit parameters override the profile
+	if all := get("/api/export?profile=detroit-chirp&zone_id=0"); strings.Contains(all.Body.String(), "InZone") {
+		t.Errorf("Expected zone_id to override the profile's zones, got %s", all.Body.String())
+	}
+
+	if rr := get("/api/export?profile=nope"); rr.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown profile, got %d", rr.Code)
+	}
+	if rr := get("/api/export?profile=missing-zone"); rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a profile naming an unknown zone, got %d", rr.Code)
+	}
+
+	// `codeplugs export -profile` writes what the server downloads
+	p := profiles[0]
+	p.Output = filepath.Join(t.TempDir(), "detroit.csv")
+	if err := cmd.RunExport(database.DB, p); err != nil {
+		t.Fatalf("RunExport failed: %v", err)
+	}
+	written, err := os.ReadFile(p.Output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(written) != byProfile.Body.String() {
+		t.Errorf("Expected the CLI and server exports to match, got:\n%s\nvs\n%s", written, byProfile.Body.String())
+	}
+	if err := cmd.RunExport(database.DB, profiles[1]); err == nil {
+		t.Error("Expected RunExport to fail without an output path")
+	}
+}
+
+func TestExportProfiles_FilterFile(t *testing.T) {
+	dir := t.TempDir()
+	database.Connect(filepath.Join(dir, "filter-file.db"))
+	defer database.Close()
+	database.DB.Create(&models.DigitalContact{Callsign: "KF8AAA", DMRID: 3100001})
+	database.DB.Create(&models.DigitalContact{Callsign: "KF8BBB", DMRID: 3100002})
+	filterFile := filepath.Join(dir, "allowed.csv")
+	if err := os.WriteFile(filterFile, []byte("Radio ID\n3100001\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	profile := config.Profile{Name: "dm32-file", Radio: "dm32uv", FilterFile: filterFile}
+	if err := profile.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	srv := api.NewServer(database.NewWorkspaces(database.NewHandle(database.DB, ""), ""), api.Config{Profiles: []config.Profile{profile}})
+	defer srv.Close()
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, httptest.NewRequest("GET", "/api/export?profile=dm32-file", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Export failed: %d %s", rr.Code, rr.Body.String())
+	}
+
+	// The server reads the profile's filter file as the CLI does
+	zr, err := zip.NewReader(bytes.NewReader(rr.Body.Bytes()), int64(rr.Body.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := zr.Open("digital_contacts.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	contacts, _ := io.ReadAll(f)
+	if !strings.Contains(string(contacts), "KF8AAA") || strings.Contains(string(contacts), "KF8BBB") {
+		t.Errorf("Expected only the filter file's contact, got %s", contacts)
+	}
+}
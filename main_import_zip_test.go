@@ -3,7 +3,6 @@ package main
 import (
 	"archive/zip"
 	"bytes"
-	"codeplugs/api"
 	"codeplugs/database"
 	"codeplugs/models"
 	"io"
@@ -58,7 +57,7 @@ func TestHandleImport_Zip_RoamingScanList(t *testing.T) {
 	w := httptest.NewRecorder()
 
 	// CALL API HANDLER
-	api.HandleImport(w, req)
+	serveAPI(w, req)
 
 	// ASSERTIONS
 	if w.Code != http.StatusOK {
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"codeplugs/api"
+	"codeplugs/database"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestOpenAPI_RoutesInSync checks that every documented method is served,
+// that undocumented methods get 405, and that no route is registered
+// outside the documented table.
+func TestOpenAPI_RoutesInSync(t *testing.T) {
+	setupTestDB()
+	mux := api.NewServerForDB(database.DB)
+	defer mux.Close()
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("GET", "/api/openapi.json", nil))
+	var spec struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &spec); err != nil || !strings.HasPrefix(spec.OpenAPI, "3.") {
+		t.Fatalf("Invalid OpenAPI document: %v %.200s", err, rr.Body.String())
+	}
+
+	for path, item := range spec.Paths {
+		if _, pattern := mux.Handler(httptest.NewRequest("GET", path, nil)); pattern != path {
+			t.Errorf("%s is documented but not routed (matched %q)", path, pattern)
+			continue
+		}
+		// Project-scoped routes are served again under each project; the rest aren't
+		scoped := false
+		for _, raw := range item {
+			var op struct {
+				Scoped bool `json:"x-project-scoped"`
+			}
+			json.Unmarshal(raw, &op)
+			scoped = scoped || op.Scoped
+		}
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, httptest.NewRequest("OPTIONS", "/api/projects/default"+strings.TrimPrefix(path, "/api"), nil))
+		if scoped && rr.Code != http.StatusMethodNotAllowed {
+			t.Errorf("%s is project-scoped but not served per project (%d)", path, rr.Code)
+		}
+		if !scoped && rr.Code != http.StatusNotFound {
+			t.Errorf("%s is global but served per project (%d)", path, rr.Code)
+		}
+		for _, method := range []string{"GET", "POST", "PUT", "PATCH", "DELETE"} {
+			_, documented := item[strings.ToLower(method)]
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(method, path, strings.NewReader("")))
+			if documented && rr.Code == http.StatusMethodNotAllowed {
+				t.Errorf("%s %s is documented but not allowed", method, path)
+			}
+			if !documented && rr.Code != http.StatusMethodNotAllowed {
+				t.Errorf("%s %s is served (%d) but not documented", method, path, rr.Code)
+			}
+		}
+	}
+
+	// Routes registered by hand must be documented too
+	files, _ := filepath.Glob("api/*.go")
+	handleFunc := regexp.MustCompile(`Handle(?:Func)?\("(/api/[^"]*)"`)
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range handleFunc.FindAllStringSubmatch(string(src), -1) {
+			if _, ok := spec.Paths[m[1]]; !ok {
+				t.Errorf("%s registers %s, which is missing from the spec", file, m[1])
+			}
+		}
+	}
+}
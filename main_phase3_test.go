@@ -2,7 +2,6 @@ package main
 
 import (
 	"bytes"
-	"codeplugs/api"
 	"codeplugs/database"
 	"codeplugs/exporter"
 	"codeplugs/importer"
@@ -29,7 +28,7 @@ func setupPhase3DB(t *testing.T) {
 	}
 
 	database.DB.SetupJoinTable(&models.Zone{}, "Channels", &models.ZoneChannel{})
-	err = database.DB.AutoMigrate(&models.Channel{}, &models.Zone{}, &models.Contact{}, &models.ZoneChannel{}, &models.DigitalContact{}, &models.ScanList{}, &models.RoamingChannel{}, &models.RoamingZone{})
+	err = database.DB.AutoMigrate(&models.Channel{}, &models.Zone{}, &models.Contact{}, &models.ZoneChannel{}, &models.DigitalContact{}, &models.ScanList{}, &models.RoamingChannel{}, &models.RoamingZone{}, &models.NXDNTalkgroup{}, &models.NXDNContact{}, &models.User{})
 	if err != nil {
 		t.Fatalf("failed to migrate: %v", err)
 	}
@@ -44,7 +43,7 @@ func TestPhase3_APIJSONError(t *testing.T) {
 	// Send invalid JSON to Channel Reorder to trigger error
 	req, _ := http.NewRequest("POST", "/api/channels/reorder", bytes.NewBufferString("{invalid_json"))
 	rr := httptest.NewRecorder()
-	http.HandlerFunc(api.HandleChannelReorder).ServeHTTP(rr, req)
+	serveAPI(rr, req)
 
 	if rr.Code != http.StatusBadRequest {
 		t.Errorf("Expected 400 Bad Request, got %d", rr.Code)
@@ -76,7 +75,7 @@ func TestPhase3_APIStandardization(t *testing.T) {
 
 	req, _ := http.NewRequest("GET", "/api/channels", nil)
 	rr := httptest.NewRecorder()
-	http.HandlerFunc(api.HandleChannels).ServeHTTP(rr, req)
+	serveAPI(rr, req)
 
 	if rr.Code != http.StatusOK {
 		t.Errorf("Expected 200 OK, got %d", rr.Code)
@@ -151,7 +150,7 @@ func TestPhase3_Integration_RoundTrip(t *testing.T) {
 
 	// 2. Export AnyTone 890
 	tempDir890 := t.TempDir()
-	if err := exporter.ExportAnyTone890(database.DB, tempDir890, 0); err != nil {
+	if _, err := exporter.ExportAnyTone890(database.DB, tempDir890, 0); err != nil {
 		t.Fatalf("ExportAnyTone890 failed: %v", err)
 	}
 
@@ -242,7 +241,7 @@ func TestPhase3_DM32UV_RoundTrip(t *testing.T) {
 
 	// 2. Export DM32UV
 	tempDirDM32UV := t.TempDir()
-	if err := exporter.ExportDM32UV(database.DB, tempDirDM32UV); err != nil {
+	if _, err := exporter.ExportDM32UV(database.DB, tempDirDM32UV); err != nil {
 		t.Fatalf("ExportDM32UV failed: %v", err)
 	}
 
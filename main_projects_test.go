@@ -0,0 +1,229 @@
+package main
+
+import (
+	"codeplugs/api"
+	"codeplugs/client"
+	"codeplugs/database"
+	"codeplugs/models"
+	"codeplugs/services"
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestProjects_IsolatedDatabases(t *testing.T) {
+	setupTestDB()
+	database.DB.Exec("DELETE FROM channels")
+	database.DB.Exec("DELETE FROM projects")
+	t.Cleanup(func() { database.DB.Exec("DELETE FROM projects") })
+	workspaces := database.NewWorkspaces(database.NewHandle(database.DB, ""), t.TempDir())
+	srv := newTestServer(t, api.NewServer(workspaces, api.Config{}))
+
+	do := func(method, path, body string) (int, ResponseWrapper) {
+		t.Helper()
+		req, _ := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var wrapper ResponseWrapper
+		json.NewDecoder(resp.Body).Decode(&wrapper)
+		return resp.StatusCode, wrapper
+	}
+	channelNames := func(path string) []string {
+		t.Helper()
+		code, w := do("GET", path, "")
+		if code != http.StatusOK {
+			t.Fatalf("GET %s: %d %s", path, code, w.Error)
+		}
+		var channels []struct {
+			Name string `json:"name"`
+		}
+		json.Unmarshal(w.Data, &channels)
+		var names []string
+		for _, ch := range channels {
+			names = append(names, ch.Name)
+		}
+		return names
+	}
+
+	if code, w := do("POST", "/api/projects", `{"id": "club", "name": "Club"}`); code != http.StatusOK {
+		t.Fatalf("Creating a project failed: %d %s", code, w.Error)
+	}
+	if code, _ := do("POST", "/api/projects", `{"id": "club"}`); code != http.StatusConflict {
+		t.Errorf("Expected 409 for a duplicate project, got %d", code)
+	}
+	for _, id := range []string{"default", "Bad ID", "../escape"} {
+		if code, _ := do("POST", "/api/projects", `{"id": "`+id+`"}`); code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for project id %q, got %d", id, code)
+		}
+	}
+
+	// Changes in a project stay in its database and on its event feed
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/projects/club/ws?events=channel.*"
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	do("POST", "/api/channels", `{"name": "Main", "rx_frequency": 146.52, "protocol": "FM"}`)
+	if code, w := do("POST", "/api/projects/club/channels", `{"name": "Club", "rx_frequency": 147.0, "protocol": "FM"}`); code != http.StatusOK {
+		t.Fatalf("Creating a project channel failed: %d %s", code, w.Error)
+	}
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		var e struct {
+			Type    string `json:"type"`
+			Project string `json:"project"`
+		}
+		if err := conn.ReadJSON(&e); err != nil {
+			t.Fatalf("Waiting for the project's channel event: %v", err)
+		}
+		if e.Type == "channel.created" {
+			if e.Project != "club" {
+				t.Errorf("Expected only club events, got one for %q", e.Project)
+			}
+			break
+		}
+	}
+	if names := channelNames("/api/channels"); len(names) != 1 || names[0] != "Main" {
+		t.Errorf("Expected only Main in the default project, got %v", names)
+	}
+	if names := channelNames("/api/projects/club/channels"); len(names) != 1 || names[0] != "Club" {
+		t.Errorf("Expected only Club in the club project, got %v", names)
+	}
+	if names := channelNames("/api/projects/default/channels"); len(names) != 1 || names[0] != "Main" {
+		t.Errorf("Expected the default project under its own prefix, got %v", names)
+	}
+
+	// A clone starts with a copy of its source
+	if code, w := do("POST", "/api/projects", `{"id": "club-2027", "name": "Club 2027", "clone_from": "club"}`); code != http.StatusOK {
+		t.Fatalf("Cloning a project failed: %d %s", code, w.Error)
+	}
+	c := client.New(srv.URL)
+	c.Project = "club-2027"
+	channels, _, err := c.ListChannels(context.Background(), nil)
+	if err != nil || len(channels) != 1 || channels[0].Name != "Club" {
+		t.Errorf("Expected the clone to have the Club channel, got %+v %v", channels, err)
+	}
+
+	// Archived projects are listed on request and can't be used
+	if code, w := do("PATCH", "/api/projects?id=club", `{"archived": true}`); code != http.StatusOK {
+		t.Fatalf("Archiving failed: %d %s", code, w.Error)
+	}
+	if code, _ := do("GET", "/api/projects/club/channels", ""); code != http.StatusGone {
+		t.Errorf("Expected 410 for an archived project, got %d", code)
+	}
+	if code, _ := do("GET", "/api/projects/nope/channels", ""); code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown project, got %d", code)
+	}
+	countProjects := func(path string) int {
+		_, w := do("GET", path, "")
+		var projects []map[string]interface{}
+		json.Unmarshal(w.Data, &projects)
+		return len(projects)
+	}
+	if n := countProjects("/api/projects"); n != 2 {
+		t.Errorf("Expected default and club-2027, got %d projects", n)
+	}
+	if n := countProjects("/api/projects?archived=true"); n != 3 {
+		t.Errorf("Expected 3 projects including archived, got %d", n)
+	}
+	if code, _ := do("PATCH", "/api/projects?id=club", `{"archived": false}`); code != http.StatusOK {
+		t.Errorf("Unarchiving failed: %d", code)
+	}
+	if names := channelNames("/api/projects/club/channels"); len(names) != 1 {
+		t.Errorf("Expected the unarchived project to keep its data, got %v", names)
+	}
+}
+
+func TestProjects_AccountsStayInMain(t *testing.T) {
+	dir := t.TempDir()
+	db, err := database.Open(filepath.Join(dir, "main.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	workspaces := database.NewWorkspaces(database.NewHandle(db, filepath.Join(dir, "main.db")), filepath.Join(dir, "projects"))
+	defer workspaces.Close()
+	if _, err := services.CreateUser(db, "root", "admin-pass", models.RoleAdmin); err != nil {
+		t.Fatal(err)
+	}
+	if err := workspaces.CreateProject(&models.Project{ID: "club", Name: "Club"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	count := func(h *database.Handle, model interface{}) int64 {
+		t.Helper()
+		var n int64
+		if err := h.DB().Model(model).Count(&n).Error; err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	// A clone of the default project leaves the accounts behind
+	if err := workspaces.CreateProject(&models.Project{ID: "copy", Name: "Copy"}, models.DefaultProject); err != nil {
+		t.Fatal(err)
+	}
+	clone, _, err := workspaces.Project("copy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, p := count(clone, &models.User{}), count(clone, &models.Project{}); n != 0 || p != 0 {
+		t.Errorf("Expected the clone without accounts or projects, got %d users and %d projects", n, p)
+	}
+
+	// Restoring the default project keeps the accounts and projects
+	backup := filepath.Join(dir, "backup.db")
+	other, err := database.Open(backup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.Create(&models.Channel{Name: "Restored", RxFrequency: 146.52})
+	if sqlDB, err := other.DB(); err == nil {
+		sqlDB.Close()
+	}
+	main := workspaces.Main()
+	if err := main.Restore(backup); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if n := count(main, &models.Channel{}); n != 1 {
+		t.Errorf("Expected the restored channel, got %d channels", n)
+	}
+	if n, p := count(main, &models.User{}), count(main, &models.Project{}); n != 1 || p != 2 {
+		t.Errorf("Expected the restore to keep 1 user and 2 projects, got %d and %d", n, p)
+	}
+	if enabled, err := services.AuthEnabled(main.DB()); err != nil || !enabled {
+		t.Errorf("Expected auth to stay on after a restore, got %v %v", enabled, err)
+	}
+
+	// A project restored from a main database drops its accounts
+	full := filepath.Join(dir, "full.db")
+	withAccounts, err := database.Open(full)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := services.CreateUser(withAccounts, "root", "admin-pass", models.RoleAdmin); err != nil {
+		t.Fatal(err)
+	}
+	if sqlDB, err := withAccounts.DB(); err == nil {
+		sqlDB.Close()
+	}
+	club, _, err := workspaces.Project("club")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := club.Restore(full); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if n, p := count(club, &models.User{}), count(club, &models.Project{}); n != 0 || p != 0 {
+		t.Errorf("Expected the project without accounts or projects, got %d users and %d projects", n, p)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"codeplugs/api"
+	"codeplugs/database"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServer_IsolatedHubsAndShutdown(t *testing.T) {
+	setupTestDB()
+	database.DB.Exec("DELETE FROM zones")
+
+	// Each server has its own hub, so events don't leak between them
+	first, second := api.NewServerForDB(database.DB), api.NewServerForDB(database.DB)
+	firstSrv, secondSrv := newTestServer(t, first), newTestServer(t, second)
+	firstConn := dialEvents(t, firstSrv, "?events=zone.*")
+	defer firstConn.Close()
+	secondConn := dialEvents(t, secondSrv, "?events=zone.*")
+	defer secondConn.Close()
+	nextEvent(t, firstConn, "subscribed")
+	nextEvent(t, secondConn, "subscribed")
+
+	resp, err := http.Post(secondSrv.URL+"/api/zones", "application/json", strings.NewReader(`{"name": "Second"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	nextEvent(t, secondConn, "zone.created")
+	firstConn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	if _, msg, err := firstConn.ReadMessage(); err == nil {
+		t.Errorf("Expected no events from another server, got %s", msg)
+	}
+
+	// Close disconnects WebSockets, which http.Server.Shutdown leaves open
+	second.Close()
+	secondConn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		if _, _, err := secondConn.ReadMessage(); err != nil {
+			break
+		}
+	}
+
+	// ListenAndServe returns once its context is done
+	s := api.NewServer(database.NewWorkspaces(database.NewHandle(database.DB, ""), ""), api.Config{Addr: "127.0.0.1:0"})
+	defer s.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.ListenAndServe(ctx) }()
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected a clean shutdown, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ListenAndServe didn't return after its context was cancelled")
+	}
+}
@@ -2,10 +2,10 @@ package main
 
 import (
 	"bytes"
-	"codeplugs/api"
 	"codeplugs/database"
 	"codeplugs/models"
 	"encoding/json"
+	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
@@ -14,12 +14,6 @@ import (
 	"testing"
 )
 
-func TestMain(m *testing.M) {
-	// Start hub for tests to avoid deadlock on broadcast
-	go api.Hub.Run()
-	os.Exit(m.Run())
-}
-
 func TestHandleContacts(t *testing.T) {
 	// Setup temporary DB
 	tmpDB, _ := os.CreateTemp("", "test-contacts-*.db")
@@ -32,7 +26,7 @@ func TestHandleContacts(t *testing.T) {
 	// 1. Test GET empty
 	req, _ := http.NewRequest("GET", "/api/contacts", nil)
 	rr := httptest.NewRecorder()
-	handler := http.HandlerFunc(api.HandleContacts)
+	handler := http.HandlerFunc(serveAPI)
 	handler.ServeHTTP(rr, req)
 
 	if status := rr.Code; status != http.StatusOK {
@@ -47,7 +41,7 @@ func TestHandleContacts(t *testing.T) {
 	}
 
 	// "data" field in wrapper maps to map[string]interface{"data": []} because RespondJSON wraps the map returned by handler.
-	// api.HandleContacts returns map[string]interface{}{"data": contacts}
+	// HandleContacts returns map[string]interface{}{"data": contacts}
 
 	// So RespondJSON output: { "success": true, "data": { "data": [] } }
 	// This double nesting of "data" is what complicates things.
@@ -141,7 +135,7 @@ func TestContactReferentialIntegrity(t *testing.T) {
 	// 3. Attempt Delete Contact (Should Fail)
 	req, _ := http.NewRequest("DELETE", "/api/contacts?id="+jsonNumber(contact.ID), nil)
 	rr := httptest.NewRecorder()
-	http.HandlerFunc(api.HandleContacts).ServeHTTP(rr, req)
+	serveAPI(rr, req)
 
 	if rr.Code != http.StatusConflict {
 		t.Errorf("Expected 409 Conflict when deleting used contact, got %d", rr.Code)
@@ -183,7 +177,7 @@ MyChannel,446.000,DMR,Biff
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 
 	rr := httptest.NewRecorder()
-	http.HandlerFunc(api.HandleImport).ServeHTTP(rr, req)
+	serveAPI(rr, req)
 
 	if rr.Code != http.StatusOK {
 		t.Fatalf("Import failed with status: %d", rr.Code)
@@ -230,7 +224,7 @@ func TestImportRadioIDIntegration(t *testing.T) {
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 
 	rr := httptest.NewRecorder()
-	http.HandlerFunc(api.HandleImport).ServeHTTP(rr, req)
+	serveAPI(rr, req)
 
 	if rr.Code != http.StatusOK {
 		t.Fatalf("Import failed with status: %d", rr.Code)
@@ -257,3 +251,59 @@ func TestImportRadioIDIntegration(t *testing.T) {
 		}
 	}
 }
+
+func TestSyncRadioIDIntegration(t *testing.T) {
+	tmpDB, _ := os.CreateTemp("", "test-radioid-sync-*.db")
+	defer os.Remove(tmpDB.Name())
+	database.Connect(tmpDB.Name())
+
+	sync := func(csv string) models.ContactSync {
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		writer.WriteField("format", "radioid")
+		writer.WriteField("sync", "true")
+		part, _ := writer.CreateFormFile("file", "user.csv")
+		part.Write([]byte(csv))
+		writer.Close()
+
+		req, _ := http.NewRequest("POST", "/api/import", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		rr := httptest.NewRecorder()
+		serveAPI(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Sync failed with status: %d (%s)", rr.Code, rr.Body.String())
+		}
+
+		var wrapper struct {
+			Data models.ContactSync `json:"data"`
+		}
+		json.Unmarshal(rr.Body.Bytes(), &wrapper)
+		return wrapper.Data
+	}
+
+	sync("radio_id,callsign,first_name,last_name\n111,N0ONE,No,One\n222,N0TWO,No,Two\n")
+	result := sync("radio_id,callsign,first_name,last_name\n111,N0ONE,No,Uno\n333,N0THR,No,Three\n")
+
+	if result.Added != 1 || result.Changed != 1 || result.Removed != 1 {
+		t.Errorf("Expected 1/1/1, got %d/%d/%d", result.Added, result.Changed, result.Removed)
+	}
+
+	// Change log for a single callsign
+	req, _ := http.NewRequest("GET", fmt.Sprintf("/api/contacts/syncs?id=%d&callsign=n0one", result.ID), nil)
+	rr := httptest.NewRecorder()
+	serveAPI(rr, req)
+
+	var wrapper struct {
+		Data models.ContactSync `json:"data"`
+	}
+	json.Unmarshal(rr.Body.Bytes(), &wrapper)
+	if len(wrapper.Data.Changes) != 1 || wrapper.Data.Changes[0].Details != "name: No One -> No Uno" {
+		t.Errorf("Unexpected change log: %+v", wrapper.Data.Changes)
+	}
+
+	var count int64
+	database.DB.Model(&models.DigitalContact{}).Count(&count)
+	if count != 3 {
+		t.Errorf("Expected 3 contacts (one retired), got %d", count)
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"codeplugs/api"
+	"codeplugs/database"
+	"codeplugs/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+type wsEvent struct {
+	Seq  uint64          `json:"seq"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+func dialEvents(t *testing.T, srv *httptest.Server, query string) *websocket.Conn {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/ws" + query
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial %s: %v", url, err)
+	}
+	return conn
+}
+
+// nextEvent reads events until one of type eventType arrives, failing on a
+// timeout or on any event type in forbidden
+func nextEvent(t *testing.T, conn *websocket.Conn, eventType string, forbidden ...string) wsEvent {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		var e wsEvent
+		if err := conn.ReadJSON(&e); err != nil {
+			t.Fatalf("waiting for %s: %v", eventType, err)
+		}
+		for _, f := range forbidden {
+			if strings.HasPrefix(e.Type, f) {
+				t.Fatalf("got unsubscribed event %s while waiting for %s", e.Type, eventType)
+			}
+		}
+		if e.Type == eventType {
+			return e
+		}
+	}
+}
+
+func TestWebSocket_ChangeFeed(t *testing.T) {
+	setupTestDB()
+	database.DB.Exec("DELETE FROM channels")
+	database.DB.Exec("DELETE FROM zones")
+
+	srv := newTestServer(t, api.NewServerForDB(database.DB))
+
+	post := func(method, path, body string) {
+		t.Helper()
+		req, _ := http.NewRequest(method, srv.URL+path, bytes.NewBufferString(body))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil || resp.StatusCode != http.StatusOK {
+			t.Fatalf("%s %s failed: %v %v", method, path, err, resp)
+		}
+		resp.Body.Close()
+	}
+
+	// Only channel events reach a client subscribed to channel.*
+	conn := dialEvents(t, srv, "?events=channel.*")
+	subscribed := nextEvent(t, conn, "subscribed")
+	post("POST", "/api/zones", `{"name": "Hidden"}`)
+	post("POST", "/api/channels", `{"name": "Simplex", "rx_frequency": 146.52, "protocol": "FM"}`)
+	created := nextEvent(t, conn, "channel.created", "zone.")
+	var change api.ChangeEvent
+	json.Unmarshal(created.Data, &change)
+	if created.Seq <= subscribed.Seq || change.ID == 0 || change.Version != 1 {
+		t.Fatalf("Unexpected channel.created event: %+v", created)
+	}
+	conn.Close()
+
+	// Changes made while disconnected are replayed from the cursor
+	post("PATCH", fmt.Sprintf("/api/channels?id=%d", change.ID), `{"power": "Low"}`)
+	conn = dialEvents(t, srv, fmt.Sprintf("?events=channel.*&since=%d", created.Seq))
+	defer conn.Close()
+	updated := nextEvent(t, conn, "channel.updated")
+	json.Unmarshal(updated.Data, &change)
+	if updated.Seq <= created.Seq || change.Version != 2 {
+		t.Errorf("Expected replayed channel.updated at version 2, got %+v", updated)
+	}
+	nextEvent(t, conn, "subscribed")
+
+	// A subscribe message switches the filter on an open socket
+	conn.WriteJSON(map[string]interface{}{"action": "subscribe", "events": []string{"zone.*"}})
+	nextEvent(t, conn, "subscribed")
+	var zone models.Zone
+	database.DB.Where("name = ?", "Hidden").First(&zone)
+	post("DELETE", fmt.Sprintf("/api/zones?id=%d", zone.ID), "")
+	post("DELETE", fmt.Sprintf("/api/channels?id=%d", change.ID), "")
+	deleted := nextEvent(t, conn, "zone.deleted", "channel.")
+	json.Unmarshal(deleted.Data, &change)
+	if change.ID != zone.ID {
+		t.Errorf("Expected zone.deleted for %d, got %s", zone.ID, deleted.Data)
+	}
+
+	// A cursor the hub can't serve asks the client to reload
+	stale := dialEvents(t, srv, "?since=999999999")
+	defer stale.Close()
+	nextEvent(t, stale, "resync")
+}
@@ -3,7 +3,6 @@ package main
 import (
 	"archive/zip"
 	"bytes"
-	"codeplugs/api"
 	"codeplugs/database"
 	"codeplugs/models"
 	"encoding/csv"
@@ -12,6 +11,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -31,7 +31,7 @@ func TestZipExportHandler(t *testing.T) {
 	// Request DM32UV Zip
 	req, _ := http.NewRequest("GET", "/api/export?radio=dm32uv&format=zip", nil)
 	rr := httptest.NewRecorder()
-	http.HandlerFunc(api.HandleExport).ServeHTTP(rr, req)
+	serveAPI(rr, req)
 
 	if rr.Code != http.StatusOK {
 		t.Fatalf("Export request failed with code %d", rr.Code)
@@ -123,7 +123,7 @@ func TestZipImportHandler(t *testing.T) {
 	req, _ := http.NewRequest("POST", "/api/import?radio=dm32uv&format=zip", body)
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 	rr := httptest.NewRecorder()
-	http.HandlerFunc(api.HandleImport).ServeHTTP(rr, req)
+	serveAPI(rr, req)
 
 	if rr.Code != http.StatusOK {
 		t.Fatalf("Import failed with code %d: %s", rr.Code, rr.Body.String())
@@ -177,7 +177,7 @@ func TestZipRoundTrip(t *testing.T) {
 	// 1. Export to Zip
 	req, _ := http.NewRequest("GET", "/api/export?radio=dm32uv&format=zip", nil)
 	rr := httptest.NewRecorder()
-	http.HandlerFunc(api.HandleExport).ServeHTTP(rr, req)
+	serveAPI(rr, req)
 
 	if rr.Code != http.StatusOK {
 		t.Fatalf("RoundTrip: Export failed %d", rr.Code)
@@ -213,7 +213,7 @@ func TestZipRoundTrip(t *testing.T) {
 	reqIn, _ := http.NewRequest("POST", "/api/import?radio=dm32uv&format=zip", body)
 	reqIn.Header.Set("Content-Type", writer.FormDataContentType())
 	rrIn := httptest.NewRecorder()
-	http.HandlerFunc(api.HandleImport).ServeHTTP(rrIn, reqIn)
+	serveAPI(rrIn, reqIn)
 
 	if rrIn.Code != http.StatusOK {
 		t.Fatalf("RoundTrip: Import failed %d: %s", rrIn.Code, rrIn.Body.String())
@@ -238,3 +238,46 @@ func TestZipRoundTrip(t *testing.T) {
 		t.Errorf("Zone channel linkage failed")
 	}
 }
+
+func TestZipExportHandler_RankingErrorFails(t *testing.T) {
+	database.Connect(filepath.Join(t.TempDir(), "rank.db"))
+	t.Cleanup(func() {
+		if sqlDB, err := database.DB.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	database.DB.Create(&models.DigitalContact{Name: "User1", DMRID: 12345})
+	database.DB.Create(&models.DigitalContact{Name: "User2", DMRID: 12346})
+
+	// Ranking over the limit reads the pins, so it fails without the table
+	if err := database.DB.Migrator().DropTable(&models.ContactPin{}); err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	serveAPI(rr, httptest.NewRequest("GET", "/api/export?radio=dm32uv&limit=1", nil))
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("Expected 500 rather than an untruncated export, got %d", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct == "application/zip" {
+		t.Errorf("Expected an error response, got %s", ct)
+	}
+}
+
+func TestZipExportHandler_OverridesErrorFails(t *testing.T) {
+	database.Connect(filepath.Join(t.TempDir(), "overrides.db"))
+	t.Cleanup(func() {
+		if sqlDB, err := database.DB.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	database.DB.Create(&models.DigitalContact{Name: "User1", DMRID: 12345})
+
+	if err := database.DB.Migrator().DropTable(&models.ContactOverride{}); err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	serveAPI(rr, httptest.NewRequest("GET", "/api/export?radio=dm32uv", nil))
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("Expected 500 rather than contacts without their overrides, got %d", rr.Code)
+	}
+}
@@ -1,16 +1,13 @@
 package models
 
-import (
-	"errors"
-
-	"gorm.io/gorm"
-)
+import "gorm.io/gorm"
 
 type Channel struct {
 	gorm.Model
+	Versioned
 	Name         string  `json:"name"`
-	SortOrder    int     `gorm:"default:0" json:"sort_order"`
-	RxFrequency  float64 `json:"rx_frequency"`
+	SortOrder    int     `gorm:"default:0;index" json:"sort_order"`
+	RxFrequency  float64 `gorm:"index" json:"rx_frequency"`
 	TxFrequency  float64 `json:"tx_frequency"`
 	Mode         string  `json:"mode"`          // FM, DMR, C4FM, D-Star
 	Power        string  `json:"power"`         // High, Mid, Low
@@ -62,19 +59,40 @@ type Channel struct {
 	CtcDcsEncode       string `json:"ctc_dcs_encode"`
 	Scramble           string `json:"scramble"`
 	RxSquelchMode      string `json:"rx_squelch_mode"`
+	RadioID            string `json:"radio_id"` // Named radio ID (DM32UV "DMR ID" column)
 	// AnyTone 890
-	TxPermit       string `json:"tx_permit"`
-	OptionalSignal string `json:"optional_signal"`
-	DtmfID         string `json:"dtmf_id"`
-	Tone2ID        string `json:"tone2_id"`
-	Tone5ID        string `json:"tone5_id"`
-	ScanList       string `json:"scan_list"`
-	TalkAround     bool   `json:"talk_around"`
-	WorkAlone      bool   `json:"work_alone"` // Similar to LoneWork, but keeping separate to match CSVs for now if needed, or map later.
+	TxPermit       TxPermit `json:"tx_permit"` // Also the DM32UV "TX Admit" column
+	OptionalSignal string   `json:"optional_signal"`
+	DtmfID         string   `json:"dtmf_id"`
+	Tone2ID        string   `json:"tone2_id"`
+	Tone5ID        string   `json:"tone5_id"`
+	ScanList       string   `json:"scan_list"`
+	TalkAround     bool     `json:"talk_around"`
+	WorkAlone      bool     `json:"work_alone"` // Similar to LoneWork, but keeping separate to match CSVs for now if needed, or map later.
+
+	// NXDN
+	NxdnRAN     int `json:"nxdn_ran"`      // Radio Access Number (0-63)
+	NxdnGroupID int `json:"nxdn_group_id"` // NXDN talkgroup ID for TX
+
+	// D-Star
+	DStarOwnCall  string `json:"dstar_own_call"`  // MYCALL
+	DStarURCall   string `json:"dstar_ur_call"`   // e.g. "CQCQCQ"
+	DStarRPT1Call string `json:"dstar_rpt1_call"` // Access repeater, e.g. "W8DET  B"
+	DStarRPT2Call string `json:"dstar_rpt2_call"` // Gateway/link repeater, e.g. "W8DET  G"
+	DStarGateway  string `json:"dstar_gateway"`   // Gateway callsign used when RPT2 is not set
+	DStarDVCode   int    `json:"dstar_dv_code"`   // Digital code squelch (0-99)
+
+	// P25
+	P25NAC         string `json:"p25_nac"`          // Network Access Code, hex (e.g. "293")
+	P25TalkgroupID int    `json:"p25_talkgroup_id"` // TX talkgroup
+	P25UnitID      int    `json:"p25_unit_id"`      // Radio's own unit ID
 
 	// DMR Specific FK
 	ContactID *uint    `json:"contact_id"`
 	Contact   *Contact `gorm:"foreignKey:ContactID" json:"contact"`
+
+	// Unmapped radio CSV columns, replayed by the matching exporter
+	VendorExtras VendorExtras `gorm:"type:text" json:"vendor_extras,omitempty"`
 }
 
 type ChannelType string
@@ -98,6 +116,7 @@ const (
 	ProtocolDStar  Protocol = "D-Star"
 	ProtocolNXDN   Protocol = "NXDN"
 	ProtocolAM     Protocol = "AM"
+	ProtocolP25    Protocol = "P25"
 )
 
 func (c *Channel) HasValidType() bool {
@@ -106,23 +125,64 @@ func (c *Channel) HasValidType() bool {
 
 func (c *Channel) HasValidProtocol() bool {
 	switch c.Protocol {
-	case ProtocolFM, ProtocolDMR, ProtocolFusion, ProtocolDStar, ProtocolNXDN, ProtocolAM:
+	case ProtocolFM, ProtocolDMR, ProtocolFusion, ProtocolDStar, ProtocolNXDN, ProtocolAM, ProtocolP25:
 		return true
 	}
 	return false
 }
 
+// Validate checks protocol-specific fields and reports every invalid one
 func (c *Channel) Validate() error {
+	var errs ValidationErrors
+	if permit, err := ParseTxPermit(string(c.TxPermit)); err != nil {
+		errs.add("tx_permit", "invalid TX permit")
+	} else if c.TxPermit != "" {
+		c.TxPermit = permit
+	}
 	if c.Protocol == ProtocolDMR {
 		// Strict check for Color Code (0 is valid in DMR spec, but test requires >0 for "set" check)
 		// Assuming we want to force user to pick a non-zero CC, or treating 0 as "not set".
 		if c.ColorCode <= 0 || c.ColorCode > 15 {
-			return useError("invalid color code")
+			errs.add("color_code", "invalid color code")
 		}
 	}
-	return nil
-}
-
-func useError(s string) error {
-	return errors.New(s)
+	if c.Protocol == ProtocolNXDN {
+		if c.NxdnRAN < 0 || c.NxdnRAN > 63 {
+			errs.add("nxdn_ran", "invalid NXDN RAN")
+		}
+		if c.NxdnGroupID != 0 && (c.NxdnGroupID < NXDNMinID || c.NxdnGroupID > NXDNMaxID) {
+			errs.add("nxdn_group_id", "invalid NXDN talkgroup ID")
+		}
+	}
+	if c.Protocol == ProtocolDStar {
+		calls := []struct {
+			field, call string
+		}{
+			{"dstar_own_call", c.DStarOwnCall},
+			{"dstar_ur_call", c.DStarURCall},
+			{"dstar_rpt1_call", c.DStarRPT1Call},
+			{"dstar_rpt2_call", c.DStarRPT2Call},
+			{"dstar_gateway", c.DStarGateway},
+		}
+		for _, f := range calls {
+			if !ValidDStarCall(f.call) {
+				errs.add(f.field, "invalid D-Star call sign")
+			}
+		}
+		if c.DStarDVCode < 0 || c.DStarDVCode > 99 {
+			errs.add("dstar_dv_code", "invalid D-Star digital code")
+		}
+	}
+	if c.Protocol == ProtocolP25 {
+		if c.P25NAC != "" && !ValidP25NAC(c.P25NAC) {
+			errs.add("p25_nac", "invalid P25 NAC")
+		}
+		if c.P25TalkgroupID < 0 || c.P25TalkgroupID > P25MaxTGID {
+			errs.add("p25_talkgroup_id", "invalid P25 talkgroup ID")
+		}
+		if c.P25UnitID < 0 || c.P25UnitID > P25MaxUnitID {
+			errs.add("p25_unit_id", "invalid P25 unit ID")
+		}
+	}
+	return errs.err()
 }
@@ -60,4 +60,76 @@ func TestChannelValidation(t *testing.T) {
 			t.Error("Expected error for missing ColorCode on DMR channel")
 		}
 	})
+
+	// 4. Test NXDN Requirements
+	t.Run("NXDN Validation", func(t *testing.T) {
+		c := Channel{Type: ChannelTypeDigitalNXDN, Protocol: ProtocolNXDN, NxdnRAN: 1, NxdnGroupID: 100}
+		if err := c.Validate(); err != nil {
+			t.Errorf("Expected valid NXDN channel, got error: %v", err)
+		}
+
+		c.NxdnRAN = 64
+		if err := c.Validate(); err == nil {
+			t.Error("Expected error for RAN out of range")
+		}
+
+		c.NxdnRAN = 1
+		c.NxdnGroupID = 65535
+		if err := c.Validate(); err == nil {
+			t.Error("Expected error for reserved NXDN talkgroup ID")
+		}
+	})
+
+	t.Run("D-Star Validation", func(t *testing.T) {
+		c := Channel{
+			Type:          ChannelTypeDigitalDStar,
+			Protocol:      ProtocolDStar,
+			DStarOwnCall:  "KF8ABC",
+			DStarURCall:   "CQCQCQ",
+			DStarRPT1Call: "W8DET  B",
+			DStarRPT2Call: "W8DET  G",
+		}
+		if err := c.Validate(); err != nil {
+			t.Errorf("Expected valid D-Star channel, got error: %v", err)
+		}
+
+		c.DStarURCall = "REF030CLX"
+		if err := c.Validate(); err == nil {
+			t.Error("Expected error for call sign longer than 8 characters")
+		}
+		c.DStarURCall = "CQCQCQ"
+		c.DStarOwnCall = "KF8ABCDEF"
+		if err := c.Validate(); err == nil {
+			t.Error("Expected error for an own call longer than 8 characters")
+		}
+	})
+
+	t.Run("P25 Validation", func(t *testing.T) {
+		c := Channel{Type: ChannelTypeDigitalP25, Protocol: ProtocolP25, P25NAC: "F7E", P25TalkgroupID: 1001}
+		if err := c.Validate(); err != nil {
+			t.Errorf("Expected valid P25 channel, got error: %v", err)
+		}
+
+		for _, nac := range []string{"1000", "G12", "-1"} {
+			c.P25NAC = nac
+			if err := c.Validate(); err == nil {
+				t.Errorf("Expected error for NAC %q", nac)
+			}
+		}
+	})
+
+	t.Run("TX Permit Validation", func(t *testing.T) {
+		c := Channel{Type: ChannelTypeAnalog, Protocol: ProtocolFM, TxPermit: "Color Code Idle"}
+		if err := c.Validate(); err != nil {
+			t.Errorf("Expected a DM32UV TX permit to be valid, got error: %v", err)
+		}
+		if c.TxPermit != TxPermitColorCode {
+			t.Errorf("Expected TX permit normalized to %q, got %q", TxPermitColorCode, c.TxPermit)
+		}
+
+		c.TxPermit = "Sometimes"
+		if err := c.Validate(); err == nil {
+			t.Error("Expected error for an unknown TX permit")
+		}
+	})
 }
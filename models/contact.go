@@ -1,10 +1,6 @@
 package models
 
-import (
-	"errors"
-
-	"gorm.io/gorm"
-)
+import "gorm.io/gorm"
 
 type ContactType string
 
@@ -16,18 +12,31 @@ const (
 
 type Contact struct {
 	gorm.Model
+	Versioned
 	Name  string
 	DMRID int         `gorm:"index:idx_dmr_id_type,unique"` // The actual Talkgroup ID or Private ID
 	Type  ContactType `gorm:"index:idx_dmr_id_type,unique"` // Group, Private, AllCall
+
+	CallAlert CallAlert // Empty is no alert
+
+	VendorExtras VendorExtras `gorm:"type:text" json:"vendor_extras,omitempty"`
 }
 
-// Validate checks if the contact layout is valid
+// Validate checks the contact type, ID and call alert
 func (c *Contact) Validate() error {
+	var errs ValidationErrors
 	if c.Type != ContactTypeGroup && c.Type != ContactTypePrivate && c.Type != ContactTypeAllCall {
-		return errors.New("invalid contact type")
+		errs.add("Type", "invalid contact type")
 	}
 	if c.DMRID <= 0 {
-		return errors.New("invalid DMR ID")
+		errs.add("DMRID", "invalid DMR ID")
+	}
+	if c.CallAlert != "" {
+		if alert, err := ParseCallAlert(string(c.CallAlert)); err != nil {
+			errs.add("CallAlert", err.Error())
+		} else {
+			c.CallAlert = alert
+		}
 	}
-	return nil
+	return errs.err()
 }
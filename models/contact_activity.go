@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ContactActivity tracks how often and how recently a DMR ID was heard on the
+// network (e.g. from a Brandmeister last-heard export). Used to rank contacts
+// when a radio's contact capacity is exceeded.
+type ContactActivity struct {
+	gorm.Model
+	DMRID      int        `gorm:"uniqueIndex" json:"dmr_id"`
+	HeardCount int        `json:"heard_count"`
+	LastHeard  *time.Time `json:"last_heard"`
+}
+
+// ContactPin marks a DMR ID that must always survive contact truncation
+type ContactPin struct {
+	gorm.Model
+	DMRID int    `gorm:"uniqueIndex" json:"dmr_id"`
+	Note  string `json:"note"`
+}
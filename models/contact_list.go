@@ -5,9 +5,11 @@ import "gorm.io/gorm"
 // ContactList represents a named collection of allowed DMR IDs
 type ContactList struct {
 	gorm.Model
+	Versioned
 	Name        string `gorm:"uniqueIndex"`
 	Description string
 	Entries     []ContactListEntry `gorm:"constraint:OnDelete:CASCADE;"` // Cascade delete entries when list is deleted
+	Rules       []ContactListRule  `gorm:"constraint:OnDelete:CASCADE;"`
 }
 
 // ContactListEntry represents a single DMR ID within a ContactList
@@ -16,3 +18,14 @@ type ContactListEntry struct {
 	ContactListID uint `gorm:"index"`
 	DMRID         int  `gorm:"index"`
 }
+
+// ContactListRule selects contacts dynamically when the list is evaluated at
+// export time. Rules sharing a Group are ANDed; groups are ORed with each
+// other and with the list's explicit entries.
+type ContactListRule struct {
+	gorm.Model
+	ContactListID uint   `gorm:"index"`
+	Group         int    `gorm:"column:rule_group"`
+	Field         string // See the Rule* constants
+	Value         string
+}
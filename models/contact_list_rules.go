@@ -0,0 +1,288 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Contact list rule fields
+const (
+	RuleCountry     = "country"      // Value: comma-separated countries
+	RuleState       = "state"        // Value: comma-separated states
+	RuleCallsign    = "callsign"     // Value: regular expression, e.g. "^KF8"
+	RuleDMRID       = "dmr_id"       // Value: comma-separated IDs or ranges, e.g. "3126000-3126999"
+	RuleHeardWithin = "heard_within" // Value: days
+	RuleList        = "list"         // Value: name of another list
+)
+
+// Validate checks the rule field is known and its value parses
+func (r *ContactListRule) Validate() error {
+	_, err := r.compile()
+	return err
+}
+
+// ParseContactListRules parses CLI rule groups. Each string is one group of
+// ANDed rules separated by ";", each rule written as field=value, e.g.
+// "state=Ohio;heard_within=30".
+func ParseContactListRules(groups []string) ([]ContactListRule, error) {
+	var rules []ContactListRule
+	for g, group := range groups {
+		for _, part := range strings.Split(group, ";") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			field, value, ok := strings.Cut(part, "=")
+			if !ok {
+				return nil, fmt.Errorf("invalid rule %q, expected field=value", part)
+			}
+			rule := ContactListRule{Group: g, Field: strings.ToLower(strings.TrimSpace(field)), Value: strings.TrimSpace(value)}
+			if err := rule.Validate(); err != nil {
+				return nil, err
+			}
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+// SetContactListRules replaces a list's rules, validating them first
+func SetContactListRules(db *gorm.DB, listID uint, rules []ContactListRule) error {
+	for i := range rules {
+		if err := rules[i].Validate(); err != nil {
+			return err
+		}
+		rules[i].ID = 0
+		rules[i].ContactListID = listID
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("contact_list_id = ?", listID).Delete(&ContactListRule{}).Error; err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+		return tx.Create(&rules).Error
+	})
+}
+
+// ruleContact is the subset of a DigitalContact that rules look at
+type ruleContact struct {
+	DMRID    int
+	Callsign string
+	State    string
+	Country  string
+}
+
+type contactMatcher func(c ruleContact) bool
+
+type idRange struct{ lo, hi int }
+
+func (r *ContactListRule) compile() (contactMatcher, error) {
+	switch r.Field {
+	case RuleCountry, RuleState:
+		set := make(map[string]bool)
+		for _, v := range strings.Split(r.Value, ",") {
+			if v = strings.ToLower(strings.TrimSpace(v)); v != "" {
+				set[v] = true
+			}
+		}
+		if len(set) == 0 {
+			return nil, fmt.Errorf("%s rule needs at least one value", r.Field)
+		}
+		if r.Field == RuleCountry {
+			return func(c ruleContact) bool { return set[strings.ToLower(strings.TrimSpace(c.Country))] }, nil
+		}
+		return func(c ruleContact) bool { return set[strings.ToLower(strings.TrimSpace(c.State))] }, nil
+	case RuleCallsign:
+		re, err := regexp.Compile("(?i)" + r.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid callsign pattern: %w", err)
+		}
+		return func(c ruleContact) bool { return re.MatchString(c.Callsign) }, nil
+	case RuleDMRID:
+		var ranges []idRange
+		for _, v := range strings.Split(r.Value, ",") {
+			v = strings.TrimSpace(v)
+			if v == "" {
+				continue
+			}
+			loStr, hiStr, isRange := strings.Cut(v, "-")
+			lo, err := strconv.Atoi(strings.TrimSpace(loStr))
+			if err != nil {
+				return nil, fmt.Errorf("invalid DMR ID %q", v)
+			}
+			hi := lo
+			if isRange {
+				if hi, err = strconv.Atoi(strings.TrimSpace(hiStr)); err != nil || hi < lo {
+					return nil, fmt.Errorf("invalid DMR ID range %q", v)
+				}
+			}
+			ranges = append(ranges, idRange{lo, hi})
+		}
+		if len(ranges) == 0 {
+			return nil, errors.New("dmr_id rule needs at least one ID or range")
+		}
+		return func(c ruleContact) bool {
+			for _, rg := range ranges {
+				if c.DMRID >= rg.lo && c.DMRID <= rg.hi {
+					return true
+				}
+			}
+			return false
+		}, nil
+	case RuleHeardWithin:
+		if days, err := strconv.Atoi(r.Value); err != nil || days <= 0 {
+			return nil, fmt.Errorf("invalid heard_within days %q", r.Value)
+		}
+		return nil, nil // Needs activity data; bound in resolve
+	case RuleList:
+		if strings.TrimSpace(r.Value) == "" {
+			return nil, errors.New("list rule needs a list name")
+		}
+		return nil, nil // Needs the other list; bound in resolve
+	}
+	return nil, fmt.Errorf("unknown rule field %q", r.Field)
+}
+
+// ResolveContactList returns every DMR ID in a list: its explicit entries plus
+// all non-retired contacts matching any of its rule groups.
+func ResolveContactList(db *gorm.DB, listID uint) (map[int]bool, error) {
+	return resolveContactList(db, listID, map[uint]bool{})
+}
+
+func resolveContactList(db *gorm.DB, listID uint, visiting map[uint]bool) (map[int]bool, error) {
+	if visiting[listID] {
+		return nil, errors.New("contact list rules reference each other in a cycle")
+	}
+	visiting[listID] = true
+	defer delete(visiting, listID)
+
+	ids := make(map[int]bool)
+	var explicit []int
+	if err := db.Model(&ContactListEntry{}).Where("contact_list_id = ?", listID).Pluck("dmr_id", &explicit).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range explicit {
+		ids[id] = true
+	}
+
+	var rules []ContactListRule
+	if err := db.Where("contact_list_id = ?", listID).Order("rule_group, id").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return ids, nil
+	}
+
+	groups := make(map[int][]contactMatcher)
+	var groupOrder []int
+	for _, rule := range rules {
+		m, err := bindRule(db, rule, visiting)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := groups[rule.Group]; !ok {
+			groupOrder = append(groupOrder, rule.Group)
+		}
+		groups[rule.Group] = append(groups[rule.Group], m)
+	}
+
+	rows, err := db.Model(&DigitalContact{}).Where("retired_at IS NULL").
+		Select("dmr_id", "callsign", "state", "country").Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c ruleContact
+		if err := db.ScanRows(rows, &c); err != nil {
+			return nil, err
+		}
+		for _, g := range groupOrder {
+			matched := true
+			for _, m := range groups[g] {
+				if !m(c) {
+					matched = false
+					break
+				}
+			}
+			if matched {
+				ids[c.DMRID] = true
+				break
+			}
+		}
+	}
+	return ids, rows.Err()
+}
+
+// bindRule compiles a rule, loading whatever data it needs from the database
+func bindRule(db *gorm.DB, rule ContactListRule, visiting map[uint]bool) (contactMatcher, error) {
+	switch rule.Field {
+	case RuleHeardWithin:
+		days, err := strconv.Atoi(rule.Value)
+		if err != nil || days <= 0 {
+			return nil, fmt.Errorf("invalid heard_within days %q", rule.Value)
+		}
+		var heard []int
+		cutoff := time.Now().AddDate(0, 0, -days)
+		if err := db.Model(&ContactActivity{}).Where("last_heard >= ?", cutoff).Pluck("dmr_id", &heard).Error; err != nil {
+			return nil, err
+		}
+		set := make(map[int]bool, len(heard))
+		for _, id := range heard {
+			set[id] = true
+		}
+		return func(c ruleContact) bool { return set[c.DMRID] }, nil
+	case RuleList:
+		var other ContactList
+		if err := db.Where("name = ?", strings.TrimSpace(rule.Value)).First(&other).Error; err != nil {
+			return nil, fmt.Errorf("list rule references unknown list %q", rule.Value)
+		}
+		set, err := resolveContactList(db, other.ID, visiting)
+		if err != nil {
+			return nil, err
+		}
+		return func(c ruleContact) bool { return set[c.DMRID] }, nil
+	}
+	return rule.compile()
+}
+
+// ContactListFilter returns a subquery selecting the DMR IDs in a list, for
+// use as `dmr_id IN (?)`. Lists with rules are evaluated now and their matches
+// passed in as a JSON array, so exports see the current matches without
+// writing to the database.
+func ContactListFilter(db *gorm.DB, listID uint) (*gorm.DB, error) {
+	var ruleCount int64
+	if err := db.Model(&ContactListRule{}).Where("contact_list_id = ?", listID).Count(&ruleCount).Error; err != nil {
+		return nil, err
+	}
+	if ruleCount == 0 {
+		return db.Model(&ContactListEntry{}).Select("dmr_id").Where("contact_list_id = ?", listID), nil
+	}
+
+	ids, err := ResolveContactList(db, listID)
+	if err != nil {
+		return nil, err
+	}
+	members := make([]int, 0, len(ids))
+	for id := range ids {
+		members = append(members, id)
+	}
+	slices.Sort(members)
+	encoded, err := json.Marshal(members)
+	if err != nil {
+		return nil, err
+	}
+	// One parameter however many members, unlike an IN list
+	return db.Raw("SELECT value AS dmr_id FROM json_each(?)", string(encoded)), nil
+}
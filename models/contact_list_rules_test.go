@@ -0,0 +1,136 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	_ "modernc.org/sqlite"
+)
+
+func setupListRulesTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Dialector{
+		DriverName: "sqlite",
+		DSN:        "file:memdb_list_rules?mode=memory&cache=shared",
+	}, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open DB: %v", err)
+	}
+	db.AutoMigrate(&DigitalContact{}, &ContactList{}, &ContactListEntry{}, &ContactListRule{}, &ContactActivity{})
+	for _, table := range []string{"digital_contacts", "contact_lists", "contact_list_entries", "contact_list_rules", "contact_activities"} {
+		db.Exec("DELETE FROM " + table)
+	}
+	return db
+}
+
+func TestParseContactListRules(t *testing.T) {
+	rules, err := ParseContactListRules([]string{"state=Michigan", "State=Ohio; heard_within=30"})
+	if err != nil {
+		t.Fatalf("ParseContactListRules failed: %v", err)
+	}
+	if len(rules) != 3 {
+		t.Fatalf("Expected 3 rules, got %d", len(rules))
+	}
+	if rules[1].Field != RuleState || rules[1].Group != 1 || rules[2].Group != 1 {
+		t.Errorf("Unexpected rule grouping: %+v", rules)
+	}
+
+	bad := [][]string{
+		{"state"},
+		{"color=blue"},
+		{"dmr_id=3126999-3126000"},
+		{"heard_within=soon"},
+		{"callsign=("},
+	}
+	for _, groups := range bad {
+		if _, err := ParseContactListRules(groups); err == nil {
+			t.Errorf("Expected error for %v", groups)
+		}
+	}
+}
+
+func TestResolveContactList_GroupsAndEntries(t *testing.T) {
+	db := setupListRulesTestDB(t)
+	recent := time.Now().AddDate(0, 0, -3)
+	retired := time.Now()
+
+	db.Create(&[]DigitalContact{
+		{DMRID: 1, Callsign: "KF8AAA", State: "Michigan", Country: "United States"},
+		{DMRID: 2, Callsign: "W8BBB", State: "Ohio", Country: "United States"},
+		{DMRID: 3, Callsign: "K8CCC", State: "Ohio", Country: "United States"},
+		{DMRID: 4, Callsign: "VE3DDD", State: "Ontario", Country: "Canada"},
+		{DMRID: 5, Callsign: "KF8EEE", State: "Michigan", Country: "United States", RetiredAt: &retired},
+	})
+	db.Create(&ContactActivity{DMRID: 2, HeardCount: 5, LastHeard: &recent})
+
+	list := ContactList{Name: "Great Lakes"}
+	db.Create(&list)
+	db.Create(&ContactListEntry{ContactListID: list.ID, DMRID: 9})
+
+	rules, err := ParseContactListRules([]string{"state=Michigan", "state=Ohio;heard_within=30"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SetContactListRules(db, list.ID, rules); err != nil {
+		t.Fatalf("SetContactListRules failed: %v", err)
+	}
+
+	ids, err := ResolveContactList(db, list.ID)
+	if err != nil {
+		t.Fatalf("ResolveContactList failed: %v", err)
+	}
+	for _, want := range []int{1, 2, 9} {
+		if !ids[want] {
+			t.Errorf("Expected %d in list", want)
+		}
+	}
+	for _, unwanted := range []int{3, 4, 5} {
+		if ids[unwanted] {
+			t.Errorf("Did not expect %d in list", unwanted)
+		}
+	}
+
+	sub, err := ContactListFilter(db, list.ID)
+	if err != nil {
+		t.Fatalf("ContactListFilter failed: %v", err)
+	}
+	var count int64
+	db.Model(&DigitalContact{}).Where("dmr_id IN (?)", sub).Count(&count)
+	if count != 2 {
+		t.Errorf("Expected filter to match 2 contacts, got %d", count)
+	}
+}
+
+func TestResolveContactList_ListReferences(t *testing.T) {
+	db := setupListRulesTestDB(t)
+	db.Create(&[]DigitalContact{
+		{DMRID: 3126001, Callsign: "KF8AAA", State: "Michigan"},
+		{DMRID: 3139001, Callsign: "W8BBB", State: "Ohio"},
+	})
+
+	base := ContactList{Name: "tg3126"}
+	db.Create(&base)
+	SetContactListRules(db, base.ID, []ContactListRule{{Field: RuleDMRID, Value: "3126000-3126999"}})
+
+	combined := ContactList{Name: "combined"}
+	db.Create(&combined)
+	SetContactListRules(db, combined.ID, []ContactListRule{
+		{Group: 0, Field: RuleList, Value: "tg3126"},
+		{Group: 1, Field: RuleCallsign, Value: "^W8"},
+	})
+
+	ids, err := ResolveContactList(db, combined.ID)
+	if err != nil {
+		t.Fatalf("ResolveContactList failed: %v", err)
+	}
+	if len(ids) != 2 {
+		t.Errorf("Expected 2 contacts, got %v", ids)
+	}
+
+	// A list that references back to the combined list must be rejected
+	SetContactListRules(db, base.ID, []ContactListRule{{Field: RuleList, Value: "combined"}})
+	if _, err := ResolveContactList(db, combined.ID); err == nil {
+		t.Error("Expected cycle error")
+	}
+}
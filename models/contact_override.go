@@ -0,0 +1,102 @@
+package models
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// CallAlert is how the radio alerts on an incoming call from a contact
+type CallAlert string
+
+const (
+	CallAlertNone   CallAlert = "None"
+	CallAlertRing   CallAlert = "Ring"
+	CallAlertOnline CallAlert = "Online Alert"
+)
+
+// ParseCallAlert reads a call alert as written by either radio: AnyTone's
+// "None"/"Ring"/"Online Alert" or DM32UV's "0"/"1". Empty means none.
+func ParseCallAlert(s string) (CallAlert, error) {
+	switch s {
+	case "", "0", "None", "none", "Off", "off":
+		return CallAlertNone, nil
+	case "1", "Ring", "ring", "On", "on":
+		return CallAlertRing, nil
+	case "Online Alert", "online", "Online":
+		return CallAlertOnline, nil
+	}
+	return "", fmt.Errorf("invalid call alert %q", s)
+}
+
+// AnyTone renders the alert for the AnyTone "Call Alert" column
+func (a CallAlert) AnyTone() string {
+	if a == "" {
+		return string(CallAlertNone)
+	}
+	return string(a)
+}
+
+// DM32UV renders the alert for the DM32UV "Alert Call" column, which is on/off
+func (a CallAlert) DM32UV() string {
+	if a == "" || a == CallAlertNone {
+		return "0"
+	}
+	return "1"
+}
+
+// ContactOverride holds per-contact settings for a DMR ID that RadioID does
+// not provide. It is kept apart from digital_contacts so re-syncs and
+// overwriting imports never reset it.
+type ContactOverride struct {
+	gorm.Model
+	DMRID     int       `gorm:"uniqueIndex" json:"dmr_id"`
+	CallAlert CallAlert `json:"call_alert"`
+	Remarks   *string   `json:"remarks"` // Replaces the RadioID remarks when set
+}
+
+// Validate checks the DMR ID and call alert
+func (o *ContactOverride) Validate() error {
+	if o.DMRID <= 0 {
+		return fmt.Errorf("invalid DMR ID %d", o.DMRID)
+	}
+	alert, err := ParseCallAlert(string(o.CallAlert))
+	if err != nil {
+		return err
+	}
+	o.CallAlert = alert
+	return nil
+}
+
+// ApplyContactOverrides sets CallAlert and replaces Remarks on contacts that
+// have an override. Overrides are loaded for just the given IDs, in chunks
+// to keep query parameters bounded.
+func ApplyContactOverrides(db *gorm.DB, contacts []DigitalContact) error {
+	const chunk = 500
+	index := make(map[int]int, len(contacts))
+	for i, c := range contacts {
+		index[c.DMRID] = i
+	}
+	for start := 0; start < len(contacts); start += chunk {
+		end := start + chunk
+		if end > len(contacts) {
+			end = len(contacts)
+		}
+		ids := make([]int, 0, end-start)
+		for _, c := range contacts[start:end] {
+			ids = append(ids, c.DMRID)
+		}
+		var overrides []ContactOverride
+		if err := db.Where("dmr_id IN ?", ids).Find(&overrides).Error; err != nil {
+			return err
+		}
+		for _, o := range overrides {
+			c := &contacts[index[o.DMRID]]
+			c.CallAlert = o.CallAlert
+			if o.Remarks != nil {
+				c.Remarks = *o.Remarks
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package models
+
+import "gorm.io/gorm"
+
+// Contact sync change actions
+const (
+	ContactSyncAdded   = "added"
+	ContactSyncChanged = "changed"
+	ContactSyncRemoved = "removed"
+)
+
+// ContactSync records one incremental RadioID sync run and its totals
+type ContactSync struct {
+	gorm.Model
+	Source    string              `json:"source"`
+	Status    string              `json:"status"` // running, completed, error
+	Error     string              `json:"error,omitempty"`
+	Added     int                 `json:"added"`
+	Changed   int                 `json:"changed"`
+	Removed   int                 `json:"removed"`
+	Unchanged int                 `json:"unchanged"`
+	Changes   []ContactSyncChange `json:"changes,omitempty"`
+}
+
+// ContactSyncChange is a single per-callsign entry in a sync's change log
+type ContactSyncChange struct {
+	ID            uint   `gorm:"primarykey" json:"id"`
+	ContactSyncID uint   `gorm:"index" json:"contact_sync_id"`
+	DMRID         int    `gorm:"index" json:"dmr_id"`
+	Callsign      string `gorm:"index" json:"callsign"`
+	Action        string `json:"action"`  // added, changed, removed
+	Details       string `json:"details"` // e.g. "city: Detroit -> Warren"
+}
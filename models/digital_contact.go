@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
@@ -14,4 +16,10 @@ type DigitalContact struct {
 	City     string
 	State    string
 	Remarks  string
+
+	// Filled from ContactOverride at export time, not stored on the row
+	CallAlert CallAlert `gorm:"-"`
+
+	// Set when the ID disappears from the RadioID dump; cleared if it returns
+	RetiredAt *time.Time `gorm:"index"`
 }
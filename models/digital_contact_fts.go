@@ -0,0 +1,91 @@
+package models
+
+import "gorm.io/gorm"
+
+// DigitalContactFTSTable is the FTS5 index over digital contact text fields.
+// It is an external-content table kept in sync with digital_contacts by
+// triggers, so the index stores no second copy of the data.
+const DigitalContactFTSTable = "digital_contacts_fts"
+
+// DigitalContactFTSColumns are the indexed fields, in index column order
+var DigitalContactFTSColumns = []string{"callsign", "name", "city", "state", "country"}
+
+var digitalContactFTSSchema = []string{
+	`CREATE VIRTUAL TABLE IF NOT EXISTS digital_contacts_fts USING fts5(
+		callsign, name, city, state, country,
+		content='digital_contacts', content_rowid='id', tokenize='unicode61'
+	)`,
+	`CREATE TRIGGER IF NOT EXISTS digital_contacts_fts_ai AFTER INSERT ON digital_contacts BEGIN
+		INSERT INTO digital_contacts_fts(rowid, callsign, name, city, state, country)
+		VALUES (new.id, new.callsign, new.name, new.city, new.state, new.country);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS digital_contacts_fts_ad AFTER DELETE ON digital_contacts BEGIN
+		INSERT INTO digital_contacts_fts(digital_contacts_fts, rowid, callsign, name, city, state, country)
+		VALUES ('delete', old.id, old.callsign, old.name, old.city, old.state, old.country);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS digital_contacts_fts_au AFTER UPDATE OF callsign, name, city, state, country ON digital_contacts BEGIN
+		INSERT INTO digital_contacts_fts(digital_contacts_fts, rowid, callsign, name, city, state, country)
+		VALUES ('delete', old.id, old.callsign, old.name, old.city, old.state, old.country);
+		INSERT INTO digital_contacts_fts(rowid, callsign, name, city, state, country)
+		VALUES (new.id, new.callsign, new.name, new.city, new.state, new.country);
+	END`,
+}
+
+var digitalContactFTSTriggers = []string{"digital_contacts_fts_ai", "digital_contacts_fts_ad", "digital_contacts_fts_au"}
+
+// SetupDigitalContactFTS creates the contact search index and its triggers.
+// When the index is new, existing contacts are indexed in one pass.
+func SetupDigitalContactFTS(db *gorm.DB) error {
+	exists, err := hasDigitalContactFTS(db)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range digitalContactFTSSchema {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	if !exists {
+		return RebuildDigitalContactFTS(db)
+	}
+	return nil
+}
+
+// WithDigitalContactFTSSuspended runs fn with the index triggers dropped and
+// rebuilds the index once afterwards, which is about twice as fast as
+// per-row trigger updates for whole-directory imports. Call it inside the
+// import's transaction so a failed import leaves the triggers in place.
+func WithDigitalContactFTSSuspended(tx *gorm.DB, fn func() error) error {
+	exists, err := hasDigitalContactFTS(tx)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fn()
+	}
+	for _, trigger := range digitalContactFTSTriggers {
+		if err := tx.Exec("DROP TRIGGER IF EXISTS " + trigger).Error; err != nil {
+			return err
+		}
+	}
+	if err := fn(); err != nil {
+		return err
+	}
+	for _, stmt := range digitalContactFTSSchema[1:] {
+		if err := tx.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return RebuildDigitalContactFTS(tx)
+}
+
+func hasDigitalContactFTS(db *gorm.DB) (bool, error) {
+	var n int64
+	err := db.Raw("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", DigitalContactFTSTable).Scan(&n).Error
+	return n > 0, err
+}
+
+// RebuildDigitalContactFTS reindexes every contact from digital_contacts
+func RebuildDigitalContactFTS(db *gorm.DB) error {
+	return db.Exec("INSERT INTO digital_contacts_fts(digital_contacts_fts) VALUES ('rebuild')").Error
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// DStarCallLength is the fixed width of a D-Star callsign field
+// (e.g. "W8DET  B", module letter in the last position).
+const DStarCallLength = 8
+
+type DStarRepeaterType string
+
+const (
+	DStarTypeRepeater  DStarRepeaterType = "Repeater"
+	DStarTypeReflector DStarRepeaterType = "Reflector"
+)
+
+// DStarRepeater is an entry in a D-Star repeater/reflector list.
+// Reflectors (e.g. "REF030C") have no RF side and only carry a callsign.
+type DStarRepeater struct {
+	gorm.Model
+	Type        DStarRepeaterType `json:"type"`
+	GroupNo     int               `json:"group_no"`
+	GroupName   string            `json:"group_name"`
+	Name        string            `json:"name"`
+	SubName     string            `json:"sub_name"`
+	CallSign    string            `json:"call_sign"`    // Repeater (RPT1) callsign incl. module, e.g. "W8DET  B"
+	GatewayCall string            `json:"gateway_call"` // Gateway (RPT2) callsign, e.g. "W8DET  G"
+	Frequency   float64           `json:"frequency"`
+	Duplex      string            `json:"duplex"` // "", "+", "-"
+	Offset      float64           `json:"offset"`
+	Mode        string            `json:"mode"` // DV, FM
+	Tone        string            `json:"tone"` // Repeater CTCSS, FM only
+	Latitude    float64           `json:"latitude"`
+	Longitude   float64           `json:"longitude"`
+	UTCOffset   string            `json:"utc_offset"` // e.g. "-5:00"
+}
+
+// Validate checks callsign widths and that repeaters have a frequency
+func (r *DStarRepeater) Validate() error {
+	if strings.TrimSpace(r.CallSign) == "" {
+		return errors.New("D-Star call sign is required")
+	}
+	if !ValidDStarCall(r.CallSign) || !ValidDStarCall(r.GatewayCall) {
+		return errors.New("invalid D-Star call sign")
+	}
+	if r.Type != DStarTypeReflector && r.Frequency <= 0 {
+		return errors.New("D-Star repeater requires a frequency")
+	}
+	return nil
+}
+
+// ValidDStarCall reports whether a callsign fits the 8 character D-Star field
+func ValidDStarCall(call string) bool {
+	return len(call) <= DStarCallLength
+}
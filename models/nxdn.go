@@ -0,0 +1,48 @@
+package models
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// NXDN IDs are 16-bit; 0 and 65520+ are reserved for special calls
+const (
+	NXDNMinID = 1
+	NXDNMaxID = 65519
+)
+
+// NXDNTalkgroup represents an NXDN group call target
+type NXDNTalkgroup struct {
+	gorm.Model
+	Name string `json:"name"`
+	TGID int    `gorm:"uniqueIndex" json:"tg_id"`
+}
+
+// NXDNContact represents an NXDN unit ID directory entry (e.g. from RadioID.net nxdn.csv)
+type NXDNContact struct {
+	gorm.Model
+	UnitID   int    `gorm:"uniqueIndex" json:"unit_id"`
+	Callsign string `json:"callsign"`
+	Name     string `json:"name"`
+	City     string `json:"city"`
+	State    string `json:"state"`
+	Country  string `json:"country"`
+	Remarks  string `json:"remarks"`
+}
+
+// Validate checks the talkgroup ID is in the usable NXDN range
+func (t *NXDNTalkgroup) Validate() error {
+	if t.TGID < NXDNMinID || t.TGID > NXDNMaxID {
+		return errors.New("invalid NXDN talkgroup ID")
+	}
+	return nil
+}
+
+// Validate checks the unit ID is in the usable NXDN range
+func (c *NXDNContact) Validate() error {
+	if c.UnitID < NXDNMinID || c.UnitID > NXDNMaxID {
+		return errors.New("invalid NXDN unit ID")
+	}
+	return nil
+}
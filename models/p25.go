@@ -0,0 +1,41 @@
+package models
+
+import (
+	"errors"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// P25 identifier ranges
+const (
+	P25MaxNAC        = 0xFFF   // 12-bit Network Access Code
+	P25MaxTGID       = 65535   // 16-bit talkgroup ID
+	P25MaxUnitID     = 9999999 // 24-bit unit ID, capped at the assignable range
+	P25DefaultNAC    = "293"   // Conventional default NAC
+	P25NACReceiveAny = "F7E"   // Receiver unmutes on any NAC
+)
+
+// P25Talkgroup represents a P25 group call target
+type P25Talkgroup struct {
+	gorm.Model
+	Name string `json:"name"`
+	TGID int    `gorm:"uniqueIndex" json:"tg_id"`
+}
+
+// Validate checks the talkgroup ID is in the usable P25 range
+func (t *P25Talkgroup) Validate() error {
+	if t.TGID < 1 || t.TGID > P25MaxTGID {
+		return errors.New("invalid P25 talkgroup ID")
+	}
+	return nil
+}
+
+// ValidP25NAC reports whether s is a 12-bit hex NAC (e.g. "293", "F7E")
+func ValidP25NAC(s string) bool {
+	if s == "" || len(s) > 3 {
+		return false
+	}
+	n, err := strconv.ParseUint(s, 16, 16)
+	return err == nil && n <= P25MaxNAC
+}
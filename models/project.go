@@ -0,0 +1,48 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultProject is the ID of the server's own database, which holds the
+// project registry and accounts and is served at /api without a prefix
+const DefaultProject = "default"
+
+var projectIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// Project is a separate codeplug workspace with its own SQLite file, named
+// after its ID, in the server's projects directory. The registry lives in
+// the main database.
+type Project struct {
+	ID          string     `gorm:"primarykey" json:"id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Name        string     `gorm:"not null" json:"name"`
+	Description string     `json:"description"`
+	ClonedFrom  string     `json:"cloned_from,omitempty"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+}
+
+// Validate checks the ID, which becomes part of URLs and the file name, and
+// the name
+func (p *Project) Validate() error {
+	var errs ValidationErrors
+	switch {
+	case !projectIDPattern.MatchString(p.ID):
+		errs.add("id", "id must be 1-63 lowercase letters, digits, '-' or '_', starting with a letter or digit")
+	case p.ID == DefaultProject:
+		errs.add("id", "id '"+DefaultProject+"' is reserved for the main database")
+	}
+	if strings.TrimSpace(p.Name) == "" {
+		errs.add("name", "name is required")
+	}
+	return errs.err()
+}
+
+// Archived reports whether the project has been archived, which keeps its
+// file but takes it offline
+func (p *Project) Archived() bool {
+	return p.ArchivedAt != nil
+}
@@ -4,6 +4,7 @@ import "gorm.io/gorm"
 
 type ScanList struct {
 	gorm.Model
+	Versioned
 	Name     string    `json:"name"`
 	Channels []Channel `gorm:"many2many:scan_list_channels;" json:"channels"`
 }
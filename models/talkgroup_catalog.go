@@ -0,0 +1,53 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// Talkgroup catalog networks
+const (
+	NetworkBrandmeister = "Brandmeister"
+	NetworkTGIF         = "TGIF"
+	NetworkDMRMARC      = "DMR-MARC"
+)
+
+// TalkgroupCatalog is a talkgroup published by a DMR network, imported from
+// an offline dump of the network's talkgroup list.
+type TalkgroupCatalog struct {
+	gorm.Model
+	Network     string `gorm:"uniqueIndex:idx_catalog_network_tg"`
+	TGID        int    `gorm:"uniqueIndex:idx_catalog_network_tg"`
+	Name        string `gorm:"index"`
+	Country     string
+	Description string
+}
+
+// Validate checks the catalog entry has a network, name and valid ID
+func (t *TalkgroupCatalog) Validate() error {
+	if strings.TrimSpace(t.Network) == "" {
+		return errors.New("network is required")
+	}
+	if t.TGID <= 0 || t.TGID > 16777215 {
+		return errors.New("invalid talkgroup ID")
+	}
+	if strings.TrimSpace(t.Name) == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+// NormalizeTalkgroupName reduces a talkgroup name to upper-case letters and
+// digits so "TG 3126 - Michigan" and "tg3126michigan" compare equal.
+func NormalizeTalkgroupName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
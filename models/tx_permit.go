@@ -0,0 +1,77 @@
+package models
+
+import "fmt"
+
+// TxPermit is when a channel may transmit, shared by the radios' "TX Admit"
+// and "Busy Lock/TX Permit" columns
+type TxPermit string
+
+const (
+	TxPermitAlways      TxPermit = "Always"
+	TxPermitChannelFree TxPermit = "ChannelFree"
+	TxPermitColorCode   TxPermit = "ColorCode"
+)
+
+// ParseTxPermit reads a TX permit as written by either radio: DM32UV's
+// "Allow TX"/"Always"/"Channel Idle"/"Color Code Idle" or AnyTone's
+// "Off"/"Always"/"Busy"/"Channel Free"/"Same Color Code". AnyTone's
+// "Repeater" and "Different Color Code" have no DM32UV equivalent and read as
+// the nearest setting. Empty means always.
+func ParseTxPermit(s string) (TxPermit, error) {
+	switch s {
+	case "", "Always", "Allow TX", "Off":
+		return TxPermitAlways, nil
+	case "ChannelFree", "Channel Free", "Channel Idle", "Busy", "Repeater":
+		return TxPermitChannelFree, nil
+	case "ColorCode", "Color Code Idle", "Same Color Code", "Different Color Code":
+		return TxPermitColorCode, nil
+	}
+	return "", fmt.Errorf("invalid TX permit %q", s)
+}
+
+// normalized reads a permit saved before it was normalized, falling back to always
+func (p TxPermit) normalized() TxPermit {
+	if n, err := ParseTxPermit(string(p)); err == nil {
+		return n
+	}
+	return TxPermitAlways
+}
+
+// DM32UV renders the permit for the DM32UV "TX Admit" column, whose analog
+// channels have no color code
+func (p TxPermit) DM32UV(digital bool) string {
+	switch p.normalized() {
+	case TxPermitChannelFree:
+		return "Channel Idle"
+	case TxPermitColorCode:
+		if digital {
+			return "Color Code Idle"
+		}
+		return "Channel Idle"
+	}
+	if digital {
+		return "Always"
+	}
+	return "Allow TX"
+}
+
+// AnyTone renders the permit for the AnyTone "Busy Lock/TX Permit" column,
+// which takes a busy lock on analog channels
+func (p TxPermit) AnyTone(digital bool) string {
+	switch p.normalized() {
+	case TxPermitChannelFree:
+		if digital {
+			return "Channel Free"
+		}
+		return "Busy"
+	case TxPermitColorCode:
+		if digital {
+			return "Same Color Code"
+		}
+		return "Busy"
+	}
+	if digital {
+		return "Always"
+	}
+	return "Off"
+}
@@ -0,0 +1,142 @@
+package models
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Role is what an account may do through the API
+type Role string
+
+const (
+	RoleReadOnly Role = "read-only" // May read everything
+	RoleEditor   Role = "editor"    // May also change the codeplug
+	RoleAdmin    Role = "admin"     // May also restore the database, overwrite on import and manage accounts
+)
+
+var roleRank = map[Role]int{RoleReadOnly: 1, RoleEditor: 2, RoleAdmin: 3}
+
+// Valid reports whether r is one of the known roles
+func (r Role) Valid() bool {
+	return roleRank[r] > 0
+}
+
+// Allows reports whether r includes everything required may do
+func (r Role) Allows(required Role) bool {
+	return roleRank[r] >= roleRank[required]
+}
+
+// User is a local account. Once any account exists the web server requires
+// a session or API token on every API call.
+type User struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	Username     string    `gorm:"uniqueIndex;not null" json:"username"`
+	PasswordHash string    `json:"-"`
+	Role         Role      `gorm:"not null" json:"role"`
+}
+
+// MinPasswordLength is the shortest password SetPassword accepts
+const MinPasswordLength = 8
+
+// passwordIterations is the PBKDF2-SHA256 work factor for new hashes; the
+// count is stored with each hash so it can be raised later
+const passwordIterations = 600000
+
+// Validate checks the username and role
+func (u *User) Validate() error {
+	var errs ValidationErrors
+	if strings.TrimSpace(u.Username) == "" {
+		errs.add("username", "username is required")
+	}
+	if !u.Role.Valid() {
+		errs.add("role", fmt.Sprintf("role must be %s, %s or %s", RoleReadOnly, RoleEditor, RoleAdmin))
+	}
+	return errs.err()
+}
+
+// SetPassword stores a salted hash of password
+func (u *User) SetPassword(password string) error {
+	if len(password) < MinPasswordLength {
+		return ValidationErrors{{Field: "password", Message: fmt.Sprintf("password must be at least %d characters", MinPasswordLength)}}
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	key, err := pbkdf2.Key(sha256.New, password, salt, passwordIterations, 32)
+	if err != nil {
+		return err
+	}
+	u.PasswordHash = fmt.Sprintf("pbkdf2-sha256$%d$%s$%s", passwordIterations,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key))
+	return nil
+}
+
+// CheckPassword reports whether password matches the stored hash
+func (u *User) CheckPassword(password string) bool {
+	parts := strings.Split(u.PasswordHash, "$")
+	if len(parts) != 4 || parts[0] != "pbkdf2-sha256" {
+		return false
+	}
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations <= 0 {
+		return false
+	}
+	salt, err1 := base64.RawStdEncoding.DecodeString(parts[2])
+	want, err2 := base64.RawStdEncoding.DecodeString(parts[3])
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	got, err := pbkdf2.Key(sha256.New, password, salt, iterations, len(want))
+	return err == nil && subtle.ConstantTimeCompare(got, want) == 1
+}
+
+// APIToken is a long-lived bearer credential for scripts. Only its hash is
+// stored; the token itself is shown once when it is created.
+type APIToken struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UserID     uint       `gorm:"index;not null" json:"user_id"`
+	User       User       `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"` // Start of the token, to tell tokens apart
+	Hash       string     `gorm:"uniqueIndex;not null" json:"-"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+// Session is a browser login, identified by the token in its cookie
+type Session struct {
+	ID        uint      `gorm:"primarykey" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UserID    uint      `gorm:"index;not null" json:"-"`
+	User      User      `gorm:"constraint:OnDelete:CASCADE" json:"-"`
+	Hash      string    `gorm:"uniqueIndex;not null" json:"-"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NewSecret returns a random token with the given prefix and the hash to
+// store for it
+func NewSecret(prefix string) (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = prefix + base64.RawURLEncoding.EncodeToString(b)
+	return token, HashSecret(token), nil
+}
+
+// HashSecret is the stored form of a session or API token
+func HashSecret(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
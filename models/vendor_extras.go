@@ -0,0 +1,78 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Radio profiles used to key vendor-specific data
+const (
+	VendorProfileAT890  = "at890"
+	VendorProfileDM32UV = "dm32uv"
+)
+
+// AT890VFOChannelNo is the AnyTone 890's VFO A channel number; VFO B follows
+// it. VFO channels keep these fixed numbers, which is the only "No." the
+// AnyTone importer keeps in VendorExtras. Other channels are numbered by
+// their position in the export.
+const AT890VFOChannelNo = 4001
+
+// VendorExtras stores CSV columns that have no dedicated model field, keyed by
+// radio profile and then by column header. Exporters replay these values so
+// settings the database doesn't understand survive an import/export cycle.
+type VendorExtras map[string]map[string]string
+
+// Get returns the stored value of a column for the given radio profile.
+func (v VendorExtras) Get(profile, column string) (string, bool) {
+	cols, ok := v[profile]
+	if !ok {
+		return "", false
+	}
+	val, ok := cols[column]
+	return val, ok
+}
+
+// Set stores the value of a column for the given radio profile.
+func (v *VendorExtras) Set(profile, column, value string) {
+	if *v == nil {
+		*v = make(VendorExtras)
+	}
+	if (*v)[profile] == nil {
+		(*v)[profile] = make(map[string]string)
+	}
+	(*v)[profile][column] = value
+}
+
+// Value implements driver.Valuer, serializing the extras as JSON.
+func (v VendorExtras) Value() (driver.Value, error) {
+	if len(v) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner, decoding extras stored as JSON.
+func (v *VendorExtras) Scan(value interface{}) error {
+	var data []byte
+	switch val := value.(type) {
+	case nil:
+		*v = nil
+		return nil
+	case string:
+		data = []byte(val)
+	case []byte:
+		data = val
+	default:
+		return fmt.Errorf("unsupported vendor extras type %T", value)
+	}
+	if len(data) == 0 {
+		*v = nil
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,27 @@
+package models
+
+import "gorm.io/gorm"
+
+// Versioned is embedded in models edited through the web UI for optimistic
+// concurrency. The version starts at 1 and every API write bumps it, so a
+// client can tell that a record changed since it read it.
+type Versioned struct {
+	Version int `gorm:"not null;default:1" json:"version"`
+}
+
+// BeforeCreate starts new records at version 1
+func (v *Versioned) BeforeCreate(tx *gorm.DB) error {
+	if v.Version < 1 {
+		v.Version = 1
+	}
+	return nil
+}
+
+// CurrentVersion returns the record's version
+func (v *Versioned) CurrentVersion() int { return v.Version }
+
+// SetVersion sets the version to write
+func (v *Versioned) SetVersion(n int) { v.Version = n }
+
+// BumpVersion is the update expression for the next version of a row
+func BumpVersion() interface{} { return gorm.Expr("version + 1") }
@@ -4,9 +4,11 @@ import "gorm.io/gorm"
 
 type Zone struct {
 	gorm.Model
+	Versioned
 	Name         string        `json:"name"`
 	Channels     []Channel     `gorm:"many2many:zone_channels;" json:"channels"`
 	ZoneChannels []ZoneChannel `gorm:"foreignKey:ZoneID" json:"-"`
+	VendorExtras VendorExtras  `gorm:"type:text" json:"vendor_extras,omitempty"`
 }
 
 func FindOrCreateZone(db *gorm.DB, name string) (*Zone, error) {
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"codeplugs/models"
+
+	"gorm.io/gorm"
+)
+
+// Prefixes of the two kinds of secret, so a leaked one is recognizable
+const (
+	sessionTokenPrefix = "cps_"
+	apiTokenPrefix     = "cpt_"
+)
+
+var (
+	// ErrInvalidLogin is returned for an unknown user or a wrong password alike
+	ErrInvalidLogin = errors.New("invalid username or password")
+	// ErrLastAdmin guards against locking every admin out
+	ErrLastAdmin = errors.New("at least one admin account is required")
+	// ErrFirstUserAdmin is returned when the first account isn't an admin,
+	// since creating it turns authentication on
+	ErrFirstUserAdmin = errors.New("the first account must be an admin")
+)
+
+// AuthEnabled reports whether any account exists. Without one the server
+// stays open, as it was before accounts were added; callers must treat an
+// error as closed, not open.
+func AuthEnabled(db *gorm.DB) (bool, error) {
+	var count int64
+	if err := db.Model(&models.User{}).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CreateUser adds an account with a hashed password
+func CreateUser(db *gorm.DB, username, password string, role models.Role) (*models.User, error) {
+	user := &models.User{Username: strings.TrimSpace(username), Role: role}
+	if err := user.Validate(); err != nil {
+		return nil, err
+	}
+	if err := user.SetPassword(password); err != nil {
+		return nil, err
+	}
+	if role != models.RoleAdmin {
+		enabled, err := AuthEnabled(db)
+		if err != nil {
+			return nil, err
+		}
+		if !enabled {
+			return nil, ErrFirstUserAdmin
+		}
+	}
+	if err := db.Create(user).Error; err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// UpdateUser changes an account's role and/or password. Changing the
+// password signs the account out everywhere.
+func UpdateUser(db *gorm.DB, id uint, role *models.Role, password *string) (*models.User, error) {
+	var user models.User
+	if err := db.First(&user, id).Error; err != nil {
+		return nil, err
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if role != nil && *role != user.Role {
+			if user.Role == models.RoleAdmin && adminCount(tx) <= 1 {
+				return ErrLastAdmin
+			}
+			user.Role = *role
+			if err := user.Validate(); err != nil {
+				return err
+			}
+		}
+		if password != nil {
+			if err := user.SetPassword(*password); err != nil {
+				return err
+			}
+			if err := tx.Where("user_id = ?", user.ID).Delete(&models.Session{}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Save(&user).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// DeleteUser removes an account with its sessions and API tokens
+func DeleteUser(db *gorm.DB, id uint) error {
+	var user models.User
+	if err := db.First(&user, id).Error; err != nil {
+		return err
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		// The last account may go, which turns authentication off again
+		if user.Role == models.RoleAdmin && adminCount(tx) <= 1 && userCount(tx) > 1 {
+			return ErrLastAdmin
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&models.Session{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&models.APIToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&user).Error
+	})
+}
+
+func adminCount(db *gorm.DB) int64 {
+	var n int64
+	db.Model(&models.User{}).Where("role = ?", models.RoleAdmin).Count(&n)
+	return n
+}
+
+func userCount(db *gorm.DB) int64 {
+	var n int64
+	db.Model(&models.User{}).Count(&n)
+	return n
+}
+
+var dummyUser = sync.OnceValue(func() *models.User {
+	u := &models.User{}
+	u.SetPassword("not a real password")
+	return u
+})
+
+// Login checks a password and starts a session lasting ttl. The returned
+// token goes in the session cookie; only its hash is stored.
+func Login(db *gorm.DB, username, password string, ttl time.Duration) (string, *models.Session, error) {
+	var user models.User
+	err := db.Where("username = ?", strings.TrimSpace(username)).First(&user).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return "", nil, err
+	}
+	if err == gorm.ErrRecordNotFound {
+		// Hash anyway so timing doesn't reveal which usernames exist
+		dummyUser().CheckPassword(password)
+		return "", nil, ErrInvalidLogin
+	}
+	if !user.CheckPassword(password) {
+		return "", nil, ErrInvalidLogin
+	}
+
+	token, hash, err := models.NewSecret(sessionTokenPrefix)
+	if err != nil {
+		return "", nil, err
+	}
+	session := &models.Session{UserID: user.ID, User: user, Hash: hash, ExpiresAt: time.Now().Add(ttl)}
+	if err := db.Omit("User").Create(session).Error; err != nil {
+		return "", nil, err
+	}
+	// Opportunistically drop expired sessions
+	db.Where("expires_at < ?", time.Now()).Delete(&models.Session{})
+	return token, session, nil
+}
+
+// Logout ends the session with the given token
+func Logout(db *gorm.DB, token string) error {
+	return db.Where("hash = ?", models.HashSecret(token)).Delete(&models.Session{}).Error
+}
+
+// UserForSession returns the account of an unexpired session, or
+// gorm.ErrRecordNotFound
+func UserForSession(db *gorm.DB, token string) (*models.User, error) {
+	var session models.Session
+	err := db.Preload("User").Where("hash = ? AND expires_at > ?", models.HashSecret(token), time.Now()).First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session.User, nil
+}
+
+// CreateAPIToken issues a token that acts as userID with its role. The
+// token is returned once and can't be recovered later.
+func CreateAPIToken(db *gorm.DB, userID uint, name string) (string, *models.APIToken, error) {
+	token, hash, err := models.NewSecret(apiTokenPrefix)
+	if err != nil {
+		return "", nil, err
+	}
+	t := &models.APIToken{UserID: userID, Name: name, Prefix: token[:len(apiTokenPrefix)+6], Hash: hash}
+	if err := db.Omit("User").Create(t).Error; err != nil {
+		return "", nil, err
+	}
+	return token, t, nil
+}
+
+// UserForAPIToken returns the account a token acts as, or
+// gorm.ErrRecordNotFound, and records the token's use
+func UserForAPIToken(db *gorm.DB, token string) (*models.User, error) {
+	var t models.APIToken
+	if err := db.Preload("User").Where("hash = ?", models.HashSecret(token)).First(&t).Error; err != nil {
+		return nil, err
+	}
+	db.Model(&t).Update("last_used_at", time.Now())
+	return &t.User, nil
+}
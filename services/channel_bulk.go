@@ -0,0 +1,265 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"codeplugs/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrEmptySelection is returned when a bulk edit has no filter, so a missing
+// selector can't silently rewrite every channel
+var ErrEmptySelection = errors.New("a channel filter is required")
+
+// channelField is a Channel field that bulk edits may set, keyed by its JSON name
+type channelField struct {
+	Name  string // Go field name, which gorm's Select accepts
+	Index int
+	Kind  reflect.Kind
+}
+
+// bulkExcludedFields are JSON fields with their own endpoints or no scalar value
+var bulkExcludedFields = map[string]bool{
+	"sort_order": true, "contact": true, "vendor_extras": true,
+}
+
+var channelFields = func() map[string]channelField {
+	fields := make(map[string]channelField)
+	t := reflect.TypeOf(models.Channel{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" || bulkExcludedFields[name] {
+			continue
+		}
+		kind := f.Type.Kind()
+		if kind == reflect.Ptr {
+			kind = f.Type.Elem().Kind()
+		}
+		fields[name] = channelField{Name: f.Name, Index: i, Kind: kind}
+	}
+	return fields
+}()
+
+// ChannelBulkEdit sets the same fields on every channel matched by Filter.
+// Set is keyed by the channel's JSON field names with JSON-typed values.
+type ChannelBulkEdit struct {
+	Filter ChannelQuery
+	Set    map[string]interface{}
+}
+
+// ChannelBulkResult reports how many channels matched and how many changed
+type ChannelBulkResult struct {
+	Matched int64  `json:"matched"`
+	Changed int64  `json:"changed"`
+	IDs     []uint `json:"ids"` // Channels that changed
+}
+
+// ChannelValidationError is a bulk edit that would make a channel invalid
+type ChannelValidationError struct {
+	ChannelID uint
+	Name      string
+	Err       error
+}
+
+func (e *ChannelValidationError) Error() string {
+	return fmt.Sprintf("channel #%d %s: %v", e.ChannelID, e.Name, e.Err)
+}
+
+func (e *ChannelValidationError) Unwrap() error { return e.Err }
+
+// BulkEditChannels applies edit in one transaction. Each changed channel is
+// validated; an edit that makes a valid channel invalid rolls back the whole
+// batch. Channels that were already invalid are not held against the edit.
+func BulkEditChannels(db *gorm.DB, edit ChannelBulkEdit) (*ChannelBulkResult, error) {
+	if len(edit.Set) == 0 {
+		return nil, fmt.Errorf("%w: no fields to set", ErrInvalidQuery)
+	}
+	if edit.Filter.empty() {
+		return nil, ErrEmptySelection
+	}
+	var fields []channelField
+	for name := range edit.Set {
+		f, ok := channelFields[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: field %q can't be bulk edited", ErrInvalidQuery, name)
+		}
+		fields = append(fields, f)
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Index < fields[j].Index })
+	columns := []string{"UpdatedAt", "Version"}
+	for _, f := range fields {
+		columns = append(columns, f.Name)
+	}
+
+	result := &ChannelBulkResult{}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		query, err := ChannelFilter(tx, edit.Filter)
+		if err != nil {
+			return err
+		}
+		var channels []models.Channel
+		if err := query.Order("id").Find(&channels).Error; err != nil {
+			return err
+		}
+		result.Matched = int64(len(channels))
+
+		for i := range channels {
+			ch := &channels[i]
+			wasValid := ch.Validate() == nil
+			before := reflect.ValueOf(*ch)
+			if err := applyChannelFields(ch, edit.Set); err != nil {
+				return err
+			}
+			after := reflect.ValueOf(*ch)
+			changed := false
+			for _, f := range fields {
+				if !reflect.DeepEqual(before.Field(f.Index).Interface(), after.Field(f.Index).Interface()) {
+					changed = true
+					break
+				}
+			}
+			if !changed {
+				continue
+			}
+			if err := ch.Validate(); err != nil && wasValid {
+				return &ChannelValidationError{ChannelID: ch.ID, Name: ch.Name, Err: err}
+			}
+			ch.Version++
+			if err := tx.Model(ch).Select(columns).Updates(ch).Error; err != nil {
+				return err
+			}
+			result.Changed++
+			result.IDs = append(result.IDs, ch.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// applyChannelFields decodes each value in set into its channel field, so
+// type mismatches are reported the same way as a JSON request body
+func applyChannelFields(ch *models.Channel, set map[string]interface{}) error {
+	v := reflect.ValueOf(ch).Elem()
+	for name, value := range set {
+		f := channelFields[name]
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		field := v.Field(f.Index)
+		decoded := reflect.New(field.Type())
+		if err := json.Unmarshal(raw, decoded.Interface()); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidQuery, name, err)
+		}
+		field.Set(decoded.Elem())
+	}
+	return nil
+}
+
+// ParseChannelAssignments converts CLI assignments such as "power=Low" or
+// "skip=true" into typed values for ChannelBulkEdit.Set. "null" clears
+// contact_id.
+func ParseChannelAssignments(assignments []string) (map[string]interface{}, error) {
+	set := make(map[string]interface{})
+	for _, a := range assignments {
+		name, value, ok := strings.Cut(a, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%w: expected field=value, got %q", ErrInvalidQuery, a)
+		}
+		f, ok := channelFields[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: field %q can't be bulk edited", ErrInvalidQuery, name)
+		}
+		var err error
+		switch {
+		case value == "null" && name == "contact_id":
+			set[name] = nil
+		case f.Kind == reflect.String:
+			set[name] = value
+		case f.Kind == reflect.Bool:
+			set[name], err = strconv.ParseBool(value)
+		case f.Kind == reflect.Float32 || f.Kind == reflect.Float64:
+			set[name], err = strconv.ParseFloat(value, 64)
+		default:
+			set[name], err = strconv.Atoi(value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: invalid value %q", ErrInvalidQuery, name, value)
+		}
+	}
+	return set, nil
+}
+
+// ParseChannelSelectors reads CLI selectors such as "zone=Detroit Area" or
+// "band=2m,70cm" into a channel filter. Keys match the /api/channels query
+// parameters, plus id for channel IDs.
+func ParseChannelSelectors(selectors []string) (ChannelQuery, error) {
+	var q ChannelQuery
+	for _, s := range selectors {
+		key, value, ok := strings.Cut(s, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if !ok || value == "" {
+			return q, fmt.Errorf("%w: expected key=value, got %q", ErrInvalidQuery, s)
+		}
+		var err error
+		switch key {
+		case "zone":
+			q.Zones = append(q.Zones, splitSelectorValues(value)...)
+		case "mode":
+			q.Modes = append(q.Modes, splitSelectorValues(value)...)
+		case "protocol":
+			q.Protocols = append(q.Protocols, splitSelectorValues(value)...)
+		case "band":
+			q.Bands = append(q.Bands, splitSelectorValues(value)...)
+		case "contact":
+			q.Contacts = append(q.Contacts, splitSelectorValues(value)...)
+		case "search":
+			q.Search = value
+		case "id":
+			for _, v := range splitSelectorValues(value) {
+				id, perr := strconv.ParseUint(v, 10, 64)
+				if perr != nil {
+					return q, fmt.Errorf("%w: invalid channel ID %q", ErrInvalidQuery, v)
+				}
+				q.IDs = append(q.IDs, uint(id))
+			}
+		case "min_freq":
+			q.MinFreq, err = strconv.ParseFloat(value, 64)
+		case "max_freq":
+			q.MaxFreq, err = strconv.ParseFloat(value, 64)
+		case "skip":
+			var skip bool
+			skip, err = strconv.ParseBool(value)
+			q.Skip = &skip
+		default:
+			return q, fmt.Errorf("%w: unknown selector %q", ErrInvalidQuery, key)
+		}
+		if err != nil {
+			return q, fmt.Errorf("%w: %s: invalid value %q", ErrInvalidQuery, key, value)
+		}
+	}
+	return q, nil
+}
+
+func splitSelectorValues(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,69 @@
+package services_test
+
+import (
+	"codeplugs/models"
+	"codeplugs/services"
+	"errors"
+	"testing"
+)
+
+func TestBulkEditChannels(t *testing.T) {
+	db := setupChannelQueryTestDB(t)
+
+	filter, err := services.ParseChannelSelectors([]string{"zone=Detroit Area"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	set, err := services.ParseChannelAssignments([]string{"power=Low", "skip=true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := services.BulkEditChannels(db, services.ChannelBulkEdit{Filter: filter, Set: set})
+	if err != nil {
+		t.Fatalf("BulkEditChannels failed: %v", err)
+	}
+	if res.Matched != 2 || res.Changed != 2 {
+		t.Errorf("Expected 2 matched and changed, got %+v", res)
+	}
+	var low int64
+	db.Model(&models.Channel{}).Where("power = ? AND skip = ?", "Low", true).Count(&low)
+	if low != 2 {
+		t.Errorf("Expected 2 low power skipped channels, got %d", low)
+	}
+
+	// Re-running changes nothing
+	res, _ = services.BulkEditChannels(db, services.ChannelBulkEdit{Filter: filter, Set: set})
+	if res.Matched != 2 || res.Changed != 0 {
+		t.Errorf("Expected 2 matched and 0 changed on rerun, got %+v", res)
+	}
+
+	// An edit that breaks a valid channel rolls back the whole batch
+	db.Model(&models.Channel{}).Where("protocol = ?", models.ProtocolDMR).Update("color_code", 1)
+	_, err = services.BulkEditChannels(db, services.ChannelBulkEdit{
+		Filter: services.ChannelQuery{Bands: []string{"70cm"}},
+		Set:    map[string]interface{}{"color_code": 16, "power": "Mid"},
+	})
+	var invalid *services.ChannelValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("Expected ChannelValidationError, got %v", err)
+	}
+	var mid int64
+	db.Model(&models.Channel{}).Where("power = ?", "Mid").Count(&mid)
+	if mid != 0 {
+		t.Errorf("Expected rollback, %d channels were changed", mid)
+	}
+
+	bad := []services.ChannelBulkEdit{
+		{Set: map[string]interface{}{"power": "Low"}},
+		{Filter: filter, Set: map[string]interface{}{"sort_order": 1}},
+		{Filter: filter, Set: map[string]interface{}{"color_code": "one"}},
+	}
+	for _, edit := range bad {
+		if _, err := services.BulkEditChannels(db, edit); err == nil {
+			t.Errorf("Expected error for %+v", edit)
+		}
+	}
+	if _, err := services.ParseChannelAssignments([]string{"skip=maybe"}); !errors.Is(err, services.ErrInvalidQuery) {
+		t.Errorf("Expected ErrInvalidQuery for bad bool, got %v", err)
+	}
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"codeplugs/models"
+
+	"gorm.io/gorm"
+)
+
+// MaxChannelQueryLimit caps one page of a channel query
+const MaxChannelQueryLimit = 1000
+
+// channelSortColumns whitelists the columns channels may be sorted by
+var channelSortColumns = map[string]string{
+	"id":           "id",
+	"sort_order":   "sort_order",
+	"name":         "name",
+	"rx_frequency": "rx_frequency",
+	"tx_frequency": "tx_frequency",
+	"mode":         "mode",
+	"protocol":     "protocol",
+	"power":        "power",
+	"type":         "type",
+}
+
+// FrequencyRange is a band edge pair in MHz
+type FrequencyRange struct {
+	Min, Max float64
+}
+
+// Bands maps band names accepted by the band filter to receive frequency
+// ranges. The amateur bands follow the US allocations; vhf and uhf cover
+// the usual commercial radio ranges.
+var Bands = map[string]FrequencyRange{
+	"10m":   {28.0, 29.7},
+	"6m":    {50.0, 54.0},
+	"2m":    {144.0, 148.0},
+	"1.25m": {222.0, 225.0},
+	"70cm":  {420.0, 450.0},
+	"33cm":  {902.0, 928.0},
+	"23cm":  {1240.0, 1300.0},
+	"vhf":   {136.0, 174.0},
+	"uhf":   {400.0, 520.0},
+}
+
+// ChannelQuery describes a filtered, sorted channel listing. Filters with
+// several values match any of them; different filters are ANDed. Zone and
+// Contact accept IDs or names. Limit 0 returns every match.
+type ChannelQuery struct {
+	IDs       []uint
+	Modes     []string
+	Protocols []string
+	Bands     []string
+	Zones     []string
+	Contacts  []string
+	Search    string
+	MinFreq   float64
+	MaxFreq   float64
+	Skip      *bool
+
+	Sort   string
+	Order  string
+	Limit  int
+	Page   int
+	Cursor string
+}
+
+// ChannelQueryResult is one page of matching channels
+type ChannelQueryResult struct {
+	Data       []models.Channel
+	Total      int64
+	NextCursor string
+}
+
+// ChannelFilter returns a channel query with q's filters applied and no
+// ordering or paging, for listings and bulk edits alike.
+func ChannelFilter(db *gorm.DB, q ChannelQuery) (*gorm.DB, error) {
+	query := db.Model(&models.Channel{})
+	if len(q.IDs) > 0 {
+		query = query.Where("id IN ?", q.IDs)
+	}
+	if len(q.Modes) > 0 {
+		query = query.Where("mode IN ?", q.Modes)
+	}
+	if len(q.Protocols) > 0 {
+		query = query.Where("protocol IN ?", q.Protocols)
+	}
+	if len(q.Bands) > 0 {
+		cond := db.Session(&gorm.Session{NewDB: true})
+		for _, name := range q.Bands {
+			band, ok := Bands[strings.ToLower(name)]
+			if !ok {
+				return nil, fmt.Errorf("%w: unknown band %q", ErrInvalidQuery, name)
+			}
+			cond = cond.Or("rx_frequency BETWEEN ? AND ?", band.Min, band.Max)
+		}
+		query = query.Where(cond)
+	}
+	if len(q.Zones) > 0 {
+		zones := db.Session(&gorm.Session{NewDB: true}).Model(&models.Zone{}).Select("id").
+			Where(idOrNameCondition(db, q.Zones, "id", "name"))
+		query = query.Where("id IN (SELECT channel_id FROM zone_channels WHERE zone_id IN (?))", zones)
+	}
+	if len(q.Contacts) > 0 {
+		query = query.Where(idOrNameCondition(db, q.Contacts, "contact_id", "tx_contact"))
+	}
+	if s := strings.TrimSpace(q.Search); s != "" {
+		like := "%" + strings.ToUpper(s) + "%"
+		query = query.Where("UPPER(name) LIKE ? OR UPPER(notes) LIKE ?", like, like)
+	}
+	if q.MinFreq > 0 {
+		query = query.Where("rx_frequency >= ?", q.MinFreq)
+	}
+	if q.MaxFreq > 0 {
+		query = query.Where("rx_frequency <= ?", q.MaxFreq)
+	}
+	if q.Skip != nil {
+		query = query.Where("skip = ?", *q.Skip)
+	}
+	return query, nil
+}
+
+// empty reports whether q has no filters, i.e. selects every channel
+func (q ChannelQuery) empty() bool {
+	return len(q.IDs) == 0 && len(q.Modes) == 0 && len(q.Protocols) == 0 && len(q.Bands) == 0 &&
+		len(q.Zones) == 0 && len(q.Contacts) == 0 && strings.TrimSpace(q.Search) == "" &&
+		q.MinFreq <= 0 && q.MaxFreq <= 0 && q.Skip == nil
+}
+
+// QueryChannels lists channels matching q in a whitelisted sort order (by
+// default the user's sort_order) with keyset (cursor) or offset pagination.
+func QueryChannels(db *gorm.DB, q ChannelQuery) (*ChannelQueryResult, error) {
+	sortCol := "sort_order"
+	if q.Sort != "" {
+		col, ok := channelSortColumns[q.Sort]
+		if !ok {
+			return nil, fmt.Errorf("%w %q", ErrInvalidSort, q.Sort)
+		}
+		sortCol = col
+	}
+	limit := q.Limit
+	if limit < 0 {
+		limit = 0
+	}
+	if limit > MaxChannelQueryLimit {
+		limit = MaxChannelQueryLimit
+	}
+
+	query, err := ChannelFilter(db, q)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ChannelQueryResult{}
+	if err := query.Count(&result.Total).Error; err != nil {
+		return nil, err
+	}
+
+	cmp, dir := ">", "ASC"
+	if strings.EqualFold(q.Order, "desc") {
+		cmp, dir = "<", "DESC"
+	}
+	if q.Cursor != "" {
+		cur, err := decodeKeysetCursor(q.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		query = cur.after(query, sortCol, cmp)
+	} else if q.Page > 1 && limit > 0 {
+		query = query.Offset((q.Page - 1) * limit)
+	}
+	if sortCol != "id" {
+		query = query.Order(sortCol + " " + dir)
+	}
+	query = query.Order("id " + dir)
+
+	if limit == 0 {
+		if err := query.Find(&result.Data).Error; err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	// Fetch one extra row to know whether another page follows
+	if err := query.Limit(limit + 1).Find(&result.Data).Error; err != nil {
+		return nil, err
+	}
+	if len(result.Data) > limit {
+		result.Data = result.Data[:limit]
+		last := result.Data[limit-1]
+		result.NextCursor = encodeKeysetCursor(keysetCursor{Value: channelSortValue(last, sortCol), ID: last.ID})
+	}
+	return result, nil
+}
+
+func channelSortValue(c models.Channel, col string) interface{} {
+	switch col {
+	case "sort_order":
+		return c.SortOrder
+	case "name":
+		return c.Name
+	case "rx_frequency":
+		return c.RxFrequency
+	case "tx_frequency":
+		return c.TxFrequency
+	case "mode":
+		return c.Mode
+	case "protocol":
+		return c.Protocol
+	case "power":
+		return c.Power
+	case "type":
+		return c.Type
+	}
+	return c.ID
+}
+
+// idOrNameCondition matches values against idCol when numeric and against
+// nameCol, case-insensitively, otherwise
+func idOrNameCondition(db *gorm.DB, values []string, idCol, nameCol string) *gorm.DB {
+	var ids []uint
+	var names []string
+	for _, v := range values {
+		if id, err := strconv.ParseUint(v, 10, 64); err == nil {
+			ids = append(ids, uint(id))
+		} else {
+			names = append(names, strings.ToUpper(v))
+		}
+	}
+	cond := db.Session(&gorm.Session{NewDB: true})
+	if len(ids) > 0 {
+		cond = cond.Or(idCol+" IN ?", ids)
+	}
+	if len(names) > 0 {
+		cond = cond.Or("UPPER("+nameCol+") IN ?", names)
+	}
+	return cond
+}
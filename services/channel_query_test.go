@@ -0,0 +1,125 @@
+package services_test
+
+import (
+	"codeplugs/models"
+	"codeplugs/services"
+	"errors"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupChannelQueryTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Dialector{
+		DriverName: "sqlite",
+		DSN:        "file:memdb_channel_query?mode=memory&cache=shared",
+	}, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open DB: %v", err)
+	}
+	db.SetupJoinTable(&models.Zone{}, "Channels", &models.ZoneChannel{})
+	db.AutoMigrate(&models.Channel{}, &models.Contact{}, &models.Zone{}, &models.ZoneChannel{})
+	db.Exec("DELETE FROM zone_channels")
+	db.Exec("DELETE FROM zones")
+	db.Exec("DELETE FROM channels")
+	db.Exec("DELETE FROM contacts")
+
+	tg := models.Contact{Name: "Michigan", DMRID: 3126, Type: models.ContactTypeGroup}
+	db.Create(&tg)
+	channels := []models.Channel{
+		{Name: "Detroit DMR", SortOrder: 1, RxFrequency: 442.5, Mode: "DMR", Protocol: models.ProtocolDMR, TxContact: "Michigan", ContactID: &tg.ID},
+		{Name: "Detroit FM", SortOrder: 2, RxFrequency: 145.33, Mode: "FM", Protocol: models.ProtocolFM},
+		{Name: "Lansing FM", SortOrder: 3, RxFrequency: 146.94, Mode: "FM", Protocol: models.ProtocolFM, Skip: true},
+		{Name: "Simplex", SortOrder: 4, RxFrequency: 146.52, Mode: "FM", Protocol: models.ProtocolFM, Notes: "national calling detroit"},
+		{Name: "Ann Arbor DMR", SortOrder: 5, RxFrequency: 443.1, Mode: "DMR", Protocol: models.ProtocolDMR, TxContact: "michigan"},
+	}
+	db.Create(&channels)
+	zone := models.Zone{Name: "Detroit Area"}
+	db.Create(&zone)
+	db.Create(&[]models.ZoneChannel{
+		{ZoneID: zone.ID, ChannelID: channels[0].ID, SortOrder: 1},
+		{ZoneID: zone.ID, ChannelID: channels[1].ID, SortOrder: 2},
+	})
+	return db
+}
+
+func channelNames(t *testing.T, db *gorm.DB, q services.ChannelQuery) ([]string, *services.ChannelQueryResult) {
+	t.Helper()
+	res, err := services.QueryChannels(db, q)
+	if err != nil {
+		t.Fatalf("QueryChannels(%+v) failed: %v", q, err)
+	}
+	var names []string
+	for _, ch := range res.Data {
+		names = append(names, ch.Name)
+	}
+	return names, res
+}
+
+func TestQueryChannels_Filters(t *testing.T) {
+	db := setupChannelQueryTestDB(t)
+	skip := true
+
+	cases := []struct {
+		name  string
+		query services.ChannelQuery
+		want  int
+	}{
+		{"all", services.ChannelQuery{}, 5},
+		{"mode", services.ChannelQuery{Modes: []string{"DMR"}}, 2},
+		{"band", services.ChannelQuery{Bands: []string{"2m"}}, 3},
+		{"bands", services.ChannelQuery{Bands: []string{"2M", "70cm"}}, 5},
+		{"zone name", services.ChannelQuery{Zones: []string{"detroit area"}}, 2},
+		{"contact name", services.ChannelQuery{Contacts: []string{"Michigan"}}, 2},
+		{"search notes", services.ChannelQuery{Search: "detroit"}, 3},
+		{"frequency range", services.ChannelQuery{MinFreq: 146, MaxFreq: 147}, 2},
+		{"skip", services.ChannelQuery{Skip: &skip}, 1},
+		{"combined", services.ChannelQuery{Modes: []string{"FM"}, Zones: []string{"Detroit Area"}}, 1},
+	}
+	for _, c := range cases {
+		names, res := channelNames(t, db, c.query)
+		if len(names) != c.want || res.Total != int64(c.want) {
+			t.Errorf("%s: got %v (total %d), want %d", c.name, names, res.Total, c.want)
+		}
+	}
+
+	if _, err := services.QueryChannels(db, services.ChannelQuery{Bands: []string{"11m"}}); !errors.Is(err, services.ErrInvalidQuery) {
+		t.Errorf("Expected ErrInvalidQuery for unknown band, got %v", err)
+	}
+	if _, err := services.QueryChannels(db, services.ChannelQuery{Sort: "name desc"}); !errors.Is(err, services.ErrInvalidSort) {
+		t.Errorf("Expected ErrInvalidSort, got %v", err)
+	}
+}
+
+func TestQueryChannels_Pagination(t *testing.T) {
+	db := setupChannelQueryTestDB(t)
+
+	var all []string
+	q := services.ChannelQuery{Sort: "rx_frequency", Order: "desc", Limit: 2}
+	for page := 0; page < 5; page++ {
+		names, res := channelNames(t, db, q)
+		if res.Total != 5 {
+			t.Errorf("Expected total 5 on every page, got %d", res.Total)
+		}
+		all = append(all, names...)
+		if res.NextCursor == "" {
+			break
+		}
+		q.Cursor = res.NextCursor
+	}
+	want := []string{"Ann Arbor DMR", "Detroit DMR", "Lansing FM", "Simplex", "Detroit FM"}
+	if len(all) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, all)
+	}
+	for i := range want {
+		if all[i] != want[i] {
+			t.Errorf("Position %d: got %s, want %s", i, all[i], want[i])
+		}
+	}
+
+	names, _ := channelNames(t, db, services.ChannelQuery{Limit: 2, Page: 3})
+	if len(names) != 1 || names[0] != "Ann Arbor DMR" {
+		t.Errorf("Expected last page in sort_order, got %v", names)
+	}
+}
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"codeplugs/models"
+
+	"gorm.io/gorm"
+)
+
+// Reasons contacts were grouped as duplicates
+const (
+	DuplicateByDMRID = "dmr_id" // Same DMR ID, different names or types
+	DuplicateByName  = "name"   // Same normalized name, one side a placeholder
+)
+
+// DuplicateGroup is a set of contacts that describe one talkgroup, with the
+// record proposed to keep
+type DuplicateGroup struct {
+	Reason     string           `json:"reason"`
+	DMRID      int              `json:"dmr_id"`
+	Canonical  models.Contact   `json:"canonical"`
+	Duplicates []models.Contact `json:"duplicates"`
+	Channels   int64            `json:"channels"` // Channels using any of the duplicates
+}
+
+// DedupeRequest merges duplicates into a canonical contact
+type DedupeRequest struct {
+	CanonicalID  uint   `json:"canonical_id"`
+	DuplicateIDs []uint `json:"duplicate_ids"`
+}
+
+// FindDuplicateContacts groups contacts that share a DMR ID (e.g. "TG 3126 MI"
+// and "Michigan 3126", possibly imported with different types) and attaches
+// placeholder contacts whose normalized name matches exactly one real ID.
+// Placeholders with the same normalized name and no real match are grouped
+// with each other.
+func FindDuplicateContacts(db *gorm.DB) ([]DuplicateGroup, error) {
+	var contacts []models.Contact
+	if err := db.Order("id").Find(&contacts).Error; err != nil {
+		return nil, err
+	}
+	usage, err := contactChannelUsage(db)
+	if err != nil {
+		return nil, err
+	}
+	var catalog []models.TalkgroupCatalog
+	if err := db.Select("tg_id", "name").Find(&catalog).Error; err != nil {
+		return nil, err
+	}
+	catalogNames := make(map[int]map[string]bool)
+	for _, tg := range catalog {
+		if catalogNames[tg.TGID] == nil {
+			catalogNames[tg.TGID] = make(map[string]bool)
+		}
+		catalogNames[tg.TGID][models.NormalizeTalkgroupName(tg.Name)] = true
+	}
+
+	byID := make(map[int][]models.Contact)
+	idsByName := make(map[string]map[int]bool)
+	placeholdersByName := make(map[string][]models.Contact)
+	var nameOrder []string
+	for _, c := range contacts {
+		key := models.NormalizeTalkgroupName(c.Name)
+		if c.DMRID > 0 {
+			byID[c.DMRID] = append(byID[c.DMRID], c)
+			if key != "" {
+				if idsByName[key] == nil {
+					idsByName[key] = make(map[int]bool)
+				}
+				idsByName[key][c.DMRID] = true
+			}
+			continue
+		}
+		if key == "" {
+			continue
+		}
+		if _, ok := placeholdersByName[key]; !ok {
+			nameOrder = append(nameOrder, key)
+		}
+		placeholdersByName[key] = append(placeholdersByName[key], c)
+	}
+
+	// Attach placeholders to the one real ID sharing their name
+	attached := make(map[int][]models.Contact)
+	var orphanKeys []string
+	for _, key := range nameOrder {
+		if ids := idsByName[key]; len(ids) == 1 {
+			for id := range ids {
+				attached[id] = append(attached[id], placeholdersByName[key]...)
+			}
+			continue
+		}
+		orphanKeys = append(orphanKeys, key)
+	}
+
+	var groups []DuplicateGroup
+	ids := make([]int, 0, len(byID))
+	for id := range byID {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		members := append(append([]models.Contact{}, byID[id]...), attached[id]...)
+		if len(members) < 2 {
+			continue
+		}
+		reason := DuplicateByDMRID
+		if len(byID[id]) == 1 {
+			reason = DuplicateByName
+		}
+		groups = append(groups, buildDuplicateGroup(reason, members, usage, catalogNames))
+	}
+	for _, key := range orphanKeys {
+		if members := placeholdersByName[key]; len(members) > 1 {
+			groups = append(groups, buildDuplicateGroup(DuplicateByName, members, usage, catalogNames))
+		}
+	}
+	return groups, nil
+}
+
+// buildDuplicateGroup picks the canonical contact: a real DMR ID first, then
+// the most used by channels, then a name matching the network catalog, then
+// the oldest record.
+func buildDuplicateGroup(reason string, members []models.Contact, usage map[uint]int64, catalogNames map[int]map[string]bool) DuplicateGroup {
+	rank := func(c models.Contact) int64 {
+		var score int64
+		if c.DMRID > 0 {
+			score += 1 << 40
+		}
+		score += usage[c.ID] << 1
+		if catalogNames[c.DMRID][models.NormalizeTalkgroupName(c.Name)] {
+			score++
+		}
+		return score
+	}
+	sort.SliceStable(members, func(i, j int) bool {
+		ri, rj := rank(members[i]), rank(members[j])
+		if ri != rj {
+			return ri > rj
+		}
+		return members[i].ID < members[j].ID
+	})
+
+	g := DuplicateGroup{Reason: reason, Canonical: members[0], DMRID: members[0].DMRID, Duplicates: members[1:]}
+	for _, c := range members {
+		g.Channels += usage[c.ID]
+	}
+	return g
+}
+
+// contactChannelUsage counts the channels using each contact
+func contactChannelUsage(db *gorm.DB) (map[uint]int64, error) {
+	var rows []struct {
+		ContactID uint
+		N         int64
+	}
+	if err := db.Model(&models.Channel{}).Select("contact_id, COUNT(*) AS n").
+		Where("contact_id IS NOT NULL").Group("contact_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	usage := make(map[uint]int64, len(rows))
+	for _, r := range rows {
+		usage[r.ContactID] = r.N
+	}
+	return usage, nil
+}
+
+// MergeDuplicateContacts merges each duplicate into the canonical contact in
+// one transaction: channels using a duplicate (by ContactID, or by TxContact
+// name when unlinked) are repointed and renamed, and the duplicate deleted.
+// It returns the number of channels rewritten.
+func MergeDuplicateContacts(db *gorm.DB, req DedupeRequest) (int64, error) {
+	var total int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		n, err := mergeDuplicates(tx, req)
+		total = n
+		return err
+	})
+	return total, err
+}
+
+// DedupeAllContacts finds every duplicate group and merges it into its
+// proposed canonical contact, all in one transaction.
+func DedupeAllContacts(db *gorm.DB) ([]DuplicateGroup, int64, error) {
+	var groups []DuplicateGroup
+	var total int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if groups, err = FindDuplicateContacts(tx); err != nil {
+			return err
+		}
+		for _, g := range groups {
+			req := DedupeRequest{CanonicalID: g.Canonical.ID}
+			for _, d := range g.Duplicates {
+				req.DuplicateIDs = append(req.DuplicateIDs, d.ID)
+			}
+			n, err := mergeDuplicates(tx, req)
+			if err != nil {
+				return err
+			}
+			total += n
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return groups, total, nil
+}
+
+func mergeDuplicates(tx *gorm.DB, req DedupeRequest) (int64, error) {
+	if req.CanonicalID == 0 || len(req.DuplicateIDs) == 0 {
+		return 0, errors.New("canonical_id and duplicate_ids are required")
+	}
+	var canonical models.Contact
+	if err := tx.First(&canonical, req.CanonicalID).Error; err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, id := range req.DuplicateIDs {
+		if id == canonical.ID {
+			continue
+		}
+		var dup models.Contact
+		if err := tx.First(&dup, id).Error; err != nil {
+			return 0, err
+		}
+		if dup.DMRID > 0 && canonical.DMRID > 0 && dup.DMRID != canonical.DMRID {
+			return 0, fmt.Errorf("contact %d (%d) and %d (%d) have different DMR IDs", dup.ID, dup.DMRID, canonical.ID, canonical.DMRID)
+		}
+		n, err := mergeContact(tx, dup, canonical)
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	// Unlinked channels still naming the canonical contact get linked too
+	res := channelsUsingContact(tx.Model(&models.Channel{}), canonical).Where("contact_id IS NULL").
+		Update("contact_id", canonical.ID)
+	if res.Error != nil {
+		return 0, res.Error
+	}
+	return total + res.RowsAffected, nil
+}
@@ -0,0 +1,73 @@
+package services_test
+
+import (
+	"codeplugs/models"
+	"codeplugs/services"
+	"testing"
+)
+
+func TestFindAndDedupeContacts(t *testing.T) {
+	db := setupResolveTestDB(t)
+
+	db.Create(&models.TalkgroupCatalog{Network: models.NetworkBrandmeister, TGID: 3126, Name: "Michigan"})
+
+	tgMI := models.Contact{Name: "TG 3126 MI", DMRID: 3126, Type: models.ContactTypeGroup}
+	michigan := models.Contact{Name: "Michigan", DMRID: 3126, Type: models.ContactTypePrivate}
+	placeholder := models.Contact{Name: "MICHIGAN", DMRID: -1, Type: models.ContactTypeGroup}
+	ohio := models.Contact{Name: "Ohio", DMRID: 3139, Type: models.ContactTypeGroup}
+	for _, c := range []*models.Contact{&tgMI, &michigan, &placeholder, &ohio} {
+		if err := db.Create(c).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	db.Create(&[]models.Channel{
+		{Name: "MI 1", TxContact: tgMI.Name, ContactID: &tgMI.ID},
+		{Name: "MI 2", TxContact: michigan.Name, ContactID: &michigan.ID},
+		{Name: "MI 3", TxContact: michigan.Name, ContactID: &michigan.ID},
+		{Name: "MI 4", TxContact: placeholder.Name, ContactID: &placeholder.ID},
+		{Name: "MI 5", TxContact: "tg 3126 mi"},
+		{Name: "OH", TxContact: ohio.Name, ContactID: &ohio.ID},
+	})
+
+	groups, err := services.FindDuplicateContacts(db)
+	if err != nil {
+		t.Fatalf("FindDuplicateContacts failed: %v", err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("Expected 1 duplicate group, got %d: %+v", len(groups), groups)
+	}
+	g := groups[0]
+	if g.DMRID != 3126 || g.Reason != services.DuplicateByDMRID || len(g.Duplicates) != 2 {
+		t.Errorf("Unexpected group: %+v", g)
+	}
+	// Most used record wins
+	if g.Canonical.ID != michigan.ID {
+		t.Errorf("Expected canonical %d, got %d (%s)", michigan.ID, g.Canonical.ID, g.Canonical.Name)
+	}
+
+	_, channels, err := services.DedupeAllContacts(db)
+	if err != nil {
+		t.Fatalf("DedupeAllContacts failed: %v", err)
+	}
+	if channels != 3 {
+		t.Errorf("Expected 3 channels rewritten, got %d", channels)
+	}
+
+	var rows []models.Channel
+	db.Where("name LIKE ?", "MI %").Find(&rows)
+	for _, ch := range rows {
+		if ch.ContactID == nil || *ch.ContactID != michigan.ID || ch.TxContact != "Michigan" {
+			t.Errorf("Channel %s not merged: %v %q", ch.Name, ch.ContactID, ch.TxContact)
+		}
+	}
+	var count int64
+	db.Unscoped().Model(&models.Contact{}).Count(&count)
+	if count != 2 {
+		t.Errorf("Expected 2 contacts left, got %d", count)
+	}
+
+	if _, err := services.MergeDuplicateContacts(db, services.DedupeRequest{CanonicalID: michigan.ID, DuplicateIDs: []uint{ohio.ID}}); err == nil {
+		t.Error("Expected error merging different DMR IDs")
+	}
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"codeplugs/models"
+	"encoding/csv"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Contact ranking weights. Pins always win; otherwise a contact's score is
+// its geographic priority plus how much and how recently it was heard.
+const (
+	rankPinned           = 1e9
+	rankPriorityState    = 1000.0
+	rankPriorityCountry  = 250.0
+	rankPerHeardDoubling = 50.0  // log2 scale, so heavy talkers don't swamp everything
+	rankRecentDays       = 365.0 // Recency bonus decays linearly to 0 over a year
+)
+
+// ContactRankOptions controls how contacts are ranked for truncation
+type ContactRankOptions struct {
+	Limit             int      // Radio capacity; <= 0 means no truncation
+	PriorityStates    []string // e.g. "Michigan", matched case-insensitively
+	PriorityCountries []string // e.g. "United States"
+	Now               time.Time
+}
+
+// RankedContact is a contact with its ranking score, used in truncation reports
+type RankedContact struct {
+	models.DigitalContact
+	Score float64 `json:"score"`
+}
+
+// TruncateContacts keeps the top opts.Limit contacts by score when the list
+// exceeds the radio's capacity. Kept contacts retain their input order;
+// dropped contacts are returned highest score first so the report shows what
+// was closest to making the cut.
+func TruncateContacts(db *gorm.DB, contacts []models.DigitalContact, opts ContactRankOptions) ([]models.DigitalContact, []RankedContact, error) {
+	if opts.Limit <= 0 || len(contacts) <= opts.Limit {
+		return contacts, nil, nil
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	activity := make(map[int]models.ContactActivity)
+	var rows []models.ContactActivity
+	if err := db.FindInBatches(&rows, 5000, func(_ *gorm.DB, _ int) error {
+		for _, a := range rows {
+			activity[a.DMRID] = a
+		}
+		return nil
+	}).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var pins []models.ContactPin
+	if err := db.Find(&pins).Error; err != nil {
+		return nil, nil, err
+	}
+	pinned := make(map[int]bool, len(pins))
+	for _, p := range pins {
+		pinned[p.DMRID] = true
+	}
+
+	states := lowerSet(opts.PriorityStates)
+	countries := lowerSet(opts.PriorityCountries)
+
+	ranked := make([]RankedContact, len(contacts))
+	order := make([]int, len(contacts))
+	for i, c := range contacts {
+		a, heard := activity[c.DMRID]
+		ranked[i] = RankedContact{DigitalContact: c, Score: contactScore(c, a, heard, pinned[c.DMRID], states, countries, opts.Now)}
+		order[i] = i
+	}
+
+	// Highest score first, ties broken by lowest DMR ID for stable output
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := ranked[order[i]], ranked[order[j]]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.DMRID < b.DMRID
+	})
+
+	keep := make([]bool, len(contacts))
+	for _, idx := range order[:opts.Limit] {
+		keep[idx] = true
+	}
+
+	kept := make([]models.DigitalContact, 0, opts.Limit)
+	for i, c := range contacts {
+		if keep[i] {
+			kept = append(kept, c)
+		}
+	}
+
+	dropped := make([]RankedContact, 0, len(contacts)-opts.Limit)
+	for _, idx := range order[opts.Limit:] {
+		dropped = append(dropped, ranked[idx])
+	}
+
+	return kept, dropped, nil
+}
+
+func contactScore(c models.DigitalContact, a models.ContactActivity, heard, pinned bool, states, countries map[string]bool, now time.Time) float64 {
+	score := 0.0
+	if pinned {
+		score += rankPinned
+	}
+	if states[strings.ToLower(strings.TrimSpace(c.State))] {
+		score += rankPriorityState
+	}
+	if countries[strings.ToLower(strings.TrimSpace(c.Country))] {
+		score += rankPriorityCountry
+	}
+	if heard {
+		score += rankPerHeardDoubling * math.Log2(1+float64(a.HeardCount))
+		if a.LastHeard != nil {
+			days := now.Sub(*a.LastHeard).Hours() / 24
+			if days < rankRecentDays {
+				score += rankRecentDays - math.Max(days, 0)
+			}
+		}
+	}
+	return score
+}
+
+func lowerSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		if v = strings.ToLower(strings.TrimSpace(v)); v != "" {
+			set[v] = true
+		}
+	}
+	return set
+}
+
+// WriteTruncationReport writes the contacts dropped by TruncateContacts as CSV
+func WriteTruncationReport(dropped []RankedContact, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"DMR ID", "Callsign", "Name", "State", "Country", "Score"}); err != nil {
+		return err
+	}
+	for _, c := range dropped {
+		if err := writer.Write([]string{
+			strconv.Itoa(c.DMRID),
+			c.Callsign,
+			c.Name,
+			c.State,
+			c.Country,
+			strconv.FormatFloat(c.Score, 'f', 1, 64),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
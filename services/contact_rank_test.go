@@ -0,0 +1,92 @@
+package services_test
+
+import (
+	"bytes"
+	"codeplugs/models"
+	"codeplugs/services"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupRankTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Dialector{
+		DriverName: "sqlite",
+		DSN:        "file:memdb_contact_rank?mode=memory&cache=shared",
+	}, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open DB: %v", err)
+	}
+	db.AutoMigrate(&models.ContactActivity{}, &models.ContactPin{})
+	db.Exec("DELETE FROM contact_activities")
+	db.Exec("DELETE FROM contact_pins")
+	return db
+}
+
+func TestTruncateContacts_RanksByPinGeoAndActivity(t *testing.T) {
+	db := setupRankTestDB(t)
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	recent := now.AddDate(0, 0, -2)
+	stale := now.AddDate(-2, 0, 0)
+
+	db.Create(&models.ContactActivity{DMRID: 2, HeardCount: 40, LastHeard: &recent})
+	db.Create(&models.ContactActivity{DMRID: 5, HeardCount: 3, LastHeard: &stale})
+	db.Create(&models.ContactPin{DMRID: 6})
+
+	contacts := []models.DigitalContact{
+		{DMRID: 1, Callsign: "K1QUIET", State: "Texas", Country: "United States"},
+		{DMRID: 2, Callsign: "K2ACTIVE", State: "Texas", Country: "United States"},
+		{DMRID: 3, Callsign: "K8LOCAL", State: "Michigan", Country: "United States"},
+		{DMRID: 4, Callsign: "VE3FAR", State: "Ontario", Country: "Canada"},
+		{DMRID: 5, Callsign: "K5STALE", State: "Ohio", Country: "United States"},
+		{DMRID: 6, Callsign: "DL1PIN", State: "Bayern", Country: "Germany"},
+	}
+
+	kept, dropped, err := services.TruncateContacts(db, contacts, services.ContactRankOptions{
+		Limit:          3,
+		PriorityStates: []string{"michigan"},
+		Now:            now,
+	})
+	if err != nil {
+		t.Fatalf("TruncateContacts failed: %v", err)
+	}
+
+	var keptCalls []string
+	for _, c := range kept {
+		keptCalls = append(keptCalls, c.Callsign)
+	}
+	// Input order preserved among the survivors
+	if strings.Join(keptCalls, ",") != "K2ACTIVE,K8LOCAL,DL1PIN" {
+		t.Errorf("Unexpected kept contacts: %v", keptCalls)
+	}
+
+	if len(dropped) != 3 {
+		t.Fatalf("Expected 3 dropped, got %d", len(dropped))
+	}
+	// Heard once long ago still outranks never heard
+	if dropped[0].Callsign != "K5STALE" {
+		t.Errorf("Expected K5STALE to be the best of the dropped, got %s", dropped[0].Callsign)
+	}
+
+	var report bytes.Buffer
+	services.WriteTruncationReport(dropped, &report)
+	if !strings.Contains(report.String(), "VE3FAR") {
+		t.Errorf("Expected VE3FAR in dropped report:\n%s", report.String())
+	}
+}
+
+func TestTruncateContacts_UnderLimit(t *testing.T) {
+	db := setupRankTestDB(t)
+	contacts := []models.DigitalContact{{DMRID: 1}, {DMRID: 2}}
+
+	kept, dropped, err := services.TruncateContacts(db, contacts, services.ContactRankOptions{Limit: 5})
+	if err != nil {
+		t.Fatalf("TruncateContacts failed: %v", err)
+	}
+	if len(kept) != 2 || len(dropped) != 0 {
+		t.Errorf("Expected no truncation, got %d kept %d dropped", len(kept), len(dropped))
+	}
+}
@@ -0,0 +1,311 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"codeplugs/models"
+
+	"gorm.io/gorm"
+)
+
+// Contact search limits
+const (
+	DefaultContactSearchLimit = 50
+	MaxContactSearchLimit     = 1000
+)
+
+// Errors returned for bad search parameters
+var (
+	ErrInvalidSort   = errors.New("invalid sort column")
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidQuery  = errors.New("invalid search query")
+)
+
+// contactSortColumns whitelists the columns contacts may be sorted by
+var contactSortColumns = map[string]string{
+	"id":       "id",
+	"dmr_id":   "dmr_id",
+	"callsign": "callsign",
+	"name":     "name",
+	"city":     "city",
+	"state":    "state",
+	"country":  "country",
+}
+
+// contactQueryFields maps query field qualifiers to indexed columns
+var contactQueryFields = map[string]string{
+	"call":     "callsign",
+	"callsign": "callsign",
+	"name":     "name",
+	"city":     "city",
+	"state":    "state",
+	"country":  "country",
+}
+
+// ContactSearch describes a digital contact search. Query uses the syntax
+// documented on parseContactQuery. Cursor continues from a previous result's
+// NextCursor; without one, Page selects an offset page.
+type ContactSearch struct {
+	Query  string
+	Sort   string
+	Order  string
+	Limit  int
+	Page   int
+	Cursor string
+}
+
+// ContactSearchResult is one page of matching contacts
+type ContactSearchResult struct {
+	Data       []models.DigitalContact
+	Total      int64
+	NextCursor string
+}
+
+// contactQuery is a parsed search: an FTS5 MATCH expression plus DMR ID terms,
+// which are not in the text index
+type contactQuery struct {
+	Match    string
+	DMRIDs   []idRange
+	Prefixes []string
+}
+
+// parseContactQuery parses a contact search such as
+//
+//	state:MI call:KF8* "grand rapids"
+//
+// Terms are ANDed. field:value matches one column (call/callsign, name, city,
+// state, country, id/dmr_id) exactly, or as a prefix with a trailing "*".
+// Bare words match any column as a prefix, and bare numbers match DMR IDs by
+// prefix. Two-letter US state codes also match the full state name RadioID
+// uses ("MI" matches "Michigan"). Quote values containing spaces.
+func parseContactQuery(q string) (*contactQuery, error) {
+	terms, err := splitQueryTerms(q)
+	if err != nil {
+		return nil, err
+	}
+
+	cq := &contactQuery{}
+	var match []string
+	for _, term := range terms {
+		field, value := "", term
+		if i := strings.IndexByte(term, ':'); i > 0 && !strings.HasPrefix(term, `"`) {
+			field, value = strings.ToLower(term[:i]), term[i+1:]
+		}
+		value = strings.Trim(value, `"`)
+		prefix := strings.HasSuffix(value, "*")
+		value = strings.TrimSpace(strings.TrimSuffix(value, "*"))
+		if value == "" {
+			continue
+		}
+
+		switch {
+		case field == "id" || field == "dmr_id" || (field == "" && isDigits(value)):
+			if !isDigits(value) {
+				return nil, fmt.Errorf("%w: DMR ID %q is not a number", ErrInvalidQuery, value)
+			}
+			if field == "" || prefix {
+				cq.Prefixes = append(cq.Prefixes, value)
+			} else {
+				id, _ := strconv.Atoi(value)
+				cq.DMRIDs = append(cq.DMRIDs, idRange{id, id})
+			}
+		case field == "":
+			match = append(match, ftsPhrase(value, true))
+		default:
+			col, ok := contactQueryFields[field]
+			if !ok {
+				return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidQuery, field)
+			}
+			expr := col + ":" + ftsPhrase(value, prefix)
+			if col == "state" && !prefix {
+				if full, ok := usStateNames[strings.ToUpper(value)]; ok {
+					expr = "(" + expr + " OR " + col + ":" + ftsPhrase(full, false) + ")"
+				}
+			}
+			match = append(match, expr)
+		}
+	}
+	cq.Match = strings.Join(match, " AND ")
+	return cq, nil
+}
+
+// SearchDigitalContacts runs a contact search against the FTS index with a
+// whitelisted sort and keyset (cursor) or offset pagination.
+func SearchDigitalContacts(db *gorm.DB, s ContactSearch) (*ContactSearchResult, error) {
+	sortCol := "id"
+	if s.Sort != "" {
+		col, ok := contactSortColumns[s.Sort]
+		if !ok {
+			return nil, fmt.Errorf("%w %q", ErrInvalidSort, s.Sort)
+		}
+		sortCol = col
+	}
+	desc := strings.EqualFold(s.Order, "desc")
+	limit := s.Limit
+	if limit < 1 {
+		limit = DefaultContactSearchLimit
+	}
+	if limit > MaxContactSearchLimit {
+		limit = MaxContactSearchLimit
+	}
+
+	cq, err := parseContactQuery(s.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	query := db.Model(&models.DigitalContact{})
+	if cq.Match != "" {
+		query = query.Where("id IN (SELECT rowid FROM "+models.DigitalContactFTSTable+" WHERE "+models.DigitalContactFTSTable+" MATCH ?)", cq.Match)
+	}
+	for _, r := range cq.DMRIDs {
+		query = query.Where("dmr_id BETWEEN ? AND ?", r.lo, r.hi)
+	}
+	for _, p := range cq.Prefixes {
+		query = query.Where(dmrIDPrefixCondition(db, p))
+	}
+
+	result := &ContactSearchResult{}
+	if err := query.Count(&result.Total).Error; err != nil {
+		return nil, wrapFTSError(err)
+	}
+
+	cmp, dir := ">", "ASC"
+	if desc {
+		cmp, dir = "<", "DESC"
+	}
+	if s.Cursor != "" {
+		cur, err := decodeKeysetCursor(s.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		query = cur.after(query, sortCol, cmp)
+	} else if s.Page > 1 {
+		query = query.Offset((s.Page - 1) * limit)
+	}
+	if sortCol != "id" {
+		query = query.Order(sortCol + " " + dir)
+	}
+	query = query.Order("id " + dir)
+
+	// Fetch one extra row to know whether another page follows
+	if err := query.Limit(limit + 1).Find(&result.Data).Error; err != nil {
+		return nil, wrapFTSError(err)
+	}
+	if len(result.Data) > limit {
+		result.Data = result.Data[:limit]
+		last := result.Data[limit-1]
+		result.NextCursor = encodeKeysetCursor(keysetCursor{Value: contactSortValue(last, sortCol), ID: last.ID})
+	}
+	return result, nil
+}
+
+// dmrIDPrefixCondition matches DMR IDs starting with the given digits using
+// index-friendly ranges, one per possible ID length (up to 8 digits)
+func dmrIDPrefixCondition(db *gorm.DB, prefix string) *gorm.DB {
+	cond := db.Session(&gorm.Session{NewDB: true})
+	base, _ := strconv.Atoi(prefix)
+	lo, hi := base, base
+	for digits := len(prefix); digits <= 8; digits++ {
+		cond = cond.Or("dmr_id BETWEEN ? AND ?", lo, hi)
+		lo, hi = lo*10, hi*10+9
+	}
+	return cond
+}
+
+func contactSortValue(c models.DigitalContact, col string) interface{} {
+	switch col {
+	case "dmr_id":
+		return c.DMRID
+	case "callsign":
+		return c.Callsign
+	case "name":
+		return c.Name
+	case "city":
+		return c.City
+	case "state":
+		return c.State
+	case "country":
+		return c.Country
+	}
+	return c.ID
+}
+
+// wrapFTSError reports FTS5 syntax errors as bad queries
+func wrapFTSError(err error) error {
+	if strings.Contains(err.Error(), "fts5") {
+		return fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+	}
+	return err
+}
+
+// ftsPhrase quotes a value as an FTS5 phrase so user input can't inject
+// query syntax
+func ftsPhrase(value string, prefix bool) string {
+	phrase := `"` + strings.ReplaceAll(value, `"`, `""`) + `"`
+	if prefix {
+		phrase += "*"
+	}
+	return phrase
+}
+
+// splitQueryTerms splits on whitespace, keeping quoted values together
+func splitQueryTerms(q string) ([]string, error) {
+	var terms []string
+	var cur strings.Builder
+	inQuote := false
+	for _, r := range q {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			cur.WriteRune(r)
+		case unicode.IsSpace(r) && !inQuote:
+			if cur.Len() > 0 {
+				terms = append(terms, cur.String())
+				cur.Reset()
+			}
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if inQuote {
+		return nil, fmt.Errorf("%w: unterminated quote", ErrInvalidQuery)
+	}
+	if cur.Len() > 0 {
+		terms = append(terms, cur.String())
+	}
+	return terms, nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+type idRange struct{ lo, hi int }
+
+// usStateNames maps USPS codes to the state names used in RadioID data
+var usStateNames = map[string]string{
+	"AL": "Alabama", "AK": "Alaska", "AZ": "Arizona", "AR": "Arkansas", "CA": "California",
+	"CO": "Colorado", "CT": "Connecticut", "DE": "Delaware", "DC": "District of Columbia", "FL": "Florida",
+	"GA": "Georgia", "HI": "Hawaii", "ID": "Idaho", "IL": "Illinois", "IN": "Indiana",
+	"IA": "Iowa", "KS": "Kansas", "KY": "Kentucky", "LA": "Louisiana", "ME": "Maine",
+	"MD": "Maryland", "MA": "Massachusetts", "MI": "Michigan", "MN": "Minnesota", "MS": "Mississippi",
+	"MO": "Missouri", "MT": "Montana", "NE": "Nebraska", "NV": "Nevada", "NH": "New Hampshire",
+	"NJ": "New Jersey", "NM": "New Mexico", "NY": "New York", "NC": "North Carolina", "ND": "North Dakota",
+	"OH": "Ohio", "OK": "Oklahoma", "OR": "Oregon", "PA": "Pennsylvania", "RI": "Rhode Island",
+	"SC": "South Carolina", "SD": "South Dakota", "TN": "Tennessee", "TX": "Texas", "UT": "Utah",
+	"VT": "Vermont", "VA": "Virginia", "WA": "Washington", "WV": "West Virginia", "WI": "Wisconsin",
+	"WY": "Wyoming",
+}
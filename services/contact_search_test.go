@@ -0,0 +1,119 @@
+package services_test
+
+import (
+	"codeplugs/models"
+	"codeplugs/services"
+	"errors"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupSearchTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Dialector{
+		DriverName: "sqlite",
+		DSN:        "file:memdb_contact_search?mode=memory&cache=shared",
+	}, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open DB: %v", err)
+	}
+	db.AutoMigrate(&models.DigitalContact{})
+	db.Exec("DELETE FROM digital_contacts")
+	if err := models.SetupDigitalContactFTS(db); err != nil {
+		t.Fatalf("Failed to set up FTS: %v", err)
+	}
+
+	db.Create(&[]models.DigitalContact{
+		{DMRID: 3126001, Callsign: "KF8AAA", Name: "Alice", City: "Grand Rapids", State: "Michigan", Country: "United States"},
+		{DMRID: 3126002, Callsign: "KF8BBB", Name: "Bob", City: "Detroit", State: "Michigan", Country: "United States"},
+		{DMRID: 3126003, Callsign: "W8CCC", Name: "Carol", City: "Lansing", State: "Michigan", Country: "United States"},
+		{DMRID: 3139001, Callsign: "KF8DDD", Name: "Dave", City: "Toledo", State: "Ohio", Country: "United States"},
+		{DMRID: 3021001, Callsign: "VE3EEE", Name: "Eve", City: "Toronto", State: "Ontario", Country: "Canada"},
+	})
+	return db
+}
+
+func searchCallsigns(t *testing.T, db *gorm.DB, s services.ContactSearch) ([]string, *services.ContactSearchResult) {
+	t.Helper()
+	res, err := services.SearchDigitalContacts(db, s)
+	if err != nil {
+		t.Fatalf("SearchDigitalContacts(%+v) failed: %v", s, err)
+	}
+	var calls []string
+	for _, c := range res.Data {
+		calls = append(calls, c.Callsign)
+	}
+	return calls, res
+}
+
+func TestSearchDigitalContacts_Queries(t *testing.T) {
+	db := setupSearchTestDB(t)
+
+	cases := map[string]int{
+		"state:MI call:KF8*":           2,
+		"state:Michigan":               3,
+		"call:KF8":                     0, // Field terms are exact without *
+		"kf8":                          3, // Bare words are prefixes
+		`city:"Grand Rapids"`:          1,
+		"gran":                         1,
+		"3126":                         3, // Bare numbers are DMR ID prefixes
+		"id:3139001":                   1,
+		"country:Canada":               1,
+		`name:"Bob" OR state:Ohio`:     0, // Operators are matched as literal words
+		"":                             5,
+		"state:MI call:KF8* 31260":     2,
+		`call:"KF8AAA" city:"lansing"`: 0,
+	}
+	for q, want := range cases {
+		calls, res := searchCallsigns(t, db, services.ContactSearch{Query: q})
+		if len(calls) != want || res.Total != int64(want) {
+			t.Errorf("%q: got %v (total %d), want %d", q, calls, res.Total, want)
+		}
+	}
+
+	for _, bad := range []string{"color:red", "id:abc", `city:"open`} {
+		if _, err := services.SearchDigitalContacts(db, services.ContactSearch{Query: bad}); !errors.Is(err, services.ErrInvalidQuery) {
+			t.Errorf("%q: expected ErrInvalidQuery, got %v", bad, err)
+		}
+	}
+	if _, err := services.SearchDigitalContacts(db, services.ContactSearch{Sort: "name; DROP TABLE digital_contacts"}); !errors.Is(err, services.ErrInvalidSort) {
+		t.Errorf("Expected ErrInvalidSort, got %v", err)
+	}
+}
+
+func TestSearchDigitalContacts_CursorPagination(t *testing.T) {
+	db := setupSearchTestDB(t)
+
+	var all []string
+	s := services.ContactSearch{Sort: "callsign", Order: "desc", Limit: 2}
+	for page := 0; page < 5; page++ {
+		calls, res := searchCallsigns(t, db, s)
+		all = append(all, calls...)
+		if res.NextCursor == "" {
+			break
+		}
+		s.Cursor = res.NextCursor
+	}
+	want := []string{"W8CCC", "VE3EEE", "KF8DDD", "KF8BBB", "KF8AAA"}
+	if len(all) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, all)
+	}
+	for i := range want {
+		if all[i] != want[i] {
+			t.Errorf("Position %d: got %s, want %s", i, all[i], want[i])
+		}
+	}
+
+	// Index stays in sync with updates and deletes
+	db.Model(&models.DigitalContact{}).Where("callsign = ?", "W8CCC").Update("state", "Ohio")
+	db.Unscoped().Where("callsign = ?", "KF8DDD").Delete(&models.DigitalContact{})
+	calls, _ := searchCallsigns(t, db, services.ContactSearch{Query: "state:OH"})
+	if len(calls) != 1 || calls[0] != "W8CCC" {
+		t.Errorf("Expected only W8CCC in Ohio after update, got %v", calls)
+	}
+
+	if _, err := services.SearchDigitalContacts(db, services.ContactSearch{Cursor: "not-a-cursor"}); !errors.Is(err, services.ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor, got %v", err)
+	}
+}
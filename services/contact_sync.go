@@ -0,0 +1,187 @@
+package services
+
+import (
+	"codeplugs/importer"
+	"codeplugs/models"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// syncWriteBatchSize caps rows per statement when writing sync results
+const syncWriteBatchSize = 100
+
+// syncedContact is the subset of a DigitalContact compared during a sync
+type syncedContact struct {
+	DMRID     int
+	Callsign  string
+	Name      string
+	City      string
+	State     string
+	Country   string
+	Remarks   string
+	RetiredAt *time.Time
+}
+
+// SyncRadioIDContacts incrementally syncs digital contacts against a RadioID.net
+// user.csv: new IDs are added, changed rows are updated in place, and IDs missing
+// from the dump are marked retired rather than deleted. Every difference is
+// written to a per-callsign change log on the returned ContactSync.
+// progress, if set, is called after each batch with the number of rows read.
+func SyncRadioIDContacts(db *gorm.DB, r io.Reader, source string, progress func(processed int)) (*models.ContactSync, error) {
+	sync := &models.ContactSync{Source: source, Status: "running"}
+	if err := db.Create(sync).Error; err != nil {
+		return nil, err
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		existing := make(map[int]syncedContact)
+		rows, err := tx.Model(&models.DigitalContact{}).
+			Select("dmr_id", "callsign", "name", "city", "state", "country", "remarks", "retired_at").Rows()
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var c syncedContact
+			if err := tx.ScanRows(rows, &c); err != nil {
+				rows.Close()
+				return err
+			}
+			existing[c.DMRID] = c
+		}
+		rows.Close()
+
+		seen := make(map[int]bool, len(existing))
+		processed := 0
+
+		err = importer.StreamRadioIDCSV(r, nil, importer.RadioIDBatchSize, func(batch []models.DigitalContact) error {
+			var writes []models.DigitalContact
+			var changes []models.ContactSyncChange
+
+			for _, c := range batch {
+				if seen[c.DMRID] {
+					continue // Duplicate row in the dump
+				}
+				seen[c.DMRID] = true
+
+				old, ok := existing[c.DMRID]
+				switch {
+				case !ok:
+					sync.Added++
+					writes = append(writes, c)
+					changes = append(changes, models.ContactSyncChange{
+						ContactSyncID: sync.ID, DMRID: c.DMRID, Callsign: c.Callsign, Action: models.ContactSyncAdded,
+					})
+				case old.RetiredAt != nil:
+					// Back in the dump after being retired
+					sync.Added++
+					writes = append(writes, c)
+					changes = append(changes, models.ContactSyncChange{
+						ContactSyncID: sync.ID, DMRID: c.DMRID, Callsign: c.Callsign, Action: models.ContactSyncAdded,
+						Details: strings.Join(append([]string{"restored"}, contactDiff(old, c)...), "; "),
+					})
+				default:
+					diff := contactDiff(old, c)
+					if len(diff) == 0 {
+						sync.Unchanged++
+						continue
+					}
+					sync.Changed++
+					writes = append(writes, c)
+					changes = append(changes, models.ContactSyncChange{
+						ContactSyncID: sync.ID, DMRID: c.DMRID, Callsign: c.Callsign, Action: models.ContactSyncChanged,
+						Details: strings.Join(diff, "; "),
+					})
+				}
+			}
+
+			if len(writes) > 0 {
+				if err := tx.Clauses(clause.OnConflict{
+					Columns:   []clause.Column{{Name: "dmr_id"}},
+					DoUpdates: clause.AssignmentColumns(importer.RadioIDUpsertColumns),
+				}).CreateInBatches(&writes, syncWriteBatchSize).Error; err != nil {
+					return err
+				}
+			}
+			if len(changes) > 0 {
+				if err := tx.CreateInBatches(&changes, syncWriteBatchSize).Error; err != nil {
+					return err
+				}
+			}
+
+			processed += len(batch)
+			if progress != nil {
+				progress(processed)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// Anything not in the new dump is retired
+		var retired []int
+		var changes []models.ContactSyncChange
+		for id, old := range existing {
+			if seen[id] || old.RetiredAt != nil {
+				continue
+			}
+			retired = append(retired, id)
+			changes = append(changes, models.ContactSyncChange{
+				ContactSyncID: sync.ID, DMRID: id, Callsign: old.Callsign, Action: models.ContactSyncRemoved,
+			})
+		}
+		sync.Removed = len(retired)
+
+		now := time.Now()
+		for i := 0; i < len(retired); i += 500 {
+			end := i + 500
+			if end > len(retired) {
+				end = len(retired)
+			}
+			if err := tx.Model(&models.DigitalContact{}).Where("dmr_id IN ?", retired[i:end]).
+				Update("retired_at", now).Error; err != nil {
+				return err
+			}
+		}
+		if len(changes) > 0 {
+			if err := tx.CreateInBatches(&changes, syncWriteBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		sync.Status = "error"
+		sync.Error = err.Error()
+		sync.Added, sync.Changed, sync.Removed, sync.Unchanged = 0, 0, 0, 0
+	} else {
+		sync.Status = "completed"
+	}
+	if saveErr := db.Save(sync).Error; saveErr != nil && err == nil {
+		err = saveErr
+	}
+	return sync, err
+}
+
+// contactDiff describes the fields that differ between the stored and incoming contact
+func contactDiff(old syncedContact, c models.DigitalContact) []string {
+	var diff []string
+	check := func(field, before, after string) {
+		if before != after {
+			diff = append(diff, fmt.Sprintf("%s: %s -> %s", field, before, after))
+		}
+	}
+	check("callsign", old.Callsign, c.Callsign)
+	check("name", old.Name, c.Name)
+	check("city", old.City, c.City)
+	check("state", old.State, c.State)
+	check("country", old.Country, c.Country)
+	check("remarks", old.Remarks, c.Remarks)
+	return diff
+}
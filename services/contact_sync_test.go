@@ -0,0 +1,94 @@
+package services_test
+
+import (
+	"codeplugs/models"
+	"codeplugs/services"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupSyncTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Dialector{
+		DriverName: "sqlite",
+		DSN:        "file:memdb_contact_sync?mode=memory&cache=shared",
+	}, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open DB: %v", err)
+	}
+	db.AutoMigrate(&models.DigitalContact{}, &models.ContactSync{}, &models.ContactSyncChange{})
+	db.Exec("DELETE FROM digital_contacts")
+	db.Exec("DELETE FROM contact_syncs")
+	db.Exec("DELETE FROM contact_sync_changes")
+	return db
+}
+
+func TestSyncRadioIDContacts(t *testing.T) {
+	db := setupSyncTestDB(t)
+
+	first := `RADIO_ID,CALLSIGN,FIRST_NAME,LAST_NAME,CITY,STATE,COUNTRY
+3126001,KF8S,Test,Op,Detroit,Michigan,United States
+3126002,W8ABC,Other,Op,Lansing,Michigan,United States
+3126003,N8OLD,Gone,Soon,Flint,Michigan,United States
+`
+	sync, err := services.SyncRadioIDContacts(db, strings.NewReader(first), "user.csv", nil)
+	if err != nil {
+		t.Fatalf("First sync failed: %v", err)
+	}
+	if sync.Added != 3 || sync.Changed != 0 || sync.Removed != 0 {
+		t.Errorf("First sync: expected 3/0/0, got %d/%d/%d", sync.Added, sync.Changed, sync.Removed)
+	}
+
+	second := `RADIO_ID,CALLSIGN,FIRST_NAME,LAST_NAME,CITY,STATE,COUNTRY
+3126001,KF8S,Test,Op,Warren,Michigan,United States
+3126002,W8ABC,Other,Op,Lansing,Michigan,United States
+3126004,K8NEW,New,Op,Troy,Michigan,United States
+`
+	sync, err = services.SyncRadioIDContacts(db, strings.NewReader(second), "user.csv", nil)
+	if err != nil {
+		t.Fatalf("Second sync failed: %v", err)
+	}
+	if sync.Added != 1 || sync.Changed != 1 || sync.Removed != 1 || sync.Unchanged != 1 {
+		t.Errorf("Second sync: expected 1/1/1/1, got %d/%d/%d/%d", sync.Added, sync.Changed, sync.Removed, sync.Unchanged)
+	}
+	if sync.Status != "completed" {
+		t.Errorf("Expected completed status, got %s", sync.Status)
+	}
+
+	var changes []models.ContactSyncChange
+	db.Where("contact_sync_id = ?", sync.ID).Order("dmr_id").Find(&changes)
+	if len(changes) != 3 {
+		t.Fatalf("Expected 3 change log entries, got %d", len(changes))
+	}
+	if changes[0].Callsign != "KF8S" || changes[0].Action != models.ContactSyncChanged || changes[0].Details != "city: Detroit -> Warren" {
+		t.Errorf("Unexpected change entry: %+v", changes[0])
+	}
+	if changes[1].Callsign != "N8OLD" || changes[1].Action != models.ContactSyncRemoved {
+		t.Errorf("Unexpected removal entry: %+v", changes[1])
+	}
+
+	// Retired, not deleted
+	var old models.DigitalContact
+	if err := db.Where("dmr_id = ?", 3126003).First(&old).Error; err != nil {
+		t.Fatalf("Retired contact should still exist: %v", err)
+	}
+	if old.RetiredAt == nil {
+		t.Error("Expected N8OLD to be marked retired")
+	}
+
+	// Coming back restores it
+	sync, err = services.SyncRadioIDContacts(db, strings.NewReader(first), "user.csv", nil)
+	if err != nil {
+		t.Fatalf("Third sync failed: %v", err)
+	}
+	if sync.Added != 1 || sync.Removed != 1 {
+		t.Errorf("Third sync: expected 1 restored and 1 removed, got %d/%d", sync.Added, sync.Removed)
+	}
+	var restored models.DigitalContact
+	db.Where("dmr_id = ?", 3126003).First(&restored)
+	if restored.RetiredAt != nil {
+		t.Error("Expected N8OLD to be restored")
+	}
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"gorm.io/gorm"
+)
+
+// keysetCursor is the keyset position after the last returned row: its sort
+// column value, with the primary key breaking ties
+type keysetCursor struct {
+	Value interface{} `json:"v"`
+	ID    uint        `json:"id"`
+}
+
+// after restricts query to rows past the cursor in sortCol order, where cmp
+// is ">" for ascending and "<" for descending. sortCol must be whitelisted.
+func (c keysetCursor) after(query *gorm.DB, sortCol, cmp string) *gorm.DB {
+	if sortCol == "id" {
+		return query.Where("id "+cmp+" ?", c.ID)
+	}
+	return query.Where("("+sortCol+" "+cmp+" ?) OR ("+sortCol+" = ? AND id "+cmp+" ?)", c.Value, c.Value, c.ID)
+}
+
+func encodeKeysetCursor(c keysetCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeKeysetCursor(s string) (keysetCursor, error) {
+	var c keysetCursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, ErrInvalidCursor
+	}
+	return c, nil
+}
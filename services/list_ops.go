@@ -0,0 +1,362 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"codeplugs/models"
+
+	"gorm.io/gorm"
+)
+
+// List expression operators
+const (
+	ListOpUnion     = "union"
+	ListOpIntersect = "intersect"
+	ListOpExclude   = "exclude"
+)
+
+// ListExpr is a parsed set expression over named contact lists. Leaves carry
+// a list Name; inner nodes combine Left and Right with Op.
+type ListExpr struct {
+	Op    string
+	Name  string
+	Left  *ListExpr
+	Right *ListExpr
+}
+
+// ParseListExpr parses a list expression such as
+//
+//	club, statewide & "BM last heard" - banned
+//
+// "," or "|" is union, "&" is intersect and " - " (with surrounding spaces)
+// is exclude. Operators share one precedence and apply left to right;
+// parentheses group. Names containing spaces or operators must be quoted.
+// A bare name is a single list, so plain -use-list values keep working.
+func ParseListExpr(s string) (*ListExpr, error) {
+	tokens, err := tokenizeListExpr(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &listExprParser{tokens: tokens}
+	expr, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in list expression", p.tokens[p.pos].text)
+	}
+	return expr, nil
+}
+
+// Names returns the distinct list names referenced by the expression
+func (e *ListExpr) Names() []string {
+	seen := make(map[string]bool)
+	var names []string
+	var walk func(*ListExpr)
+	walk = func(n *ListExpr) {
+		if n == nil {
+			return
+		}
+		if n.Op == "" {
+			if !seen[n.Name] {
+				seen[n.Name] = true
+				names = append(names, n.Name)
+			}
+			return
+		}
+		walk(n.Left)
+		walk(n.Right)
+	}
+	walk(e)
+	return names
+}
+
+// String renders the expression back in ParseListExpr syntax
+func (e *ListExpr) String() string {
+	if e.Op == "" {
+		if strings.ContainsAny(e.Name, " ,|&()\"") {
+			return fmt.Sprintf("%q", e.Name)
+		}
+		return e.Name
+	}
+	right := e.Right.String()
+	if e.Right.Op != "" {
+		right = "(" + right + ")"
+	}
+	switch e.Op {
+	case ListOpUnion:
+		return e.Left.String() + ", " + right
+	case ListOpIntersect:
+		return e.Left.String() + " & " + right
+	}
+	return e.Left.String() + " - " + right
+}
+
+// Query returns a subquery selecting the expression's DMR IDs, for use as
+// `dmr_id IN (?)`. Rule-based lists are evaluated via ContactListFilter.
+func (e *ListExpr) Query(db *gorm.DB) (*gorm.DB, error) {
+	if e.Op == "" {
+		var list models.ContactList
+		if err := db.Where("name = ?", e.Name).First(&list).Error; err != nil {
+			return nil, fmt.Errorf("filter list '%s' not found", e.Name)
+		}
+		return models.ContactListFilter(db, list.ID)
+	}
+
+	left, err := e.Left.Query(db)
+	if err != nil {
+		return nil, err
+	}
+	right, err := e.Right.Query(db)
+	if err != nil {
+		return nil, err
+	}
+	var compound string
+	switch e.Op {
+	case ListOpUnion:
+		compound = "UNION"
+	case ListOpIntersect:
+		compound = "INTERSECT"
+	case ListOpExclude:
+		compound = "EXCEPT"
+	default:
+		return nil, fmt.Errorf("unknown list operator %q", e.Op)
+	}
+	// Operands are wrapped so nested compounds keep their grouping
+	return db.Raw("SELECT dmr_id FROM (?) "+compound+" SELECT dmr_id FROM (?)", left, right), nil
+}
+
+// ContactListExprFilter parses a -use-list value and returns its DMR ID subquery
+func ContactListExprFilter(db *gorm.DB, expr string) (*gorm.DB, error) {
+	e, err := ParseListExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+	return e.Query(db)
+}
+
+// ListOperation describes `codeplugs list-op`: the union of Union, narrowed
+// to contacts in every Intersect list, minus anything in an Exclude list.
+// Expression, when set, is used instead of the three name lists.
+type ListOperation struct {
+	Union       []string `json:"union"`
+	Intersect   []string `json:"intersect"`
+	Exclude     []string `json:"exclude"`
+	Expression  string   `json:"expression"`
+	Into        string   `json:"into"`
+	Description string   `json:"description"`
+}
+
+// ListOperationResult reports the size of each input list and of the result
+type ListOperationResult struct {
+	Expression string              `json:"expression"`
+	Inputs     map[string]int64    `json:"inputs"`
+	Count      int                 `json:"count"`
+	List       *models.ContactList `json:"list,omitempty"`
+}
+
+// Expr builds the expression for the operation
+func (op ListOperation) Expr() (*ListExpr, error) {
+	if strings.TrimSpace(op.Expression) != "" {
+		return ParseListExpr(op.Expression)
+	}
+
+	var expr *ListExpr
+	combine := func(kind string, names []string) {
+		for _, name := range names {
+			if name = strings.TrimSpace(name); name == "" {
+				continue
+			}
+			leaf := &ListExpr{Name: name}
+			if expr == nil {
+				expr = leaf
+				continue
+			}
+			expr = &ListExpr{Op: kind, Left: expr, Right: leaf}
+		}
+	}
+	combine(ListOpUnion, op.Union)
+	combine(ListOpIntersect, op.Intersect)
+	if expr == nil {
+		return nil, errors.New("list operation needs at least one -union or -intersect list")
+	}
+	combine(ListOpExclude, op.Exclude)
+	return expr, nil
+}
+
+// RunListOperation evaluates a list operation. When Into is set the result
+// replaces that list's entries (creating it if needed) and clears its rules,
+// so the new list is a snapshot rather than a live view of its inputs.
+func RunListOperation(db *gorm.DB, op ListOperation) (*ListOperationResult, error) {
+	expr, err := op.Expr()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ListOperationResult{Expression: expr.String(), Inputs: make(map[string]int64)}
+	for _, name := range expr.Names() {
+		sub, err := (&ListExpr{Name: name}).Query(db)
+		if err != nil {
+			return nil, err
+		}
+		var n int64
+		if err := db.Raw("SELECT COUNT(*) FROM (?)", sub).Scan(&n).Error; err != nil {
+			return nil, err
+		}
+		result.Inputs[name] = n
+	}
+
+	query, err := expr.Query(db)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int
+	if err := db.Raw("SELECT dmr_id FROM (?)", query).Scan(&ids).Error; err != nil {
+		return nil, err
+	}
+	sort.Ints(ids)
+	result.Count = len(ids)
+
+	into := strings.TrimSpace(op.Into)
+	if into == "" {
+		return result, nil
+	}
+	for _, name := range expr.Names() {
+		if name == into {
+			return nil, fmt.Errorf("list '%s' cannot be both an input and the output", into)
+		}
+	}
+
+	var list models.ContactList
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where(models.ContactList{Name: into}).FirstOrCreate(&list).Error; err != nil {
+			return err
+		}
+		list.Description = op.Description
+		if list.Description == "" {
+			list.Description = "list-op: " + result.Expression
+		}
+		if err := tx.Save(&list).Error; err != nil {
+			return err
+		}
+		for _, m := range []interface{}{&models.ContactListEntry{}, &models.ContactListRule{}} {
+			if err := tx.Unscoped().Where("contact_list_id = ?", list.ID).Delete(m).Error; err != nil {
+				return err
+			}
+		}
+		entries := make([]models.ContactListEntry, len(ids))
+		for i, id := range ids {
+			entries[i] = models.ContactListEntry{ContactListID: list.ID, DMRID: id}
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(&entries, 100).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.List = &list
+	return result, nil
+}
+
+type listExprToken struct {
+	kind string // "name", "op" or "paren"
+	text string
+}
+
+func tokenizeListExpr(s string) ([]listExprToken, error) {
+	var tokens []listExprToken
+	rs := []rune(s)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			// " - " is exclude; a hyphen inside a name is part of the name
+			if i+2 < len(rs) && rs[i+1] == '-' && unicode.IsSpace(rs[i+2]) {
+				tokens = append(tokens, listExprToken{"op", ListOpExclude})
+				i += 3
+				continue
+			}
+			i++
+		case r == ',' || r == '|':
+			tokens = append(tokens, listExprToken{"op", ListOpUnion})
+			i++
+		case r == '&':
+			tokens = append(tokens, listExprToken{"op", ListOpIntersect})
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, listExprToken{"paren", string(r)})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(rs) && rs[end] != '"' {
+				end++
+			}
+			if end == len(rs) {
+				return nil, errors.New("unterminated quote in list expression")
+			}
+			tokens = append(tokens, listExprToken{"name", string(rs[i+1 : end])})
+			i = end + 1
+		default:
+			start := i
+			for i < len(rs) && !unicode.IsSpace(rs[i]) && !strings.ContainsRune(",|&()\"", rs[i]) {
+				i++
+			}
+			tokens = append(tokens, listExprToken{"name", string(rs[start:i])})
+		}
+	}
+	return tokens, nil
+}
+
+type listExprParser struct {
+	tokens []listExprToken
+	pos    int
+}
+
+func (p *listExprParser) parseExpr() (*ListExpr, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	for p.pos < len(p.tokens) && p.tokens[p.pos].kind == "op" {
+		op := p.tokens[p.pos].text
+		p.pos++
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		left = &ListExpr{Op: op, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *listExprParser) parseOperand() (*ListExpr, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, errors.New("list expression ends unexpectedly")
+	}
+	tok := p.tokens[p.pos]
+	p.pos++
+	switch {
+	case tok.kind == "name":
+		if tok.text == "" {
+			return nil, errors.New("empty list name in expression")
+		}
+		return &ListExpr{Name: tok.text}, nil
+	case tok.text == "(":
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.tokens) || p.tokens[p.pos].text != ")" {
+			return nil, errors.New("missing ) in list expression")
+		}
+		p.pos++
+		return expr, nil
+	}
+	return nil, fmt.Errorf("unexpected %q in list expression", tok.text)
+}
@@ -0,0 +1,151 @@
+package services_test
+
+import (
+	"codeplugs/models"
+	"codeplugs/services"
+	"reflect"
+	"sort"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupListOpsTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Dialector{
+		DriverName: "sqlite",
+		DSN:        "file:memdb_list_ops?mode=memory&cache=shared",
+	}, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open DB: %v", err)
+	}
+	db.AutoMigrate(&models.DigitalContact{}, &models.ContactList{}, &models.ContactListEntry{}, &models.ContactListRule{})
+	db.Exec("DELETE FROM digital_contacts")
+	db.Exec("DELETE FROM contact_lists")
+	db.Exec("DELETE FROM contact_list_entries")
+	db.Exec("DELETE FROM contact_list_rules")
+	return db
+}
+
+func createTestList(t *testing.T, db *gorm.DB, name string, ids ...int) {
+	list := models.ContactList{Name: name}
+	if err := db.Create(&list).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range ids {
+		db.Create(&models.ContactListEntry{ContactListID: list.ID, DMRID: id})
+	}
+}
+
+func listIDs(t *testing.T, db *gorm.DB, expr string) []int {
+	sub, err := services.ContactListExprFilter(db, expr)
+	if err != nil {
+		t.Fatalf("ContactListExprFilter(%q) failed: %v", expr, err)
+	}
+	var ids []int
+	db.Raw("SELECT dmr_id FROM (?)", sub).Scan(&ids)
+	sort.Ints(ids)
+	return ids
+}
+
+func TestParseListExpr(t *testing.T) {
+	cases := map[string]string{
+		"club":                            "club",
+		"bm-last-heard":                   "bm-last-heard",
+		"a,b & c - d":                     "a, b & c - d",
+		"a | (b & c)":                     "a, (b & c)",
+		`"club members" - banned`:         `"club members" - banned`,
+		"  statewide  &  bm-last-heard  ": "statewide & bm-last-heard",
+	}
+	for in, want := range cases {
+		e, err := services.ParseListExpr(in)
+		if err != nil {
+			t.Errorf("ParseListExpr(%q) failed: %v", in, err)
+			continue
+		}
+		if got := e.String(); got != want {
+			t.Errorf("ParseListExpr(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	for _, bad := range []string{"", "a &", "(a, b", "a b", `"open`} {
+		if _, err := services.ParseListExpr(bad); err == nil {
+			t.Errorf("Expected error for %q", bad)
+		}
+	}
+}
+
+func TestListExprQuery(t *testing.T) {
+	db := setupListOpsTestDB(t)
+	createTestList(t, db, "club", 1, 2, 3)
+	createTestList(t, db, "bm-heard", 3, 4, 5)
+	createTestList(t, db, "statewide", 2, 3, 4, 6)
+	createTestList(t, db, "banned", 4)
+
+	if got, want := listIDs(t, db, "club"), []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("single list = %v, want %v", got, want)
+	}
+	if got, want := listIDs(t, db, "club, bm-heard & statewide - banned"), []int{2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("left to right = %v, want %v", got, want)
+	}
+	if got, want := listIDs(t, db, "club, (bm-heard - banned)"), []int{1, 2, 3, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("grouped = %v, want %v", got, want)
+	}
+	if _, err := services.ContactListExprFilter(db, "club, missing"); err == nil {
+		t.Error("Expected error for unknown list")
+	}
+
+	// Rule-based lists combine like explicit ones
+	db.Create(&models.DigitalContact{DMRID: 2, Callsign: "KF8AAA", State: "Ohio"})
+	db.Create(&models.DigitalContact{DMRID: 7, Callsign: "KF8BBB", State: "Ohio"})
+	ohio := models.ContactList{Name: "ohio"}
+	db.Create(&ohio)
+	if err := models.SetContactListRules(db, ohio.ID, []models.ContactListRule{{Field: models.RuleState, Value: "Ohio"}}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := listIDs(t, db, "ohio, banned - club"), []int{4, 7}; !reflect.DeepEqual(got, want) {
+		t.Errorf("with rules = %v, want %v", got, want)
+	}
+}
+
+func TestRunListOperation(t *testing.T) {
+	db := setupListOpsTestDB(t)
+	createTestList(t, db, "a", 1, 2, 3)
+	createTestList(t, db, "b", 3, 4)
+	createTestList(t, db, "c", 2, 3, 4, 5)
+	createTestList(t, db, "d", 3)
+	createTestList(t, db, "combined", 99)
+
+	op := services.ListOperation{
+		Union:     []string{"a", "b"},
+		Intersect: []string{"c"},
+		Exclude:   []string{"d"},
+		Into:      "combined",
+	}
+	result, err := services.RunListOperation(db, op)
+	if err != nil {
+		t.Fatalf("RunListOperation failed: %v", err)
+	}
+	if result.Count != 2 {
+		t.Errorf("Expected 2 contacts, got %d", result.Count)
+	}
+	if result.Inputs["a"] != 3 || result.Inputs["c"] != 4 {
+		t.Errorf("Unexpected input counts: %v", result.Inputs)
+	}
+	if result.List == nil || result.List.Name != "combined" {
+		t.Fatalf("Expected result saved to combined, got %+v", result.List)
+	}
+
+	// The target list is replaced, not appended to
+	if got, want := listIDs(t, db, "combined"), []int{2, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("combined = %v, want %v", got, want)
+	}
+
+	op.Into = "a"
+	if _, err := services.RunListOperation(db, op); err == nil {
+		t.Error("Expected error writing into an input list")
+	}
+	if _, err := services.RunListOperation(db, services.ListOperation{Exclude: []string{"d"}}); err == nil {
+		t.Error("Expected error for exclude-only operation")
+	}
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"codeplugs/models"
+
+	"gorm.io/gorm"
+)
+
+// Suggestion sources
+const (
+	SuggestionContact = "contact"
+	SuggestionCatalog = "catalog"
+)
+
+const (
+	// minSuggestionScore drops suggestions that share too little of the name
+	minSuggestionScore = 0.5
+	// containedNameScore rates a name that contains the other outright
+	containedNameScore = 0.8
+)
+
+// ChannelRef identifies a channel in reports
+type ChannelRef struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// ContactSuggestion is a possible real talkgroup for a placeholder contact.
+// ContactID is set for existing contacts; Network for catalog entries.
+type ContactSuggestion struct {
+	Source    string  `json:"source"`
+	ContactID uint    `json:"contact_id,omitempty"`
+	Network   string  `json:"network,omitempty"`
+	DMRID     int     `json:"dmr_id"`
+	Name      string  `json:"name"`
+	Score     float64 `json:"score"`
+}
+
+// UnresolvedContact is a placeholder contact with the channels using it and
+// the best-matching real talkgroups
+type UnresolvedContact struct {
+	models.Contact
+	Channels    []ChannelRef        `json:"channels"`
+	Suggestions []ContactSuggestion `json:"suggestions"`
+}
+
+// UnresolvedContacts reports every negative-ID placeholder contact with the
+// channels that use it and up to maxSuggestions fuzzy name matches from
+// existing contacts and the talkgroup catalog, best first.
+func UnresolvedContacts(db *gorm.DB, maxSuggestions int) ([]UnresolvedContact, error) {
+	var placeholders []models.Contact
+	if err := db.Where("dmr_id < 0").Order("name").Find(&placeholders).Error; err != nil {
+		return nil, err
+	}
+	if len(placeholders) == 0 {
+		return []UnresolvedContact{}, nil
+	}
+
+	var contacts []models.Contact
+	if err := db.Where("dmr_id > 0").Find(&contacts).Error; err != nil {
+		return nil, err
+	}
+	var catalog []models.TalkgroupCatalog
+	if err := db.Find(&catalog).Error; err != nil {
+		return nil, err
+	}
+
+	report := make([]UnresolvedContact, 0, len(placeholders))
+	for _, p := range placeholders {
+		u := UnresolvedContact{Contact: p, Channels: []ChannelRef{}}
+		if err := channelsUsingContact(db.Model(&models.Channel{}), p).
+			Select("id", "name").Order("sort_order, id").Scan(&u.Channels).Error; err != nil {
+			return nil, err
+		}
+
+		var suggestions []ContactSuggestion
+		for _, c := range contacts {
+			if c.Type != p.Type {
+				continue
+			}
+			if score := talkgroupNameScore(p.Name, c.Name, c.DMRID); score >= minSuggestionScore {
+				suggestions = append(suggestions, ContactSuggestion{Source: SuggestionContact, ContactID: c.ID, DMRID: c.DMRID, Name: c.Name, Score: score})
+			}
+		}
+		for _, tg := range catalog {
+			if score := talkgroupNameScore(p.Name, tg.Name, tg.TGID); score >= minSuggestionScore {
+				suggestions = append(suggestions, ContactSuggestion{Source: SuggestionCatalog, Network: tg.Network, DMRID: tg.TGID, Name: tg.Name, Score: score})
+			}
+		}
+		sort.SliceStable(suggestions, func(i, j int) bool {
+			if suggestions[i].Score != suggestions[j].Score {
+				return suggestions[i].Score > suggestions[j].Score
+			}
+			// Prefer a contact already in the codeplug over a catalog entry
+			return suggestions[i].Source == SuggestionContact && suggestions[j].Source != SuggestionContact
+		})
+		if maxSuggestions > 0 && len(suggestions) > maxSuggestions {
+			suggestions = suggestions[:maxSuggestions]
+		}
+		u.Suggestions = suggestions
+		if u.Suggestions == nil {
+			u.Suggestions = []ContactSuggestion{}
+		}
+		report = append(report, u)
+	}
+	return report, nil
+}
+
+// MergeRequest picks the real talkgroup for a placeholder: either an existing
+// contact, or a talkgroup ID (typically from the catalog).
+type MergeRequest struct {
+	PlaceholderID uint `json:"placeholder_id"`
+	ContactID     uint `json:"contact_id"`
+	DMRID         int  `json:"dmr_id"`
+}
+
+// MergeResult reports the contact a placeholder was merged into
+type MergeResult struct {
+	Contact         models.Contact `json:"contact"`
+	ChannelsUpdated int64          `json:"channels_updated"`
+}
+
+// ErrNotPlaceholder is returned when merging a contact with a real DMR ID
+var ErrNotPlaceholder = errors.New("contact is not a placeholder")
+
+// MergePlaceholder repoints a placeholder's channels to the chosen contact and
+// deletes the placeholder. With a DMRID instead of a ContactID, the existing
+// contact with that ID is used, or the placeholder simply takes the ID.
+func MergePlaceholder(db *gorm.DB, req MergeRequest) (*MergeResult, error) {
+	var result MergeResult
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var placeholder models.Contact
+		if err := tx.First(&placeholder, req.PlaceholderID).Error; err != nil {
+			return err
+		}
+		if placeholder.DMRID >= 0 {
+			return ErrNotPlaceholder
+		}
+
+		var target models.Contact
+		switch {
+		case req.ContactID != 0:
+			if err := tx.First(&target, req.ContactID).Error; err != nil {
+				return err
+			}
+			if target.DMRID <= 0 {
+				return fmt.Errorf("cannot merge into contact %d without a real DMR ID", target.ID)
+			}
+		case req.DMRID > 0:
+			err := tx.Where("dmr_id = ? AND type = ?", req.DMRID, placeholder.Type).First(&target).Error
+			if err == gorm.ErrRecordNotFound {
+				if err := tx.Model(&placeholder).Update("dmr_id", req.DMRID).Error; err != nil {
+					return err
+				}
+				result.Contact = placeholder
+				return channelsUsingContact(tx.Model(&models.Channel{}), placeholder).Count(&result.ChannelsUpdated).Error
+			}
+			if err != nil {
+				return err
+			}
+		default:
+			return errors.New("contact_id or dmr_id is required")
+		}
+
+		moved, err := mergeContact(tx, placeholder, target)
+		if err != nil {
+			return err
+		}
+		result.Contact = target
+		result.ChannelsUpdated = moved
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// talkgroupNameScore rates how well a placeholder name matches a talkgroup,
+// from 0 to 1. A talkgroup number appearing in the name is a certain match;
+// otherwise it is the edit-distance similarity of the normalized names.
+func talkgroupNameScore(placeholder, name string, dmrID int) float64 {
+	id := strconv.Itoa(dmrID)
+	for _, num := range talkgroupNumber.FindAllString(placeholder, -1) {
+		if num == id {
+			return 1
+		}
+	}
+
+	a := models.NormalizeTalkgroupName(placeholder)
+	b := models.NormalizeTalkgroupName(name)
+	if a == "" || b == "" {
+		return 0
+	}
+	longest := max(utf8.RuneCountInString(a), utf8.RuneCountInString(b))
+	score := 1 - float64(levenshtein(a, b))/float64(longest)
+	// "Michigan Statewide" for "Michigan" is a strong hint despite the length gap
+	if strings.Contains(a, b) || strings.Contains(b, a) {
+		score = max(score, containedNameScore)
+	}
+	return score
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,86 @@
+package services_test
+
+import (
+	"codeplugs/models"
+	"codeplugs/services"
+	"testing"
+)
+
+func TestUnresolvedContacts_ReportAndMerge(t *testing.T) {
+	db := setupResolveTestDB(t)
+
+	db.Create(&models.TalkgroupCatalog{Network: models.NetworkBrandmeister, TGID: 3126, Name: "Michigan"})
+	real := models.Contact{Name: "Ohio Statewide", DMRID: 3139, Type: models.ContactTypeGroup}
+	db.Create(&real)
+
+	channels := []models.Channel{
+		{Name: "MI 1", TxContact: "Michigan Statewide"},
+		{Name: "MI 2", TxContact: "Michigan Statewide"},
+		{Name: "OH", TxContact: "Ohio State Wide"},
+		{Name: "Club", TxContact: "Club Net"},
+	}
+	services.ResolveContacts(db, channels)
+	for i := range channels {
+		db.Create(&channels[i])
+	}
+
+	report, err := services.UnresolvedContacts(db, 3)
+	if err != nil {
+		t.Fatalf("UnresolvedContacts failed: %v", err)
+	}
+	if len(report) != 3 {
+		t.Fatalf("Expected 3 placeholders, got %d", len(report))
+	}
+	byName := make(map[string]services.UnresolvedContact)
+	for _, u := range report {
+		byName[u.Name] = u
+	}
+
+	mi := byName["Michigan Statewide"]
+	if len(mi.Channels) != 2 {
+		t.Errorf("Expected 2 channels for Michigan placeholder, got %d", len(mi.Channels))
+	}
+	if len(mi.Suggestions) == 0 || mi.Suggestions[0].Source != services.SuggestionCatalog || mi.Suggestions[0].DMRID != 3126 {
+		t.Errorf("Expected catalog suggestion 3126, got %+v", mi.Suggestions)
+	}
+	oh := byName["Ohio State Wide"]
+	if len(oh.Suggestions) == 0 || oh.Suggestions[0].ContactID != real.ID {
+		t.Errorf("Expected existing contact suggestion, got %+v", oh.Suggestions)
+	}
+	if club := byName["Club Net"]; len(club.Suggestions) != 0 {
+		t.Errorf("Expected no suggestions for Club Net, got %+v", club.Suggestions)
+	}
+
+	// Merge into an existing contact
+	res, err := services.MergePlaceholder(db, services.MergeRequest{PlaceholderID: oh.ID, ContactID: real.ID})
+	if err != nil {
+		t.Fatalf("MergePlaceholder failed: %v", err)
+	}
+	if res.ChannelsUpdated != 1 {
+		t.Errorf("Expected 1 channel updated, got %d", res.ChannelsUpdated)
+	}
+	var ch models.Channel
+	db.Where("name = ?", "OH").First(&ch)
+	if ch.ContactID == nil || *ch.ContactID != real.ID || ch.TxContact != real.Name {
+		t.Errorf("Channel not repointed: %v %s", ch.ContactID, ch.TxContact)
+	}
+
+	// Merge by talkgroup ID with no existing contact keeps the placeholder
+	res, err = services.MergePlaceholder(db, services.MergeRequest{PlaceholderID: mi.ID, DMRID: 3126})
+	if err != nil {
+		t.Fatalf("MergePlaceholder by DMR ID failed: %v", err)
+	}
+	if res.Contact.ID != mi.ID || res.Contact.DMRID != 3126 || res.ChannelsUpdated != 2 {
+		t.Errorf("Unexpected merge result: %+v", res)
+	}
+
+	// Real contacts can't be merged as placeholders
+	if _, err := services.MergePlaceholder(db, services.MergeRequest{PlaceholderID: real.ID, DMRID: 1}); err != services.ErrNotPlaceholder {
+		t.Errorf("Expected ErrNotPlaceholder, got %v", err)
+	}
+
+	report, _ = services.UnresolvedContacts(db, 3)
+	if len(report) != 1 || report[0].Name != "Club Net" {
+		t.Errorf("Expected only Club Net left unresolved, got %+v", report)
+	}
+}
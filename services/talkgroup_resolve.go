@@ -0,0 +1,156 @@
+package services
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"codeplugs/models"
+
+	"gorm.io/gorm"
+)
+
+// Placeholder resolution outcomes
+const (
+	PlaceholderResolved  = "resolved"  // Placeholder took the catalog talkgroup ID
+	PlaceholderMerged    = "merged"    // Channels moved to an existing contact with that ID
+	PlaceholderAmbiguous = "ambiguous" // Several catalog talkgroups match the name
+	PlaceholderUnmatched = "unmatched" // Nothing in the catalog matches
+)
+
+// PlaceholderResolution reports what happened to one placeholder contact
+type PlaceholderResolution struct {
+	ContactID  uint                      `json:"contact_id"`
+	Name       string                    `json:"name"`
+	OldDMRID   int                       `json:"old_dmr_id"`
+	NewDMRID   int                       `json:"new_dmr_id,omitempty"`
+	Network    string                    `json:"network,omitempty"`
+	MergedInto uint                      `json:"merged_into,omitempty"`
+	Status     string                    `json:"status"`
+	Candidates []models.TalkgroupCatalog `json:"candidates,omitempty"`
+}
+
+var talkgroupNumber = regexp.MustCompile(`\d{2,8}`)
+
+// ResolvePlaceholderContacts fixes the negative-ID group contacts that
+// ResolveContacts invents for unknown TxContact names. Each placeholder's
+// name is matched against the talkgroup catalog, first by normalized name
+// and then by any talkgroup number in the name ("TG 3126 MI"). When one
+// talkgroup ID matches, the placeholder takes that ID, or, if a contact with
+// that ID already exists, its channels are moved there and it is deleted.
+// Networks limits matching to those catalogs; empty means all.
+func ResolvePlaceholderContacts(db *gorm.DB, networks []string) ([]PlaceholderResolution, error) {
+	var placeholders []models.Contact
+	if err := db.Where("dmr_id < 0").Order("id").Find(&placeholders).Error; err != nil {
+		return nil, err
+	}
+	if len(placeholders) == 0 {
+		return nil, nil
+	}
+
+	catalogQuery := db.Model(&models.TalkgroupCatalog{})
+	if len(networks) > 0 {
+		catalogQuery = catalogQuery.Where("network IN ?", networks)
+	}
+	var catalog []models.TalkgroupCatalog
+	if err := catalogQuery.Order("tg_id, network").Find(&catalog).Error; err != nil {
+		return nil, err
+	}
+	byName := make(map[string][]models.TalkgroupCatalog)
+	byID := make(map[int][]models.TalkgroupCatalog)
+	for _, tg := range catalog {
+		key := models.NormalizeTalkgroupName(tg.Name)
+		byName[key] = append(byName[key], tg)
+		byID[tg.TGID] = append(byID[tg.TGID], tg)
+	}
+
+	results := make([]PlaceholderResolution, 0, len(placeholders))
+	for _, p := range placeholders {
+		res := PlaceholderResolution{ContactID: p.ID, Name: p.Name, OldDMRID: p.DMRID, Status: PlaceholderUnmatched}
+
+		candidates := byName[models.NormalizeTalkgroupName(p.Name)]
+		if len(candidates) == 0 {
+			for _, num := range talkgroupNumber.FindAllString(p.Name, -1) {
+				if id, err := strconv.Atoi(num); err == nil {
+					candidates = append(candidates, byID[id]...)
+				}
+			}
+		}
+
+		ids := distinctTalkgroupIDs(candidates)
+		switch {
+		case len(ids) == 1:
+			res.NewDMRID = ids[0]
+			res.Network = candidates[0].Network
+			mergedInto, err := applyPlaceholderID(db, p, ids[0])
+			if err != nil {
+				return nil, err
+			}
+			res.Status = PlaceholderResolved
+			if mergedInto != 0 {
+				res.Status = PlaceholderMerged
+				res.MergedInto = mergedInto
+			}
+		case len(ids) > 1:
+			res.Status = PlaceholderAmbiguous
+			res.Candidates = candidates
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+func distinctTalkgroupIDs(candidates []models.TalkgroupCatalog) []int {
+	seen := make(map[int]bool)
+	var ids []int
+	for _, c := range candidates {
+		if !seen[c.TGID] {
+			seen[c.TGID] = true
+			ids = append(ids, c.TGID)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// applyPlaceholderID gives a placeholder its real talkgroup ID, merging it
+// into an existing contact with that ID and type if there is one.
+func applyPlaceholderID(db *gorm.DB, placeholder models.Contact, dmrID int) (uint, error) {
+	var mergedInto uint
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var existing models.Contact
+		err := tx.Where("dmr_id = ? AND type = ?", dmrID, placeholder.Type).First(&existing).Error
+		if err == gorm.ErrRecordNotFound {
+			return tx.Model(&placeholder).Update("dmr_id", dmrID).Error
+		}
+		if err != nil {
+			return err
+		}
+		mergedInto = existing.ID
+		_, err = mergeContact(tx, placeholder, existing)
+		return err
+	})
+	return mergedInto, err
+}
+
+// mergeContact repoints every channel using from (by ID, or by TxContact
+// name when unlinked) to into, then deletes from. The delete is permanent so
+// the (dmr_id, type) unique index is freed. It returns the channels moved.
+func mergeContact(tx *gorm.DB, from, into models.Contact) (int64, error) {
+	res := channelsUsingContact(tx.Model(&models.Channel{}), from).
+		Updates(map[string]interface{}{"contact_id": into.ID, "tx_contact": into.Name})
+	if res.Error != nil {
+		return 0, res.Error
+	}
+	return res.RowsAffected, tx.Unscoped().Delete(&models.Contact{}, from.ID).Error
+}
+
+// channelsUsingContact scopes a channel query to those using the contact
+func channelsUsingContact(q *gorm.DB, c models.Contact) *gorm.DB {
+	if strings.TrimSpace(c.Name) == "" {
+		return q.Where("contact_id = ?", c.ID)
+	}
+	return q.Where("contact_id = ? OR (contact_id IS NULL AND UPPER(TRIM(tx_contact)) = ?)",
+		c.ID, strings.ToUpper(strings.TrimSpace(c.Name)))
+}
@@ -0,0 +1,100 @@
+package services_test
+
+import (
+	"codeplugs/models"
+	"codeplugs/services"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupResolveTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Dialector{
+		DriverName: "sqlite",
+		DSN:        "file:memdb_tg_resolve?mode=memory&cache=shared",
+	}, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open DB: %v", err)
+	}
+	db.AutoMigrate(&models.Channel{}, &models.Contact{}, &models.TalkgroupCatalog{})
+	db.Exec("DELETE FROM channels")
+	db.Exec("DELETE FROM contacts")
+	db.Exec("DELETE FROM talkgroup_catalogs")
+	return db
+}
+
+func TestResolvePlaceholderContacts(t *testing.T) {
+	db := setupResolveTestDB(t)
+
+	db.Create(&[]models.TalkgroupCatalog{
+		{Network: models.NetworkBrandmeister, TGID: 3126, Name: "Michigan"},
+		{Network: models.NetworkBrandmeister, TGID: 91, Name: "World-wide"},
+		{Network: models.NetworkBrandmeister, TGID: 3100, Name: "USA"},
+		{Network: models.NetworkTGIF, TGID: 31665, Name: "USA"},
+	})
+
+	existing := models.Contact{Name: "Worldwide", DMRID: 91, Type: models.ContactTypeGroup}
+	db.Create(&existing)
+
+	// Placeholders as created by ResolveContacts
+	channels := []models.Channel{
+		{Name: "MI", TxContact: "michigan"},
+		{Name: "WW", TxContact: "World Wide"},
+		{Name: "US", TxContact: "USA"},
+		{Name: "TG", TxContact: "TG 3100 Nationwide"},
+		{Name: "Local", TxContact: "Club Net"},
+	}
+	services.ResolveContacts(db, channels)
+	for i := range channels {
+		db.Create(&channels[i])
+	}
+
+	results, err := services.ResolvePlaceholderContacts(db, nil)
+	if err != nil {
+		t.Fatalf("ResolvePlaceholderContacts failed: %v", err)
+	}
+	status := make(map[string]services.PlaceholderResolution)
+	for _, r := range results {
+		status[r.Name] = r
+	}
+
+	if r := status["michigan"]; r.Status != services.PlaceholderResolved || r.NewDMRID != 3126 {
+		t.Errorf("michigan: %+v", r)
+	}
+	if r := status["TG 3100 Nationwide"]; r.Status != services.PlaceholderResolved || r.NewDMRID != 3100 {
+		t.Errorf("TG 3100: %+v", r)
+	}
+	if r := status["World Wide"]; r.Status != services.PlaceholderMerged || r.MergedInto != existing.ID {
+		t.Errorf("World Wide: %+v", r)
+	}
+	if r := status["USA"]; r.Status != services.PlaceholderAmbiguous || len(r.Candidates) != 2 {
+		t.Errorf("USA: %+v", r)
+	}
+	if r := status["Club Net"]; r.Status != services.PlaceholderUnmatched {
+		t.Errorf("Club Net: %+v", r)
+	}
+
+	// The merged placeholder's channel now uses the existing contact
+	var ww models.Channel
+	db.Where("name = ?", "WW").First(&ww)
+	if ww.ContactID == nil || *ww.ContactID != existing.ID || ww.TxContact != "Worldwide" {
+		t.Errorf("Expected WW channel repointed to contact %d, got %v (%s)", existing.ID, ww.ContactID, ww.TxContact)
+	}
+	var count int64
+	db.Model(&models.Contact{}).Unscoped().Where("dmr_id < 0").Count(&count)
+	if count != 2 {
+		t.Errorf("Expected 2 placeholders left (USA, Club Net), got %d", count)
+	}
+
+	// Limiting to one network removes the ambiguity
+	results, err = services.ResolvePlaceholderContacts(db, []string{models.NetworkTGIF})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if r.Name == "USA" && (r.Status != services.PlaceholderResolved || r.NewDMRID != 31665) {
+			t.Errorf("USA on TGIF: %+v", r)
+		}
+	}
+}